@@ -0,0 +1,113 @@
+// Fetch command for downloading teams and schedules from ESPN into JSON files
+
+package main
+
+import (
+    "flag"
+    "fmt"
+
+    "gamescript/internal/importer"
+    "gamescript/internal/models"
+    "gamescript/internal/services/espn"
+)
+
+
+func runFetch(args []string) error {
+    return runAction("fetch", args, map[string]func([]string) error{
+        "teams":    fetchTeams,
+        "schedule": fetchSchedule,
+    }, []string{"teams", "schedule"})
+}
+
+func addDataDirFlag(flags *flag.FlagSet) *string {
+    return flags.String("data-dir", "database", "Directory holding the nfl/ and nba/ data files")
+}
+
+func fetchTeams(args []string) error {
+    flags := newFlagSet("fetch teams")
+    selection := addSeasonFlags(flags)
+    dataDir := addDataDirFlag(flags)
+    output := flags.String("out", "", "File to write (default: <data-dir>/<sport>/teams/<sport>_teams_<season>.json)")
+    dryRun := flags.Bool("dry-run", false, "Fetch without writing the file")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+    if err := selection.validate(); err != nil {
+        return err
+    }
+
+    client := espn.NewClient()
+    var teams []models.Team
+    var err error
+    if selection.sport == importer.SportNBA {
+        teams, err = client.FetchNBATeams()
+    } else {
+        teams, err = client.FetchNFLTeams()
+    }
+    if err != nil {
+        return fmt.Errorf("failed to fetch teams: %v", err)
+    }
+
+    path := *output
+    if path == "" {
+        path = importer.TeamsFile(*dataDir, selection.sport, selection.year)
+    }
+    if *dryRun {
+        fmt.Printf("Fetched %d %s teams, dry run so %s was not written\n", len(teams), selection, path)
+        return nil
+    }
+
+    if err := importer.WriteJSON(path, teams); err != nil {
+        return err
+    }
+    fmt.Printf("Wrote %d %s teams to %s\n", len(teams), selection, path)
+    return nil
+}
+
+func fetchSchedule(args []string) error {
+    flags := newFlagSet("fetch schedule")
+    selection := addSeasonFlags(flags)
+    dataDir := addDataDirFlag(flags)
+    week := flags.Int("week", 0, "Fetch a single NFL week instead of the whole season")
+    output := flags.String("out", "", "File to write (default: <data-dir>/<sport>/schedules/<sport>_schedule_<season>_full.json)")
+    dryRun := flags.Bool("dry-run", false, "Fetch without writing the file")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+    if err := selection.validate(); err != nil {
+        return err
+    }
+    if *week < 0 || (*week > 0 && selection.sport != importer.SportNFL) {
+        return usagef("-week must be a positive NFL week")
+    }
+
+    client := espn.NewClient()
+    var games []models.Game
+    var err error
+    switch {
+    case selection.sport == importer.SportNBA:
+        games, err = client.FetchEntireNBASeason(selection.year)
+    case *week > 0:
+        games, err = client.FetchNFLSchedule(selection.year, *week)
+    default:
+        games, err = client.FetchEntireNFLSeason(selection.year)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to fetch schedule: %v", err)
+    }
+
+    path := *output
+    if path == "" {
+        path = importer.ScheduleFile(*dataDir, selection.sport, selection.year, *week)
+    }
+    if *dryRun {
+        fmt.Printf("Fetched %d %s games, dry run so %s was not written\n", len(games), selection, path)
+        return nil
+    }
+
+    if err := importer.WriteJSON(path, games); err != nil {
+        return err
+    }
+    fmt.Printf("Wrote %d %s games to %s\n", len(games), selection, path)
+    return nil
+}
@@ -0,0 +1,154 @@
+// Import command for loading fetched teams and schedules into the database
+
+package main
+
+import (
+    "database/sql"
+    "fmt"
+
+    "gamescript/internal/database"
+    "gamescript/internal/importer"
+)
+
+
+func runImport(args []string) error {
+    return runAction("import", args, map[string]func([]string) error{
+        "teams":    importTeams,
+        "schedule": importSchedule,
+    }, []string{"teams", "schedule"})
+}
+
+func importTeams(args []string) error {
+    flags := newFlagSet("import teams")
+    selection := addSeasonFlags(flags)
+    dataDir := addDataDirFlag(flags)
+    input := flags.String("file", "", "File to import (default: <data-dir>/<sport>/teams/<sport>_teams_<season>.json)")
+    dryRun := flags.Bool("dry-run", false, "Import inside a transaction and roll it back")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+    if err := selection.validate(); err != nil {
+        return err
+    }
+
+    path := *input
+    if path == "" {
+        path = importer.TeamsFile(*dataDir, selection.sport, selection.year)
+    }
+    teams, err := importer.ReadTeams(path)
+    if err != nil {
+        return err
+    }
+
+    db, err := connect()
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    sportID, seasonID, err := selection.resolve(db)
+    if err != nil {
+        return err
+    }
+
+    var changed int
+    err = inTransaction(db, *dryRun, func(tx *sql.Tx) error {
+        changed, err = importer.UpsertTeams(tx, sportID, seasonID, teams)
+        return err
+    })
+    if err != nil {
+        return err
+    }
+
+    fmt.Printf("%s %d teams from %s into %s: %d inserted or updated, %d unchanged\n",
+        importVerb(*dryRun), len(teams), path, selection, changed, len(teams)-changed)
+    return nil
+}
+
+func importSchedule(args []string) error {
+    flags := newFlagSet("import schedule")
+    selection := addSeasonFlags(flags)
+    dataDir := addDataDirFlag(flags)
+    week := flags.Int("week", 0, "Import a single NFL week's file instead of the whole season")
+    input := flags.String("file", "", "File to import (default: <data-dir>/<sport>/schedules/<sport>_schedule_<season>_full.json)")
+    dryRun := flags.Bool("dry-run", false, "Import inside a transaction and roll it back")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+    if err := selection.validate(); err != nil {
+        return err
+    }
+    if *week < 0 || (*week > 0 && selection.sport != importer.SportNFL) {
+        return usagef("-week must be a positive NFL week")
+    }
+
+    path := *input
+    if path == "" {
+        path = importer.ScheduleFile(*dataDir, selection.sport, selection.year, *week)
+    }
+    games, err := importer.ReadGames(path)
+    if err != nil {
+        return err
+    }
+
+    db, err := connect()
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    _, seasonID, err := selection.resolve(db)
+    if err != nil {
+        return err
+    }
+
+    changed := 0
+    err = inTransaction(db, *dryRun, func(tx *sql.Tx) error {
+        for _, game := range games {
+            gameChanged, err := importer.UpsertGame(tx, seasonID, game)
+            if err != nil {
+                return fmt.Errorf("failed to import game %s: %v", game.ESPNID, err)
+            }
+            if gameChanged {
+                changed++
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return err
+    }
+
+    fmt.Printf("%s %d games from %s into %s: %d inserted or updated, %d unchanged\n",
+        importVerb(*dryRun), len(games), path, selection, changed, len(games)-changed)
+    return nil
+}
+
+// Runs fn in a transaction that is committed, or rolled back on error or for a dry run. Imports either
+// land completely or not at all.
+func inTransaction(db *database.DB, dryRun bool, fn func(tx *sql.Tx) error) error {
+    tx, err := db.Conn.Begin()
+    if err != nil {
+        return fmt.Errorf("failed to start transaction: %v", err)
+    }
+    defer tx.Rollback()
+
+    if err := fn(tx); err != nil {
+        return err
+    }
+
+    if dryRun {
+        return nil
+    }
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit: %v", err)
+    }
+    return nil
+}
+
+func importVerb(dryRun bool) string {
+    if dryRun {
+        return "Dry run, rolled back importing"
+    }
+    return "Imported"
+}
@@ -0,0 +1,200 @@
+// Command-line entry point for fetching, importing, inspecting, and migrating GameScript data
+//
+// Exit codes: 0 on success, 1 when a command fails, 2 for invalid commands, flags, or arguments.
+
+package main
+
+import (
+    "database/sql"
+    "errors"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/joho/godotenv"
+
+    "gamescript/internal/database"
+    "gamescript/internal/importer"
+)
+
+
+const (
+    exitFailure = 1
+    exitUsage   = 2
+)
+
+type command struct {
+    summary string
+    run     func(args []string) error
+}
+
+var commands = map[string]command{
+    "fetch":     {"Fetch teams or schedules from ESPN into JSON files", runFetch},
+    "import":    {"Import teams or schedules from JSON files into the database", runImport},
+    "sync":      {"Fetch a schedule from ESPN, update games, and refresh ratings and leagues", runSync},
+    "standings": {"Print standings, seeds, and draft order for a season or scenario", runStandings},
+    "simulate":  {"Simulate the rest of a season and print playoff odds", runSimulate},
+    "migrate":   {"Apply, roll back, or list database migrations", runMigrate},
+}
+
+// Command names in the order usage lists them
+var commandOrder = []string{"fetch", "import", "sync", "standings", "simulate", "migrate"}
+
+// Invalid command line, reported with exit code 2
+type usageError struct {
+    message string
+}
+
+func (e usageError) Error() string {
+    return e.message
+}
+
+func usagef(format string, args ...interface{}) error {
+    return usageError{message: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+    log.SetFlags(0)
+
+    if len(os.Args) < 2 {
+        usage()
+        os.Exit(exitUsage)
+    }
+
+    name := os.Args[1]
+    if name == "help" || name == "-h" || name == "--help" {
+        usage()
+        return
+    }
+
+    cmd, exists := commands[name]
+    if !exists {
+        fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+        usage()
+        os.Exit(exitUsage)
+    }
+
+    err := cmd.run(os.Args[2:])
+    if err == nil || errors.Is(err, flag.ErrHelp) {
+        return
+    }
+
+    fmt.Fprintf(os.Stderr, "gamescript %s: %v\n", name, err)
+    var usageErr usageError
+    if errors.As(err, &usageErr) {
+        os.Exit(exitUsage)
+    }
+    os.Exit(exitFailure)
+}
+
+func usage() {
+    fmt.Fprintln(os.Stderr, "Usage: gamescript <command> [arguments]")
+    fmt.Fprintln(os.Stderr)
+    fmt.Fprintln(os.Stderr, "Commands:")
+    for _, name := range commandOrder {
+        fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+    }
+    fmt.Fprintln(os.Stderr)
+    fmt.Fprintln(os.Stderr, "Run gamescript <command> -h for a command's arguments.")
+}
+
+// Runs the action named by the first argument, like the "up" in "gamescript migrate up"
+func runAction(command string, args []string, actions map[string]func(args []string) error, order []string) error {
+    if len(args) < 1 || strings.HasPrefix(args[0], "-") {
+        return usagef("usage: gamescript %s %s [flags]", command, strings.Join(order, "|"))
+    }
+
+    action, exists := actions[args[0]]
+    if !exists {
+        return usagef("unknown %s action %q, expected %s", command, args[0], strings.Join(order, ", "))
+    }
+    return action(args[1:])
+}
+
+// Parses flags, reporting bad flags and stray arguments as usage errors
+func parseFlags(flags *flag.FlagSet, args []string) error {
+    if err := flags.Parse(args); err != nil {
+        if errors.Is(err, flag.ErrHelp) {
+            return err
+        }
+        return usageError{message: err.Error()}
+    }
+    if flags.NArg() > 0 {
+        return usagef("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+    }
+    return nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+    return flag.NewFlagSet("gamescript "+name, flag.ContinueOnError)
+}
+
+// Sport and season selection shared by commands that work on one season
+type seasonFlags struct {
+    sport string
+    year  int
+}
+
+func addSeasonFlags(flags *flag.FlagSet) *seasonFlags {
+    selection := &seasonFlags{}
+    flags.StringVar(&selection.sport, "sport", importer.SportNFL, "Sport (nfl or nba)")
+    flags.IntVar(&selection.year, "season", 0, "Season start year (default: the current season)")
+    return selection
+}
+
+// Checks the sport and fills in the current season when none was given
+func (selection *seasonFlags) validate() error {
+    selection.sport = strings.ToLower(selection.sport)
+    if selection.sport != importer.SportNFL && selection.sport != importer.SportNBA {
+        return usagef("unknown sport %q, expected nfl or nba", selection.sport)
+    }
+    if selection.year == 0 {
+        selection.year = importer.CurrentSeasonYear(selection.sport, time.Now())
+    }
+    if selection.year < 1900 {
+        return usagef("invalid season %d, expected a start year like 2025", selection.year)
+    }
+    return nil
+}
+
+// Looks up the sport and season IDs for the selected season
+func (selection *seasonFlags) resolve(db *database.DB) (int, int, error) {
+    var sportID, seasonID int
+    err := db.Conn.QueryRow(`
+        SELECT sports.id, seasons.id
+        FROM seasons
+        JOIN sports ON sports.id = seasons.sport_id
+        WHERE LOWER(sports.short_name) = $1 AND seasons.start_year = $2
+    `, selection.sport, selection.year).Scan(&sportID, &seasonID)
+    if err == sql.ErrNoRows {
+        return 0, 0, fmt.Errorf("no %s season starting in %d, run gamescript migrate up to seed seasons",
+            strings.ToUpper(selection.sport), selection.year)
+    }
+    if err != nil {
+        return 0, 0, fmt.Errorf("failed to look up season: %v", err)
+    }
+    return sportID, seasonID, nil
+}
+
+func (selection *seasonFlags) String() string {
+    if selection.sport == importer.SportNBA {
+        return fmt.Sprintf("NBA %d-%d", selection.year, selection.year+1)
+    }
+    return fmt.Sprintf("NFL %d", selection.year)
+}
+
+// Connects using the same .env and environment variables as the server
+func connect() (*database.DB, error) {
+    if err := godotenv.Load(); err != nil {
+        log.Println("No .env file found")
+    }
+
+    db, err := database.NewConnection()
+    if err != nil {
+        return nil, fmt.Errorf("failed to connect to database: %v", err)
+    }
+    return db, nil
+}
@@ -0,0 +1,150 @@
+// Migrate command for applying and rolling back schema migrations
+
+package main
+
+import (
+    "fmt"
+    "os"
+    "text/tabwriter"
+
+    "gamescript/internal/migrations"
+)
+
+
+func runMigrate(args []string) error {
+    return runAction("migrate", args, map[string]func([]string) error{
+        "up":     migrateUp,
+        "down":   migrateDown,
+        "status": migrateStatus,
+    }, []string{"up", "down", "status"})
+}
+
+func migrateUp(args []string) error {
+    flags := newFlagSet("migrate up")
+    dryRun := flags.Bool("dry-run", false, "List pending migrations without applying them")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+
+    db, err := connect()
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    if *dryRun {
+        pending, err := migrations.Pending(db)
+        if err != nil {
+            return err
+        }
+        for _, migration := range pending {
+            fmt.Printf("Would apply %04d_%s\n", migration.Version, migration.Name)
+        }
+        if len(pending) == 0 {
+            fmt.Println("No pending migrations")
+        }
+        return nil
+    }
+
+    applied, err := migrations.Up(db)
+    for _, migration := range applied {
+        fmt.Printf("Applied %04d_%s\n", migration.Version, migration.Name)
+    }
+    if err != nil {
+        return err
+    }
+
+    if len(applied) == 0 {
+        fmt.Println("No pending migrations")
+    }
+    return nil
+}
+
+func migrateDown(args []string) error {
+    flags := newFlagSet("migrate down")
+    steps := flags.Int("steps", 1, "Number of migrations to roll back")
+    dryRun := flags.Bool("dry-run", false, "List the migrations that would be rolled back without rolling them back")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+    if *steps < 1 {
+        return usagef("-steps must be at least 1")
+    }
+
+    db, err := connect()
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    if *dryRun {
+        statuses, err := migrations.GetStatus(db)
+        if err != nil {
+            return err
+        }
+        count := 0
+        for i := len(statuses) - 1; i >= 0 && count < *steps; i-- {
+            if statuses[i].Applied && !statuses[i].Unknown {
+                fmt.Printf("Would roll back %04d_%s\n", statuses[i].Version, statuses[i].Name)
+                count++
+            }
+        }
+        if count == 0 {
+            fmt.Println("No applied migrations to roll back")
+        }
+        return nil
+    }
+
+    rolledBack, err := migrations.Down(db, *steps)
+    for _, migration := range rolledBack {
+        fmt.Printf("Rolled back %04d_%s\n", migration.Version, migration.Name)
+    }
+    if err != nil {
+        return err
+    }
+
+    if len(rolledBack) == 0 {
+        fmt.Println("No applied migrations to roll back")
+    }
+    return nil
+}
+
+func migrateStatus(args []string) error {
+    flags := newFlagSet("migrate status")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+
+    db, err := connect()
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    statuses, err := migrations.GetStatus(db)
+    if err != nil {
+        return err
+    }
+
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(writer, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+    pending := 0
+    for _, status := range statuses {
+        state, appliedAt := "pending", ""
+        if status.Applied {
+            state = "applied"
+            appliedAt = status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+        }
+        if status.Unknown {
+            state = "applied (not in this build)"
+        }
+        if !status.Applied {
+            pending++
+        }
+        fmt.Fprintf(writer, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+    }
+    writer.Flush()
+
+    fmt.Printf("\n%d pending\n", pending)
+    return nil
+}
@@ -0,0 +1,109 @@
+// Simulate command for estimating playoff odds from the rest of a season
+
+package main
+
+import (
+    "fmt"
+    "math/rand"
+    "os"
+    "text/tabwriter"
+    "time"
+
+    "gamescript/internal/importer"
+    "gamescript/internal/simulation"
+    "gamescript/internal/standings"
+)
+
+
+func runSimulate(args []string) error {
+    flags := newFlagSet("simulate")
+    selection := addSeasonFlags(flags)
+    scenarioID := flags.Int("scenario", 0, "Scenario whose picks count as decided (its sport and season replace -sport and -season)")
+    iterations := flags.Int("iterations", 1000, "Number of seasons to simulate")
+    seed := flags.Int64("seed", 0, "Random seed for reproducible runs (default: random)")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+    if err := selection.validate(); err != nil {
+        return err
+    }
+    if *iterations < 1 {
+        return usagef("-iterations must be at least 1")
+    }
+    if *scenarioID < 0 {
+        return usagef("invalid -scenario %d", *scenarioID)
+    }
+    if *seed == 0 {
+        *seed = time.Now().UnixNano()
+    }
+
+    db, err := connect()
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    seasonID, err := resolveScenarioSeason(db, selection, *scenarioID)
+    if err != nil {
+        return err
+    }
+
+    games, err := simulation.LoadGames(db, seasonID)
+    if err != nil {
+        return err
+    }
+
+    rng := rand.New(rand.NewSource(*seed))
+    decided := make(map[int]bool)
+    var odds []simulation.TeamOdds
+    if selection.sport == importer.SportNBA {
+        teams, results, err := standings.LoadNBAResults(db, *scenarioID, seasonID)
+        if err != nil {
+            return err
+        }
+        for _, result := range results {
+            decided[result.GameID] = true
+        }
+        odds = simulation.SimulateNBA(teams, results, simulation.Remaining(games, decided), *iterations, rng)
+    } else {
+        teams, results, err := standings.LoadNFLResults(db, *scenarioID, seasonID)
+        if err != nil {
+            return err
+        }
+        for _, result := range results {
+            decided[result.GameID] = true
+        }
+        odds = simulation.SimulateNFL(teams, results, simulation.Remaining(games, decided), *iterations, rng)
+    }
+
+    fmt.Printf("%s playoff odds from %d simulations of %d remaining games (seed %d)\n",
+        selection, *iterations, len(games)-len(decided), *seed)
+    printOdds(odds, selection.sport == importer.SportNBA)
+    return nil
+}
+
+func printOdds(odds []simulation.TeamOdds, showPlayIn bool) {
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+    conference := ""
+    for _, teamOdds := range odds {
+        if teamOdds.Conference != conference {
+            conference = teamOdds.Conference
+            if showPlayIn {
+                fmt.Fprintf(writer, "\n%s\tWINS\tPLAYOFFS\tPLAY-IN\tDIVISION\tTOP SEED\t\n", conference)
+            } else {
+                fmt.Fprintf(writer, "\n%s\tWINS\tPLAYOFFS\tDIVISION\tTOP SEED\t\n", conference)
+            }
+        }
+
+        fmt.Fprintf(writer, "%s %s\t%.1f\t%s\t", teamOdds.TeamAbbr, teamOdds.TeamName, teamOdds.AverageWins, formatShare(teamOdds.Playoffs))
+        if showPlayIn {
+            fmt.Fprintf(writer, "%s\t", formatShare(teamOdds.PlayIn))
+        }
+        fmt.Fprintf(writer, "%s\t%s\t\n", formatShare(teamOdds.DivisionTitle), formatShare(teamOdds.TopSeed))
+    }
+    writer.Flush()
+}
+
+func formatShare(share float64) string {
+    return fmt.Sprintf("%.1f%%", share*100)
+}
@@ -0,0 +1,349 @@
+// Standings command for inspecting a season's or scenario's standings, seeds, tiebreakers, and draft order
+// without the server
+
+package main
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "sort"
+    "strings"
+    "text/tabwriter"
+
+    "gamescript/internal/database"
+    "gamescript/internal/handlers"
+    "gamescript/internal/importer"
+    "gamescript/internal/models"
+    "gamescript/internal/standings"
+)
+
+
+// Where standings load teams and games from
+const (
+    sourceDatabase = "db"
+    sourceFiles    = "files"
+)
+
+const (
+    formatText = "text"
+    formatJSON = "json"
+)
+
+// Standings along with the inputs they came from, printed as is by -format json
+type standingsReport struct {
+    Sport          string                         `json:"sport"`
+    Season         int                            `json:"season"`
+    Source         string                         `json:"source"`
+    ScenarioID     int                            `json:"scenario_id,omitempty"`
+    PicksFile      string                         `json:"picks_file,omitempty"`
+    UnmatchedPicks []string                       `json:"unmatched_picks,omitempty"`
+    Standings      handlers.StandingsResponse     `json:"standings"`
+    Tiebreakers    []standings.TiebreakerDecision `json:"tiebreakers"`
+
+    nfl *standings.NFLStandings
+    nba *standings.NBAStandings
+}
+
+func runStandings(args []string) error {
+    flags := newFlagSet("standings")
+    selection := addSeasonFlags(flags)
+    source := flags.String("source", sourceDatabase, "Where to load teams and games from (db or files)")
+    dataDir := addDataDirFlag(flags)
+    teamsFile := flags.String("teams-file", "", "Teams file for -source files (default: <data-dir>/<sport>/teams/<sport>_teams_<season>.json)")
+    scheduleFile := flags.String("schedule-file", "", "Schedule file for -source files (default: <data-dir>/<sport>/schedules/<sport>_schedule_<season>_full.json)")
+    scenarioID := flags.Int("scenario", 0, "Scenario whose picks to apply with -source db (its sport and season replace -sport and -season)")
+    picksFile := flags.String("picks", "", "Exported scenario whose picks to apply with -source files (its sport and season replace -sport and -season)")
+    format := flags.String("format", formatText, "Output format (text or json)")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+    if err := selection.validate(); err != nil {
+        return err
+    }
+    if *scenarioID < 0 {
+        return usagef("invalid -scenario %d", *scenarioID)
+    }
+    if *format != formatText && *format != formatJSON {
+        return usagef("unknown format %q, expected text or json", *format)
+    }
+
+    report := &standingsReport{Source: *source}
+    switch *source {
+    case sourceDatabase:
+        if *picksFile != "" || *teamsFile != "" || *scheduleFile != "" {
+            return usagef("-picks, -teams-file, and -schedule-file need -source files")
+        }
+        if err := loadDatabaseStandings(report, selection, *scenarioID); err != nil {
+            return err
+        }
+    case sourceFiles:
+        if *scenarioID != 0 {
+            return usagef("-scenario needs -source db, export the scenario and pass it with -picks instead")
+        }
+        if err := loadFileStandings(report, selection, *dataDir, *teamsFile, *scheduleFile, *picksFile); err != nil {
+            return err
+        }
+    default:
+        return usagef("unknown source %q, expected db or files", *source)
+    }
+
+    if *format == formatJSON {
+        encoder := json.NewEncoder(os.Stdout)
+        encoder.SetIndent("", "  ")
+        return encoder.Encode(report)
+    }
+
+    for _, pick := range report.UnmatchedPicks {
+        fmt.Fprintf(os.Stderr, "Skipped pick for %s\n", pick)
+    }
+    title := fmt.Sprintf("%s standings", selection)
+    if report.ScenarioID > 0 {
+        title += fmt.Sprintf(" (scenario %d)", report.ScenarioID)
+    }
+    if report.PicksFile != "" {
+        title += fmt.Sprintf(" (picks from %s)", report.PicksFile)
+    }
+    if report.nba != nil {
+        printNBAStandings(os.Stdout, title, report.nba)
+    } else {
+        printNFLStandings(os.Stdout, title, report.nfl)
+    }
+    printTiebreakers(os.Stdout, report.Tiebreakers)
+    return nil
+}
+
+func loadDatabaseStandings(report *standingsReport, selection *seasonFlags, scenarioID int) error {
+    db, err := connect()
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    seasonID, err := resolveScenarioSeason(db, selection, scenarioID)
+    if err != nil {
+        return err
+    }
+    report.ScenarioID = scenarioID
+
+    if selection.sport == importer.SportNBA {
+        teams, games, err := standings.LoadNBAResults(db, scenarioID, seasonID)
+        if err != nil {
+            return err
+        }
+        report.setNBA(selection, teams, games)
+        return nil
+    }
+
+    teams, games, err := standings.LoadNFLResults(db, scenarioID, seasonID)
+    if err != nil {
+        return err
+    }
+    report.setNFL(selection, teams, games)
+    return nil
+}
+
+// Loads standings from data files, with a scenario document's picks applied when one is given
+func loadFileStandings(report *standingsReport, selection *seasonFlags, dataDir string, teamsFile string, scheduleFile string, picksFile string) error {
+    var document *models.ScenarioDocument
+    if picksFile != "" {
+        var err error
+        document, err = importer.ReadScenarioDocument(picksFile)
+        if err != nil {
+            return err
+        }
+        selection.sport = strings.ToLower(document.Sport)
+        selection.year = document.Season.StartYear
+        if selection.sport != importer.SportNFL && selection.sport != importer.SportNBA {
+            return fmt.Errorf("%s is for an unsupported sport (%s)", picksFile, document.Sport)
+        }
+        report.PicksFile = picksFile
+    }
+
+    if teamsFile == "" {
+        teamsFile = importer.TeamsFile(dataDir, selection.sport, selection.year)
+    }
+    if scheduleFile == "" {
+        scheduleFile = importer.ScheduleFile(dataDir, selection.sport, selection.year, 0)
+    }
+    season, err := importer.LoadFileSeason(teamsFile, scheduleFile)
+    if err != nil {
+        return err
+    }
+    if document != nil {
+        report.UnmatchedPicks, err = season.ApplyPicks(document)
+        if err != nil {
+            return fmt.Errorf("failed to apply picks from %s: %v", picksFile, err)
+        }
+    }
+
+    if selection.sport == importer.SportNBA {
+        teams, games := season.NBAResults()
+        report.setNBA(selection, teams, games)
+        return nil
+    }
+
+    teams, games := season.NFLResults()
+    report.setNFL(selection, teams, games)
+    return nil
+}
+
+func (report *standingsReport) setNFL(selection *seasonFlags, teams []standings.NFLTeamRecord, games []standings.NFLGameResult) {
+    report.Sport, report.Season = selection.sport, selection.year
+    report.nfl, report.Tiebreakers = standings.TraceNFLStandings(teams, games)
+    formatted := handlers.FormatNFLStandings(report.nfl)
+    report.Standings.NFL = &formatted
+}
+
+func (report *standingsReport) setNBA(selection *seasonFlags, teams []standings.NBATeamRecord, games []standings.NBAGameResult) {
+    report.Sport, report.Season = selection.sport, selection.year
+    report.nba, report.Tiebreakers = standings.TraceNBAStandings(teams, games)
+    formatted := handlers.FormatNBAStandings(report.nba)
+    report.Standings.NBA = &formatted
+}
+
+// Resolves the season to load, taking it from the scenario when one is given
+func resolveScenarioSeason(db *database.DB, selection *seasonFlags, scenarioID int) (int, error) {
+    if scenarioID == 0 {
+        _, seasonID, err := selection.resolve(db)
+        return seasonID, err
+    }
+
+    var seasonID int
+    err := db.Conn.QueryRow(`
+        SELECT scenarios.season_id, LOWER(sports.short_name), seasons.start_year
+        FROM scenarios
+        JOIN seasons ON seasons.id = scenarios.season_id
+        JOIN sports ON sports.id = scenarios.sport_id
+        WHERE scenarios.id = $1
+    `, scenarioID).Scan(&seasonID, &selection.sport, &selection.year)
+    if err == sql.ErrNoRows {
+        return 0, fmt.Errorf("scenario %d not found", scenarioID)
+    }
+    if err != nil {
+        return 0, fmt.Errorf("failed to look up scenario: %v", err)
+    }
+    if selection.sport != importer.SportNFL && selection.sport != importer.SportNBA {
+        return 0, fmt.Errorf("scenario %d is for an unsupported sport (%s)", scenarioID, strings.ToUpper(selection.sport))
+    }
+    return seasonID, nil
+}
+
+func sortedDivisionNames[T any](divisions map[string]T) []string {
+    names := make([]string, 0, len(divisions))
+    for name := range divisions {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+func formatNFLRecord(wins int, losses int, ties int) string {
+    if ties > 0 {
+        return fmt.Sprintf("%d-%d-%d", wins, losses, ties)
+    }
+    return fmt.Sprintf("%d-%d", wins, losses)
+}
+
+func printNFLStandings(out io.Writer, title string, season *standings.NFLStandings) {
+    fmt.Fprintf(out, "%s\n", title)
+
+    for _, conference := range []struct {
+        name      string
+        standings standings.NFLConferenceStandings
+    }{{"AFC", season.AFC}, {"NFC", season.NFC}} {
+        writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+        for _, division := range sortedDivisionNames(conference.standings.Divisions) {
+            fmt.Fprintf(writer, "\n%s\tRECORD\tPCT\tDIV\tCONF\tPF\tPA\tGB\n", division)
+            for _, team := range conference.standings.Divisions[division] {
+                fmt.Fprintf(writer, "%s %s\t%s\t%.3f\t%s\t%s\t%d\t%d\t%.1f\n",
+                    team.TeamAbbr, team.TeamName,
+                    formatNFLRecord(team.Wins, team.Losses, team.Ties), team.WinPct,
+                    formatNFLRecord(team.DivisionWins, team.DivisionLosses, team.DivisionTies),
+                    formatNFLRecord(team.ConferenceWins, team.ConferenceLosses, team.ConferenceTies),
+                    team.PointsFor, team.PointsAgainst, team.DivisionGamesBack)
+            }
+        }
+
+        fmt.Fprintf(writer, "\n%s seeds\tRECORD\tSOV\tSOS\t\n", conference.name)
+        for _, seed := range conference.standings.PlayoffSeeds {
+            if seed.Seed > 7 {
+                break
+            }
+            marker := ""
+            if seed.IsDivisionWinner {
+                marker = "division winner"
+            }
+            fmt.Fprintf(writer, "%d. %s %s\t%s\t%.3f\t%.3f\t%s\n",
+                seed.Seed, seed.Team.TeamAbbr, seed.Team.TeamName,
+                formatNFLRecord(seed.Team.Wins, seed.Team.Losses, seed.Team.Ties),
+                seed.Team.StrengthOfVictory, seed.Team.StrengthOfSchedule, marker)
+        }
+        writer.Flush()
+    }
+
+    writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+    fmt.Fprintf(writer, "\nDraft order\tRECORD\tSOS\n")
+    for _, pick := range season.DraftOrder {
+        fmt.Fprintf(writer, "%d. %s %s\t%s\t%.3f\n", pick.Pick, pick.Team.TeamAbbr, pick.Team.TeamName,
+            formatNFLRecord(pick.Team.Wins, pick.Team.Losses, pick.Team.Ties), pick.Team.StrengthOfSchedule)
+    }
+    writer.Flush()
+}
+
+func printNBAStandings(out io.Writer, title string, season *standings.NBAStandings) {
+    fmt.Fprintf(out, "%s\n", title)
+
+    for _, conference := range []struct {
+        name      string
+        standings standings.NBAConferenceStandings
+    }{{"Eastern", season.Eastern}, {"Western", season.Western}} {
+        writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+        for _, division := range sortedDivisionNames(conference.standings.Divisions) {
+            fmt.Fprintf(writer, "\n%s\tRECORD\tPCT\tDIV\tCONF\tGB\n", division)
+            for _, team := range conference.standings.Divisions[division] {
+                fmt.Fprintf(writer, "%s %s\t%d-%d\t%.3f\t%d-%d\t%d-%d\t%.1f\n",
+                    team.TeamAbbr, team.TeamName, team.Wins, team.Losses, team.WinPct,
+                    team.DivisionWins, team.DivisionLosses, team.ConferenceWins, team.ConferenceLosses,
+                    team.DivisionGamesBack)
+            }
+        }
+
+        fmt.Fprintf(writer, "\n%s seeds\tRECORD\tGB\t\n", conference.name)
+        for _, seed := range conference.standings.PlayoffSeeds {
+            if seed.Seed > 10 {
+                break
+            }
+            marker := ""
+            if seed.Seed > 6 {
+                marker = "play-in"
+            }
+            fmt.Fprintf(writer, "%d. %s %s\t%d-%d\t%.1f\t%s\n", seed.Seed, seed.Team.TeamAbbr, seed.Team.TeamName,
+                seed.Team.Wins, seed.Team.Losses, seed.Team.ConferenceGamesBack, marker)
+        }
+        writer.Flush()
+    }
+
+    writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+    fmt.Fprintf(writer, "\nDraft order\tRECORD\n")
+    for _, pick := range season.DraftOrder {
+        fmt.Fprintf(writer, "%d. %s %s\t%d-%d\n", pick.Pick, pick.Team.TeamAbbr, pick.Team.TeamName, pick.Team.Wins, pick.Team.Losses)
+    }
+    writer.Flush()
+}
+
+// Lists the step that broke each tie, grouped by where it was broken
+func printTiebreakers(out io.Writer, decisions []standings.TiebreakerDecision) {
+    writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+    context := ""
+    for _, decision := range decisions {
+        if decision.Context != context {
+            context = decision.Context
+            fmt.Fprintf(writer, "\n%s tiebreakers\tAHEAD\tSTEP\n", context)
+        }
+        fmt.Fprintf(writer, "%s\t%s\t%s\n", strings.Join(decision.Teams, ", "), decision.Winner, decision.Step)
+    }
+    writer.Flush()
+}
@@ -0,0 +1,88 @@
+// Sync command for running the scheduler's daily update on demand
+
+package main
+
+import (
+    "database/sql"
+    "fmt"
+
+    "gamescript/internal/importer"
+    "gamescript/internal/leagues"
+    "gamescript/internal/models"
+    "gamescript/internal/ratings"
+    "gamescript/internal/services/espn"
+)
+
+
+// Fetches a season's schedule, upserts its games, then refreshes ratings and grades leagues like the scheduler.
+// A running server picks up the new results when its cached standings expire.
+func runSync(args []string) error {
+    flags := newFlagSet("sync")
+    selection := addSeasonFlags(flags)
+    dryRun := flags.Bool("dry-run", false, "Update games inside a transaction and roll it back, skipping ratings and leagues")
+    if err := parseFlags(flags, args); err != nil {
+        return err
+    }
+    if err := selection.validate(); err != nil {
+        return err
+    }
+
+    db, err := connect()
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    _, seasonID, err := selection.resolve(db)
+    if err != nil {
+        return err
+    }
+
+    client := espn.NewClient()
+    var games []models.Game
+    if selection.sport == importer.SportNBA {
+        games, err = client.FetchEntireNBASeason(selection.year)
+    } else {
+        games, err = client.FetchEntireNFLSeason(selection.year)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to fetch schedule: %v", err)
+    }
+
+    changed := 0
+    err = inTransaction(db, *dryRun, func(tx *sql.Tx) error {
+        for _, game := range games {
+            gameChanged, err := importer.UpsertGame(tx, seasonID, game)
+            if err != nil {
+                return fmt.Errorf("failed to update game %s: %v", game.ESPNID, err)
+            }
+            if gameChanged {
+                changed++
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return err
+    }
+
+    if *dryRun {
+        fmt.Printf("Dry run, rolled back %s sync: %d games would be inserted or updated, %d unchanged\n",
+            selection, changed, len(games)-changed)
+        return nil
+    }
+    fmt.Printf("Synced %s: %d games inserted or updated, %d unchanged\n", selection, changed, len(games)-changed)
+
+    if err := ratings.RefreshSeason(db, seasonID); err != nil {
+        return fmt.Errorf("failed to refresh ratings: %v", err)
+    }
+    fmt.Println("Ratings refreshed")
+
+    graded, err := leagues.GradeSeason(db, seasonID)
+    if err != nil {
+        return fmt.Errorf("failed to grade leagues: %v", err)
+    }
+    fmt.Printf("League entries graded: %d\n", graded)
+
+    return nil
+}
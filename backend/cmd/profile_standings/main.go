@@ -0,0 +1,141 @@
+// Profiles full-season standings computation on a synthetic or stored season
+
+package main
+
+import (
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "runtime"
+    "runtime/pprof"
+    "strings"
+    "time"
+
+    "github.com/joho/godotenv"
+
+    "gamescript/internal/database"
+    "gamescript/internal/standings"
+)
+
+
+func main() {
+    sport := flag.String("sport", "nfl", "Sport to compute standings for (nfl or nba)")
+    pattern := flag.String("pattern", standings.PatternRandom, "Synthetic season pattern ("+strings.Join(standings.SyntheticPatterns(), ", ")+")")
+    seed := flag.Int64("seed", 1, "Random seed for synthetic seasons")
+    scenarioID := flag.Int("scenario", 0, "Scenario to load picks from instead of generating a season (requires -season)")
+    seasonID := flag.Int("season", 0, "Season to load games from instead of generating a season")
+    iterations := flag.Int("iterations", 100, "Number of standings computations to run")
+    cpuProfile := flag.String("cpuprofile", "", "Write a CPU profile to this file")
+    memProfile := flag.String("memprofile", "", "Write a heap profile to this file")
+    flag.Parse()
+
+    if *iterations < 1 {
+        log.Fatal("-iterations must be at least 1")
+    }
+
+    build, source, err := loadSeason(*sport, *pattern, *seed, *scenarioID, *seasonID)
+    if err != nil {
+        log.Fatal(err)
+    }
+
+    if *cpuProfile != "" {
+        file, err := os.Create(*cpuProfile)
+        if err != nil {
+            log.Fatal("Failed to create CPU profile:", err)
+        }
+        defer file.Close()
+        if err := pprof.StartCPUProfile(file); err != nil {
+            log.Fatal("Failed to start CPU profile:", err)
+        }
+    }
+
+    var before, after runtime.MemStats
+    runtime.GC()
+    runtime.ReadMemStats(&before)
+    start := time.Now()
+
+    for i := 0; i < *iterations; i++ {
+        build()
+    }
+
+    elapsed := time.Since(start)
+    runtime.ReadMemStats(&after)
+
+    if *cpuProfile != "" {
+        pprof.StopCPUProfile()
+    }
+
+    fmt.Printf("%s standings from %s\n", strings.ToUpper(*sport), source)
+    fmt.Printf("%d iterations in %v\n", *iterations, elapsed)
+    fmt.Printf("%v per computation\n", elapsed/time.Duration(*iterations))
+    fmt.Printf("%d bytes and %d allocations per computation\n",
+        (after.TotalAlloc-before.TotalAlloc)/uint64(*iterations),
+        (after.Mallocs-before.Mallocs)/uint64(*iterations))
+
+    if *memProfile != "" {
+        file, err := os.Create(*memProfile)
+        if err != nil {
+            log.Fatal("Failed to create heap profile:", err)
+        }
+        defer file.Close()
+        runtime.GC()
+        if err := pprof.WriteHeapProfile(file); err != nil {
+            log.Fatal("Failed to write heap profile:", err)
+        }
+    }
+}
+
+// Loads a season once and returns a function that computes its standings, so only the computation is profiled
+func loadSeason(sport string, pattern string, seed int64, scenarioID int, seasonID int) (func(), string, error) {
+    if scenarioID != 0 && seasonID == 0 {
+        return nil, "", fmt.Errorf("-scenario requires -season")
+    }
+
+    var db *database.DB
+    source := fmt.Sprintf("synthetic %s season (seed %d)", pattern, seed)
+    if seasonID != 0 {
+        if err := godotenv.Load(); err != nil {
+            log.Println("No .env file found")
+        }
+
+        var err error
+        db, err = database.NewConnection()
+        if err != nil {
+            return nil, "", fmt.Errorf("failed to connect to database: %w", err)
+        }
+        defer db.Close()
+        source = fmt.Sprintf("season %d, scenario %d", seasonID, scenarioID)
+    }
+
+    switch sport {
+    case "nfl":
+        var teams []standings.NFLTeamRecord
+        var games []standings.NFLGameResult
+        var err error
+        if db != nil {
+            teams, games, err = standings.LoadNFLResults(db, scenarioID, seasonID)
+        } else {
+            teams, games, err = standings.GenerateNFLSeason(pattern, seed)
+        }
+        if err != nil {
+            return nil, "", err
+        }
+        return func() { standings.BuildNFLStandings(teams, games) }, source, nil
+    case "nba":
+        var teams []standings.NBATeamRecord
+        var games []standings.NBAGameResult
+        var err error
+        if db != nil {
+            teams, games, err = standings.LoadNBAResults(db, scenarioID, seasonID)
+        } else {
+            teams, games, err = standings.GenerateNBASeason(pattern, seed)
+        }
+        if err != nil {
+            return nil, "", err
+        }
+        return func() { standings.BuildNBAStandings(teams, games) }, source, nil
+    default:
+        return nil, "", fmt.Errorf("unknown sport %q, expected nfl or nba", sport)
+    }
+}
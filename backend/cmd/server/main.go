@@ -15,9 +15,11 @@ import (
     "github.com/gofiber/fiber/v2/middleware/recover"
     "github.com/joho/godotenv"
 
+    "gamescript/internal/apperror"
     "gamescript/internal/database"
     "gamescript/internal/handlers"
     "gamescript/internal/middleware"
+    "gamescript/internal/migrations"
     "gamescript/internal/scheduler"
 )
 
@@ -35,6 +37,20 @@ func main() {
     }
     defer db.Close()
 
+    // Refuse to start against an outdated schema when REQUIRE_MIGRATIONS is set
+    if os.Getenv("REQUIRE_MIGRATIONS") == "true" {
+        pending, err := migrations.Pending(db)
+        if err != nil {
+            log.Fatal("Failed to check migrations:", err)
+        }
+        if len(pending) > 0 {
+            for _, migration := range pending {
+                log.Printf("Pending migration %04d_%s", migration.Version, migration.Name)
+            }
+            log.Fatalf("%d pending migrations, run `gamescript migrate up` before starting the server", len(pending))
+        }
+    }
+
     // Start background scheduler
     scheduler := scheduler.NewScheduler(db)
     scheduler.Start()
@@ -42,8 +58,9 @@ func main() {
 
     // Create Fiber app
     app := fiber.New(fiber.Config{
-        AppName:   "GameScript API",
-        BodyLimit: 4 * 1024 * 1024, // 4 MB max body size
+        AppName:      "GameScript API",
+        BodyLimit:    4 * 1024 * 1024, // 4 MB max body size
+        ErrorHandler: apperror.Handler, // Sends handler errors as problem+json
     })
 
     // Recovery middleware (must be first)
@@ -72,7 +89,7 @@ func main() {
 
     // Health check
     api.Get("/health", func(c *fiber.Ctx) error {
-        return c.JSON(fiber.Map{"status": "ok"})
+        return c.JSON(handlers.HealthResponse{Status: "ok"})
     })
     api.Head("/health", func(c *fiber.Ctx) error {
         return c.SendStatus(fiber.StatusOK)
@@ -1,6 +0,0 @@
--- Insert new sports into the sports table
-
-INSERT INTO sports(name, short_name) VALUES
-    ('National Football League', 'NFL'),
-    ('National Basketball Association', 'NBA'),
-    ('College Football', 'CFB');
\ No newline at end of file
@@ -0,0 +1,245 @@
+// Application errors with stable codes, sent to clients as RFC 7807 problem details
+
+package apperror
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+
+// Machine-readable error code; codes never change once clients can see them
+type Code string
+
+// Request problems
+const (
+	InvalidRequestBody  Code = "INVALID_REQUEST_BODY"
+	InvalidParameter    Code = "INVALID_PARAMETER"
+	ValidationFailed    Code = "VALIDATION_FAILED"
+	UnsupportedSport    Code = "UNSUPPORTED_SPORT"
+	UnsupportedDocument Code = "UNSUPPORTED_DOCUMENT"
+	DocumentMismatch    Code = "DOCUMENT_MISMATCH"
+	SeasonMismatch      Code = "SEASON_MISMATCH"
+	PayloadTooLarge     Code = "PAYLOAD_TOO_LARGE"
+	RateLimited         Code = "RATE_LIMITED"
+)
+
+// Authentication and authorization
+const (
+	AuthenticationRequired Code = "AUTHENTICATION_REQUIRED"
+	InvalidToken           Code = "INVALID_TOKEN"
+	SessionRequired        Code = "SESSION_REQUIRED"
+	InvalidCredentials     Code = "INVALID_CREDENTIALS"
+	AccountLocked          Code = "ACCOUNT_LOCKED"
+	Forbidden              Code = "FORBIDDEN"
+	NotLeagueMember        Code = "NOT_LEAGUE_MEMBER"
+	EmailInUse             Code = "EMAIL_IN_USE"
+	UsernameInUse          Code = "USERNAME_IN_USE"
+)
+
+// Missing resources
+const (
+	NotFound           Code = "NOT_FOUND"
+	UserNotFound       Code = "USER_NOT_FOUND"
+	SeasonNotFound     Code = "SEASON_NOT_FOUND"
+	TeamNotFound       Code = "TEAM_NOT_FOUND"
+	GameNotFound       Code = "GAME_NOT_FOUND"
+	ScenarioNotFound   Code = "SCENARIO_NOT_FOUND"
+	PickNotFound       Code = "PICK_NOT_FOUND"
+	SnapshotNotFound   Code = "SNAPSHOT_NOT_FOUND"
+	MemberNotFound     Code = "MEMBER_NOT_FOUND"
+	InviteNotFound     Code = "INVITE_NOT_FOUND"
+	LeagueNotFound     Code = "LEAGUE_NOT_FOUND"
+	MatchupNotFound    Code = "MATCHUP_NOT_FOUND"
+	PlayoffsNotEnabled Code = "PLAYOFFS_NOT_ENABLED"
+)
+
+// Requests that conflict with the current state
+const (
+	PickAlreadyExists       Code = "PICK_ALREADY_EXISTS"
+	PickConflict            Code = "PICK_CONFLICT"
+	PicksLocked             Code = "PICKS_LOCKED"
+	PickOverrideNotAllowed  Code = "PICK_OVERRIDE_NOT_ALLOWED"
+	TiebreakerLocked        Code = "TIEBREAKER_LOCKED"
+	RegularSeasonIncomplete Code = "REGULAR_SEASON_INCOMPLETE"
+	RoundIncomplete         Code = "ROUND_INCOMPLETE"
+	NoMoreRounds            Code = "NO_MORE_ROUNDS"
+	NothingToUndo           Code = "NOTHING_TO_UNDO"
+	NothingToRedo           Code = "NOTHING_TO_REDO"
+	ChangeNotRevertible     Code = "CHANGE_NOT_REVERTIBLE"
+	InviteExpired           Code = "INVITE_EXPIRED"
+	InviteUsedUp            Code = "INVITE_USED_UP"
+	LeagueOwnerNotRemovable Code = "LEAGUE_OWNER_NOT_REMOVABLE"
+	ImportMismatch          Code = "IMPORT_MISMATCH"
+)
+
+// Failures on our side; their causes are logged and never sent to clients
+const (
+	Internal Code = "INTERNAL_ERROR"
+)
+
+type definition struct {
+	status int
+	title  string
+}
+
+var definitions = map[Code]definition{
+	InvalidRequestBody:  {http.StatusBadRequest, "Invalid request body"},
+	InvalidParameter:    {http.StatusBadRequest, "Invalid parameter"},
+	ValidationFailed:    {http.StatusBadRequest, "Validation failed"},
+	UnsupportedSport:    {http.StatusBadRequest, "Unsupported sport"},
+	UnsupportedDocument: {http.StatusBadRequest, "Unsupported document"},
+	DocumentMismatch:    {http.StatusBadRequest, "Document doesn't match the season"},
+	SeasonMismatch:      {http.StatusBadRequest, "Seasons don't match"},
+	PayloadTooLarge:     {http.StatusRequestEntityTooLarge, "Request body too large"},
+	RateLimited:         {http.StatusTooManyRequests, "Too many requests"},
+
+	AuthenticationRequired: {http.StatusUnauthorized, "Authentication required"},
+	InvalidToken:           {http.StatusUnauthorized, "Invalid token"},
+	SessionRequired:        {http.StatusBadRequest, "Guest session required"},
+	InvalidCredentials:     {http.StatusUnauthorized, "Invalid credentials"},
+	AccountLocked:          {http.StatusLocked, "Account locked"},
+	Forbidden:              {http.StatusForbidden, "Forbidden"},
+	NotLeagueMember:        {http.StatusForbidden, "Not a league member"},
+	EmailInUse:             {http.StatusConflict, "Email already in use"},
+	UsernameInUse:          {http.StatusConflict, "Username already in use"},
+
+	NotFound:           {http.StatusNotFound, "Not found"},
+	UserNotFound:       {http.StatusNotFound, "User not found"},
+	SeasonNotFound:     {http.StatusNotFound, "Season not found"},
+	TeamNotFound:       {http.StatusNotFound, "Team not found"},
+	GameNotFound:       {http.StatusNotFound, "Game not found"},
+	ScenarioNotFound:   {http.StatusNotFound, "Scenario not found"},
+	PickNotFound:       {http.StatusNotFound, "Pick not found"},
+	SnapshotNotFound:   {http.StatusNotFound, "Snapshot not found"},
+	MemberNotFound:     {http.StatusNotFound, "Member not found"},
+	InviteNotFound:     {http.StatusNotFound, "Invite not found"},
+	LeagueNotFound:     {http.StatusNotFound, "League not found"},
+	MatchupNotFound:    {http.StatusNotFound, "Matchup not found"},
+	PlayoffsNotEnabled: {http.StatusNotFound, "Playoffs not enabled"},
+
+	PickAlreadyExists:       {http.StatusConflict, "Pick already exists"},
+	PickConflict:            {http.StatusConflict, "Pick changed by another editor"},
+	PicksLocked:             {http.StatusLocked, "Picks locked"},
+	PickOverrideNotAllowed:  {http.StatusUnprocessableEntity, "Pick override not allowed"},
+	TiebreakerLocked:        {http.StatusLocked, "Tiebreaker locked"},
+	RegularSeasonIncomplete: {http.StatusConflict, "Regular season incomplete"},
+	RoundIncomplete:         {http.StatusConflict, "Round incomplete"},
+	NoMoreRounds:            {http.StatusConflict, "No more rounds"},
+	NothingToUndo:           {http.StatusConflict, "Nothing to undo"},
+	NothingToRedo:           {http.StatusConflict, "Nothing to redo"},
+	ChangeNotRevertible:     {http.StatusConflict, "Change can't be reverted"},
+	InviteExpired:           {http.StatusGone, "Invite expired"},
+	InviteUsedUp:            {http.StatusGone, "Invite used up"},
+	LeagueOwnerNotRemovable: {http.StatusConflict, "League owner can't be removed"},
+	ImportMismatch:          {http.StatusUnprocessableEntity, "Import doesn't match the season"},
+
+	Internal: {http.StatusInternalServerError, "Internal server error"},
+}
+
+// Codes for statuses Fiber reports on its own, such as unknown routes or oversized bodies
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:            InvalidRequestBody,
+	http.StatusUnauthorized:          AuthenticationRequired,
+	http.StatusForbidden:             Forbidden,
+	http.StatusNotFound:              NotFound,
+	http.StatusRequestEntityTooLarge: PayloadTooLarge,
+	http.StatusTooManyRequests:       RateLimited,
+}
+
+// Every code clients can receive, so documentation lists them
+func (Code) EnumValues() []interface{} {
+	codes := make([]string, 0, len(definitions))
+	for code := range definitions {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+
+	values := make([]interface{}, len(codes))
+	for i, code := range codes {
+		values[i] = code
+	}
+	return values
+}
+
+// HTTP status sent with a code
+func (code Code) Status() int {
+	if definition, ok := definitions[code]; ok {
+		return definition.status
+	}
+	return http.StatusInternalServerError
+}
+
+// Short summary of a code that doesn't change between occurrences
+func (code Code) Title() string {
+	if definition, ok := definitions[code]; ok {
+		return definition.title
+	}
+	return definitions[Internal].title
+}
+
+// Problem type URI, e.g. urn:gamescript:problem:pick-already-exists
+func (code Code) Type() string {
+	return "urn:gamescript:problem:" + strings.ReplaceAll(strings.ToLower(string(code)), "_", "-")
+}
+
+// An error a handler returns for the central error handler to send
+type Error struct {
+	Code   Code
+	Detail string // Explanation of this occurrence, shown to clients
+
+	// Extra members sent alongside the standard ones, such as conflicting picks;
+	// a struct whose JSON fields become members of the problem
+	Extensions interface{}
+
+	// Underlying error, logged but never sent
+	Cause error
+}
+
+func New(code Code, detail string) *Error {
+	return &Error{Code: code, Detail: detail}
+}
+
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// Wraps an unexpected error; clients only see a generic detail
+func Wrap(err error) *Error {
+	return &Error{Code: Internal, Detail: "An unexpected error occurred", Cause: err}
+}
+
+// Adds extra problem members, see Extensions
+func (e *Error) With(extensions interface{}) *Error {
+	e.Extensions = extensions
+	return e
+}
+
+// Records the underlying error for the server log
+func (e *Error) WithCause(err error) *Error {
+	e.Cause = err
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Detail, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Detail)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func (e *Error) Status() int {
+	return e.Code.Status()
+}
+
+// Whether err is an application error with the given code
+func Is(err error, code Code) bool {
+	appErr, ok := As(err)
+	return ok && appErr.Code == code
+}
@@ -0,0 +1,115 @@
+// Central Fiber error handler that sends errors as problem+json
+
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+
+const ProblemContentType = "application/problem+json"
+
+// RFC 7807 problem details body. Routes whose errors carry extensions document a type that embeds Problem.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+	Code     Code   `json:"code"`
+
+	// Same as Detail, for clients written against the earlier {"error": ...} bodies
+	Error string `json:"error"`
+}
+
+// Media type problems are documented with
+func (Problem) MediaType() string {
+	return ProblemContentType
+}
+
+// Finds the application error in err's chain
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
+
+// Converts any error a handler returns: application errors keep their code, Fiber's own errors
+// get the code for their status, and anything else is an internal error
+func From(err error) *Error {
+	if appErr, ok := As(err); ok {
+		return appErr
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		if code, ok := statusCodes[fiberErr.Code]; ok {
+			return New(code, fiberErr.Message)
+		}
+	}
+	return Wrap(err)
+}
+
+// Problem body for an error on a request path
+func (e *Error) Problem(instance string) Problem {
+	return Problem{
+		Type:     e.Code.Type(),
+		Title:    e.Code.Title(),
+		Status:   e.Status(),
+		Detail:   e.Detail,
+		Instance: instance,
+		Code:     e.Code,
+		Error:    e.Detail,
+	}
+}
+
+// Problem body with the error's extensions merged in; standard members win over extension fields of the same name
+func (e *Error) MarshalProblem(instance string) ([]byte, error) {
+	problem := e.Problem(instance)
+	if e.Extensions == nil {
+		return json.Marshal(problem)
+	}
+
+	members := map[string]json.RawMessage{}
+	extensions, err := json.Marshal(e.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(extensions, &members); err != nil {
+		return nil, err
+	}
+	standard, err := json.Marshal(problem)
+	if err != nil {
+		return nil, err
+	}
+	var standardMembers map[string]json.RawMessage
+	if err := json.Unmarshal(standard, &standardMembers); err != nil {
+		return nil, err
+	}
+	for name, value := range standardMembers {
+		members[name] = value
+	}
+	return json.Marshal(members)
+}
+
+// Fiber ErrorHandler that sends every error as problem+json, logging causes and internal errors
+func Handler(c *fiber.Ctx, err error) error {
+	appErr := From(err)
+	if appErr.Cause != nil || appErr.Status() >= fiber.StatusInternalServerError {
+		log.Printf("%s %s: %v", c.Method(), c.Path(), appErr)
+	}
+
+	body, err := appErr.MarshalProblem(c.Path())
+	if err != nil {
+		log.Printf("%s %s: failed to encode problem: %v", c.Method(), c.Path(), err)
+		body, _ = Wrap(err).MarshalProblem(c.Path())
+		c.Status(fiber.StatusInternalServerError)
+	} else {
+		c.Status(appErr.Status())
+	}
+	c.Set(fiber.HeaderContentType, ProblemContentType)
+	return c.Send(body)
+}
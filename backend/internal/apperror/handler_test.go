@@ -0,0 +1,158 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type sampleConflict struct {
+	Problem
+	GameIDs []int `json:"game_ids"`
+}
+
+func sendError(t *testing.T, err error) (int, string, map[string]interface{}) {
+	t.Helper()
+	app := fiber.New(fiber.Config{ErrorHandler: Handler})
+	app.Get("/api/picks/:id", func(c *fiber.Ctx) error {
+		return err
+	})
+
+	resp, testErr := app.Test(httptest.NewRequest("GET", "/api/picks/7", nil))
+	if testErr != nil {
+		t.Fatal(testErr)
+	}
+	defer resp.Body.Close()
+	body, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	var problem map[string]interface{}
+	if err := json.Unmarshal(body, &problem); err != nil {
+		t.Fatalf("Body %q isn't JSON: %v", body, err)
+	}
+	return resp.StatusCode, resp.Header.Get("Content-Type"), problem
+}
+
+func TestHandlerSendsProblem(t *testing.T) {
+	status, contentType, problem := sendError(t, New(PickAlreadyExists, "A pick already exists for this game"))
+
+	if status != 409 {
+		t.Errorf("status = %d; want 409", status)
+	}
+	if contentType != ProblemContentType {
+		t.Errorf("Content-Type = %q; want %q", contentType, ProblemContentType)
+	}
+	want := map[string]interface{}{
+		"type":     "urn:gamescript:problem:pick-already-exists",
+		"title":    "Pick already exists",
+		"status":   float64(409),
+		"detail":   "A pick already exists for this game",
+		"instance": "/api/picks/7",
+		"code":     "PICK_ALREADY_EXISTS",
+		"error":    "A pick already exists for this game",
+	}
+	for name, value := range want {
+		if problem[name] != value {
+			t.Errorf("%s = %v; want %v", name, problem[name], value)
+		}
+	}
+}
+
+func TestHandlerMergesExtensions(t *testing.T) {
+	err := New(PicksLocked, "Picks are locked").With(sampleConflict{GameIDs: []int{3, 4}})
+	status, _, problem := sendError(t, err)
+
+	if status != 423 {
+		t.Errorf("status = %d; want 423", status)
+	}
+	if ids, ok := problem["game_ids"].([]interface{}); !ok || len(ids) != 2 {
+		t.Errorf("game_ids = %v; want [3 4]", problem["game_ids"])
+	}
+	// The embedded Problem's zero values don't replace the standard members
+	if problem["code"] != "PICKS_LOCKED" || problem["detail"] != "Picks are locked" {
+		t.Errorf("Standard members were overwritten: %v", problem)
+	}
+}
+
+func TestHandlerHidesInternalErrors(t *testing.T) {
+	cause := errors.New(`pq: duplicate key value violates unique constraint "picks_pkey"`)
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"plain error", cause},
+		{"wrapped", Wrap(cause)},
+		{"internal with detail", New(Internal, "Failed to create user").WithCause(cause)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, _, problem := sendError(t, test.err)
+			if status != 500 {
+				t.Errorf("status = %d; want 500", status)
+			}
+			if problem["code"] != string(Internal) {
+				t.Errorf("code = %v; want %s", problem["code"], Internal)
+			}
+			for name, value := range problem {
+				if text, ok := value.(string); ok && strings.Contains(text, "pq:") {
+					t.Errorf("%s leaks the cause: %q", name, text)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerMapsFiberErrors(t *testing.T) {
+	status, _, problem := sendError(t, fiber.ErrRequestEntityTooLarge)
+	if status != 413 || problem["code"] != string(PayloadTooLarge) {
+		t.Errorf("got %d %v; want 413 %s", status, problem["code"], PayloadTooLarge)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: Handler})
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/missing", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != 404 || body["code"] != string(NotFound) {
+		t.Errorf("Unknown route got %d %v; want 404 %s", resp.StatusCode, body["code"], NotFound)
+	}
+}
+
+func TestEveryCodeIsDefined(t *testing.T) {
+	for _, value := range Code("").EnumValues() {
+		code := Code(value.(string))
+		if code.Status() < 400 || code.Title() == "" {
+			t.Errorf("%s has status %d and title %q", code, code.Status(), code.Title())
+		}
+	}
+	for status, code := range statusCodes {
+		if code.Status() != status {
+			t.Errorf("Fiber status %d maps to %s, which is sent as %d", status, code, code.Status())
+		}
+	}
+}
+
+func TestAsFindsWrappedErrors(t *testing.T) {
+	err := New(ScenarioNotFound, "Scenario not found")
+	wrapped := errors.Join(errors.New("loading comparison"), err)
+
+	if !Is(wrapped, ScenarioNotFound) {
+		t.Error("Is didn't find the wrapped application error")
+	}
+	if Is(wrapped, GameNotFound) {
+		t.Error("Is matched the wrong code")
+	}
+	if Is(errors.New("plain"), Internal) {
+		t.Error("Is matched a plain error")
+	}
+}
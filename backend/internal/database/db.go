@@ -4,11 +4,12 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 
@@ -60,4 +61,10 @@ func (db *DB) Close() error {
 // Executes a query and returns the resulting rows
 func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	return db.Conn.Query(query, args...)
-}
\ No newline at end of file
+}
+
+// Whether err is a unique violation of the named constraint, such as users_email_key
+func IsUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
@@ -1,3 +0,0 @@
--- Migration: Add network column to games table
-
-ALTER TABLE games ADD COLUMN network VARCHAR(64);
\ No newline at end of file
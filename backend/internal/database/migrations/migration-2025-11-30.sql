@@ -1,8 +0,0 @@
--- Migration: Add security fields to users table
-ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_login_attempts INT DEFAULT 0;
-ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
-ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login TIMESTAMP;
-ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
-
--- Migration: Add index for faster lookups on locked accounts
-CREATE INDEX IF NOT EXISTS idx_users_locked_until ON users(locked_until) WHERE locked_until IS NOT NULL;
\ No newline at end of file
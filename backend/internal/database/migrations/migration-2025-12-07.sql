@@ -1,13 +0,0 @@
--- Migration: Adjust game start times for DST in 2025
-UPDATE games 
-SET start_time = start_time AT TIME ZONE 'America/Los_Angeles' AT TIME ZONE 'UTC'
-WHERE start_time < '2025-03-09 02:00:00'  -- Before DST starts
-   OR start_time >= '2025-11-02 02:00:00'; -- After DST ends
-
-UPDATE games 
-SET start_time = start_time AT TIME ZONE 'America/Los_Angeles' AT TIME ZONE 'UTC'
-WHERE start_time >= '2025-03-09 02:00:00' 
-  AND start_time < '2025-11-02 02:00:00';
-
--- Migration: Add alternate logo URL to teams
-ALTER TABLE teams ADD COLUMN alternate_logo_url VARCHAR(255);
\ No newline at end of file
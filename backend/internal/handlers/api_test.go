@@ -0,0 +1,522 @@
+package handlers
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "testing"
+
+    "gamescript/internal/testdb"
+
+    "github.com/gofiber/fiber/v2"
+    "github.com/stretchr/testify/assert"
+)
+
+// Test client that keeps the guest session cookie between requests like a browser would, and sends a
+// bearer token once it has one
+type apiClient struct {
+    t            *testing.T
+    app          *fiber.App
+    sessionToken string
+    token        string
+}
+
+func newAPIClient(t *testing.T, app *fiber.App) *apiClient {
+    return &apiClient{t: t, app: app}
+}
+
+// Sends a request with an optional JSON body, decodes the response into out if given, and returns the status
+func (client *apiClient) do(method string, path string, body interface{}, out interface{}) int {
+    client.t.Helper()
+
+    var reader io.Reader
+    if body != nil {
+        payload, err := json.Marshal(body)
+        if err != nil {
+            client.t.Fatal(err)
+        }
+        reader = bytes.NewReader(payload)
+    }
+
+    req := httptest.NewRequest(method, path, reader)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    if client.sessionToken != "" {
+        req.AddCookie(&http.Cookie{Name: "session_token", Value: client.sessionToken})
+    }
+    if client.token != "" {
+        req.Header.Set("Authorization", "Bearer "+client.token)
+    }
+
+    // Standings and playoff generation can take longer than the default test timeout
+    resp, err := client.app.Test(req, -1)
+    if err != nil {
+        client.t.Fatal(err)
+    }
+    defer resp.Body.Close()
+
+    for _, cookie := range resp.Cookies() {
+        if cookie.Name == "session_token" {
+            client.sessionToken = cookie.Value
+        }
+    }
+    payload, err := io.ReadAll(resp.Body)
+    if err != nil {
+        client.t.Fatal(err)
+    }
+    checkContract(client.t, method, path, resp, payload)
+    if out != nil {
+        if err := json.Unmarshal(payload, out); err != nil {
+            client.t.Fatalf("%s %s: failed to decode %d response: %v", method, path, resp.StatusCode, err)
+        }
+    }
+    return resp.StatusCode
+}
+
+// Creates a scenario on the fixture season of a sport and returns its ID
+func (client *apiClient) createScenario(sportID int, seasonID int, name string, isPublic bool) int {
+    client.t.Helper()
+    var scenario map[string]interface{}
+    status := client.do("POST", "/api/scenarios", map[string]interface{}{
+        "name":      name,
+        "sport_id":  sportID,
+        "season_id": seasonID,
+        "is_public": isPublic,
+    }, &scenario)
+    if status != 201 {
+        client.t.Fatalf("failed to create scenario: %d %v", status, scenario)
+    }
+    return int(scenario["id"].(float64))
+}
+
+// Finds the first game of the season with the given status
+func (client *apiClient) findGame(seasonID int, status string) map[string]interface{} {
+    client.t.Helper()
+    var games []map[string]interface{}
+    client.do("GET", fmt.Sprintf("/api/seasons/%d/games", seasonID), nil, &games)
+    for _, game := range games {
+        if game["status"] == status {
+            return game
+        }
+    }
+    client.t.Fatalf("no %s game in season %d", status, seasonID)
+    return nil
+}
+
+func TestScenarioCRUD(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NFL")
+    guest := newAPIClient(t, app)
+
+    id := guest.createScenario(sportID, seasonID, "Playoff Push", false)
+    assert.NotEmpty(t, guest.sessionToken, "Creating a scenario as a guest should start a session")
+    path := fmt.Sprintf("/api/scenarios/%d", id)
+
+    var scenarios []map[string]interface{}
+    assert.Equal(t, 200, guest.do("GET", "/api/scenarios", nil, &scenarios))
+    if assert.Len(t, scenarios, 1) {
+        assert.Equal(t, "Playoff Push", scenarios[0]["name"])
+        assert.Equal(t, ScenarioRoleOwner, scenarios[0]["role"])
+    }
+
+    var scenario map[string]interface{}
+    assert.Equal(t, 200, guest.do("GET", path, nil, &scenario))
+    assert.Equal(t, "NFL", scenario["sport_short_name"])
+    assert.Equal(t, ScenarioModeWhatIf, scenario["mode"])
+
+    assert.Equal(t, 200, guest.do("PUT", path, map[string]interface{}{"name": "Wild Card Chase", "is_public": true}, &scenario))
+    assert.Equal(t, "Wild Card Chase", scenario["name"])
+    assert.Equal(t, true, scenario["is_public"])
+
+    var body map[string]interface{}
+    assert.Equal(t, 400, guest.do("PUT", path, map[string]interface{}{}, &body))
+    assert.Equal(t, 400, guest.do("PUT", path, map[string]interface{}{"mode": "fantasy"}, &body))
+
+    assert.Equal(t, 200, guest.do("DELETE", path, nil, &body))
+    assert.Equal(t, 404, guest.do("GET", path, nil, &body))
+}
+
+func TestCreateScenarioValidation(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NFL")
+    guest := newAPIClient(t, app)
+
+    var body map[string]interface{}
+    assert.Equal(t, 400, guest.do("POST", "/api/scenarios", map[string]interface{}{"name": "No Season", "sport_id": sportID}, &body))
+    assert.Equal(t, "VALIDATION_FAILED", body["code"])
+    assert.Equal(t, "Missing required fields", body["error"])
+
+    status := guest.do("POST", "/api/scenarios", map[string]interface{}{
+        "name":        "Bad Mode",
+        "sport_id":    sportID,
+        "season_id":   seasonID,
+        "result_mode": "rewrite",
+    }, &body)
+    assert.Equal(t, 400, status)
+}
+
+func TestGuestSessionOwnership(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NFL")
+    owner := newAPIClient(t, app)
+    stranger := newAPIClient(t, app)
+    stranger.sessionToken = "stranger-session"
+
+    id := owner.createScenario(sportID, seasonID, "Private Bracket", false)
+    path := fmt.Sprintf("/api/scenarios/%d", id)
+
+    // Another guest can't see or change a private scenario
+    var body map[string]interface{}
+    assert.Equal(t, 403, stranger.do("GET", path, nil, &body))
+    assert.Equal(t, 403, stranger.do("PUT", path, map[string]interface{}{"name": "Mine Now"}, &body))
+    assert.Equal(t, 403, stranger.do("DELETE", path, nil, &body))
+
+    var scenarios []map[string]interface{}
+    assert.Equal(t, 200, stranger.do("GET", "/api/scenarios", nil, &scenarios))
+    assert.Empty(t, scenarios, "Other guests' scenarios shouldn't be listed")
+
+    // Making a scenario public lets others fork it, but not view or change it
+    assert.Equal(t, 200, owner.do("PUT", path, map[string]interface{}{"is_public": true}, &body))
+    assert.Equal(t, 403, stranger.do("GET", path, nil, &body))
+    assert.Equal(t, 403, stranger.do("GET", path+"/standings", nil, &body))
+    assert.Equal(t, 403, stranger.do("PUT", path, map[string]interface{}{"name": "Mine Now"}, &body))
+    assert.Equal(t, 403, newAPIClient(t, app).do("GET", path, nil, &body), "Public scenarios shouldn't be visible without a session")
+    var fork map[string]interface{}
+    assert.Equal(t, 201, stranger.do("POST", path+"/fork", nil, &fork))
+
+    // Registering and claiming moves ownership from the guest session to the account
+    var registered map[string]interface{}
+    status := owner.do("POST", "/api/auth/register", map[string]interface{}{
+        "email":    "claimer@example.com",
+        "username": "claimer",
+        "password": "Password123!",
+    }, &registered)
+    if !assert.Equal(t, 201, status) {
+        return
+    }
+    owner.token = registered["token"].(string)
+    assert.Equal(t, 200, owner.do("POST", path+"/claim", nil, &body))
+
+    guestOnly := newAPIClient(t, app)
+    guestOnly.sessionToken = owner.sessionToken
+    assert.Equal(t, 403, guestOnly.do("PUT", path, map[string]interface{}{"is_public": false}, &body), "The session shouldn't own a claimed scenario")
+    assert.Equal(t, 200, owner.do("PUT", path, map[string]interface{}{"is_public": false}, &body))
+    assert.Equal(t, 404, owner.do("POST", path+"/claim", nil, &body), "A scenario can only be claimed once")
+}
+
+func TestPickLifecycle(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NFL")
+    guest := newAPIClient(t, app)
+
+    id := guest.createScenario(sportID, seasonID, "Picks", false)
+    game := guest.findGame(seasonID, "upcoming")
+    gameID := int(game["id"].(float64))
+    homeTeamID := int(game["home_team_id"].(float64))
+    awayTeamID := int(game["away_team_id"].(float64))
+    path := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", id, gameID)
+
+    var pick map[string]interface{}
+    assert.Equal(t, 201, guest.do("POST", path, map[string]interface{}{"picked_team_id": homeTeamID}, &pick))
+    assert.Equal(t, float64(homeTeamID), pick["picked_team_id"])
+
+    var body map[string]interface{}
+    assert.Equal(t, 409, guest.do("POST", path, map[string]interface{}{"picked_team_id": awayTeamID}, &body), "A game can only be picked once")
+    assert.Equal(t, "PICK_ALREADY_EXISTS", body["code"])
+
+    var picks []map[string]interface{}
+    assert.Equal(t, 200, guest.do("GET", fmt.Sprintf("/api/picks/scenarios/%d", id), nil, &picks))
+    if assert.Len(t, picks, 1) {
+        pickedTeam, _ := picks[0]["picked_team"].(map[string]interface{})
+        homeTeam, _ := game["home_team"].(map[string]interface{})
+        assert.Equal(t, homeTeam["abbreviation"], pickedTeam["abbreviation"])
+    }
+
+    // Predicted scores decide the winner over the picked team
+    status := guest.do("PUT", path, map[string]interface{}{
+        "picked_team_id":       homeTeamID,
+        "predicted_home_score": 17,
+        "predicted_away_score": 24,
+    }, &pick)
+    assert.Equal(t, 200, status)
+    assert.Equal(t, float64(awayTeamID), pick["picked_team_id"])
+    assert.Equal(t, float64(24), pick["predicted_away_score"])
+
+    stranger := newAPIClient(t, app)
+    stranger.sessionToken = "stranger-session"
+    assert.Equal(t, 403, stranger.do("PUT", path, map[string]interface{}{"picked_team_id": homeTeamID}, &body))
+    assert.Equal(t, 403, stranger.do("GET", fmt.Sprintf("/api/picks/scenarios/%d", id), nil, &body))
+
+    assert.Equal(t, 200, guest.do("DELETE", path, nil, &body))
+    assert.Equal(t, "Pick deleted successfully", body["message"])
+    assert.Equal(t, 200, guest.do("GET", fmt.Sprintf("/api/picks/scenarios/%d", id), nil, &picks))
+    assert.Empty(t, picks)
+
+    // Deleting it again finds nothing to delete
+    body = nil
+    assert.Equal(t, 404, guest.do("DELETE", path, nil, &body))
+    assert.Equal(t, "PICK_NOT_FOUND", body["code"])
+}
+
+func TestPickChangesAreLogged(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NFL")
+    guest := newAPIClient(t, app)
+
+    id := guest.createScenario(sportID, seasonID, "Logged", false)
+    game := guest.findGame(seasonID, "upcoming")
+    gameID := int(game["id"].(float64))
+    path := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", id, gameID)
+
+    var pick map[string]interface{}
+    if !assert.Equal(t, 201, guest.do("POST", path, map[string]interface{}{"picked_team_id": game["home_team_id"]}, &pick)) {
+        return
+    }
+    assert.Equal(t, 409, guest.do("POST", path, map[string]interface{}{"picked_team_id": game["away_team_id"]}, nil))
+    assert.Equal(t, 200, guest.do("DELETE", path, nil, nil))
+
+    // Deletes that remove nothing leave no change behind
+    assert.Equal(t, 404, guest.do("DELETE", path, nil, nil))
+    assert.Equal(t, 409, guest.do("DELETE", path+"?expected_updated_at="+url.QueryEscape(pick["updated_at"].(string)), nil, nil))
+
+    var log map[string]interface{}
+    assert.Equal(t, 200, guest.do("GET", fmt.Sprintf("/api/scenarios/%d/history", id), nil, &log))
+    changes, _ := log["changes"].([]interface{})
+    if assert.Len(t, changes, 2) {
+        for i, action := range []string{"pick_delete", "pick_create"} {
+            change := changes[i].(map[string]interface{})
+            assert.Equal(t, action, change["action"])
+            assert.Equal(t, float64(gameID), change["game_id"])
+        }
+    }
+}
+
+func TestPickFinalGameRequiresOverride(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NFL")
+    guest := newAPIClient(t, app)
+
+    id := guest.createScenario(sportID, seasonID, "Overrides", false)
+    game := guest.findGame(seasonID, "final")
+    path := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", id, int(game["id"].(float64)))
+    awayTeamID := game["away_team_id"]
+
+    var body map[string]interface{}
+    assert.Equal(t, 422, guest.do("POST", path, map[string]interface{}{"picked_team_id": awayTeamID}, &body))
+    assert.Equal(t, "PICK_OVERRIDE_NOT_ALLOWED", body["code"])
+    assert.Equal(t, "Game is final; set is_override to replace its real result", body["error"])
+
+    var pick map[string]interface{}
+    assert.Equal(t, 201, guest.do("POST", path, map[string]interface{}{"picked_team_id": awayTeamID, "is_override": true}, &pick))
+    assert.Equal(t, true, pick["is_override"])
+}
+
+func TestPredictionModeCantBeUnlocked(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NFL")
+    owner := newAPIClient(t, app)
+
+    var scenario map[string]interface{}
+    status := owner.do("POST", "/api/scenarios", map[string]interface{}{
+        "name":      "Locked In",
+        "sport_id":  sportID,
+        "season_id": seasonID,
+        "mode":      ScenarioModePrediction,
+    }, &scenario)
+    if !assert.Equal(t, 201, status) {
+        return
+    }
+    path := fmt.Sprintf("/api/scenarios/%d", int(scenario["id"].(float64)))
+    game := owner.findGame(seasonID, "final")
+    pickPath := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", int(scenario["id"].(float64)), int(game["id"].(float64)))
+
+    // Switching to what_if, picking the started game, and switching back is refused at the first step
+    var body map[string]interface{}
+    assert.Equal(t, 423, owner.do("PUT", path, map[string]interface{}{"mode": ScenarioModeWhatIf}, &body))
+    assert.Equal(t, "PICKS_LOCKED", body["code"])
+    assert.Contains(t, body["locked_game_ids"], game["id"])
+
+    assert.Equal(t, 423, owner.do("POST", pickPath, map[string]interface{}{"picked_team_id": game["away_team_id"], "is_override": true}, &body))
+    assert.Equal(t, 200, owner.do("GET", path, nil, &scenario))
+    assert.Equal(t, ScenarioModePrediction, scenario["mode"])
+
+    // Other fields can still change, and what-if scenarios can still become predictions
+    assert.Equal(t, 200, owner.do("PUT", path, map[string]interface{}{"name": "Still Locked", "mode": ScenarioModePrediction}, &scenario))
+    whatIf := owner.createScenario(sportID, seasonID, "What If", false)
+    assert.Equal(t, 200, owner.do("PUT", fmt.Sprintf("/api/scenarios/%d", whatIf), map[string]interface{}{"mode": ScenarioModePrediction}, &scenario))
+}
+
+func TestForkIntoPredictionChecksStartedPicks(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NFL")
+    owner := newAPIClient(t, app)
+
+    id := owner.createScenario(sportID, seasonID, "What If", false)
+    game := owner.findGame(seasonID, "final")
+    pickPath := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", id, int(game["id"].(float64)))
+    if !assert.Equal(t, 201, owner.do("POST", pickPath, map[string]interface{}{"picked_team_id": game["away_team_id"], "is_override": true}, nil)) {
+        return
+    }
+    forkPath := fmt.Sprintf("/api/scenarios/%d/fork", id)
+
+    // The fork would lock in a pick made after its game started
+    var body map[string]interface{}
+    assert.Equal(t, 423, owner.do("POST", forkPath, map[string]interface{}{"mode": ScenarioModePrediction}, &body))
+    assert.Equal(t, "PICKS_LOCKED", body["code"])
+    assert.Contains(t, body["locked_game_ids"], game["id"])
+
+    // A what-if fork copies the pick as before
+    var fork map[string]interface{}
+    assert.Equal(t, 201, owner.do("POST", forkPath, nil, &fork))
+    assert.Equal(t, ScenarioModeWhatIf, fork["mode"])
+}
+
+func TestPickValidation(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NFL")
+    _, nbaSeasonID := testdb.Season(t, db, "NBA")
+    guest := newAPIClient(t, app)
+
+    id := guest.createScenario(sportID, seasonID, "Validation", false)
+    game := guest.findGame(seasonID, "upcoming")
+    path := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", id, int(game["id"].(float64)))
+
+    var body map[string]interface{}
+    assert.Equal(t, 400, guest.do("POST", path, map[string]interface{}{"predicted_home_score": -3, "predicted_away_score": 10}, &body))
+    assert.Equal(t, "VALIDATION_FAILED", body["code"])
+    if errors, ok := body["errors"].([]interface{}); assert.True(t, ok) && assert.Len(t, errors, 1) {
+        fieldErr := errors[0].(map[string]interface{})
+        assert.Equal(t, "predicted_home_score", fieldErr["field"])
+        assert.Equal(t, "body", fieldErr["in"])
+    }
+
+    body = nil
+    assert.Equal(t, 400, guest.do("POST", path, map[string]interface{}{"picked_team_id": 999999}, &body))
+    assert.Equal(t, "Picked team is not playing in this game", body["detail"])
+
+    // A game from another season can't be picked, even by ID
+    nbaGame := guest.findGame(nbaSeasonID, "upcoming")
+    body = nil
+    otherPath := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", id, int(nbaGame["id"].(float64)))
+    assert.Equal(t, 400, guest.do("POST", otherPath, map[string]interface{}{"picked_team_id": nbaGame["home_team_id"]}, &body))
+    assert.Equal(t, "INVALID_PARAMETER", body["code"])
+    assert.Equal(t, "Game not found in scenario's season", body["detail"])
+
+    body = nil
+    assert.Equal(t, 400, guest.do("POST", fmt.Sprintf("/api/picks/scenarios/%d/games/abc", id), map[string]interface{}{"picked_team_id": 1}, &body))
+    assert.Equal(t, "Invalid game ID", body["detail"])
+}
+
+func TestImportChecksPicks(t *testing.T) {
+    app, db := setupTestApp(t)
+    sportID, seasonID := testdb.Season(t, db, "NBA")
+    guest := newAPIClient(t, app)
+    id := guest.createScenario(sportID, seasonID, "NBA Import", false)
+
+    var games []map[string]interface{}
+    assert.Equal(t, 200, guest.do("GET", fmt.Sprintf("/api/seasons/%d/games", seasonID), nil, &games))
+    var upcoming []map[string]interface{}
+    for _, game := range games {
+        if game["status"] == "upcoming" && len(upcoming) < 2 {
+            upcoming = append(upcoming, game)
+        }
+    }
+    if !assert.Len(t, upcoming, 2) {
+        return
+    }
+    for _, game := range upcoming {
+        path := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", id, int(game["id"].(float64)))
+        assert.Equal(t, 201, guest.do("POST", path, map[string]interface{}{"picked_team_id": game["home_team_id"]}, nil))
+    }
+
+    var document map[string]interface{}
+    assert.Equal(t, 200, guest.do("GET", fmt.Sprintf("/api/scenarios/%d/export", id), nil, &document))
+    picks := document["picks"].([]interface{})
+    if !assert.Len(t, picks, 2) {
+        return
+    }
+
+    // A tie on an NBA game can't be imported, and scores that contradict the picked team decide the winner
+    tie := picks[0].(map[string]interface{})
+    tie["is_tie"], tie["picked_team"] = true, nil
+    contradicted := picks[1].(map[string]interface{})
+    contradicted["predicted_home_score"], contradicted["predicted_away_score"] = 98, 104
+
+    var imported map[string]interface{}
+    if !assert.Equal(t, 201, guest.do("POST", "/api/scenarios/import", document, &imported)) {
+        return
+    }
+    assert.Equal(t, float64(1), imported["imported_picks"])
+    if unmatched, ok := imported["unmatched_games"].([]interface{}); assert.True(t, ok) && assert.Len(t, unmatched, 1) {
+        assert.Equal(t, "Ties are only allowed for NFL games", unmatched[0].(map[string]interface{})["reason"])
+    }
+
+    importedID := int(imported["scenario"].(map[string]interface{})["id"].(float64))
+    var importedPicks []map[string]interface{}
+    assert.Equal(t, 200, guest.do("GET", fmt.Sprintf("/api/picks/scenarios/%d", importedID), nil, &importedPicks))
+    if assert.Len(t, importedPicks, 1) {
+        game := upcoming[0]
+        if importedPicks[0]["game_id"] != game["id"] {
+            game = upcoming[1]
+        }
+        assert.Equal(t, game["away_team_id"], importedPicks[0]["picked_team_id"])
+    }
+
+    var body map[string]interface{}
+    assert.Equal(t, 422, guest.do("POST", "/api/scenarios/import?strict=true", document, &body))
+    assert.Equal(t, "IMPORT_MISMATCH", body["code"])
+}
+
+// Finds a team's wins in the playoff seeds of a conference
+func seedWins(t *testing.T, standings map[string]interface{}, teamID float64) float64 {
+    t.Helper()
+    for _, conference := range []string{"afc", "nfc", "eastern", "western"} {
+        section, _ := standings[conference].(map[string]interface{})
+        seeds, _ := section["playoff_seeds"].([]interface{})
+        for _, seed := range seeds {
+            team := seed.(map[string]interface{})
+            if team["team_id"] == teamID {
+                return team["wins"].(float64)
+            }
+        }
+    }
+    t.Fatalf("team %v not in standings", teamID)
+    return 0
+}
+
+func TestStandingsReflectPicks(t *testing.T) {
+    app, db := setupTestApp(t)
+    guest := newAPIClient(t, app)
+
+    for _, sport := range []string{"NFL", "NBA"} {
+        t.Run(sport, func(t *testing.T) {
+            guest.t = t
+            sportID, seasonID := testdb.Season(t, db, sport)
+            id := guest.createScenario(sportID, seasonID, sport+" Standings", false)
+            path := fmt.Sprintf("/api/scenarios/%d/standings", id)
+
+            var before map[string]interface{}
+            if !assert.Equal(t, 200, guest.do("GET", path, nil, &before)) {
+                return
+            }
+            assert.NotEmpty(t, before["draft_order"])
+
+            game := guest.findGame(seasonID, "upcoming")
+            homeTeamID := game["home_team_id"].(float64)
+            wins := seedWins(t, before, homeTeamID)
+
+            var pick map[string]interface{}
+            pickPath := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", id, int(game["id"].(float64)))
+            assert.Equal(t, 201, guest.do("POST", pickPath, map[string]interface{}{"picked_team_id": homeTeamID}, &pick))
+
+            var after map[string]interface{}
+            assert.Equal(t, 200, guest.do("GET", path, nil, &after))
+            assert.Equal(t, wins+1, seedWins(t, after, homeTeamID), "Picking a game should add a win for the picked team")
+        })
+    }
+}
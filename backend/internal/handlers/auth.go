@@ -3,6 +3,7 @@
 package handlers
 
 import (
+    "database/sql"
     "os"
     "regexp"
     "time"
@@ -12,7 +13,9 @@ import (
     "github.com/golang-jwt/jwt/v5"
     "golang.org/x/crypto/bcrypt"
 
+    "gamescript/internal/apperror"
     "gamescript/internal/database"
+    "gamescript/internal/validation"
 )
 
 
@@ -77,37 +80,97 @@ func validateUsername(username string) []string {
     return errors
 }
 
+// Records each broken rule from validatePassword or validateUsername against field
+func addRuleErrors(errs *validation.Errors, field string, rules []string) {
+    for _, rule := range rules {
+        errs.Add(validation.InBody, field, rule)
+    }
+}
+
+type RegisterRequest struct {
+    Email    string `json:"email"`
+    Username string `json:"username"`
+    Password string `json:"password"`
+}
+
+type LoginRequest struct {
+    Email    string `json:"email"`
+    Password string `json:"password"`
+}
+
+// Fields left out or empty are unchanged; changing the password requires the current one
+type UpdateProfileRequest struct {
+    Username        *string `json:"username"`
+    Email           *string `json:"email"`
+    CurrentPassword *string `json:"current_password"`
+    NewPassword     *string `json:"new_password"`
+}
+
+// A new session after registering or logging in
+type AuthResponse struct {
+    User  AuthUserResponse `json:"user"`
+    Token string           `json:"token"`
+}
+
+type AuthUserResponse struct {
+    ID        int       `json:"id"`
+    Email     string    `json:"email"`
+    Username  string    `json:"username"`
+    IsAdmin   bool      `json:"is_admin"`
+    CreatedAt time.Time `json:"created_at"`
+}
+
+type UserResponse struct {
+    ID        int       `json:"id"`
+    Email     string    `json:"email"`
+    Username  string    `json:"username"`
+    IsAdmin   bool      `json:"is_admin"`
+    AvatarURL *string   `json:"avatar_url"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+type AccountLockedResponse struct {
+    apperror.Problem
+    LockedForMinutes int `json:"locked_for_minutes,omitempty"`
+}
+
 func RegisterUser(db *database.DB) fiber.Handler {
     return func(c *fiber.Ctx) error {
-        type RegisterRequest struct {
-            Email    string `json:"email"`
-            Username string `json:"username"`
-            Password string `json:"password"`
-        }
-
         var req RegisterRequest
         if err := c.BodyParser(&req); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
+            return apperror.New(apperror.InvalidRequestBody, "Invalid request")
         }
 
         // Validate all fields
-        var errors []string
-        if req.Email == "" || req.Username == "" || req.Password == "" {
-            return c.Status(400).JSON(fiber.Map{"error": "Missing required fields"})
+        var required validation.Errors
+        if req.Email == "" {
+            required.Add(validation.InBody, "email", "email is required")
         }
+        if req.Username == "" {
+            required.Add(validation.InBody, "username", "username is required")
+        }
+        if req.Password == "" {
+            required.Add(validation.InBody, "password", "password is required")
+        }
+        if err := required.ErrWithDetail("Missing required fields"); err != nil {
+            return err
+        }
+
+        var errs validation.Errors
         if !validateEmail(req.Email) {
-            errors = append(errors, "Invalid email format")
+            errs.Add(validation.InBody, "email", "Invalid email format")
         }
-        errors = append(errors, validateUsername(req.Username)...)
-        errors = append(errors, validatePassword(req.Password)...)
-        if len(errors) > 0 {
-            return c.Status(400).JSON(fiber.Map{"error": errors[0], "errors": errors})
+        addRuleErrors(&errs, "username", validateUsername(req.Username))
+        addRuleErrors(&errs, "password", validatePassword(req.Password))
+        if err := errs.Err(); err != nil {
+            return err
         }
 
 		// Hash password with higher cost for production
         hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), 12)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to hash password"})
+            return apperror.New(apperror.Internal, "Failed to hash password").WithCause(err)
         }
 
         query := `
@@ -125,45 +188,40 @@ func RegisterUser(db *database.DB) fiber.Handler {
             &id, &email, &username, &isAdmin, &createdAt,
         )
         if err != nil {
-            if err.Error() == "pq: duplicate key value violates unique constraint \"users_email_key\"" {
-                return c.Status(400).JSON(fiber.Map{"error": "Email already in use"})
+            if database.IsUniqueViolation(err, "users_email_key") {
+                return apperror.New(apperror.EmailInUse, "Email already in use")
             }
-            if err.Error() == "pq: duplicate key value violates unique constraint \"users_username_key\"" {
-                return c.Status(400).JSON(fiber.Map{"error": "Username already in use"})
+            if database.IsUniqueViolation(err, "users_username_key") {
+                return apperror.New(apperror.UsernameInUse, "Username already in use")
             }
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to create user"})
+            return apperror.New(apperror.Internal, "Failed to create user").WithCause(err)
         }
 
         token := generateJWT(id, email, username)
 
-        return c.Status(201).JSON(fiber.Map{
-            "user": map[string]interface{}{
-                "id":         id,
-                "email":      email,
-                "username":   username,
-                "is_admin":   isAdmin,
-                "created_at": createdAt,
+        return c.Status(201).JSON(AuthResponse{
+            User: AuthUserResponse{
+                ID:        id,
+                Email:     email,
+                Username:  username,
+                IsAdmin:   isAdmin,
+                CreatedAt: createdAt,
             },
-            "token": token,
+            Token: token,
         })
     }
 }
 
 func LoginUser(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		type LoginRequest struct {
-			Email string `json:"email"`
-			Password string `json:"password"`
-		}
-
 		var req LoginRequest
 		if err := c.BodyParser(&req); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
 		}
 
         // Validate input
 		if req.Email == "" || req.Password == "" {
-			return c.Status(400).JSON(fiber.Map{"error": "Email and password are required"})
+			return apperror.New(apperror.ValidationFailed, "Email and password are required")
 		}
 
 		// Check for account lockout
@@ -179,10 +237,8 @@ func LoginUser(db *database.DB) fiber.Handler {
 
 		if err == nil && lockedUntil != nil && time.Now().Before(*lockedUntil) {
             remainingTime := time.Until(*lockedUntil).Minutes()
-            return c.Status(423).JSON(fiber.Map{
-                "error": "Account is temporarily locked due to multiple failed login attempts. Please try again later.",
-                "locked_for_minutes": int(remainingTime) + 1,
-            })
+            return apperror.New(apperror.AccountLocked, "Account is temporarily locked due to multiple failed login attempts. Please try again later.").
+                With(AccountLockedResponse{LockedForMinutes: int(remainingTime) + 1})
         }
 
 		query := `
@@ -200,7 +256,7 @@ func LoginUser(db *database.DB) fiber.Handler {
 			&id, &email, &username, &passwordHash, &isAdmin, &createdAt,
 		)
 		if err != nil {
-			return c.Status(401).JSON(fiber.Map{"error": "Invalid email or password"})
+			return apperror.New(apperror.InvalidCredentials, "Invalid email or password")
 		}
 
 		if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.Password)); err != nil {
@@ -222,12 +278,10 @@ func LoginUser(db *database.DB) fiber.Handler {
             db.Conn.Exec(updateQuery, failedAttempts, lockTime, id)
 
             if failedAttempts >= 5 {
-                return c.Status(423).JSON(fiber.Map{
-                    "error": "Too many failed attempts. Account locked for 15 minutes.",
-                })
+                return apperror.New(apperror.AccountLocked, "Too many failed attempts. Account locked for 15 minutes.")
             }
 
-            return c.Status(401).JSON(fiber.Map{"error": "Invalid email or password"})
+            return apperror.New(apperror.InvalidCredentials, "Invalid email or password")
         }
 
 		// Reset failed attempts and update last login on successful login
@@ -240,15 +294,15 @@ func LoginUser(db *database.DB) fiber.Handler {
 
 		token := generateJWT(id, email, username)
 
-		return c.JSON(fiber.Map{
-			"user": map[string]interface{}{
-				"id": id,
-				"email": email,
-				"username": username,
-				"is_admin": isAdmin,
-				"created_at": createdAt,
+		return c.JSON(AuthResponse{
+			User: AuthUserResponse{
+				ID:        id,
+				Email:     email,
+				Username:  username,
+				IsAdmin:   isAdmin,
+				CreatedAt: createdAt,
 			},
-			"token": token,
+			Token: token,
 		})
 	}
 }
@@ -263,28 +317,18 @@ func GetCurrentUser(db *database.DB) fiber.Handler {
 			WHERE id = $1
 		`
 
-		var id int
-		var email, username string
-		var isAdmin bool
-		var avatarURL *string
-		var createdAt, updatedAt time.Time
-
+		var user UserResponse
 		err := db.Conn.QueryRow(query, userID).Scan(
-			&id, &email, &username, &isAdmin, &avatarURL, &createdAt, &updatedAt,
+			&user.ID, &user.Email, &user.Username, &user.IsAdmin, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt,
 		)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.UserNotFound, "User not found")
+		}
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+			return err
 		}
 
-		return c.JSON(map[string]interface{}{
-			"id": id,
-			"email": email,
-			"username": username,
-			"is_admin": isAdmin,
-			"avatar_url": avatarURL,
-			"created_at": createdAt,
-			"updated_at": updatedAt,
-		})
+		return c.JSON(user)
 	}
 }
 
@@ -292,45 +336,40 @@ func UpdateProfile(db *database.DB) fiber.Handler {
     return func(c *fiber.Ctx) error {
         userID := c.Locals("user_id").(int)
 
-        type UpdateProfileRequest struct {
-            Username       *string `json:"username"`
-            Email          *string `json:"email"`
-            CurrentPassword *string `json:"current_password"`
-            NewPassword    *string `json:"new_password"`
-        }
-
         var request UpdateProfileRequest
         if err := c.BodyParser(&request); err != nil {
-            return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+            return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
         }
 
         // If changing password, verify current password
         if request.NewPassword != nil && *request.NewPassword != "" {
             if request.CurrentPassword == nil || *request.CurrentPassword == "" {
-                return c.Status(400).JSON(fiber.Map{"error": "Current password is required to change password"})
+                return validation.Field("current_password", "Current password is required to change password")
             }
 
             // Validate new password
-            if errors := validatePassword(*request.NewPassword); len(errors) > 0 {
-                return c.Status(400).JSON(fiber.Map{"error": errors[0], "errors": errors})
+            var errs validation.Errors
+            addRuleErrors(&errs, "new_password", validatePassword(*request.NewPassword))
+            if err := errs.Err(); err != nil {
+                return err
             }
 
             // Get current password hash
             var currentHash string
             err := db.Conn.QueryRow("SELECT password_hash FROM users WHERE id = $1", userID).Scan(&currentHash)
             if err != nil {
-                return c.Status(500).JSON(fiber.Map{"error": "Failed to verify password"})
+                return apperror.New(apperror.Internal, "Failed to verify password").WithCause(err)
             }
 
             // Verify current password
             if err := bcrypt.CompareHashAndPassword([]byte(currentHash), []byte(*request.CurrentPassword)); err != nil {
-                return c.Status(401).JSON(fiber.Map{"error": "Current password is incorrect"})
+                return apperror.New(apperror.InvalidCredentials, "Current password is incorrect")
             }
 
             // Hash new password
             hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*request.NewPassword), 12)
             if err != nil {
-                return c.Status(500).JSON(fiber.Map{"error": "Failed to hash new password"})
+                return apperror.New(apperror.Internal, "Failed to hash new password").WithCause(err)
             }
 
             // Update password
@@ -340,65 +379,54 @@ func UpdateProfile(db *database.DB) fiber.Handler {
                 WHERE id = $4
             `, string(hashedPassword), time.Now(), time.Now(), userID)
             if err != nil {
-                return c.Status(500).JSON(fiber.Map{"error": "Failed to update password"})
+                return apperror.New(apperror.Internal, "Failed to update password").WithCause(err)
             }
         }
 
         // Update username if provided
         if request.Username != nil && *request.Username != "" {
-            if errors := validateUsername(*request.Username); len(errors) > 0 {
-                return c.Status(400).JSON(fiber.Map{"error": errors[0], "errors": errors})
+            var errs validation.Errors
+            addRuleErrors(&errs, "username", validateUsername(*request.Username))
+            if err := errs.Err(); err != nil {
+                return err
             }
 
             _, err := db.Conn.Exec("UPDATE users SET username = $1, updated_at = $2 WHERE id = $3", *request.Username, time.Now(), userID)
             if err != nil {
-                if err.Error() == "pq: duplicate key value violates unique constraint \"users_username_key\"" {
-                    return c.Status(400).JSON(fiber.Map{"error": "Username already in use"})
+                if database.IsUniqueViolation(err, "users_username_key") {
+                    return apperror.New(apperror.UsernameInUse, "Username already in use")
                 }
-                return c.Status(500).JSON(fiber.Map{"error": "Failed to update username"})
+                return apperror.New(apperror.Internal, "Failed to update username").WithCause(err)
             }
         }
 
         // Update email if provided
         if request.Email != nil && *request.Email != "" {
             if !validateEmail(*request.Email) {
-                return c.Status(400).JSON(fiber.Map{"error": "Invalid email format"})
+                return apperror.New(apperror.ValidationFailed, "Invalid email format")
             }
 
             _, err := db.Conn.Exec("UPDATE users SET email = $1, updated_at = $2 WHERE id = $3", *request.Email, time.Now(), userID)
             if err != nil {
-                if err.Error() == "pq: duplicate key value violates unique constraint \"users_email_key\"" {
-                    return c.Status(400).JSON(fiber.Map{"error": "Email already in use"})
+                if database.IsUniqueViolation(err, "users_email_key") {
+                    return apperror.New(apperror.EmailInUse, "Email already in use")
                 }
-                return c.Status(500).JSON(fiber.Map{"error": "Failed to update email"})
+                return apperror.New(apperror.Internal, "Failed to update email").WithCause(err)
             }
         }
 
         // Return updated user
-        var id int
-        var email, username string
-        var isAdmin bool
-        var avatarURL *string
-        var createdAt, updatedAt time.Time
-
+        var user UserResponse
         err := db.Conn.QueryRow(`
             SELECT id, email, username, is_admin, avatar_url, created_at, updated_at
             FROM users
             WHERE id = $1
-        `, userID).Scan(&id, &email, &username, &isAdmin, &avatarURL, &createdAt, &updatedAt)
+        `, userID).Scan(&user.ID, &user.Email, &user.Username, &user.IsAdmin, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve user"})
+            return apperror.New(apperror.Internal, "Failed to retrieve user").WithCause(err)
         }
 
-        return c.JSON(fiber.Map{
-            "id":             id,
-            "email":          email,
-            "username":       username,
-            "is_admin":      isAdmin,
-            "avatar_url":    avatarURL,
-            "created_at":    createdAt,
-            "updated_at":    updatedAt,
-        })
+        return c.JSON(user)
     }
 }
 
@@ -0,0 +1,358 @@
+// Autofill handlers
+
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/history"
+	"gamescript/internal/standings"
+	"gamescript/internal/validation"
+)
+
+
+// Supported autofill strategies
+const (
+	AutofillHomeTeam          = "home_team"
+	AutofillBetterRecord      = "better_record"
+	AutofillPointDifferential = "point_differential"
+	AutofillRandom            = "random"
+	AutofillChalk             = "chalk"
+)
+
+type AutofillRequest struct {
+	Strategy string `json:"strategy"`
+	Seed     *int64 `json:"seed"`
+	Week     *int   `json:"week"`
+	TeamID   *int   `json:"team_id"`
+}
+
+// Picks created by autofill; seed is set for the random strategy so the run can be repeated
+type AutofillResponse struct {
+	Strategy	string			`json:"strategy"`
+	Created		int				`json:"created"`
+	Picks		[]AutofillPick	`json:"picks"`
+	Seed		*int64			`json:"seed,omitempty"`
+}
+
+type AutofillPick struct {
+	ID					int			`json:"id"`
+	ScenarioID			int			`json:"scenario_id"`
+	GameID				int			`json:"game_id"`
+	Week				*int		`json:"week"`
+	PickedTeamID		int			`json:"picked_team_id"`
+	PredictedHomeScore	*int		`json:"predicted_home_score"`
+	PredictedAwayScore	*int		`json:"predicted_away_score"`
+	Status				*string		`json:"status"`
+	CreatedAt			time.Time	`json:"created_at"`
+	UpdatedAt			time.Time	`json:"updated_at"`
+}
+
+// Game without a pick that is eligible for autofill
+type autofillGame struct {
+	ID         int
+	HomeTeamID int
+	AwayTeamID int
+	Week       *int
+}
+
+// Real results of a team from final games
+type actualTeamRecord struct {
+	Wins          int
+	Losses        int
+	Ties          int
+	PointsFor     int
+	PointsAgainst int
+}
+
+func autofillPicks(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
+		}
+		sID := access.ID
+
+		var req AutofillRequest
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+		}
+
+		req.Strategy = strings.ToLower(strings.TrimSpace(req.Strategy))
+		switch req.Strategy {
+		case AutofillHomeTeam, AutofillBetterRecord, AutofillPointDifferential, AutofillRandom, AutofillChalk:
+		default:
+			return validation.Field("strategy", fmt.Sprintf("Invalid strategy. Must be one of: %s, %s, %s, %s, %s",
+				AutofillHomeTeam, AutofillBetterRecord, AutofillPointDifferential, AutofillRandom, AutofillChalk))
+		}
+
+		var seasonID, sportID int
+		err = db.Conn.QueryRow(`SELECT season_id, sport_id FROM scenarios WHERE id = $1`, sID).Scan(&seasonID, &sportID)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.ScenarioNotFound, "Scenario not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		// Prediction scenarios can't pick games that have already started
+		var startsAfter *time.Time
+		if access.Mode == ScenarioModePrediction {
+			now := time.Now()
+			startsAfter = &now
+		}
+
+		games, err := getAutofillGames(db, sID, seasonID, req.Week, req.TeamID, startsAfter)
+		if err != nil {
+			return err
+		}
+
+		// Build the chooser for the requested strategy
+		var chooseWinner func(game autofillGame) int
+		var seed int64
+
+		switch req.Strategy {
+		case AutofillHomeTeam:
+			chooseWinner = func(game autofillGame) int {
+				return game.HomeTeamID
+			}
+		case AutofillBetterRecord, AutofillPointDifferential:
+			records, err := getActualTeamRecords(db, seasonID)
+			if err != nil {
+				return err
+			}
+			rank := actualWinPct
+			if req.Strategy == AutofillPointDifferential {
+				rank = actualPointDifferential
+			}
+			chooseWinner = func(game autofillGame) int {
+				// Home team wins when teams are even
+				if rank(records[game.AwayTeamID]) > rank(records[game.HomeTeamID]) {
+					return game.AwayTeamID
+				}
+				return game.HomeTeamID
+			}
+		case AutofillRandom:
+			if req.Seed != nil {
+				seed = *req.Seed
+			} else {
+				seed = time.Now().UnixNano()
+			}
+			rng := rand.New(rand.NewSource(seed))
+			chooseWinner = func(game autofillGame) int {
+				if rng.Intn(2) == 0 {
+					return game.HomeTeamID
+				}
+				return game.AwayTeamID
+			}
+		case AutofillChalk:
+			// Current scenario standings, where a later draft pick means a better team
+			outcomes, _, err := getTeamOutcomes(db, &comparedScenario{ID: sID, SportID: sportID, SeasonID: seasonID})
+			if err != nil {
+				return err
+			}
+			chooseWinner = func(game autofillGame) int {
+				if outcomes[game.AwayTeamID].DraftPick > outcomes[game.HomeTeamID].DraftPick {
+					return game.AwayTeamID
+				}
+				return game.HomeTeamID
+			}
+		}
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		gameIDs := make([]int, len(games))
+		for i, game := range games {
+			gameIDs[i] = game.ID
+		}
+		before, err := captureScenarioState(tx, sID, false, gameIDs, true)
+		if err != nil {
+			return err
+		}
+
+		created := []AutofillPick{}
+		for _, game := range games {
+			pickedTeamID := chooseWinner(game)
+
+			var id int
+			var status *string
+			var createdAt, updatedAt time.Time
+
+			// Skip games picked concurrently since the eligible games were loaded
+			err := tx.QueryRow(`
+				INSERT INTO picks (scenario_id, game_id, picked_team_id, status)
+				VALUES ($1, $2, $3, 'pending')
+				ON CONFLICT (scenario_id, game_id) DO NOTHING
+				RETURNING id, status, created_at, updated_at
+			`, sID, game.ID, pickedTeamID).Scan(&id, &status, &createdAt, &updatedAt)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					continue
+				}
+				return err
+			}
+
+			created = append(created, AutofillPick{
+				ID:           id,
+				ScenarioID:   sID,
+				GameID:       game.ID,
+				Week:         game.Week,
+				PickedTeamID: pickedTeamID,
+				Status:       status,
+				CreatedAt:    createdAt,
+				UpdatedAt:    updatedAt,
+			})
+		}
+
+		if len(created) > 0 {
+			// Regular season picks changed, so any existing playoff bracket is stale
+			_, err = tx.Exec(`DELETE FROM playoff_states WHERE scenario_id = $1`, sID)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(`UPDATE scenarios SET updated_at = NOW() WHERE id = $1`, sID)
+			if err != nil {
+				return err
+			}
+
+			err = recordScenarioChange(tx, c, sID, scenarioChange{Action: history.ActionPicksAutofill, Before: before})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		standings.InvalidateScenario(sID)
+
+		response := AutofillResponse{
+			Strategy: req.Strategy,
+			Created:  len(created),
+			Picks:    created,
+		}
+		if req.Strategy == AutofillRandom {
+			response.Seed = &seed
+		}
+
+		return c.Status(201).JSON(response)
+	}
+}
+
+// Gets games in a season that are not final and have no pick in the scenario, optionally only those starting after a time
+func getAutofillGames(db *database.DB, scenarioID int, seasonID int, week *int, teamID *int, startsAfter *time.Time) ([]autofillGame, error) {
+	query := `
+		SELECT game.id, game.home_team_id, game.away_team_id, game.week
+		FROM games game
+		LEFT JOIN picks pick ON pick.game_id = game.id AND pick.scenario_id = $1
+		WHERE game.season_id = $2
+			AND game.status IS DISTINCT FROM 'final'
+			AND pick.id IS NULL
+	`
+	args := []interface{}{scenarioID, seasonID}
+
+	if week != nil {
+		args = append(args, *week)
+		query += fmt.Sprintf(" AND game.week = $%d", len(args))
+	}
+	if teamID != nil {
+		args = append(args, *teamID)
+		query += fmt.Sprintf(" AND (game.home_team_id = $%d OR game.away_team_id = $%d)", len(args), len(args))
+	}
+	if startsAfter != nil {
+		args = append(args, startsAfter.UTC().Format("2006-01-02 15:04:05.999999"))
+		query += fmt.Sprintf(" AND game.start_time > $%d::timestamp", len(args))
+	}
+	query += " ORDER BY game.start_time, game.id"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []autofillGame
+	for rows.Next() {
+		var game autofillGame
+		if err := rows.Scan(&game.ID, &game.HomeTeamID, &game.AwayTeamID, &game.Week); err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
+
+// Gets each team's real record and points from final games in a season
+func getActualTeamRecords(db *database.DB, seasonID int) (map[int]actualTeamRecord, error) {
+	rows, err := db.Query(`
+		SELECT home_team_id, away_team_id, home_score, away_score
+		FROM games
+		WHERE season_id = $1
+			AND status = 'final'
+			AND home_score IS NOT NULL
+			AND away_score IS NOT NULL
+	`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make(map[int]actualTeamRecord)
+	for rows.Next() {
+		var homeTeamID, awayTeamID, homeScore, awayScore int
+		if err := rows.Scan(&homeTeamID, &awayTeamID, &homeScore, &awayScore); err != nil {
+			return nil, err
+		}
+
+		home := records[homeTeamID]
+		away := records[awayTeamID]
+
+		home.PointsFor += homeScore
+		home.PointsAgainst += awayScore
+		away.PointsFor += awayScore
+		away.PointsAgainst += homeScore
+
+		if homeScore > awayScore {
+			home.Wins++
+			away.Losses++
+		} else if awayScore > homeScore {
+			away.Wins++
+			home.Losses++
+		} else {
+			home.Ties++
+			away.Ties++
+		}
+
+		records[homeTeamID] = home
+		records[awayTeamID] = away
+	}
+
+	return records, rows.Err()
+}
+
+// Win percentage counting ties as half a win, 0 for teams without games
+func actualWinPct(record actualTeamRecord) float64 {
+	total := record.Wins + record.Losses + record.Ties
+	if total == 0 {
+		return 0
+	}
+	return (float64(record.Wins) + float64(record.Ties)*0.5) / float64(total)
+}
+
+func actualPointDifferential(record actualTeamRecord) float64 {
+	return float64(record.PointsFor - record.PointsAgainst)
+}
@@ -0,0 +1,316 @@
+// iCalendar feed handlers
+
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/ical"
+	"gamescript/internal/validation"
+)
+
+
+// Expected event lengths used for DTEND, since games only store a start time
+const (
+	nflGameDuration = 3*time.Hour + 30*time.Minute
+	nbaGameDuration = 2*time.Hour + 30*time.Minute
+)
+
+type calendarGame struct {
+	ID         int
+	ESPNID     *string
+	SportID    int
+	SportShort string
+	StartTime  time.Time
+	Week       *int
+	Location   *string
+	Primetime  *string
+	Network    *string
+	Status     *string
+	HomeScore  *int
+	AwayScore  *int
+	HomeAbbr   string
+	HomeCity   string
+	HomeName   string
+	AwayAbbr   string
+	AwayCity   string
+	AwayName   string
+}
+
+func getTeamGamesCalendar(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		teamID, err := validation.PathID(c, "team_id")
+		if err != nil {
+			return err
+		}
+
+		var city, name string
+		var startYear int
+		var endYear *int
+		err = db.Conn.QueryRow(`
+			SELECT team.city, team.name, season.start_year, season.end_year
+			FROM teams team
+			JOIN seasons season ON team.season_id = season.id
+			WHERE team.id = $1
+		`, teamID).Scan(&city, &name, &startYear, &endYear)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.TeamNotFound, "Team not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		games, err := getCalendarGames(db, `game.home_team_id = $1 OR game.away_team_id = $1`, teamID)
+		if err != nil {
+			return err
+		}
+
+		calendar := ical.Calendar{
+			Name:        fmt.Sprintf("%s %s %s", city, name, calendarSeasonLabel(startYear, endYear)),
+			Description: fmt.Sprintf("%s %s schedule", city, name),
+			Events:      buildCalendarEvents(games),
+		}
+
+		return sendCalendar(c, calendar, fmt.Sprintf("team-%d-games.ics", teamID))
+	}
+}
+
+func getSeasonGamesCalendar(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		seasonID, err := validation.PathID(c, "season_id")
+		if err != nil {
+			return err
+		}
+
+		var sportShortName string
+		var startYear int
+		var endYear *int
+		err = db.Conn.QueryRow(`
+			SELECT sport.short_name, season.start_year, season.end_year
+			FROM seasons season
+			JOIN sports sport ON season.sport_id = sport.id
+			WHERE season.id = $1
+		`, seasonID).Scan(&sportShortName, &startYear, &endYear)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.SeasonNotFound, "Season not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("%s %s", sportShortName, calendarSeasonLabel(startYear, endYear))
+
+		// Optional comma-separated team filter, by team ID or abbreviation
+		var games []calendarGame
+		if teamsParam := strings.TrimSpace(c.Query("teams")); teamsParam != "" {
+			teamIDs, abbreviations, err := resolveCalendarTeams(db, seasonID, teamsParam)
+			if err != nil {
+				return err
+			}
+
+			games, err = getCalendarGames(db, `game.season_id = $1 AND (game.home_team_id = ANY($2) OR game.away_team_id = ANY($2))`, seasonID, pq.Array(teamIDs))
+			if err != nil {
+				return err
+			}
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(abbreviations, ", "))
+		} else {
+			games, err = getCalendarGames(db, `game.season_id = $1`, seasonID)
+			if err != nil {
+				return err
+			}
+		}
+
+		calendar := ical.Calendar{
+			Name:        name,
+			Description: name + " schedule",
+			Events:      buildCalendarEvents(games),
+		}
+
+		return sendCalendar(c, calendar, fmt.Sprintf("season-%d-games.ics", seasonID))
+	}
+}
+
+// Resolves a teams query parameter to team IDs in the season, accepting IDs or abbreviations
+func resolveCalendarTeams(db *database.DB, seasonID int, teamsParam string) ([]int, []string, error) {
+	rows, err := db.Query(`SELECT id, abbreviation FROM teams WHERE season_id = $1`, seasonID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int]string)
+	byAbbreviation := make(map[string]int)
+	for rows.Next() {
+		var id int
+		var abbreviation string
+		if err := rows.Scan(&id, &abbreviation); err != nil {
+			return nil, nil, err
+		}
+		byID[id] = abbreviation
+		byAbbreviation[strings.ToUpper(abbreviation)] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var teamIDs []int
+	var abbreviations []string
+	seen := make(map[int]bool)
+	for _, value := range strings.Split(teamsParam, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		teamID, exists := byAbbreviation[strings.ToUpper(value)]
+		if !exists {
+			id, err := strconv.Atoi(value)
+			if err != nil || byID[id] == "" {
+				return nil, nil, apperror.Newf(apperror.InvalidParameter, "Team %s not found in this season", value)
+			}
+			teamID = id
+		}
+
+		if !seen[teamID] {
+			seen[teamID] = true
+			teamIDs = append(teamIDs, teamID)
+			abbreviations = append(abbreviations, byID[teamID])
+		}
+	}
+
+	if len(teamIDs) == 0 {
+		return nil, nil, apperror.New(apperror.InvalidParameter, "No teams given")
+	}
+
+	return teamIDs, abbreviations, nil
+}
+
+// Gets games matching a WHERE clause with the team and sport details needed for calendar events
+func getCalendarGames(db *database.DB, where string, args ...interface{}) ([]calendarGame, error) {
+	rows, err := db.Query(`
+		SELECT
+			game.id, game.espn_id, season.sport_id, sport.short_name,
+			game.start_time, game.week, game.location, game.primetime, game.network,
+			game.status, game.home_score, game.away_score,
+			ht.abbreviation, ht.city, ht.name,
+			at.abbreviation, at.city, at.name
+		FROM games game
+		JOIN seasons season ON game.season_id = season.id
+		JOIN sports sport ON season.sport_id = sport.id
+		JOIN teams ht ON game.home_team_id = ht.id
+		JOIN teams at ON game.away_team_id = at.id
+		WHERE `+where+`
+		ORDER BY game.start_time, game.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []calendarGame
+	for rows.Next() {
+		var game calendarGame
+		err := rows.Scan(
+			&game.ID, &game.ESPNID, &game.SportID, &game.SportShort,
+			&game.StartTime, &game.Week, &game.Location, &game.Primetime, &game.Network,
+			&game.Status, &game.HomeScore, &game.AwayScore,
+			&game.HomeAbbr, &game.HomeCity, &game.HomeName,
+			&game.AwayAbbr, &game.AwayCity, &game.AwayName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
+
+func buildCalendarEvents(games []calendarGame) []ical.Event {
+	events := make([]ical.Event, 0, len(games))
+	for _, game := range games {
+		// Times are stored in UTC without a zone
+		start := time.Date(game.StartTime.Year(), game.StartTime.Month(), game.StartTime.Day(),
+			game.StartTime.Hour(), game.StartTime.Minute(), game.StartTime.Second(), 0, time.UTC)
+
+		duration := nflGameDuration
+		if game.SportID == 2 {
+			duration = nbaGameDuration
+		}
+
+		// Stable across reschedules so calendar apps move the existing event
+		uid := ical.UID(strings.ToLower(game.SportShort), "game-"+strconv.Itoa(game.ID))
+		if game.ESPNID != nil && *game.ESPNID != "" {
+			uid = ical.UID(strings.ToLower(game.SportShort), *game.ESPNID)
+		}
+
+		summary := fmt.Sprintf("%s @ %s", game.AwayAbbr, game.HomeAbbr)
+		if game.Status != nil && *game.Status == "final" && game.HomeScore != nil && game.AwayScore != nil {
+			summary = fmt.Sprintf("%s %d @ %s %d (Final)", game.AwayAbbr, *game.AwayScore, game.HomeAbbr, *game.HomeScore)
+		}
+
+		var details []string
+		details = append(details, fmt.Sprintf("%s %s at %s %s", game.AwayCity, game.AwayName, game.HomeCity, game.HomeName))
+		if game.Week != nil {
+			details = append(details, fmt.Sprintf("Week %d", *game.Week))
+		}
+		if game.Network != nil && *game.Network != "" {
+			details = append(details, "TV: "+*game.Network)
+		}
+
+		var categories []string
+		if game.Primetime != nil && *game.Primetime != "" {
+			details = append(details, "Primetime: "+strings.ReplaceAll(*game.Primetime, ",", ", "))
+			for _, label := range strings.Split(*game.Primetime, ",") {
+				if label = strings.TrimSpace(label); label != "" {
+					categories = append(categories, label)
+				}
+			}
+		}
+
+		event := ical.Event{
+			UID:         uid,
+			Start:       start,
+			End:         start.Add(duration),
+			Summary:     summary,
+			Description: strings.Join(details, "\n"),
+			Categories:  categories,
+		}
+		if game.Location != nil {
+			event.Location = *game.Location
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func calendarSeasonLabel(startYear int, endYear *int) string {
+	if endYear != nil {
+		return fmt.Sprintf("%d-%02d", startYear, *endYear%100)
+	}
+	return strconv.Itoa(startYear)
+}
+
+func sendCalendar(c *fiber.Ctx, calendar ical.Calendar, filename string) error {
+	var buffer bytes.Buffer
+	if err := ical.Write(&buffer, calendar, time.Now()); err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, filename))
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	return c.Send(buffer.Bytes())
+}
@@ -0,0 +1,250 @@
+// Scenario share card handlers
+
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"sort"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/playoffs"
+	"gamescript/internal/render"
+	"gamescript/internal/standings"
+	"gamescript/internal/validation"
+)
+
+
+var (
+	cardLogoCache     *render.LogoCache
+	cardLogoCacheOnce sync.Once
+)
+
+// Shared logo cache, created on first use so LOGO_CACHE_DIR from .env is honored
+func getCardLogoCache() *render.LogoCache {
+	cardLogoCacheOnce.Do(func() {
+		cardLogoCache = render.NewLogoCache()
+	})
+	return cardLogoCache
+}
+
+func getScenarioCard(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sID, err := validation.PathID(c, "scenario_id")
+		if err != nil {
+			return err
+		}
+
+		scenario, err := loadComparedScenario(db, sID, c)
+		if err != nil {
+			return err
+		}
+
+		var sportShortName string
+		var startYear int
+		var endYear *int
+		err = db.Conn.QueryRow(`
+			SELECT sport.short_name, season.start_year, season.end_year
+			FROM seasons season
+			JOIN sports sport ON season.sport_id = sport.id
+			WHERE season.id = $1
+		`, scenario.SeasonID).Scan(&sportShortName, &startYear, &endYear)
+		if err != nil {
+			return apperror.New(apperror.Internal, "Failed to retrieve sport information").WithCause(err)
+		}
+
+		teams, err := getCardTeams(db, scenario.SeasonID)
+		if err != nil {
+			return err
+		}
+
+		card := render.Card{Title: scenario.Name}
+		if endYear != nil {
+			card.Subtitle = fmt.Sprintf("%s %d-%02d Playoff Picture", sportShortName, startYear, *endYear%100)
+		} else {
+			card.Subtitle = fmt.Sprintf("%s %d Playoff Picture", sportShortName, startYear)
+		}
+
+		// Conference seeds with current records
+		seeds := make(map[int]int)
+		if scenario.SportID == 1 {
+			nflStandings, err := standings.CalculateNFLStandings(db, scenario.ID, scenario.SeasonID)
+			if err != nil {
+				return err
+			}
+			for i, conference := range []struct {
+				name  string
+				seeds []standings.NFLPlayoffSeed
+			}{{"AFC", nflStandings.AFC.PlayoffSeeds}, {"NFC", nflStandings.NFC.PlayoffSeeds}} {
+				card.Conferences[i].Name = conference.name
+				for _, seed := range conference.seeds {
+					team := teams[seed.Team.TeamID]
+					team.Record = fmt.Sprintf("%d-%d", seed.Team.Wins, seed.Team.Losses)
+					if seed.Team.Ties > 0 {
+						team.Record += fmt.Sprintf("-%d", seed.Team.Ties)
+					}
+					teams[seed.Team.TeamID] = team
+					seeds[seed.Team.TeamID] = seed.Seed
+					card.Conferences[i].Seeds = append(card.Conferences[i].Seeds, render.CardSeed{Seed: seed.Seed, Team: team})
+				}
+			}
+		} else if scenario.SportID == 2 {
+			nbaStandings, err := standings.CalculateNBAStandings(db, scenario.ID, scenario.SeasonID)
+			if err != nil {
+				return err
+			}
+			for i, conference := range []struct {
+				name  string
+				seeds []standings.NBAPlayoffSeed
+			}{{"Eastern", nbaStandings.Eastern.PlayoffSeeds}, {"Western", nbaStandings.Western.PlayoffSeeds}} {
+				card.Conferences[i].Name = conference.name
+				for _, seed := range conference.seeds {
+					team := teams[seed.Team.TeamID]
+					team.Record = fmt.Sprintf("%d-%d", seed.Team.Wins, seed.Team.Losses)
+					teams[seed.Team.TeamID] = team
+					seeds[seed.Team.TeamID] = seed.Seed
+					card.Conferences[i].Seeds = append(card.Conferences[i].Seeds, render.CardSeed{Seed: seed.Seed, Team: team})
+				}
+			}
+		} else {
+			return apperror.New(apperror.UnsupportedSport, "Share cards are not supported for this sport")
+		}
+
+		// Bracket rounds and champion
+		slots, err := getBracketSlots(db, scenario.ID)
+		if err != nil {
+			return err
+		}
+		card.Rounds = buildCardRounds(slots, scenario.SportID, teams, seeds)
+		if championID := findChampion(slots, scenario.SportID); championID != nil {
+			champion := teams[*championID]
+			card.Champion = &champion
+		}
+
+		img, err := render.RenderCard(card)
+		if err != nil {
+			return err
+		}
+
+		var buffer bytes.Buffer
+		if err := png.Encode(&buffer, img); err != nil {
+			return err
+		}
+
+		c.Set(fiber.HeaderContentType, "image/png")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		return c.Send(buffer.Bytes())
+	}
+}
+
+// Gets every team in a season with colors and cached logos
+func getCardTeams(db *database.DB, seasonID int) (map[int]render.CardTeam, error) {
+	rows, err := db.Query(`
+		SELECT id, abbreviation, city, name, primary_color, secondary_color, logo_url
+		FROM teams
+		WHERE season_id = $1
+	`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make(map[int]render.CardTeam)
+	logoURLs := make(map[int]string)
+	for rows.Next() {
+		var team render.CardTeam
+		var primaryColor, secondaryColor, logoURL *string
+		if err := rows.Scan(&team.ID, &team.Abbreviation, &team.City, &team.Name, &primaryColor, &secondaryColor, &logoURL); err != nil {
+			return nil, err
+		}
+		if primaryColor != nil {
+			team.PrimaryColor = *primaryColor
+		}
+		if secondaryColor != nil {
+			team.SecondaryColor = *secondaryColor
+		}
+		if logoURL != nil {
+			logoURLs[team.ID] = *logoURL
+		}
+		teams[team.ID] = team
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(logoURLs))
+	for _, url := range logoURLs {
+		urls = append(urls, url)
+	}
+	logos := getCardLogoCache().GetAll(urls)
+	for teamID, url := range logoURLs {
+		team := teams[teamID]
+		team.Logo = logos[url]
+		teams[teamID] = team
+	}
+
+	return teams, nil
+}
+
+// Groups bracket slots into rounds, skipping individual games within a series
+func buildCardRounds(slots map[string]bracketSlot, sportID int, teams map[int]render.CardTeam, seeds map[int]int) []render.CardRound {
+	roundNames := map[int]string{
+		playoffs.RoundWildCard:               "Wild Card",
+		playoffs.RoundDivisional:             "Divisional",
+		playoffs.RoundConferenceChampionship: "Conference",
+		playoffs.RoundSuperBowl:              "Super Bowl",
+	}
+	finalRound := playoffs.RoundSuperBowl
+	if sportID == 2 {
+		roundNames = map[int]string{
+			playoffs.RoundPlayInA:                 "Play-In",
+			playoffs.RoundPlayInB:                 "Play-In Final",
+			playoffs.RoundConferenceQuarterfinals: "First Round",
+			playoffs.RoundConferenceSemifinals:    "Semifinals",
+			playoffs.RoundConferenceFinals:        "Conf. Finals",
+			playoffs.RoundNBAFinals:               "NBA Finals",
+		}
+		finalRound = playoffs.RoundNBAFinals
+	}
+
+	byRound := make(map[int][]bracketSlot)
+	for _, slot := range slots {
+		if slot.GameNumber > 0 {
+			continue
+		}
+		byRound[slot.Round] = append(byRound[slot.Round], slot)
+	}
+
+	var rounds []render.CardRound
+	for round := 1; round <= finalRound; round++ {
+		roundSlots := byRound[round]
+		if len(roundSlots) == 0 {
+			continue
+		}
+		sort.Slice(roundSlots, func(i, j int) bool {
+			if roundSlots[i].Conference != roundSlots[j].Conference {
+				return roundSlots[i].Conference < roundSlots[j].Conference
+			}
+			return roundSlots[i].Order < roundSlots[j].Order
+		})
+
+		cardRound := render.CardRound{Name: roundNames[round]}
+		for _, slot := range roundSlots {
+			cardRound.Matchups = append(cardRound.Matchups, render.CardMatchup{
+				HigherSeed: seeds[slot.HigherSeedTeamID],
+				LowerSeed:  seeds[slot.LowerSeedTeamID],
+				HigherTeam: teams[slot.HigherSeedTeamID],
+				LowerTeam:  teams[slot.LowerSeedTeamID],
+				WinnerID:   slot.PickedTeamID,
+			})
+		}
+		rounds = append(rounds, cardRound)
+	}
+
+	return rounds
+}
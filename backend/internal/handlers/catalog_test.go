@@ -0,0 +1,155 @@
+package handlers
+
+import (
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "gamescript/internal/apperror"
+    "gamescript/internal/middleware"
+    "gamescript/internal/models"
+    "gamescript/internal/store"
+
+    "github.com/gofiber/fiber/v2"
+    "github.com/stretchr/testify/assert"
+)
+
+// Sets up the store-backed routes over an in-memory store with one NFL season, two teams, and two games
+func setupMemoryApp(t *testing.T) (*fiber.App, *store.Memory) {
+    t.Helper()
+    data := store.NewMemory()
+    data.AddSport(models.Sport{ID: 1, Name: "National Football League", ShortName: "NFL"})
+    data.AddSeason(models.Season{ID: 1, SportID: 1, StartYear: 2024})
+    data.AddSeason(models.Season{ID: 2, SportID: 1, StartYear: 2025, IsActive: true})
+    data.AddTeam(models.Team{ID: 1, SportID: 1, SeasonID: 2, Abbreviation: "KC", City: "Kansas City", Name: "Chiefs"})
+    data.AddTeam(models.Team{ID: 2, SportID: 1, SeasonID: 2, Abbreviation: "BAL", City: "Baltimore", Name: "Ravens"})
+
+    kickoff := time.Date(2025, 9, 5, 0, 20, 0, 0, time.UTC)
+    week1, week2 := 1, 2
+    data.AddGame(models.Game{ID: 11, SeasonID: 2, HomeTeamID: 2, AwayTeamID: 1, StartTime: kickoff.Add(7 * 24 * time.Hour), Week: &week2})
+    data.AddGame(models.Game{ID: 10, SeasonID: 2, HomeTeamID: 1, AwayTeamID: 2, StartTime: kickoff, Week: &week1})
+
+    app := fiber.New(fiber.Config{ErrorHandler: apperror.Handler})
+    api := app.Group("/api")
+    setupCatalogRoutes(api, data)
+    api.Get("/picks/scenarios/:scenario_id", middleware.OptionalAuth, getPicksByScenario(data))
+    return app, data
+}
+
+func getJSON(t *testing.T, app *fiber.App, request *http.Request, body interface{}) int {
+    t.Helper()
+    resp, err := app.Test(request)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer resp.Body.Close()
+    payload, err := io.ReadAll(resp.Body)
+    if err != nil {
+        t.Fatal(err)
+    }
+    checkContract(t, request.Method, request.URL.RequestURI(), resp, payload)
+    if err := json.Unmarshal(payload, body); err != nil {
+        t.Fatal(err)
+    }
+    return resp.StatusCode
+}
+
+func TestGetSeasonsFromStore(t *testing.T) {
+    app, _ := setupMemoryApp(t)
+
+    var seasons []map[string]interface{}
+    status := getJSON(t, app, httptest.NewRequest("GET", "/api/sports/1/seasons", nil), &seasons)
+
+    assert.Equal(t, 200, status)
+    if assert.Len(t, seasons, 2) {
+        assert.Equal(t, float64(2025), seasons[0]["start_year"], "Newest season should be first")
+    }
+}
+
+func TestGetTeamsBySeasonFromStore(t *testing.T) {
+    app, _ := setupMemoryApp(t)
+
+    var teams []map[string]interface{}
+    status := getJSON(t, app, httptest.NewRequest("GET", "/api/seasons/2/teams", nil), &teams)
+
+    assert.Equal(t, 200, status)
+    if assert.Len(t, teams, 2) {
+        assert.Equal(t, "Chiefs", teams[0]["name"])
+        assert.Equal(t, "Ravens", teams[1]["name"])
+    }
+}
+
+func TestGetGamesFromStore(t *testing.T) {
+    app, _ := setupMemoryApp(t)
+
+    var games []map[string]interface{}
+    status := getJSON(t, app, httptest.NewRequest("GET", "/api/seasons/2/games", nil), &games)
+
+    assert.Equal(t, 200, status)
+    if assert.Len(t, games, 2) {
+        assert.Equal(t, float64(10), games[0]["id"], "Games should be ordered by start time")
+        homeTeam, _ := games[0]["home_team"].(map[string]interface{})
+        assert.Equal(t, "KC", homeTeam["abbreviation"])
+    }
+
+    status = getJSON(t, app, httptest.NewRequest("GET", "/api/seasons/2/weeks/2/games", nil), &games)
+    assert.Equal(t, 200, status)
+    if assert.Len(t, games, 1) {
+        assert.Equal(t, float64(11), games[0]["id"])
+    }
+}
+
+func TestGetGameErrorsFromStore(t *testing.T) {
+    app, _ := setupMemoryApp(t)
+
+    var body map[string]interface{}
+    assert.Equal(t, 404, getJSON(t, app, httptest.NewRequest("GET", "/api/games/99", nil), &body))
+    assert.Equal(t, "GAME_NOT_FOUND", body["code"])
+    assert.Equal(t, "Game not found", body["detail"])
+    assert.Equal(t, "/api/games/99", body["instance"])
+
+    body = nil
+    assert.Equal(t, 400, getJSON(t, app, httptest.NewRequest("GET", "/api/games/abc", nil), &body))
+    assert.Equal(t, "INVALID_PARAMETER", body["code"])
+    assert.Equal(t, "Invalid game ID", body["error"])
+
+    body = nil
+    assert.Equal(t, 400, getJSON(t, app, httptest.NewRequest("GET", "/api/seasons/0/weeks/60/games", nil), &body))
+    assert.Equal(t, "INVALID_PARAMETER", body["code"])
+    if errors, ok := body["errors"].([]interface{}); assert.True(t, ok) && assert.Len(t, errors, 2) {
+        assert.Equal(t, "season_id", errors[0].(map[string]interface{})["field"])
+        assert.Equal(t, "Invalid week, must be between 1 and 53", errors[1].(map[string]interface{})["message"])
+    }
+}
+
+func TestGetPicksByScenarioFromStore(t *testing.T) {
+    app, data := setupMemoryApp(t)
+
+    owner, member := "owner-session", "viewer-session"
+    data.AddScenario(models.Scenario{ID: 5, SessionToken: &owner, Name: "Private", SportID: 1, SeasonID: 2, Mode: ScenarioModePrediction})
+    data.AddScenarioMember(5, 0, member, ScenarioRoleViewer)
+    homeTeamID := 1
+    data.AddPick(models.Pick{ID: 1, ScenarioID: 5, GameID: 10, PickedTeamID: &homeTeamID})
+
+    request := func(sessionToken string) *http.Request {
+        req := httptest.NewRequest("GET", "/api/picks/scenarios/5", nil)
+        req.AddCookie(&http.Cookie{Name: "session_token", Value: sessionToken})
+        return req
+    }
+
+    var picks []map[string]interface{}
+    status := getJSON(t, app, request(member), &picks)
+    assert.Equal(t, 200, status)
+    if assert.Len(t, picks, 1) {
+        pickedTeam, _ := picks[0]["picked_team"].(map[string]interface{})
+        assert.Equal(t, "KC", pickedTeam["abbreviation"])
+        assert.Equal(t, true, picks[0]["is_locked"], "Started games should be locked in prediction scenarios")
+    }
+
+    var body map[string]interface{}
+    assert.Equal(t, 403, getJSON(t, app, request("stranger-session"), &body))
+    assert.Equal(t, 200, getJSON(t, app, request(owner), &picks))
+}
@@ -0,0 +1,562 @@
+// Scenario comparison handlers
+
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/playoffs"
+	"gamescript/internal/standings"
+	"gamescript/internal/validation"
+)
+
+
+// Per-team outcome of a scenario used for comparisons
+type teamOutcome struct {
+	TeamID     int
+	TeamAbbr   string
+	Conference string
+	Wins       int
+	Losses     int
+	Ties       int
+	Seed       int
+	DraftPick  int
+}
+
+// Single playoff matchup or series slot used for comparisons
+type bracketSlot struct {
+	Round            int
+	Conference       string
+	Order            int
+	GameNumber       int
+	HigherSeedTeamID int
+	LowerSeedTeamID  int
+	PickedTeamID     *int
+}
+
+// Differences between two scenarios of the same season, from A to B
+type CompareScenariosResponse struct {
+	ScenarioA				ScenarioRef					`json:"scenario_a"`
+	ScenarioB				ScenarioRef					`json:"scenario_b"`
+	SeasonID				int							`json:"season_id"`
+	PickDifferences			[]PickDifferenceResponse	`json:"pick_differences"`
+	TeamDifferences			[]TeamDifferenceResponse	`json:"team_differences"`
+	PlayoffDifferences		[]PlayoffDifferenceResponse	`json:"playoff_differences"`
+	Champion				ChampionComparison			`json:"champion"`
+	DraftOrderDifferences	[]DraftOrderDifference		`json:"draft_order_differences"`
+}
+
+type ScenarioRef struct {
+	ID		int		`json:"id"`
+	Name	string	`json:"name"`
+}
+
+type PickDifferenceResponse struct {
+	GameID			int				`json:"game_id"`
+	Week			*int			`json:"week"`
+	StartTime		time.Time		`json:"start_time"`
+	Status			*string			`json:"status"`
+	HomeTeamID		int				`json:"home_team_id"`
+	HomeTeamAbbr	string			`json:"home_team_abbr"`
+	AwayTeamID		int				`json:"away_team_id"`
+	AwayTeamAbbr	string			`json:"away_team_abbr"`
+	A				*ComparedPick	`json:"a"`
+	B				*ComparedPick	`json:"b"`
+}
+
+// A scenario's pick for a game; null when the scenario has none
+type ComparedPick struct {
+	PickedTeamID		*int	`json:"picked_team_id"`
+	PredictedHomeScore	*int	`json:"predicted_home_score"`
+	PredictedAwayScore	*int	`json:"predicted_away_score"`
+}
+
+type TeamDifferenceResponse struct {
+	TeamID			int				`json:"team_id"`
+	TeamAbbr		string			`json:"team_abbr"`
+	Conference		string			`json:"conference"`
+	A				ComparedOutcome	`json:"a"`
+	B				ComparedOutcome	`json:"b"`
+	WinsDelta		int				`json:"wins_delta"`
+	LossesDelta		int				`json:"losses_delta"`
+	TiesDelta		int				`json:"ties_delta"`
+	SeedDelta		int				`json:"seed_delta"`
+	DraftPickDelta	int				`json:"draft_pick_delta"`
+}
+
+type ComparedOutcome struct {
+	Wins		int	`json:"wins"`
+	Losses		int	`json:"losses"`
+	Ties		int	`json:"ties"`
+	Seed		int	`json:"seed"`
+	DraftPick	int	`json:"draft_pick"`
+}
+
+type PlayoffDifferenceResponse struct {
+	Round		int				`json:"round"`
+	Conference	*string			`json:"conference"`
+	Order		int				`json:"order"`
+	A			*ComparedSlot	`json:"a"`
+	B			*ComparedSlot	`json:"b"`
+}
+
+// A scenario's matchup or series at a bracket position; null when the scenario has none
+type ComparedSlot struct {
+	HigherSeedTeamID	int		`json:"higher_seed_team_id"`
+	LowerSeedTeamID		int		`json:"lower_seed_team_id"`
+	PickedTeamID		*int	`json:"picked_team_id"`
+}
+
+type ChampionComparison struct {
+	A	*int	`json:"a"`
+	B	*int	`json:"b"`
+}
+
+type DraftOrderDifference struct {
+	Pick		int		`json:"pick"`
+	ATeamID		*int	`json:"a_team_id"`
+	ATeamAbbr	*string	`json:"a_team_abbr"`
+	BTeamID		*int	`json:"b_team_id"`
+	BTeamAbbr	*string	`json:"b_team_abbr"`
+}
+
+type comparedScenario struct {
+	ID       int
+	Name     string
+	SportID  int
+	SeasonID int
+}
+
+func compareScenarios(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		aID, bID := params.QueryID("a"), params.QueryID("b")
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		scenarioA, err := loadComparedScenario(db, aID, c)
+		if err != nil {
+			return err
+		}
+		scenarioB, err := loadComparedScenario(db, bID, c)
+		if err != nil {
+			return err
+		}
+
+		if scenarioA.SeasonID != scenarioB.SeasonID {
+			return apperror.New(apperror.SeasonMismatch, "Scenarios must belong to the same season")
+		}
+
+		pickDifferences, err := getPickDifferences(db, scenarioA.ID, scenarioB.ID, scenarioA.SeasonID)
+		if err != nil {
+			return err
+		}
+
+		outcomesA, draftA, err := getTeamOutcomes(db, scenarioA)
+		if err != nil {
+			return err
+		}
+		outcomesB, draftB, err := getTeamOutcomes(db, scenarioB)
+		if err != nil {
+			return err
+		}
+
+		bracketA, err := getBracketSlots(db, scenarioA.ID)
+		if err != nil {
+			return err
+		}
+		bracketB, err := getBracketSlots(db, scenarioB.ID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(CompareScenariosResponse{
+			ScenarioA:          ScenarioRef{ID: scenarioA.ID, Name: scenarioA.Name},
+			ScenarioB:          ScenarioRef{ID: scenarioB.ID, Name: scenarioB.Name},
+			SeasonID:           scenarioA.SeasonID,
+			PickDifferences:    pickDifferences,
+			TeamDifferences:    compareTeamOutcomes(outcomesA, outcomesB),
+			PlayoffDifferences: compareBracketSlots(bracketA, bracketB),
+			Champion: ChampionComparison{
+				A: findChampion(bracketA, scenarioA.SportID),
+				B: findChampion(bracketB, scenarioB.SportID),
+			},
+			DraftOrderDifferences: compareDraftOrders(draftA, draftB, outcomesA, outcomesB),
+		})
+	}
+}
+
+// Loads a scenario the current user is allowed to view
+func loadComparedScenario(db *database.DB, scenarioID int, c *fiber.Ctx) (*comparedScenario, error) {
+	access, err := authorizeScenario(db, scenarioID, ScenarioRoleViewer, c)
+	if err != nil {
+		if apperror.Is(err, apperror.ScenarioNotFound) {
+			return nil, apperror.Newf(apperror.ScenarioNotFound, "Scenario %d not found", scenarioID)
+		}
+		return nil, err
+	}
+
+	return &comparedScenario{
+		ID:       access.ID,
+		Name:     access.Name,
+		SportID:  access.SportID,
+		SeasonID: access.SeasonID,
+	}, nil
+}
+
+func getPickDifferences(db *database.DB, scenarioAID int, scenarioBID int, seasonID int) ([]PickDifferenceResponse, error) {
+	query := `
+		SELECT
+			game.id, game.week, game.start_time, game.status,
+			game.home_team_id, game.away_team_id,
+			home_team.abbreviation, away_team.abbreviation,
+			pick_a.id IS NOT NULL, pick_a.picked_team_id, pick_a.predicted_home_score, pick_a.predicted_away_score,
+			pick_b.id IS NOT NULL, pick_b.picked_team_id, pick_b.predicted_home_score, pick_b.predicted_away_score
+		FROM games game
+		JOIN teams home_team ON game.home_team_id = home_team.id
+		JOIN teams away_team ON game.away_team_id = away_team.id
+		LEFT JOIN picks pick_a ON pick_a.game_id = game.id AND pick_a.scenario_id = $1
+		LEFT JOIN picks pick_b ON pick_b.game_id = game.id AND pick_b.scenario_id = $2
+		WHERE game.season_id = $3
+		AND (pick_a.id IS NOT NULL OR pick_b.id IS NOT NULL)
+		AND (
+			pick_a.id IS NULL OR pick_b.id IS NULL
+			OR pick_a.picked_team_id IS DISTINCT FROM pick_b.picked_team_id
+			OR pick_a.predicted_home_score IS DISTINCT FROM pick_b.predicted_home_score
+			OR pick_a.predicted_away_score IS DISTINCT FROM pick_b.predicted_away_score
+		)
+		ORDER BY game.start_time
+	`
+
+	rows, err := db.Query(query, scenarioAID, scenarioBID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	differences := []PickDifferenceResponse{}
+	for rows.Next() {
+		var gameID, homeTeamID, awayTeamID int
+		var week *int
+		var startTime time.Time
+		var status *string
+		var homeAbbr, awayAbbr string
+		var hasPickA, hasPickB bool
+		var pickedA, homeScoreA, awayScoreA, pickedB, homeScoreB, awayScoreB *int
+
+		err := rows.Scan(
+			&gameID, &week, &startTime, &status,
+			&homeTeamID, &awayTeamID,
+			&homeAbbr, &awayAbbr,
+			&hasPickA, &pickedA, &homeScoreA, &awayScoreA,
+			&hasPickB, &pickedB, &homeScoreB, &awayScoreB,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		differences = append(differences, PickDifferenceResponse{
+			GameID:       gameID,
+			Week:         week,
+			StartTime:    startTime,
+			Status:       status,
+			HomeTeamID:   homeTeamID,
+			HomeTeamAbbr: homeAbbr,
+			AwayTeamID:   awayTeamID,
+			AwayTeamAbbr: awayAbbr,
+			A:            formatComparedPick(hasPickA, pickedA, homeScoreA, awayScoreA),
+			B:            formatComparedPick(hasPickB, pickedB, homeScoreB, awayScoreB),
+		})
+	}
+
+	return differences, nil
+}
+
+func formatComparedPick(hasPick bool, pickedTeamID *int, predictedHomeScore *int, predictedAwayScore *int) *ComparedPick {
+	if !hasPick {
+		return nil
+	}
+	return &ComparedPick{
+		PickedTeamID:       pickedTeamID,
+		PredictedHomeScore: predictedHomeScore,
+		PredictedAwayScore: predictedAwayScore,
+	}
+}
+
+// Runs the standings engine for a scenario and flattens the result per team
+func getTeamOutcomes(db *database.DB, scenario *comparedScenario) (map[int]teamOutcome, []int, error) {
+	outcomes := make(map[int]teamOutcome)
+	var draftOrder []int
+
+	if scenario.SportID == 1 {
+		nflStandings, err := standings.CalculateNFLStandings(db, scenario.ID, scenario.SeasonID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, conference := range []standings.NFLConferenceStandings{nflStandings.AFC, nflStandings.NFC} {
+			for _, seed := range conference.PlayoffSeeds {
+				outcomes[seed.Team.TeamID] = teamOutcome{
+					TeamID:     seed.Team.TeamID,
+					TeamAbbr:   seed.Team.TeamAbbr,
+					Conference: seed.Team.Conference,
+					Wins:       seed.Team.Wins,
+					Losses:     seed.Team.Losses,
+					Ties:       seed.Team.Ties,
+					Seed:       seed.Seed,
+				}
+			}
+		}
+		for _, pick := range nflStandings.DraftOrder {
+			draftOrder = append(draftOrder, pick.Team.TeamID)
+		}
+	} else if scenario.SportID == 2 {
+		nbaStandings, err := standings.CalculateNBAStandings(db, scenario.ID, scenario.SeasonID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, conference := range []standings.NBAConferenceStandings{nbaStandings.Eastern, nbaStandings.Western} {
+			for _, seed := range conference.PlayoffSeeds {
+				outcomes[seed.Team.TeamID] = teamOutcome{
+					TeamID:     seed.Team.TeamID,
+					TeamAbbr:   seed.Team.TeamAbbr,
+					Conference: seed.Team.Conference,
+					Wins:       seed.Team.Wins,
+					Losses:     seed.Team.Losses,
+					Seed:       seed.Seed,
+				}
+			}
+		}
+		for _, pick := range nbaStandings.DraftOrder {
+			draftOrder = append(draftOrder, pick.Team.TeamID)
+		}
+	} else {
+		return nil, nil, fmt.Errorf("standings not supported for sport %d", scenario.SportID)
+	}
+
+	for i, teamID := range draftOrder {
+		outcome := outcomes[teamID]
+		outcome.DraftPick = i + 1
+		outcomes[teamID] = outcome
+	}
+
+	return outcomes, draftOrder, nil
+}
+
+func compareTeamOutcomes(a map[int]teamOutcome, b map[int]teamOutcome) []TeamDifferenceResponse {
+	teamIDs := make([]int, 0, len(a))
+	for teamID := range a {
+		teamIDs = append(teamIDs, teamID)
+	}
+	sort.Ints(teamIDs)
+
+	differences := []TeamDifferenceResponse{}
+	for _, teamID := range teamIDs {
+		outcomeA := a[teamID]
+		outcomeB, exists := b[teamID]
+		if !exists {
+			continue
+		}
+
+		if outcomeA.Wins == outcomeB.Wins && outcomeA.Losses == outcomeB.Losses && outcomeA.Ties == outcomeB.Ties &&
+			outcomeA.Seed == outcomeB.Seed && outcomeA.DraftPick == outcomeB.DraftPick {
+			continue
+		}
+
+		differences = append(differences, TeamDifferenceResponse{
+			TeamID:         teamID,
+			TeamAbbr:       outcomeA.TeamAbbr,
+			Conference:     outcomeA.Conference,
+			A:              formatTeamOutcome(outcomeA),
+			B:              formatTeamOutcome(outcomeB),
+			WinsDelta:      outcomeB.Wins - outcomeA.Wins,
+			LossesDelta:    outcomeB.Losses - outcomeA.Losses,
+			TiesDelta:      outcomeB.Ties - outcomeA.Ties,
+			SeedDelta:      outcomeB.Seed - outcomeA.Seed,
+			DraftPickDelta: outcomeB.DraftPick - outcomeA.DraftPick,
+		})
+	}
+
+	return differences
+}
+
+func formatTeamOutcome(outcome teamOutcome) ComparedOutcome {
+	return ComparedOutcome{
+		Wins:      outcome.Wins,
+		Losses:    outcome.Losses,
+		Ties:      outcome.Ties,
+		Seed:      outcome.Seed,
+		DraftPick: outcome.DraftPick,
+	}
+}
+
+func compareDraftOrders(a []int, b []int, outcomesA map[int]teamOutcome, outcomesB map[int]teamOutcome) []DraftOrderDifference {
+	differences := []DraftOrderDifference{}
+
+	total := len(a)
+	if len(b) > total {
+		total = len(b)
+	}
+
+	for i := 0; i < total; i++ {
+		var teamA, teamB *int
+		var abbrA, abbrB *string
+		if i < len(a) {
+			teamA = &a[i]
+			abbr := outcomesA[a[i]].TeamAbbr
+			abbrA = &abbr
+		}
+		if i < len(b) {
+			teamB = &b[i]
+			abbr := outcomesB[b[i]].TeamAbbr
+			abbrB = &abbr
+		}
+		if teamA != nil && teamB != nil && *teamA == *teamB {
+			continue
+		}
+
+		differences = append(differences, DraftOrderDifference{
+			Pick:      i + 1,
+			ATeamID:   teamA,
+			ATeamAbbr: abbrA,
+			BTeamID:   teamB,
+			BTeamAbbr: abbrB,
+		})
+	}
+
+	return differences
+}
+
+// Collects every playoff matchup and series for a scenario, keyed by bracket position
+func getBracketSlots(db *database.DB, scenarioID int) (map[string]bracketSlot, error) {
+	slots := make(map[string]bracketSlot)
+
+	query := `
+		SELECT m.round, COALESCE(m.conference, ''), m.matchup_order, COALESCE(m.game_number, 0),
+			m.higher_seed_team_id, m.lower_seed_team_id, m.picked_team_id
+		FROM playoff_matchups m
+		JOIN playoff_states ps ON m.playoff_state_id = ps.id
+		WHERE ps.scenario_id = $1 AND m.playoff_series_id IS NULL
+		UNION ALL
+		SELECT s.round, COALESCE(s.conference, ''), s.series_order, 0,
+			s.higher_seed_team_id, s.lower_seed_team_id, s.picked_team_id
+		FROM playoff_series s
+		JOIN playoff_states ps ON s.playoff_state_id = ps.id
+		WHERE ps.scenario_id = $1
+	`
+
+	rows, err := db.Query(query, scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var slot bracketSlot
+		err := rows.Scan(&slot.Round, &slot.Conference, &slot.Order, &slot.GameNumber,
+			&slot.HigherSeedTeamID, &slot.LowerSeedTeamID, &slot.PickedTeamID)
+		if err != nil {
+			return nil, err
+		}
+		slots[bracketSlotKey(slot)] = slot
+	}
+
+	return slots, nil
+}
+
+func bracketSlotKey(slot bracketSlot) string {
+	return fmt.Sprintf("%d|%s|%d|%d", slot.Round, slot.Conference, slot.Order, slot.GameNumber)
+}
+
+func compareBracketSlots(a map[string]bracketSlot, b map[string]bracketSlot) []PlayoffDifferenceResponse {
+	keys := make(map[string]bracketSlot)
+	for key, slot := range a {
+		keys[key] = slot
+	}
+	for key, slot := range b {
+		keys[key] = slot
+	}
+
+	var positions []bracketSlot
+	for _, slot := range keys {
+		positions = append(positions, slot)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Round != positions[j].Round {
+			return positions[i].Round < positions[j].Round
+		}
+		if positions[i].Conference != positions[j].Conference {
+			return positions[i].Conference < positions[j].Conference
+		}
+		if positions[i].Order != positions[j].Order {
+			return positions[i].Order < positions[j].Order
+		}
+		return positions[i].GameNumber < positions[j].GameNumber
+	})
+
+	differences := []PlayoffDifferenceResponse{}
+	for _, position := range positions {
+		key := bracketSlotKey(position)
+		slotA, inA := a[key]
+		slotB, inB := b[key]
+
+		if inA && inB && slotA.HigherSeedTeamID == slotB.HigherSeedTeamID && slotA.LowerSeedTeamID == slotB.LowerSeedTeamID &&
+			equalIntPtr(slotA.PickedTeamID, slotB.PickedTeamID) {
+			continue
+		}
+
+		var conference *string
+		if position.Conference != "" {
+			conference = &position.Conference
+		}
+
+		differences = append(differences, PlayoffDifferenceResponse{
+			Round:      position.Round,
+			Conference: conference,
+			Order:      position.Order,
+			A:          formatBracketSlot(slotA, inA),
+			B:          formatBracketSlot(slotB, inB),
+		})
+	}
+
+	return differences
+}
+
+func formatBracketSlot(slot bracketSlot, exists bool) *ComparedSlot {
+	if !exists {
+		return nil
+	}
+	return &ComparedSlot{
+		HigherSeedTeamID: slot.HigherSeedTeamID,
+		LowerSeedTeamID:  slot.LowerSeedTeamID,
+		PickedTeamID:     slot.PickedTeamID,
+	}
+}
+
+// Returns the picked winner of the final round, if the bracket has one
+func findChampion(slots map[string]bracketSlot, sportID int) *int {
+	finalRound := playoffs.RoundSuperBowl
+	if sportID == 2 {
+		finalRound = playoffs.RoundNBAFinals
+	}
+
+	for _, slot := range slots {
+		if slot.Round == finalRound && slot.PickedTeamID != nil {
+			return slot.PickedTeamID
+		}
+	}
+	return nil
+}
+
+func equalIntPtr(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
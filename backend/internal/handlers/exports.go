@@ -0,0 +1,343 @@
+// Spreadsheet export handlers for standings and picks
+
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/standings"
+	"gamescript/internal/tabular"
+)
+
+
+// Returns the requested tabular format from ?format= or the Accept header, or "" for JSON
+func getExportFormat(c *fiber.Ctx) (string, error) {
+	if format := strings.ToLower(c.Query("format")); format != "" {
+		switch format {
+		case "json":
+			return "", nil
+		case tabular.FormatCSV, tabular.FormatTSV, tabular.FormatXLSX:
+			return format, nil
+		}
+		return "", apperror.New(apperror.InvalidParameter, "Invalid format. Must be one of: json, csv, tsv, xlsx")
+	}
+
+	switch c.Accepts(fiber.MIMEApplicationJSON, "text/csv", "text/tab-separated-values", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet") {
+	case "text/csv":
+		return tabular.FormatCSV, nil
+	case "text/tab-separated-values":
+		return tabular.FormatTSV, nil
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		return tabular.FormatXLSX, nil
+	}
+	return "", nil
+}
+
+// Sends tables as a file download, optionally limited to a single table chosen by ?table=
+func sendTables(c *fiber.Ctx, format string, filename string, tables []tabular.Table) error {
+	if tableName := c.Query("table"); tableName != "" {
+		var selected []tabular.Table
+		for _, table := range tables {
+			if tableKey(table.Name) == tableKey(tableName) {
+				selected = append(selected, table)
+			}
+		}
+		if len(selected) == 0 {
+			available := make([]string, len(tables))
+			for i, table := range tables {
+				available[i] = tableKey(table.Name)
+			}
+			return apperror.New(apperror.InvalidParameter, "Invalid table. Must be one of: "+strings.Join(available, ", "))
+		}
+		tables = selected
+	}
+
+	var buffer bytes.Buffer
+	if err := tabular.Write(&buffer, format, tables); err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, tabular.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	return c.Send(buffer.Bytes())
+}
+
+// Converts a table name to its ?table= key ("Draft Order" -> "draft_order")
+func tableKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+}
+
+func roundTo(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
+
+// Builds seed, division, and draft order tables for a scenario's standings as of a checkpoint
+func buildStandingsTables(db *database.DB, scenarioID int, seasonID int, sportID int, cutoff standings.Cutoff) ([]tabular.Table, error) {
+	if sportID == 1 {
+		nflStandings, err := standings.CalculateNFLStandingsAsOf(db, scenarioID, seasonID, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		return nflStandingsTables(nflStandings), nil
+	} else if sportID == 2 {
+		nbaStandings, err := standings.CalculateNBAStandingsAsOf(db, scenarioID, seasonID, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		return nbaStandingsTables(nbaStandings), nil
+	}
+	return nil, fmt.Errorf("standings not supported for sport %d", sportID)
+}
+
+func nflStandingsTables(nflStandings *standings.NFLStandings) []tabular.Table {
+	seeds := tabular.Table{
+		Name:   "Playoff Seeds",
+		Header: []string{"conference", "seed", "team_abbr", "team_city", "team_name", "division", "wins", "losses", "ties", "win_pct", "is_division_winner"},
+	}
+	divisions := tabular.Table{
+		Name: "Division Standings",
+		Header: []string{
+			"conference", "division", "division_rank", "seed", "team_abbr", "team_city", "team_name",
+			"wins", "losses", "ties", "win_pct",
+			"home_wins", "home_losses", "home_ties", "away_wins", "away_losses", "away_ties",
+			"division_wins", "division_losses", "division_ties", "conference_wins", "conference_losses", "conference_ties",
+			"division_games_back", "conference_games_back", "points_for", "points_against", "point_diff",
+			"strength_of_schedule", "strength_of_victory",
+		},
+	}
+
+	conferences := []struct {
+		name      string
+		standings standings.NFLConferenceStandings
+	}{
+		{"AFC", nflStandings.AFC},
+		{"NFC", nflStandings.NFC},
+	}
+	for _, conference := range conferences {
+		seedMap := make(map[int]int)
+		for _, seed := range conference.standings.PlayoffSeeds {
+			seedMap[seed.Team.TeamID] = seed.Seed
+			seeds.Rows = append(seeds.Rows, []interface{}{
+				conference.name, seed.Seed, seed.Team.TeamAbbr, seed.Team.TeamCity, seed.Team.TeamName, seed.Team.Division,
+				seed.Team.Wins, seed.Team.Losses, seed.Team.Ties, roundTo(seed.Team.WinPct, 3), seed.IsDivisionWinner,
+			})
+		}
+
+		for _, divisionName := range sortedKeys(conference.standings.Divisions) {
+			for rank, team := range conference.standings.Divisions[divisionName] {
+				var seed interface{}
+				if s, exists := seedMap[team.TeamID]; exists {
+					seed = s
+				}
+				divisions.Rows = append(divisions.Rows, []interface{}{
+					conference.name, divisionName, rank + 1, seed, team.TeamAbbr, team.TeamCity, team.TeamName,
+					team.Wins, team.Losses, team.Ties, roundTo(team.WinPct, 3),
+					team.HomeWins, team.HomeLosses, team.HomeTies, team.AwayWins, team.AwayLosses, team.AwayTies,
+					team.DivisionWins, team.DivisionLosses, team.DivisionTies, team.ConferenceWins, team.ConferenceLosses, team.ConferenceTies,
+					team.DivisionGamesBack, team.ConferenceGamesBack, team.PointsFor, team.PointsAgainst, team.PointsFor - team.PointsAgainst,
+					roundTo(team.StrengthOfSchedule, 3), roundTo(team.StrengthOfVictory, 3),
+				})
+			}
+		}
+	}
+
+	draftOrder := tabular.Table{
+		Name:   "Draft Order",
+		Header: []string{"pick", "team_abbr", "team_city", "team_name", "conference", "division", "wins", "losses", "ties", "win_pct", "strength_of_schedule"},
+	}
+	for _, pick := range nflStandings.DraftOrder {
+		draftOrder.Rows = append(draftOrder.Rows, []interface{}{
+			pick.Pick, pick.Team.TeamAbbr, pick.Team.TeamCity, pick.Team.TeamName, pick.Team.Conference, pick.Team.Division,
+			pick.Team.Wins, pick.Team.Losses, pick.Team.Ties, roundTo(pick.Team.WinPct, 3), roundTo(pick.Team.StrengthOfSchedule, 3),
+		})
+	}
+
+	return []tabular.Table{seeds, divisions, draftOrder}
+}
+
+func nbaStandingsTables(nbaStandings *standings.NBAStandings) []tabular.Table {
+	seeds := tabular.Table{
+		Name:   "Playoff Seeds",
+		Header: []string{"conference", "seed", "team_abbr", "team_city", "team_name", "division", "wins", "losses", "win_pct", "is_division_winner"},
+	}
+	divisions := tabular.Table{
+		Name: "Division Standings",
+		Header: []string{
+			"conference", "division", "division_rank", "seed", "team_abbr", "team_city", "team_name",
+			"wins", "losses", "win_pct",
+			"home_wins", "home_losses", "away_wins", "away_losses",
+			"division_wins", "division_losses", "conference_wins", "conference_losses",
+			"division_games_back", "conference_games_back", "points_for", "points_against", "point_diff", "games_with_scores",
+			"strength_of_schedule", "strength_of_victory", "is_division_winner",
+		},
+	}
+
+	conferences := []struct {
+		name      string
+		standings standings.NBAConferenceStandings
+	}{
+		{"Eastern", nbaStandings.Eastern},
+		{"Western", nbaStandings.Western},
+	}
+	for _, conference := range conferences {
+		seedMap := make(map[int]int)
+		for _, seed := range conference.standings.PlayoffSeeds {
+			seedMap[seed.Team.TeamID] = seed.Seed
+			seeds.Rows = append(seeds.Rows, []interface{}{
+				conference.name, seed.Seed, seed.Team.TeamAbbr, seed.Team.TeamCity, seed.Team.TeamName, seed.Team.Division,
+				seed.Team.Wins, seed.Team.Losses, roundTo(seed.Team.WinPct, 3), seed.IsDivisionWinner,
+			})
+		}
+
+		for _, divisionName := range sortedKeys(conference.standings.Divisions) {
+			for rank, team := range conference.standings.Divisions[divisionName] {
+				var seed interface{}
+				if s, exists := seedMap[team.TeamID]; exists {
+					seed = s
+				}
+				divisions.Rows = append(divisions.Rows, []interface{}{
+					conference.name, divisionName, rank + 1, seed, team.TeamAbbr, team.TeamCity, team.TeamName,
+					team.Wins, team.Losses, roundTo(team.WinPct, 3),
+					team.HomeWins, team.HomeLosses, team.AwayWins, team.AwayLosses,
+					team.DivisionWins, team.DivisionLosses, team.ConferenceWins, team.ConferenceLosses,
+					team.DivisionGamesBack, team.ConferenceGamesBack, team.PointsFor, team.PointsAgainst, team.PointsFor - team.PointsAgainst, team.GamesWithScores,
+					roundTo(team.StrengthOfSchedule, 3), roundTo(team.StrengthOfVictory, 3), team.IsDivisionWinner,
+				})
+			}
+		}
+	}
+
+	draftOrder := tabular.Table{
+		Name:   "Draft Order",
+		Header: []string{"pick", "team_abbr", "team_city", "team_name", "conference", "division", "wins", "losses", "win_pct", "strength_of_schedule"},
+	}
+	for _, pick := range nbaStandings.DraftOrder {
+		draftOrder.Rows = append(draftOrder.Rows, []interface{}{
+			pick.Pick, pick.Team.TeamAbbr, pick.Team.TeamCity, pick.Team.TeamName, pick.Team.Conference, pick.Team.Division,
+			pick.Team.Wins, pick.Team.Losses, roundTo(pick.Team.WinPct, 3), roundTo(pick.Team.StrengthOfSchedule, 3),
+		})
+	}
+
+	return []tabular.Table{seeds, divisions, draftOrder}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func exportPicksTable(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleViewer, c)
+		if err != nil {
+			return err
+		}
+		sID := access.ID
+
+		format, err := getExportFormat(c)
+		if err != nil {
+			return err
+		}
+		if format == "" {
+			format = tabular.FormatCSV
+		}
+
+		query := `
+			SELECT
+				game.id, game.espn_id, game.week, game.start_time, game.day_of_week,
+				game.location, game.primetime, game.network, game.status,
+				ht.abbreviation, ht.city, ht.name,
+				at.abbreviation, at.city, at.name,
+				game.home_score, game.away_score,
+				pick.picked_team_id, pick.predicted_home_score, pick.predicted_away_score, pick.status,
+				game.home_team_id, game.away_team_id
+			FROM picks pick
+			JOIN games game ON pick.game_id = game.id
+			JOIN teams ht ON game.home_team_id = ht.id
+			JOIN teams at ON game.away_team_id = at.id
+			WHERE pick.scenario_id = $1
+			ORDER BY game.start_time, game.id
+		`
+
+		rows, err := db.Query(query, sID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		table := tabular.Table{
+			Name: "Picks",
+			Header: []string{
+				"game_id", "espn_id", "week", "start_time", "day_of_week", "location", "primetime", "network", "game_status",
+				"away_team_abbr", "away_team_city", "away_team_name", "home_team_abbr", "home_team_city", "home_team_name",
+				"away_score", "home_score", "picked_team_abbr", "predicted_away_score", "predicted_home_score", "pick_status", "pick_result",
+			},
+		}
+		for rows.Next() {
+			var gameID, homeTeamID, awayTeamID int
+			var week, homeScore, awayScore, pickedTeamID, predictedHomeScore, predictedAwayScore *int
+			var espnID, dayOfWeek, location, primetime, network, gameStatus, pickStatus *string
+			var homeAbbr, homeCity, homeName, awayAbbr, awayCity, awayName string
+			var startTime time.Time
+
+			err := rows.Scan(
+				&gameID, &espnID, &week, &startTime, &dayOfWeek,
+				&location, &primetime, &network, &gameStatus,
+				&homeAbbr, &homeCity, &homeName,
+				&awayAbbr, &awayCity, &awayName,
+				&homeScore, &awayScore,
+				&pickedTeamID, &predictedHomeScore, &predictedAwayScore, &pickStatus,
+				&homeTeamID, &awayTeamID,
+			)
+			if err != nil {
+				return err
+			}
+
+			var pickedAbbr interface{}
+			if pickedTeamID != nil {
+				switch *pickedTeamID {
+				case homeTeamID:
+					pickedAbbr = homeAbbr
+				case awayTeamID:
+					pickedAbbr = awayAbbr
+				case 0:
+					pickedAbbr = "TIE"
+				}
+			}
+
+			table.Rows = append(table.Rows, []interface{}{
+				gameID, espnID, week, startTime.Format(time.RFC3339), dayOfWeek, location, primetime, network, gameStatus,
+				awayAbbr, awayCity, awayName, homeAbbr, homeCity, homeName,
+				awayScore, homeScore, pickedAbbr, predictedAwayScore, predictedHomeScore, pickStatus,
+				pickResult(pickedTeamID, homeTeamID, awayTeamID, gameStatus, homeScore, awayScore),
+			})
+		}
+
+		return sendTables(c, format, fmt.Sprintf("scenario-%d-picks", sID), []tabular.Table{table})
+	}
+}
+
+// Grades a pick against the real result: "correct", "incorrect", or empty until the game is final
+func pickResult(pickedTeamID *int, homeTeamID int, awayTeamID int, gameStatus *string, homeScore *int, awayScore *int) string {
+	if pickedTeamID == nil || gameStatus == nil || *gameStatus != "final" || homeScore == nil || awayScore == nil {
+		return ""
+	}
+	if winnerFromScores(homeTeamID, awayTeamID, *homeScore, *awayScore) == *pickedTeamID {
+		return "correct"
+	}
+	return "incorrect"
+}
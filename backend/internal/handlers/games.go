@@ -3,365 +3,157 @@
 package handlers
 
 import (
+    "time"
+
     "github.com/gofiber/fiber/v2"
 
-    "gamescript/internal/database"
+    "gamescript/internal/apperror"
+    "gamescript/internal/models"
+    "gamescript/internal/store"
+    "gamescript/internal/validation"
 )
 
 
-func getGamesBySeason(db *database.DB) fiber.Handler {
-    return func(c *fiber.Ctx) error {
-        seasonID := c.Params("season_id")
+// Highest week a game can be in; NBA weeks count on from the season's first week
+const maxWeek = 53
+
+type GameResponse struct {
+    ID          int              `json:"id"`
+    SeasonID    int              `json:"season_id"`
+    ESPNID      string           `json:"espn_id"`
+    HomeTeamID  int              `json:"home_team_id"`
+    AwayTeamID  int              `json:"away_team_id"`
+    StartTime   time.Time        `json:"start_time"`
+    DayOfWeek   *string          `json:"day_of_week"`
+    Week        *int             `json:"week"`
+    Location    *string          `json:"location"`
+    Primetime   *string          `json:"primetime"`
+    Network     *string          `json:"network"`
+    HomeScore   *int             `json:"home_score"`
+    AwayScore   *int             `json:"away_score"`
+    Status      *string          `json:"status"`
+    HomeTeam    GameTeamResponse `json:"home_team"`
+    AwayTeam    GameTeamResponse `json:"away_team"`
+}
 
-        query := `
-            SELECT
-                game.id, game.season_id, game.espn_id,
-                game.home_team_id, game.away_team_id,
-                game.start_time, game.day_of_week, game.week,
-                game.location, game.primetime, game.network,
-                game.home_score, game.away_score, game.status, 
-                ht.id as home_id, ht.abbreviation as home_abbr, ht.city as home_city, ht.name as home_name, ht.conference as home_conference, ht.division as home_division, ht.primary_color as home_primary_color, ht.secondary_color as home_secondary_color, ht.logo_url as home_logo_url, ht.alternate_logo_url as home_alternate_logo_url,
-                at.id as away_id, at.abbreviation as away_abbr, at.city as away_city, at.name as away_name, at.conference as away_conference, at.division as away_division, at.primary_color as away_primary_color, at.secondary_color as away_secondary_color, at.logo_url as away_logo_url, at.alternate_logo_url as away_alternate_logo_url
-            FROM games game
-            JOIN teams ht ON game.home_team_id = ht.id
-            JOIN teams at ON game.away_team_id = at.id
-            WHERE game.season_id = $1
-            ORDER BY game.start_time
-        `
+// The team fields included with a game
+type GameTeamResponse struct {
+    ID               int     `json:"id"`
+    Abbreviation     string  `json:"abbreviation"`
+    City             string  `json:"city"`
+    Name             string  `json:"name"`
+    Conference       *string `json:"conference"`
+    Division         *string `json:"division"`
+    PrimaryColor     string  `json:"primary_color"`
+    SecondaryColor   string  `json:"secondary_color"`
+    LogoURL          *string `json:"logo_url"`
+    AlternateLogoURL *string `json:"alternate_logo_url"`
+}
 
-        rows, err := db.Query(query, seasonID)
+func getGamesBySeason(data store.Store) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        seasonID, err := validation.PathID(c, "season_id")
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+            return err
         }
-        defer rows.Close()
 
-        var games[]map[string]interface {}
-        for rows.Next() {
-            var id, seasonID, homeTeamID, awayTeamID int
-            var week, homeScore, awayScore *int
-            var espnID, startTime, homeAbbr, homeCity, homeName, homePrimaryColor, homeSecondaryColor, awayAbbr, awayCity, awayName, awayPrimaryColor, awaySecondaryColor string
-            var dayOfWeek, location, primetime, network, status, homeConference, homeDivision, homeLogoURL, homeAlternateLogoURL, awayConference, awayDivision, awayLogoURL, awayAlternateLogoURL *string
-
-            err := rows.Scan(
-                &id, &seasonID, &espnID,
-                &homeTeamID, &awayTeamID,
-                &startTime, &dayOfWeek, &week,
-                &location, &primetime, &network,
-                &homeScore, &awayScore, &status, 
-                &homeTeamID, &homeAbbr, &homeCity, &homeName, &homeConference, &homeDivision, &homePrimaryColor, &homeSecondaryColor, &homeLogoURL, &homeAlternateLogoURL,
-                &awayTeamID, &awayAbbr, &awayCity, &awayName, &awayConference, &awayDivision, &awayPrimaryColor, &awaySecondaryColor, &awayLogoURL, &awayAlternateLogoURL,
-            )
-            if err != nil {
-                continue
-            }
-
-            games = append(games, map[string]interface{}{
-                "id": id,
-                "season_id": seasonID,
-                "espn_id": espnID,
-                "home_team_id": homeTeamID,
-                "away_team_id": awayTeamID,
-                "start_time": startTime,
-                "day_of_week": dayOfWeek,
-                "week": week,
-                "location": location,
-                "primetime": primetime,
-                "network": network,
-                "home_score": homeScore,
-                "away_score": awayScore,
-                "status": status,
-                "home_team": map[string]interface{}{
-                    "id": homeTeamID,
-                    "abbreviation": homeAbbr,
-                    "city": homeCity,
-                    "name": homeName,
-                    "conference": homeConference,
-                    "division": homeDivision,
-                    "primary_color": homePrimaryColor,
-                    "secondary_color": homeSecondaryColor,
-                    "logo_url": homeLogoURL,
-                    "alternate_logo_url": homeAlternateLogoURL,
-                },
-                "away_team": map[string]interface{}{
-                    "id": awayTeamID,
-                    "abbreviation": awayAbbr,
-                    "city": awayCity,
-                    "name": awayName,
-                    "conference": awayConference,
-                    "division": awayDivision,
-                    "primary_color": awayPrimaryColor,
-                    "secondary_color": awaySecondaryColor,
-                    "logo_url": awayLogoURL,
-                    "alternate_logo_url": awayAlternateLogoURL,
-                },
-            })
+        games, err := data.GamesBySeason(seasonID)
+        if err != nil {
+            return err
         }
-
-        return c.JSON(games)
+        return c.JSON(formatGames(games))
     }
 }
 
-func getGamesByWeek(db *database.DB) fiber.Handler {
+func getGamesByWeek(data store.Store) fiber.Handler {
     return func(c *fiber.Ctx) error {
-        seasonID := c.Params("season_id")
-        week := c.Params("week")
-
-        query := `
-            SELECT
-                game.id, game.season_id, game.espn_id,
-                game.home_team_id, game.away_team_id,
-                game.start_time, game.day_of_week, game.week,
-                game.location, game.primetime, game.network,
-                game.home_score, game.away_score, game.status, 
-                ht.id as home_id, ht.abbreviation as home_abbr, ht.city as home_city, ht.name as home_name, ht.conference as home_conference, ht.division as home_division, ht.primary_color as home_primary_color, ht.secondary_color as home_secondary_color, ht.logo_url as home_logo_url, ht.alternate_logo_url as home_alternate_logo_url,
-                at.id as away_id, at.abbreviation as away_abbr, at.city as away_city, at.name as away_name, at.conference as away_conference, at.division as away_division, at.primary_color as away_primary_color, at.secondary_color as away_secondary_color, at.logo_url as away_logo_url, at.alternate_logo_url as away_alternate_logo_url
-            FROM games game
-            JOIN teams ht ON game.home_team_id = ht.id
-            JOIN teams at ON game.away_team_id = at.id
-            WHERE game.season_id = $1 AND game.week = $2
-            ORDER BY game.start_time
-        `
-
-        rows, err := db.Query(query, seasonID, week)
-        if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+        params := validation.NewParams(c)
+        seasonID, week := params.ID("season_id"), params.Int("week", 1, maxWeek)
+        if err := params.Err(); err != nil {
+            return err
         }
-        defer rows.Close()
-
-        var games []map[string]interface {}
-        for rows.Next() {
-            var id, seasonID, homeTeamID, awayTeamID int
-            var week, homeScore, awayScore *int
-            var espnID, startTime, homeAbbr, homeCity, homeName, homePrimaryColor, homeSecondaryColor, awayAbbr, awayCity, awayName, awayPrimaryColor, awaySecondaryColor string
-            var dayOfWeek, location, primetime, network, status, homeConference, homeDivision, homeLogoURL, homeAlternateLogoURL, awayConference, awayDivision, awayLogoURL, awayAlternateLogoURL *string
-
-            err := rows.Scan(
-                &id, &seasonID, &espnID,
-                &homeTeamID, &awayTeamID,
-                &startTime, &dayOfWeek, &week,
-                &location, &primetime, &network,
-                &homeScore, &awayScore, &status, 
-                &homeTeamID, &homeAbbr, &homeCity, &homeName, &homeConference, &homeDivision, &homePrimaryColor, &homeSecondaryColor, &homeLogoURL, &homeAlternateLogoURL,
-                &awayTeamID, &awayAbbr, &awayCity, &awayName, &awayConference, &awayDivision, &awayPrimaryColor, &awaySecondaryColor, &awayLogoURL, &awayAlternateLogoURL,
-            )
-            if err != nil {
-                continue
-            }
 
-            games = append(games, map[string]interface{}{
-                "id": id,
-                "season_id": seasonID,
-                "espn_id": espnID,
-                "home_team_id": homeTeamID,
-                "away_team_id": awayTeamID,
-                "start_time": startTime,
-                "day_of_week": dayOfWeek,
-                "week": week,
-                "location": location,
-                "primetime": primetime,
-                "network": network,
-                "home_score": homeScore,
-                "away_score": awayScore,
-                "status": status,
-                "home_team": map[string]interface{}{
-                    "id": homeTeamID,
-                    "abbreviation": homeAbbr,
-                    "city": homeCity,
-                    "name": homeName,
-                    "conference": homeConference,
-                    "division": homeDivision,
-                    "primary_color": homePrimaryColor,
-                    "secondary_color": homeSecondaryColor,
-                    "logo_url": homeLogoURL,
-                    "alternate_logo_url": homeAlternateLogoURL,
-                },
-                "away_team": map[string]interface{}{
-                    "id": awayTeamID,
-                    "abbreviation": awayAbbr,
-                    "city": awayCity,
-                    "name": awayName,
-                    "conference": awayConference,
-                    "division": awayDivision,
-                    "primary_color": awayPrimaryColor,
-                    "secondary_color": awaySecondaryColor,
-                    "logo_url": awayLogoURL,
-                    "alternate_logo_url": awayAlternateLogoURL,
-                },
-            })
+        games, err := data.GamesByWeek(seasonID, week)
+        if err != nil {
+            return err
         }
-
-        return c.JSON(games)
+        return c.JSON(formatGames(games))
     }
 }
 
-func getGamesByTeam(db *database.DB) fiber.Handler {
+func getGamesByTeam(data store.Store) fiber.Handler {
     return func(c *fiber.Ctx) error {
-        teamID := c.Params("team_id")
-
-        query := `
-            SELECT
-                game.id, game.season_id, game.espn_id,
-                game.home_team_id, game.away_team_id,
-                game.start_time, game.day_of_week, game.week,
-                game.location, game.primetime, game.network,
-                game.home_score, game.away_score, game.status, 
-                ht.id as home_id, ht.abbreviation as home_abbr, ht.city as home_city, ht.name as home_name, ht.conference as home_conference, ht.division as home_division, ht.primary_color as home_primary_color, ht.secondary_color as home_secondary_color, ht.logo_url as home_logo_url, ht.alternate_logo_url as home_alternate_logo_url,
-                at.id as away_id, at.abbreviation as away_abbr, at.city as away_city, at.name as away_name, at.conference as away_conference, at.division as away_division, at.primary_color as away_primary_color, at.secondary_color as away_secondary_color, at.logo_url as away_logo_url, at.alternate_logo_url as away_alternate_logo_url
-            FROM games game
-            JOIN teams ht ON game.home_team_id = ht.id
-            JOIN teams at ON game.away_team_id = at.id
-            WHERE game.home_team_id = $1 OR game.away_team_id = $1
-            ORDER BY game.start_time
-        `
-
-        rows, err := db.Query(query, teamID)
+        teamID, err := validation.PathID(c, "team_id")
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+            return err
         }
-        defer rows.Close()
 
-        var games []map[string]interface{}
-        for rows.Next() {
-            var id, seasonID, homeTeamID, awayTeamID int
-            var week, homeScore, awayScore *int
-            var espnID, startTime, homeAbbr, homeCity, homeName, homePrimaryColor, homeSecondaryColor, awayAbbr, awayCity, awayName, awayPrimaryColor, awaySecondaryColor string
-            var dayOfWeek, location, primetime, network, status, homeConference, homeDivision, homeLogoURL, homeAlternateLogoURL, awayConference, awayDivision, awayLogoURL, awayAlternateLogoURL *string
-
-            err := rows.Scan(
-                &id, &seasonID, &espnID,
-                &homeTeamID, &awayTeamID,
-                &startTime, &dayOfWeek, &week,
-                &location, &primetime, &network,
-                &homeScore, &awayScore, &status, 
-                &homeTeamID, &homeAbbr, &homeCity, &homeName, &homeConference, &homeDivision, &homePrimaryColor, &homeSecondaryColor, &homeLogoURL, &homeAlternateLogoURL,
-                &awayTeamID, &awayAbbr, &awayCity, &awayName, &awayConference, &awayDivision, &awayPrimaryColor, &awaySecondaryColor, &awayLogoURL, &awayAlternateLogoURL,
-            )
-            if err != nil {
-                continue
-            }
-
-            games = append(games, map[string]interface{}{
-                "id": id,
-                "season_id": seasonID,
-                "espn_id": espnID,
-                "home_team_id": homeTeamID,
-                "away_team_id": awayTeamID,
-                "start_time": startTime,
-                "day_of_week": dayOfWeek,
-                "week": week,
-                "location": location,
-                "primetime": primetime,
-                "network": network,
-                "home_score": homeScore,
-                "away_score": awayScore,
-                "status": status,
-                "home_team": map[string]interface{}{
-                    "id": homeTeamID,
-                    "abbreviation": homeAbbr,
-                    "city": homeCity,
-                    "name": homeName,
-                    "conference": homeConference,
-                    "division": homeDivision,
-                    "primary_color": homePrimaryColor,
-                    "secondary_color": homeSecondaryColor,
-                    "logo_url": homeLogoURL,
-                    "alternate_logo_url": homeAlternateLogoURL,
-                },
-                "away_team": map[string]interface{}{
-                    "id": awayTeamID,
-                    "abbreviation": awayAbbr,
-                    "city": awayCity,
-                    "name": awayName,
-                    "conference": awayConference,
-                    "division": awayDivision,
-                    "primary_color": awayPrimaryColor,
-                    "secondary_color": awaySecondaryColor,
-                    "logo_url": awayLogoURL,
-                    "alternate_logo_url": awayAlternateLogoURL,
-                },
-            })
+        games, err := data.GamesByTeam(teamID)
+        if err != nil {
+            return err
         }
-
-        return c.JSON(games)
+        return c.JSON(formatGames(games))
     }
 }
 
-func getGame(db *database.DB) fiber.Handler {
+func getGame(data store.Store) fiber.Handler {
     return func(c *fiber.Ctx) error {
-        gameID := c.Params("game_id")
-
-        query := `
-            SELECT
-                game.id, game.season_id, game.espn_id,
-                game.home_team_id, game.away_team_id,
-                game.start_time, game.day_of_week, game.week,
-                game.location, game.primetime, game.network,
-                game.home_score, game.away_score, game.status, 
-                ht.id as home_id, ht.abbreviation as home_abbr, ht.city as home_city, ht.name as home_name, ht.conference as home_conference, ht.division as home_division, ht.primary_color as home_primary_color, ht.secondary_color as home_secondary_color, ht.logo_url as home_logo_url, ht.alternate_logo_url as home_alternate_logo_url,
-                at.id as away_id, at.abbreviation as away_abbr, at.city as away_city, at.name as away_name, at.conference as away_conference, at.division as away_division, at.primary_color as away_primary_color, at.secondary_color as away_secondary_color, at.logo_url as away_logo_url, at.alternate_logo_url as away_alternate_logo_url
-            FROM games game
-            JOIN teams ht ON game.home_team_id = ht.id
-            JOIN teams at ON game.away_team_id = at.id
-            WHERE game.id = $1
-        `
-
-        var id, seasonID, homeTeamID, awayTeamID int
-        var week, homeScore, awayScore *int
-        var espnID, startTime, homeAbbr, homeCity, homeName, homePrimaryColor, homeSecondaryColor, awayAbbr, awayCity, awayName, awayPrimaryColor, awaySecondaryColor string
-        var dayOfWeek, location, primetime, network, status, homeConference, homeDivision, homeLogoURL, homeAlternateLogoURL, awayConference, awayDivision, awayLogoURL, awayAlternateLogoURL *string
+        gameID, err := validation.PathID(c, "game_id")
+        if err != nil {
+            return err
+        }
 
-        err := db.Conn.QueryRow(query, gameID).Scan(
-            &id, &seasonID, &espnID,
-            &homeTeamID, &awayTeamID,
-            &startTime, &dayOfWeek, &week,
-            &location, &primetime, &network,
-            &homeScore, &awayScore, &status, 
-            &homeTeamID, &homeAbbr, &homeCity, &homeName, &homeConference, &homeDivision, &homePrimaryColor, &homeSecondaryColor, &homeLogoURL, &homeAlternateLogoURL,
-            &awayTeamID, &awayAbbr, &awayCity, &awayName, &awayConference, &awayDivision, &awayPrimaryColor, &awaySecondaryColor, &awayLogoURL, &awayAlternateLogoURL,
-        )
+        game, err := data.Game(gameID)
+        if err == store.ErrNotFound {
+            return apperror.New(apperror.GameNotFound, "Game not found")
+        }
         if err != nil {
-            return c.Status(404).JSON(fiber.Map{"error": "Game not found"})
+            return err
         }
+        return c.JSON(formatGame(*game))
+    }
+}
+
+func formatGames(games []models.GameWithTeams) []GameResponse {
+    formatted := make([]GameResponse, 0, len(games))
+    for _, game := range games {
+        formatted = append(formatted, formatGame(game))
+    }
+    return formatted
+}
 
-        return c.JSON(map[string]interface{}{
-            "id": id,
-            "season_id": seasonID,
-            "espn_id": espnID,
-            "home_team_id": homeTeamID,
-            "away_team_id": awayTeamID,
-            "start_time": startTime,
-            "day_of_week": dayOfWeek,
-            "week": week,
-            "location": location,
-            "primetime": primetime,
-            "network": network,
-            "home_score": homeScore,
-            "away_score": awayScore,
-            "status": status,
-            "home_team": map[string]interface{}{
-                "id": homeTeamID,
-                "abbreviation": homeAbbr,
-                "city": homeCity,
-                "name": homeName,
-                "conference": homeConference,
-                "division": homeDivision,
-                "primary_color": homePrimaryColor,
-                "secondary_color": homeSecondaryColor,
-                "logo_url": homeLogoURL,
-                "alternate_logo_url": homeAlternateLogoURL,
-            },
-            "away_team": map[string]interface{}{
-                "id": awayTeamID,
-                "abbreviation": awayAbbr,
-                "city": awayCity,
-                "name": awayName,
-                "conference": awayConference,
-                "division": awayDivision,
-                "primary_color": awayPrimaryColor,
-                "secondary_color": awaySecondaryColor,
-                "logo_url": awayLogoURL,
-                "alternate_logo_url": awayAlternateLogoURL,
-            },
-        })
+func formatGame(game models.GameWithTeams) GameResponse {
+    return GameResponse{
+        ID:         game.ID,
+        SeasonID:   game.SeasonID,
+        ESPNID:     game.ESPNID,
+        HomeTeamID: game.HomeTeamID,
+        AwayTeamID: game.AwayTeamID,
+        StartTime:  game.StartTime,
+        DayOfWeek:  game.DayOfWeek,
+        Week:       game.Week,
+        Location:   game.Location,
+        Primetime:  game.Primetime,
+        Network:    game.Network,
+        HomeScore:  game.HomeScore,
+        AwayScore:  game.AwayScore,
+        Status:     game.Status,
+        HomeTeam:   formatGameTeam(game.HomeTeam),
+        AwayTeam:   formatGameTeam(game.AwayTeam),
     }
-}
\ No newline at end of file
+}
+
+func formatGameTeam(team models.Team) GameTeamResponse {
+    return GameTeamResponse{
+        ID:               team.ID,
+        Abbreviation:     team.Abbreviation,
+        City:             team.City,
+        Name:             team.Name,
+        Conference:       team.Conference,
+        Division:         team.Division,
+        PrimaryColor:     team.PrimaryColor,
+        SecondaryColor:   team.SecondaryColor,
+        LogoURL:          team.LogoURL,
+        AlternateLogoURL: team.AlternateLogoURL,
+    }
+}
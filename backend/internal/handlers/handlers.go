@@ -7,9 +7,12 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
+	"gamescript/internal/apperror"
 	"gamescript/internal/database"
 	"gamescript/internal/middleware"
 	"gamescript/internal/scheduler"
+	"gamescript/internal/store"
+	"gamescript/internal/validation"
 )
 
 
@@ -24,38 +27,58 @@ func SetupRoutes(app *fiber.App, db *database.DB, scheduler *scheduler.Scheduler
 	// 	})
 	// })
 
-	// Sports routes
-	api.Get("/sports", getSports(db))
+	// Generated API description
+	api.Get("/openapi.json", getOpenAPIDocument)
 
-	// Seasons routes
-	api.Get("/sports/:sport_id/seasons", getSeasons(db))
-	api.Get("/seasons/:season_id", getSeason(db))
+	// Sports, seasons, teams, and games routes
+	data := store.NewPostgres(db)
+	setupCatalogRoutes(api, data)
 
-	// Teams routes
-	api.Get("/seasons/:season_id/teams", getTeamsBySeason(db))
-	api.Get("/teams/:team_id", getTeam(db))
+	// Calendar routes
+	api.Get("/seasons/:season_id/games.ics", getSeasonGamesCalendar(db))
+	api.Get("/teams/:team_id/games.ics", getTeamGamesCalendar(db))
 
-	// Games routes
-	api.Get("/seasons/:season_id/games", getGamesBySeason(db))
-	api.Get("/seasons/:season_id/weeks/:week/games", getGamesByWeek(db))
-	api.Get("/teams/:team_id/games", getGamesByTeam(db))
-	api.Get("/games/:game_id", getGame(db))
+	// Ratings routes
+	api.Get("/seasons/:season_id/ratings", getSeasonRatings(db))
 
 	// Scenarios (optional auth - guest or user)
 	scenarios := api.Group("/scenarios")
 	scenarios.Use(middleware.OptionalAuth)
 	scenarios.Get("/", getScenarios(db))
 	scenarios.Post("/", createScenario(db))
+	scenarios.Get("/compare", compareScenarios(db))
+	scenarios.Post("/import", importScenario(db))
+	scenarios.Post("/invites/:token/accept", acceptScenarioInvite(db))
 	scenarios.Get("/:scenario_id", getScenario(db))
 	scenarios.Put("/:scenario_id", updateScenario(db))
 	scenarios.Delete("/:scenario_id", deleteScenario(db))
 	scenarios.Post("/:scenario_id/claim", middleware.AuthMiddleware, claimScenario(db))
+	scenarios.Post("/:scenario_id/fork", forkScenario(db))
+	scenarios.Get("/:scenario_id/export", exportScenario(db))
 	scenarios.Get("/:scenario_id/standings", getStandings(db))
+	scenarios.Get("/:scenario_id/standings/trajectory", getStandingsTrajectory(db))
+	scenarios.Get("/:scenario_id/card.png", getScenarioCard(db))
+	scenarios.Post("/:scenario_id/autofill", autofillPicks(db))
+	scenarios.Get("/:scenario_id/history", getScenarioHistory(db))
+	scenarios.Post("/:scenario_id/undo", undoScenarioChange(db))
+	scenarios.Post("/:scenario_id/redo", redoScenarioChange(db))
+	scenarios.Get("/:scenario_id/snapshots", getScenarioSnapshots(db))
+	scenarios.Post("/:scenario_id/snapshots", createScenarioSnapshot(db))
+	scenarios.Delete("/:scenario_id/snapshots/:snapshot_id", deleteScenarioSnapshot(db))
+	scenarios.Post("/:scenario_id/snapshots/:snapshot_id/restore", restoreScenarioSnapshot(db))
+	scenarios.Get("/:scenario_id/members", getScenarioMembers(db))
+	scenarios.Put("/:scenario_id/members/:member_id", updateScenarioMember(db))
+	scenarios.Delete("/:scenario_id/members/:member_id", removeScenarioMember(db))
+	scenarios.Get("/:scenario_id/invites", getScenarioInvites(db))
+	scenarios.Post("/:scenario_id/invites", createScenarioInvite(db))
+	scenarios.Delete("/:scenario_id/invites/:invite_id", deleteScenarioInvite(db))
 
 	// Picks (optional auth - guest or user)
 	picks := api.Group("/picks")
 	picks.Use(middleware.OptionalAuth)
-	picks.Get("/scenarios/:scenario_id", getPicksByScenario(db))
+	picks.Get("/scenarios/:scenario_id", getPicksByScenario(data))
+	picks.Get("/scenarios/:scenario_id/export", exportPicksTable(db))
+	picks.Put("/scenarios/:scenario_id/batch", batchUpdatePicks(db))
 	picks.Get("/scenarios/:scenario_id/games/:game_id", getPick(db))
 	picks.Post("/scenarios/:scenario_id/games/:game_id", createPick(db))
 	picks.Put("/scenarios/:scenario_id/games/:game_id", updatePick(db))
@@ -71,6 +94,20 @@ func SetupRoutes(app *fiber.App, db *database.DB, scheduler *scheduler.Scheduler
 	playoffs.Delete("/scenarios/:scenario_id/matchups/:matchup_id", deletePlayoffPick(db))
 	playoffs.Post("/scenarios/:scenario_id/generate", generateNextPlayoffRound(db))
 
+	// Pick'em leagues (authenticated users only)
+	leagues := api.Group("/leagues")
+	leagues.Use(middleware.AuthMiddleware)
+	leagues.Get("/", getLeagues(db))
+	leagues.Post("/", createLeague(db))
+	leagues.Post("/join/:invite_code", joinLeague(db))
+	leagues.Get("/:league_id", getLeague(db))
+	leagues.Delete("/:league_id", deleteLeague(db))
+	leagues.Delete("/:league_id/members/:member_id", removeLeagueMember(db))
+	leagues.Get("/:league_id/standings", getLeagueStandings(db))
+	leagues.Get("/:league_id/weeks/:week/entry", getLeagueEntry(db))
+	leagues.Put("/:league_id/weeks/:week/entry", saveLeagueEntry(db))
+	leagues.Get("/:league_id/weeks/:week/entries", getLeagueWeekEntries(db))
+
 	// Admin routes
 	admin := api.Group("/admin")
 	admin.Post("/update-schedule/nfl", triggerNFLUpdate(scheduler))
@@ -78,126 +115,73 @@ func SetupRoutes(app *fiber.App, db *database.DB, scheduler *scheduler.Scheduler
 	// admin.Post("/update-schedule/cfb", triggerCFBUpdate(scheduler))
 }
 
-func getSports(db *database.DB) fiber.Handler {
+// Routes that only read through the store, so tests can serve them from an in-memory one
+func setupCatalogRoutes(api fiber.Router, data store.Store) {
+	api.Get("/sports", getSports(data))
+	api.Get("/sports/:sport_id/seasons", getSeasons(data))
+	api.Get("/seasons/:season_id", getSeason(data))
+	api.Get("/seasons/:season_id/teams", getTeamsBySeason(data))
+	api.Get("/teams/:team_id", getTeam(data))
+	api.Get("/seasons/:season_id/games", getGamesBySeason(data))
+	api.Get("/seasons/:season_id/weeks/:week/games", getGamesByWeek(data))
+	api.Get("/teams/:team_id/games", getGamesByTeam(data))
+	api.Get("/games/:game_id", getGame(data))
+}
+
+func getSports(data store.Store) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		rows, err := db.Query("SELECT id, name, short_name, created_at FROM sports")
+		sports, err := data.Sports()
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
-		}
-		defer rows.Close()
-
-		var sports []map[string]interface{}
-		for rows.Next() {
-			var id int
-			var name, shortName string
-			var createdAt string
-
-			if err := rows.Scan(&id, &name, &shortName, &createdAt); err != nil {
-				continue
-			}
-
-			sports = append(sports, map[string]interface{}{
-				"id":         id,
-				"name":       name,
-				"short_name": shortName,
-				"created_at": createdAt,
-			})
+			return err
 		}
-
 		return c.JSON(sports)
 	}
 }
 
-func getSeasons(db *database.DB) fiber.Handler {
+func getSeasons(data store.Store) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		sportID := c.Params("sport_id")
-
-		query := `
-			SELECT id, sport_id, start_year, end_year, is_active, created_at
-			FROM seasons
-			WHERE sport_id = $1
-			ORDER BY start_year DESC
-		`
-
-		rows, err := db.Query(query, sportID)
+		sportID, err := validation.PathID(c, "sport_id")
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
-		}
-		defer rows.Close()
-
-		var seasons []map[string]interface{}
-		for rows.Next() {
-			var id, sportID, startYear int
-			var endYear *int
-			var isActive bool
-			var createdAt string
-
-			if err := rows.Scan(&id, &sportID, &startYear, &endYear, &isActive, &createdAt); err != nil {
-				continue
-			}
-
-			seasons = append(seasons, map[string]interface{}{
-				"id":         id,
-				"sport_id":   sportID,
-				"start_year": startYear,
-				"end_year":   endYear,
-				"is_active":  isActive,
-				"created_at": createdAt,
-			})
+			return err
 		}
 
+		seasons, err := data.SeasonsBySport(sportID)
+		if err != nil {
+			return err
+		}
 		return c.JSON(seasons)
 	}
 }
 
-func getSeason(db *database.DB) fiber.Handler {
+func getSeason(data store.Store) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		seasonID := c.Params("season_id")
-
-		query := `
-			SELECT id, sport_id, start_year, end_year, is_active, created_at
-			FROM seasons
-			WHERE id = $1
-		`
-
-		var id, sportID, startYear int
-		var endYear *int
-		var isActive bool
-		var createdAt string
-
-		err := db.Conn.QueryRow(query, seasonID).Scan(&id, &sportID, &startYear, &endYear, &isActive, &createdAt)
+		seasonID, err := validation.PathID(c, "season_id")
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Season not found"})
+			return err
 		}
 
-		return c.JSON(map[string]interface{}{
-			"id":         id,
-			"sport_id":   sportID,
-			"start_year": startYear,
-			"end_year":   endYear,
-			"is_active":  isActive,
-			"created_at": createdAt,
-		})
+		season, err := data.Season(seasonID)
+		if err == store.ErrNotFound {
+			return apperror.New(apperror.SeasonNotFound, "Season not found")
+		}
+		if err != nil {
+			return err
+		}
+		return c.JSON(season)
 	}
 }
 
 func triggerNFLUpdate(scheduler *scheduler.Scheduler) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		scheduler.UpdateNFLSchedule()
-		return c.JSON(fiber.Map{
-			"status":  "ok",
-			"message": "NFL schedule update triggered",
-		})
+		return c.JSON(TriggerResponse{Status: "ok", Message: "NFL schedule update triggered"})
 	}
 }
 
 func triggerNBAUpdate(scheduler *scheduler.Scheduler) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		scheduler.UpdateNBASchedule()
-		return c.JSON(fiber.Map{
-			"status": "ok",
-			"message": "NBA schedule update triggered",
-		})
+		return c.JSON(TriggerResponse{Status: "ok", Message: "NBA schedule update triggered"})
 	}
 }
 
@@ -5,10 +5,14 @@ import (
     "encoding/json"
     "log"
     "net/http/httptest"
+    "os"
     "testing"
 
+    "gamescript/internal/apperror"
     "gamescript/internal/database"
+    "gamescript/internal/middleware"
     "gamescript/internal/scheduler"
+    "gamescript/internal/testdb"
 
     "github.com/gofiber/fiber/v2"
     "github.com/joho/godotenv"
@@ -22,19 +26,34 @@ func init() {
     }
 }
 
-// Test helper to setup test app
+func TestMain(m *testing.M) {
+    os.Exit(testdb.Run(m))
+}
+
+// Test helper to setup test app on a fresh database with the fixture seasons loaded
 func setupTestApp(t *testing.T) (*fiber.App, *database.DB) {
-    // Connect to test database
-    db, err := database.NewConnection()
-    if err != nil {
-        t.Fatal("Failed to connect to test database:", err)
-    }
+    db := testdb.New(t)
+    return newTestApp(db), db
+}
+
+// Registers every route on a new app, with health and auth routes as cmd/server registers them but without the rate limits
+func newTestApp(db *database.DB) *fiber.App {
+    app := fiber.New(fiber.Config{ErrorHandler: apperror.Handler})
+
+    api := app.Group("/api")
+    api.Get("/health", func(c *fiber.Ctx) error {
+        return c.JSON(HealthResponse{Status: "ok"})
+    })
+    auth := api.Group("/auth")
+    auth.Post("/register", RegisterUser(db))
+    auth.Post("/login", LoginUser(db))
+    auth.Get("/me", middleware.AuthMiddleware, GetCurrentUser(db))
+    auth.Put("/profile", middleware.AuthMiddleware, UpdateProfile(db))
 
-    app := fiber.New()
     scheduler := scheduler.NewScheduler(db)
     SetupRoutes(app, db, scheduler)
 
-    return app, db
+    return app
 }
 
 func TestHealthCheck(t *testing.T) {
@@ -121,10 +140,11 @@ func TestRegisterUserDuplicateEmail(t *testing.T) {
     req2.Header.Set("Content-Type", "application/json")
     resp, _ := app.Test(req2)
 
-    assert.Equal(t, 400, resp.StatusCode)
+    assert.Equal(t, 409, resp.StatusCode)
 
     var body map[string]interface{}
     json.NewDecoder(resp.Body).Decode(&body)
+    assert.Equal(t, "EMAIL_IN_USE", body["code"])
     assert.Contains(t, body["error"], "Email already")
 
     // Cleanup
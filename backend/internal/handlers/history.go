@@ -0,0 +1,653 @@
+// Scenario version history handlers: change log, undo/redo, and snapshots
+
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/history"
+	"gamescript/internal/models"
+	"gamescript/internal/standings"
+	"gamescript/internal/validation"
+)
+
+
+// Query methods shared by *sql.DB and *sql.Tx
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Logged change; the after state is captured over the same games as Before
+type scenarioChange struct {
+	Action     string
+	GameID     *int
+	SnapshotID *int
+	Before     *models.ScenarioState
+}
+
+type CreateSnapshotRequest struct {
+	Name string `json:"name"`
+}
+
+// A scenario's change log, newest first, with whether undo and redo are available
+type ScenarioHistoryResponse struct {
+	ScenarioID	int						`json:"scenario_id"`
+	CanUndo		bool					`json:"can_undo"`
+	CanRedo		bool					`json:"can_redo"`
+	Changes		[]ScenarioChangeResponse	`json:"changes"`
+}
+
+type ScenarioChangeResponse struct {
+	ID				int			`json:"id"`
+	Action			string		`json:"action"`
+	GameID			*int		`json:"game_id"`
+	SnapshotID		*int		`json:"snapshot_id"`
+	TargetChangeID	*int		`json:"target_change_id"`
+	UserID			*int		`json:"user_id"`
+	IsUndone		bool		`json:"is_undone"`
+	CreatedAt		time.Time	`json:"created_at"`
+}
+
+// Result of an undo or redo, naming the change it reverted or reapplied
+type HistoryStepResponse struct {
+	ID				int		`json:"id"`
+	Action			string	`json:"action"`
+	TargetChangeID	int		`json:"target_change_id"`
+	TargetAction	string	`json:"target_action"`
+	GameID			*int	`json:"game_id"`
+	CanUndo			bool	`json:"can_undo"`
+	CanRedo			bool	`json:"can_redo"`
+}
+
+type SnapshotResponse struct {
+	ID			int			`json:"id"`
+	ScenarioID	int			`json:"scenario_id"`
+	Name		string		`json:"name"`
+	PickCount	int			`json:"pick_count"`
+	HasPlayoffs	bool		`json:"has_playoffs"`
+	UserID		*int		`json:"user_id"`
+	CreatedAt	time.Time	`json:"created_at"`
+}
+
+type RestoreSnapshotResponse struct {
+	Message			string	`json:"message"`
+	SnapshotID		int		`json:"snapshot_id"`
+	RestoredPicks	int		`json:"restored_picks"`
+	HasPlayoffs		bool	`json:"has_playoffs"`
+}
+
+// Captures picks for the given games, or every game when allGames is set, and optionally the playoff bracket
+func captureScenarioState(q sqlExecutor, scenarioID int, allGames bool, gameIDs []int, includePlayoffs bool) (*models.ScenarioState, error) {
+	state := &models.ScenarioState{
+		AllGames:         allGames,
+		GameIDs:          gameIDs,
+		Picks:            []models.StatePick{},
+		IncludesPlayoffs: includePlayoffs,
+	}
+	if state.GameIDs == nil {
+		state.GameIDs = []int{}
+	}
+
+	if allGames || len(gameIDs) > 0 {
+		query := `
+			SELECT game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override
+			FROM picks
+			WHERE scenario_id = $1
+		`
+		args := []interface{}{scenarioID}
+		if !allGames {
+			query += ` AND game_id = ANY($2)`
+			args = append(args, pq.Array(gameIDs))
+		}
+		query += ` ORDER BY game_id`
+
+		rows, err := q.Query(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var pick models.StatePick
+			if err := rows.Scan(&pick.GameID, &pick.PickedTeamID, &pick.PredictedHomeScore, &pick.PredictedAwayScore, &pick.Status, &pick.IsOverride); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			state.Picks = append(state.Picks, pick)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if includePlayoffs {
+		teams, err := getScenarioTeamIndex(q, scenarioID)
+		if err != nil {
+			return nil, err
+		}
+		state.Playoffs, err = exportPlayoffs(q, scenarioID, teams)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+func getScenarioTeamIndex(q sqlExecutor, scenarioID int) (*seasonTeamIndex, error) {
+	var seasonID int
+	if err := q.QueryRow(`SELECT season_id FROM scenarios WHERE id = $1`, scenarioID).Scan(&seasonID); err != nil {
+		return nil, err
+	}
+	return getSeasonTeamIndex(q, seasonID)
+}
+
+// Replaces the picks and bracket covered by a state with the state's contents
+func applyScenarioState(tx *sql.Tx, scenarioID int, state *models.ScenarioState) error {
+	if state.AllGames {
+		if _, err := tx.Exec(`DELETE FROM picks WHERE scenario_id = $1`, scenarioID); err != nil {
+			return fmt.Errorf("error clearing picks: %w", err)
+		}
+	} else if len(state.GameIDs) > 0 {
+		if _, err := tx.Exec(`DELETE FROM picks WHERE scenario_id = $1 AND game_id = ANY($2)`, scenarioID, pq.Array(state.GameIDs)); err != nil {
+			return fmt.Errorf("error clearing picks: %w", err)
+		}
+	}
+
+	// Games removed from the schedule since the state was captured are skipped
+	for _, pick := range state.Picks {
+		_, err := tx.Exec(`
+			INSERT INTO picks (scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override)
+			SELECT $1, $2, $3, $4, $5, COALESCE($6, 'pending'), $7
+			WHERE EXISTS (SELECT 1 FROM games WHERE id = $2)
+		`, scenarioID, pick.GameID, pick.PickedTeamID, pick.PredictedHomeScore, pick.PredictedAwayScore, pick.Status, pick.IsOverride)
+		if err != nil {
+			return fmt.Errorf("error restoring pick for game %d: %w", pick.GameID, err)
+		}
+	}
+
+	if state.IncludesPlayoffs {
+		if _, err := tx.Exec(`DELETE FROM playoff_states WHERE scenario_id = $1`, scenarioID); err != nil {
+			return fmt.Errorf("error clearing playoffs: %w", err)
+		}
+		if state.Playoffs != nil {
+			teams, err := getScenarioTeamIndex(tx, scenarioID)
+			if err != nil {
+				return err
+			}
+			if err := importPlayoffBracket(tx, scenarioID, state.Playoffs, teams); err != nil {
+				return fmt.Errorf("error restoring playoffs: %w", err)
+			}
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE scenarios SET updated_at = NOW() WHERE id = $1`, scenarioID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Captures the state after a change and appends the change to the scenario's log
+func recordScenarioChange(q sqlExecutor, c *fiber.Ctx, scenarioID int, change scenarioChange) error {
+	after, err := captureScenarioState(q, scenarioID, change.Before.AllGames, change.Before.GameIDs, change.Before.IncludesPlayoffs)
+	if err != nil {
+		return err
+	}
+
+	beforeJSON, err := json.Marshal(change.Before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(`
+		INSERT INTO scenario_changes (scenario_id, action, game_id, snapshot_id, before_state, after_state, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, scenarioID, change.Action, change.GameID, change.SnapshotID, beforeJSON, afterJSON, getRequestUserID(c))
+	return err
+}
+
+// Logs a change made outside a transaction. The change itself already succeeded, so failures
+// are kept in locals like the other follow-up writes instead of failing the request.
+func logScenarioChange(db *database.DB, c *fiber.Ctx, scenarioID int, change scenarioChange) {
+	if change.GameID == nil && !change.Before.AllGames && len(change.Before.GameIDs) == 1 {
+		change.GameID = &change.Before.GameIDs[0]
+	}
+	if err := recordScenarioChange(db.Conn, c, scenarioID, change); err != nil {
+		c.Locals("history_error", err.Error())
+	}
+}
+
+// Returns the authenticated user's ID, or nil for guests
+func getRequestUserID(c *fiber.Ctx) *int {
+	isAuthenticated, _ := c.Locals("is_authenticated").(bool)
+	currentUserID, _ := c.Locals("user_id").(int)
+	if isAuthenticated && currentUserID > 0 {
+		return &currentUserID
+	}
+	return nil
+}
+
+func getHistoryEntries(q sqlExecutor, scenarioID int) ([]history.Entry, error) {
+	rows, err := q.Query(`
+		SELECT id, action, target_change_id
+		FROM scenario_changes
+		WHERE scenario_id = $1
+		ORDER BY id
+	`, scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []history.Entry
+	for rows.Next() {
+		var entry history.Entry
+		if err := rows.Scan(&entry.ID, &entry.Action, &entry.TargetID); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func getScenarioHistory(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		sID := params.ID("scenario_id")
+		limit := 100
+		if value := params.QueryInt("limit", 1, 1000); value != nil {
+			limit = *value
+		}
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		if _, err := loadComparedScenario(db, sID, c); err != nil {
+			return err
+		}
+
+		entries, err := getHistoryEntries(db.Conn, sID)
+		if err != nil {
+			return err
+		}
+		undo, redo := history.Stacks(entries)
+
+		undone := make(map[int]bool)
+		for _, id := range redo {
+			undone[id] = true
+		}
+
+		rows, err := db.Query(`
+			SELECT id, action, game_id, snapshot_id, target_change_id, user_id, created_at
+			FROM scenario_changes
+			WHERE scenario_id = $1
+			ORDER BY id DESC
+			LIMIT $2
+		`, sID, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		changes := []ScenarioChangeResponse{}
+		for rows.Next() {
+			var id int
+			var action string
+			var gameID, snapshotID, targetChangeID, userID *int
+			var createdAt time.Time
+			if err := rows.Scan(&id, &action, &gameID, &snapshotID, &targetChangeID, &userID, &createdAt); err != nil {
+				return err
+			}
+
+			changes = append(changes, ScenarioChangeResponse{
+				ID:             id,
+				Action:         action,
+				GameID:         gameID,
+				SnapshotID:     snapshotID,
+				TargetChangeID: targetChangeID,
+				UserID:         userID,
+				IsUndone:       undone[id],
+				CreatedAt:      createdAt,
+			})
+		}
+
+		return c.JSON(ScenarioHistoryResponse{
+			ScenarioID: sID,
+			CanUndo:    len(undo) > 0,
+			CanRedo:    len(redo) > 0,
+			Changes:    changes,
+		})
+	}
+}
+
+func undoScenarioChange(db *database.DB) fiber.Handler {
+	return stepScenarioHistory(db, history.ActionUndo)
+}
+
+func redoScenarioChange(db *database.DB) fiber.Handler {
+	return stepScenarioHistory(db, history.ActionRedo)
+}
+
+// Reverts the latest change (undo) or reapplies the latest undone change (redo) in one transaction
+func stepScenarioHistory(db *database.DB, action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
+		}
+		sID := access.ID
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Serialize undo and redo per scenario
+		if _, err := tx.Exec(`SELECT id FROM scenarios WHERE id = $1 FOR UPDATE`, sID); err != nil {
+			return err
+		}
+
+		entries, err := getHistoryEntries(tx, sID)
+		if err != nil {
+			return err
+		}
+		undo, redo := history.Stacks(entries)
+
+		// Undo restores the state before the change; redo restores the state after it
+		stack, stateColumn := undo, "before_state"
+		if action == history.ActionRedo {
+			stack, stateColumn = redo, "after_state"
+		}
+		if len(stack) == 0 {
+			if action == history.ActionRedo {
+				return apperror.New(apperror.NothingToRedo, "Nothing to redo")
+			}
+			return apperror.New(apperror.NothingToUndo, "Nothing to undo")
+		}
+		targetID := stack[len(stack)-1]
+
+		var targetAction string
+		var gameID *int
+		var stateJSON []byte
+		err = tx.QueryRow(`
+			SELECT action, game_id, `+stateColumn+`
+			FROM scenario_changes
+			WHERE id = $1
+		`, targetID).Scan(&targetAction, &gameID, &stateJSON)
+		if err != nil {
+			return err
+		}
+		if stateJSON == nil {
+			return apperror.Newf(apperror.ChangeNotRevertible, "Change %d cannot be reverted", targetID)
+		}
+
+		var state models.ScenarioState
+		if err := json.Unmarshal(stateJSON, &state); err != nil {
+			return err
+		}
+		locked, err := getLockedStateGameIDs(tx, access, sID, &state, time.Now())
+		if err != nil {
+			return err
+		}
+		if len(locked) > 0 {
+			return picksLockedError(locked)
+		}
+		if err := applyScenarioState(tx, sID, &state); err != nil {
+			return err
+		}
+
+		var entryID int
+		err = tx.QueryRow(`
+			INSERT INTO scenario_changes (scenario_id, action, game_id, target_change_id, user_id)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, sID, action, gameID, targetID, getRequestUserID(c)).Scan(&entryID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		standings.InvalidateScenario(sID)
+
+		undo, redo = history.Stacks(append(entries, history.Entry{ID: entryID, Action: action, TargetID: &targetID}))
+
+		return c.JSON(HistoryStepResponse{
+			ID:             entryID,
+			Action:         action,
+			TargetChangeID: targetID,
+			TargetAction:   targetAction,
+			GameID:         gameID,
+			CanUndo:        len(undo) > 0,
+			CanRedo:        len(redo) > 0,
+		})
+	}
+}
+
+func getScenarioSnapshots(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sID, err := validation.PathID(c, "scenario_id")
+		if err != nil {
+			return err
+		}
+
+		if _, err := loadComparedScenario(db, sID, c); err != nil {
+			return err
+		}
+
+		rows, err := db.Query(`
+			SELECT id, name, jsonb_array_length(state->'picks'), state->'playoffs' <> 'null'::jsonb, user_id, created_at
+			FROM scenario_snapshots
+			WHERE scenario_id = $1
+			ORDER BY created_at DESC, id DESC
+		`, sID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		snapshots := []SnapshotResponse{}
+		for rows.Next() {
+			var id, pickCount int
+			var name string
+			var hasPlayoffs bool
+			var userID *int
+			var createdAt time.Time
+			if err := rows.Scan(&id, &name, &pickCount, &hasPlayoffs, &userID, &createdAt); err != nil {
+				return err
+			}
+
+			snapshots = append(snapshots, SnapshotResponse{
+				ID:          id,
+				ScenarioID:  sID,
+				Name:        name,
+				PickCount:   pickCount,
+				HasPlayoffs: hasPlayoffs,
+				UserID:      userID,
+				CreatedAt:   createdAt,
+			})
+		}
+
+		return c.JSON(snapshots)
+	}
+}
+
+func createScenarioSnapshot(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
+		}
+		sID := access.ID
+
+		var req CreateSnapshotRequest
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+		}
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			return validation.Field("name", "Snapshot name is required")
+		}
+		if len(req.Name) > 100 {
+			return validation.Field("name", "Snapshot name must be 100 characters or fewer")
+		}
+
+		state, err := captureScenarioState(db.Conn, sID, true, nil, true)
+		if err != nil {
+			return err
+		}
+		stateJSON, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+
+		var id int
+		var createdAt time.Time
+		userID := getRequestUserID(c)
+		err = db.Conn.QueryRow(`
+			INSERT INTO scenario_snapshots (scenario_id, name, state, user_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`, sID, req.Name, stateJSON, userID).Scan(&id, &createdAt)
+		if err != nil {
+			return err
+		}
+
+		return c.Status(201).JSON(SnapshotResponse{
+			ID:          id,
+			ScenarioID:  sID,
+			Name:        req.Name,
+			PickCount:   len(state.Picks),
+			HasPlayoffs: state.Playoffs != nil,
+			UserID:      userID,
+			CreatedAt:   createdAt,
+		})
+	}
+}
+
+func deleteScenarioSnapshot(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		scenarioID, snapshotID := params.ID("scenario_id"), params.ID("snapshot_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		if _, err := authorizeScenario(db, scenarioID, ScenarioRoleEditor, c); err != nil {
+			return err
+		}
+
+		result, err := db.Conn.Exec(`
+			DELETE FROM scenario_snapshots
+			WHERE id = $1 AND scenario_id = $2
+		`, snapshotID, scenarioID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, _ := result.RowsAffected()
+		if rowsAffected == 0 {
+			return apperror.New(apperror.SnapshotNotFound, "Snapshot not found")
+		}
+
+		return c.JSON(MessageResponse{Message: "Snapshot deleted successfully"})
+	}
+}
+
+// Restores every pick and the playoff bracket from a snapshot; the restore is logged so it can be undone
+func restoreScenarioSnapshot(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		sID, snapshotID := params.ID("scenario_id"), params.ID("snapshot_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		access, err := authorizeScenario(db, sID, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.Exec(`SELECT id FROM scenarios WHERE id = $1 FOR UPDATE`, sID); err != nil {
+			return err
+		}
+
+		var name string
+		var stateJSON []byte
+		err = tx.QueryRow(`
+			SELECT name, state
+			FROM scenario_snapshots
+			WHERE id = $1 AND scenario_id = $2
+		`, snapshotID, sID).Scan(&name, &stateJSON)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.SnapshotNotFound, "Snapshot not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		var state models.ScenarioState
+		if err := json.Unmarshal(stateJSON, &state); err != nil {
+			return err
+		}
+
+		before, err := captureScenarioState(tx, sID, true, nil, true)
+		if err != nil {
+			return err
+		}
+		locked, err := getLockedStateGameIDs(tx, access, sID, &state, time.Now())
+		if err != nil {
+			return err
+		}
+		if len(locked) > 0 {
+			return picksLockedError(locked)
+		}
+		if err := applyScenarioState(tx, sID, &state); err != nil {
+			return err
+		}
+		err = recordScenarioChange(tx, c, sID, scenarioChange{
+			Action:     history.ActionSnapshotRestore,
+			SnapshotID: &snapshotID,
+			Before:     before,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		standings.InvalidateScenario(sID)
+
+		return c.JSON(RestoreSnapshotResponse{
+			Message:       fmt.Sprintf("Scenario restored to snapshot %q", name),
+			SnapshotID:    snapshotID,
+			RestoredPicks: len(state.Picks),
+			HasPlayoffs:   state.Playoffs != nil,
+		})
+	}
+}
@@ -0,0 +1,1182 @@
+// Pick'em league handlers
+
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/leagues"
+	"gamescript/internal/validation"
+)
+
+
+// League roles
+const (
+	LeagueRoleOwner  = "owner"
+	LeagueRoleMember = "member"
+)
+
+// League loaded for a request by one of its members
+type leagueMembership struct {
+	ID            int
+	Name          string
+	SportID       int
+	SeasonID      int
+	Scoring       string
+	UseTiebreaker bool
+	InviteCode    string
+	MemberID      int
+	Role          string
+}
+
+type CreateLeagueRequest struct {
+	Name          string `json:"name"`
+	SeasonID      int    `json:"season_id"`
+	Scoring       string `json:"scoring"`
+	UseTiebreaker bool   `json:"use_tiebreaker"`
+}
+
+type LeaguePickRequest struct {
+	GameID       int  `json:"game_id"`
+	PickedTeamID int  `json:"picked_team_id"`
+	Confidence   *int `json:"confidence"`
+}
+
+type SaveLeagueEntryRequest struct {
+	Picks           []LeaguePickRequest `json:"picks"`
+	TiebreakerTotal *int                `json:"tiebreaker_total"`
+}
+
+// A league in the current user's list
+type LeagueSummaryResponse struct {
+	ID				int			`json:"id"`
+	Name			string		`json:"name"`
+	SportID			int			`json:"sport_id"`
+	SeasonID		int			`json:"season_id"`
+	SportShortName	string		`json:"sport_short_name"`
+	SeasonStartYear	int			`json:"season_start_year"`
+	SeasonEndYear	*int		`json:"season_end_year"`
+	Scoring			string		`json:"scoring"`
+	UseTiebreaker	bool		`json:"use_tiebreaker"`
+	Role			string		`json:"role"`
+	MemberCount		int			`json:"member_count"`
+	CreatedAt		time.Time	`json:"created_at"`
+}
+
+type CreateLeagueResponse struct {
+	ID				int			`json:"id"`
+	Name			string		`json:"name"`
+	SportID			int			`json:"sport_id"`
+	SeasonID		int			`json:"season_id"`
+	Scoring			string		`json:"scoring"`
+	UseTiebreaker	bool		`json:"use_tiebreaker"`
+	InviteCode		string		`json:"invite_code"`
+	Role			string		`json:"role"`
+	CreatedAt		time.Time	`json:"created_at"`
+}
+
+// A league and its members; the invite code is only included for the owner
+type LeagueResponse struct {
+	ID				int						`json:"id"`
+	Name			string					`json:"name"`
+	SportID			int						`json:"sport_id"`
+	SeasonID		int						`json:"season_id"`
+	Scoring			string					`json:"scoring"`
+	UseTiebreaker	bool					`json:"use_tiebreaker"`
+	Role			string					`json:"role"`
+	MemberID		int						`json:"member_id"`
+	Members			[]LeagueMemberResponse	`json:"members"`
+	InviteCode		string					`json:"invite_code,omitempty"`
+}
+
+type LeagueMemberResponse struct {
+	ID			int			`json:"id"`
+	UserID		int			`json:"user_id"`
+	Username	string		`json:"username"`
+	Role		string		`json:"role"`
+	JoinedAt	time.Time	`json:"joined_at"`
+}
+
+type JoinLeagueResponse struct {
+	LeagueID	int		`json:"league_id"`
+	LeagueName	string	`json:"league_name"`
+	MemberID	int		`json:"member_id"`
+	Role		string	`json:"role"`
+}
+
+// The current member's entry for a week; tiebreaker is only included when the league uses one
+type LeagueEntryResponse struct {
+	LeagueID		int							`json:"league_id"`
+	MemberID		int							`json:"member_id"`
+	Week			int							`json:"week"`
+	Scoring			string						`json:"scoring"`
+	Points			int							`json:"points"`
+	CorrectPicks	int							`json:"correct_picks"`
+	GradedPicks		int							`json:"graded_picks"`
+	Games			[]LeagueEntryGameResponse	`json:"games"`
+	Tiebreaker		*LeagueTiebreakerResponse	`json:"tiebreaker,omitempty"`
+}
+
+type LeagueEntryGameResponse struct {
+	GameID		int							`json:"game_id"`
+	StartTime	time.Time					`json:"start_time"`
+	HomeTeamID	int							`json:"home_team_id"`
+	AwayTeamID	int							`json:"away_team_id"`
+	HomeAbbr	string						`json:"home_abbr"`
+	AwayAbbr	string						`json:"away_abbr"`
+	HomeScore	*int						`json:"home_score"`
+	AwayScore	*int						`json:"away_score"`
+	IsFinal		bool						`json:"is_final"`
+	IsLocked	bool						`json:"is_locked"`
+	Pick		*LeagueEntryPickResponse	`json:"pick"`
+}
+
+type LeagueEntryPickResponse struct {
+	PickedTeamID	int		`json:"picked_team_id"`
+	Confidence		*int	`json:"confidence"`
+	IsCorrect		*bool	`json:"is_correct"`
+	Points			*int	`json:"points"`
+}
+
+type LeagueTiebreakerResponse struct {
+	GameID		int		`json:"game_id"`
+	Total		*int	`json:"total"`
+	Diff		*int	`json:"diff"`
+	IsLocked	bool	`json:"is_locked"`
+}
+
+// Details are only sent when individual picks failed validation
+type LeagueEntryErrorResponse struct {
+	apperror.Problem
+	Details	[]LeaguePickError	`json:"details,omitempty"`
+}
+
+// Field names the invalid field, e.g. picks[2].picked_team_id
+type LeaguePickError struct {
+	Index	int		`json:"index"`
+	GameID	int		`json:"game_id"`
+	Field	string	`json:"field"`
+	Error	string	`json:"error"`
+}
+
+type LeagueWeekEntriesResponse struct {
+	LeagueID	int						`json:"league_id"`
+	Week		int						`json:"week"`
+	Entries		[]LeagueWeekEntryResponse	`json:"entries"`
+}
+
+// Another member's entry; tiebreaker_total is left out until the tiebreaker game locks
+type LeagueWeekEntryResponse struct {
+	MemberID		int							`json:"member_id"`
+	Username		string						`json:"username"`
+	Rank			int							`json:"rank"`
+	Points			int							`json:"points"`
+	CorrectPicks	int							`json:"correct_picks"`
+	GradedPicks		int							`json:"graded_picks"`
+	TiebreakerDiff	*int						`json:"tiebreaker_diff"`
+	Picks			[]LeagueWeekPickResponse	`json:"picks"`
+	HiddenPicks		int							`json:"hidden_picks"`
+	TiebreakerTotal	*int						`json:"tiebreaker_total,omitempty"`
+}
+
+type LeagueWeekPickResponse struct {
+	GameID			int		`json:"game_id"`
+	PickedTeamID	int		`json:"picked_team_id"`
+	Confidence		*int	`json:"confidence"`
+	IsCorrect		*bool	`json:"is_correct"`
+	Points			*int	`json:"points"`
+}
+
+type LeagueWeekStandingsResponse struct {
+	LeagueID	int								`json:"league_id"`
+	Week		int								`json:"week"`
+	Scoring		string							`json:"scoring"`
+	Standings	[]LeagueWeekStandingResponse	`json:"standings"`
+}
+
+type LeagueWeekStandingResponse struct {
+	Rank			int		`json:"rank"`
+	MemberID		int		`json:"member_id"`
+	Username		string	`json:"username"`
+	Points			int		`json:"points"`
+	CorrectPicks	int		`json:"correct_picks"`
+	GradedPicks		int		`json:"graded_picks"`
+	TiebreakerDiff	*int	`json:"tiebreaker_diff"`
+}
+
+type LeagueSeasonStandingsResponse struct {
+	LeagueID	int								`json:"league_id"`
+	Scoring		string							`json:"scoring"`
+	Standings	[]LeagueSeasonStandingResponse	`json:"standings"`
+}
+
+type LeagueSeasonStandingResponse struct {
+	Rank			int		`json:"rank"`
+	MemberID		int		`json:"member_id"`
+	Username		string	`json:"username"`
+	Points			int		`json:"points"`
+	CorrectPicks	int		`json:"correct_picks"`
+	GradedPicks		int		`json:"graded_picks"`
+	WeeksPlayed		int		`json:"weeks_played"`
+	WeeksWon		int		`json:"weeks_won"`
+}
+
+// Standings are for a single week with ?week=, otherwise for the season
+type LeagueStandingsResponse struct{}
+
+func (LeagueStandingsResponse) OneOf() []interface{} {
+	return []interface{}{LeagueWeekStandingsResponse{}, LeagueSeasonStandingsResponse{}}
+}
+
+// Game in a league week with the team details shown on an entry
+type leagueWeekGame struct {
+	Game     leagues.Game
+	HomeAbbr string
+	AwayAbbr string
+}
+
+// Stored pick on a league entry
+type leagueEntryPick struct {
+	PickedTeamID int
+	Confidence   *int
+	IsCorrect    *bool
+	Points       *int
+}
+
+// Loads a league the current user belongs to
+func loadLeagueMembership(db *database.DB, leagueID int, c *fiber.Ctx) (*leagueMembership, error) {
+	userID, _ := c.Locals("user_id").(int)
+
+	var league leagueMembership
+	var memberID *int
+	var role *string
+	err := db.Conn.QueryRow(`
+		SELECT
+			league.id, league.name, league.sport_id, league.season_id, league.scoring, league.use_tiebreaker, league.invite_code,
+			member.id, member.role
+		FROM leagues league
+		LEFT JOIN league_members member ON member.league_id = league.id AND member.user_id = $2
+		WHERE league.id = $1
+	`, leagueID, userID).Scan(
+		&league.ID, &league.Name, &league.SportID, &league.SeasonID, &league.Scoring, &league.UseTiebreaker, &league.InviteCode,
+		&memberID, &role,
+	)
+	if err == sql.ErrNoRows {
+		return nil, apperror.New(apperror.LeagueNotFound, "League not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if memberID == nil {
+		return nil, apperror.New(apperror.NotLeagueMember, "Not a member of this league")
+	}
+
+	league.MemberID = *memberID
+	league.Role = *role
+	return &league, nil
+}
+
+func getLeagues(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(int)
+
+		rows, err := db.Query(`
+			SELECT
+				league.id, league.name, league.sport_id, league.season_id, league.scoring, league.use_tiebreaker, league.created_at,
+				sport.short_name, season.start_year, season.end_year, member.role,
+				(SELECT COUNT(*) FROM league_members other WHERE other.league_id = league.id)
+			FROM league_members member
+			JOIN leagues league ON member.league_id = league.id
+			JOIN sports sport ON league.sport_id = sport.id
+			JOIN seasons season ON league.season_id = season.id
+			WHERE member.user_id = $1
+			ORDER BY league.created_at DESC
+		`, userID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		result := []LeagueSummaryResponse{}
+		for rows.Next() {
+			var id, sportID, seasonID, startYear, memberCount int
+			var endYear *int
+			var name, scoring, sportShortName, role string
+			var useTiebreaker bool
+			var createdAt time.Time
+			err := rows.Scan(&id, &name, &sportID, &seasonID, &scoring, &useTiebreaker, &createdAt,
+				&sportShortName, &startYear, &endYear, &role, &memberCount)
+			if err != nil {
+				return err
+			}
+
+			result = append(result, LeagueSummaryResponse{
+				ID:              id,
+				Name:            name,
+				SportID:         sportID,
+				SeasonID:        seasonID,
+				SportShortName:  sportShortName,
+				SeasonStartYear: startYear,
+				SeasonEndYear:   endYear,
+				Scoring:         scoring,
+				UseTiebreaker:   useTiebreaker,
+				Role:            role,
+				MemberCount:     memberCount,
+				CreatedAt:       createdAt,
+			})
+		}
+
+		return c.JSON(result)
+	}
+}
+
+func createLeague(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(int)
+
+		var req CreateLeagueRequest
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+		}
+		var required validation.Errors
+		if req.Name == "" {
+			required.Add(validation.InBody, "name", "name is required")
+		}
+		if req.SeasonID <= 0 {
+			required.Add(validation.InBody, "season_id", "season_id is required")
+		}
+		if err := required.ErrWithDetail("Missing required fields"); err != nil {
+			return err
+		}
+
+		var errs validation.Errors
+		if len(req.Name) > 100 {
+			errs.Add(validation.InBody, "name", "name must be at most 100 characters")
+		}
+		if req.Scoring == "" {
+			req.Scoring = leagues.ScoringStraightUp
+		}
+		if !leagues.IsValidScoring(req.Scoring) {
+			errs.Add(validation.InBody, "scoring", "scoring must be one of: straight_up, confidence")
+		}
+		if err := errs.Err(); err != nil {
+			return err
+		}
+
+		var sportID int
+		err := db.Conn.QueryRow(`SELECT sport_id FROM seasons WHERE id = $1`, req.SeasonID).Scan(&sportID)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.SeasonNotFound, "Season not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var id int
+		var createdAt time.Time
+		inviteCode := generateSessionToken()[:16]
+		err = tx.QueryRow(`
+			INSERT INTO leagues (name, sport_id, season_id, owner_user_id, scoring, use_tiebreaker, invite_code)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, created_at
+		`, req.Name, sportID, req.SeasonID, userID, req.Scoring, req.UseTiebreaker, inviteCode).Scan(&id, &createdAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO league_members (league_id, user_id, role)
+			VALUES ($1, $2, $3)
+		`, id, userID, LeagueRoleOwner)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		return c.Status(201).JSON(CreateLeagueResponse{
+			ID:            id,
+			Name:          req.Name,
+			SportID:       sportID,
+			SeasonID:      req.SeasonID,
+			Scoring:       req.Scoring,
+			UseTiebreaker: req.UseTiebreaker,
+			InviteCode:    inviteCode,
+			Role:          LeagueRoleOwner,
+			CreatedAt:     createdAt,
+		})
+	}
+}
+
+func getLeague(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		leagueID, err := validation.PathID(c, "league_id")
+		if err != nil {
+			return err
+		}
+		league, err := loadLeagueMembership(db, leagueID, c)
+		if err != nil {
+			return err
+		}
+
+		rows, err := db.Query(`
+			SELECT member.id, member.user_id, member_user.username, member.role, member.joined_at
+			FROM league_members member
+			JOIN users member_user ON member.user_id = member_user.id
+			WHERE member.league_id = $1
+			ORDER BY member.joined_at, member.id
+		`, league.ID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		members := []LeagueMemberResponse{}
+		for rows.Next() {
+			var id, userID int
+			var username, role string
+			var joinedAt time.Time
+			if err := rows.Scan(&id, &userID, &username, &role, &joinedAt); err != nil {
+				return err
+			}
+			members = append(members, LeagueMemberResponse{
+				ID:       id,
+				UserID:   userID,
+				Username: username,
+				Role:     role,
+				JoinedAt: joinedAt,
+			})
+		}
+
+		response := LeagueResponse{
+			ID:            league.ID,
+			Name:          league.Name,
+			SportID:       league.SportID,
+			SeasonID:      league.SeasonID,
+			Scoring:       league.Scoring,
+			UseTiebreaker: league.UseTiebreaker,
+			Role:          league.Role,
+			MemberID:      league.MemberID,
+			Members:       members,
+		}
+		// Only the owner can share the invite code
+		if league.Role == LeagueRoleOwner {
+			response.InviteCode = league.InviteCode
+		}
+
+		return c.JSON(response)
+	}
+}
+
+func deleteLeague(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		leagueID, err := validation.PathID(c, "league_id")
+		if err != nil {
+			return err
+		}
+		league, err := loadLeagueMembership(db, leagueID, c)
+		if err != nil {
+			return err
+		}
+		if league.Role != LeagueRoleOwner {
+			return apperror.New(apperror.Forbidden, "Unauthorized")
+		}
+
+		_, err = db.Conn.Exec(`DELETE FROM leagues WHERE id = $1`, league.ID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(MessageResponse{Message: "League deleted successfully"})
+	}
+}
+
+func joinLeague(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(int)
+
+		params := validation.NewParams(c)
+		inviteCode := params.Token("invite_code")
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		var leagueID int
+		var name string
+		err := db.Conn.QueryRow(`
+			SELECT id, name FROM leagues WHERE invite_code = $1
+		`, inviteCode).Scan(&leagueID, &name)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.LeagueNotFound, "League not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		var memberID int
+		var role string
+		err = db.Conn.QueryRow(`
+			INSERT INTO league_members (league_id, user_id, role)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (league_id, user_id) DO NOTHING
+			RETURNING id, role
+		`, leagueID, userID, LeagueRoleMember).Scan(&memberID, &role)
+		responseStatus := 201
+		if err == sql.ErrNoRows {
+			// Already a member
+			err = db.Conn.QueryRow(`
+				SELECT id, role FROM league_members WHERE league_id = $1 AND user_id = $2
+			`, leagueID, userID).Scan(&memberID, &role)
+			responseStatus = 200
+		}
+		if err != nil {
+			return err
+		}
+
+		return c.Status(responseStatus).JSON(JoinLeagueResponse{
+			LeagueID:   leagueID,
+			LeagueName: name,
+			MemberID:   memberID,
+			Role:       role,
+		})
+	}
+}
+
+// Removes a member and their entries; the owner can remove anyone else and members can leave
+func removeLeagueMember(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		leagueID, memberID := params.ID("league_id"), params.ID("member_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		league, err := loadLeagueMembership(db, leagueID, c)
+		if err != nil {
+			return err
+		}
+
+		var role string
+		err = db.Conn.QueryRow(`
+			SELECT role FROM league_members WHERE id = $1 AND league_id = $2
+		`, memberID, league.ID).Scan(&role)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.MemberNotFound, "Member not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if league.Role != LeagueRoleOwner && memberID != league.MemberID {
+			return apperror.New(apperror.Forbidden, "Unauthorized")
+		}
+		if role == LeagueRoleOwner {
+			return apperror.New(apperror.LeagueOwnerNotRemovable, "The league owner can't be removed; delete the league instead")
+		}
+
+		_, err = db.Conn.Exec(`DELETE FROM league_members WHERE id = $1`, memberID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(MessageResponse{Message: "Member removed successfully"})
+	}
+}
+
+func getLeagueEntry(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		leagueID, week := params.ID("league_id"), params.Int("week", 1, maxWeek)
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		league, err := loadLeagueMembership(db, leagueID, c)
+		if err != nil {
+			return err
+		}
+
+		entry, err := buildLeagueEntry(db, league, week, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(entry)
+	}
+}
+
+// Saves the current member's picks for a week. Picks on games that have started are locked:
+// they can be sent back unchanged but not added, changed, or removed. Unlocked games left out
+// of the request have their picks removed.
+func saveLeagueEntry(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		leagueID, week := params.ID("league_id"), params.Int("week", 1, maxWeek)
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		league, err := loadLeagueMembership(db, leagueID, c)
+		if err != nil {
+			return err
+		}
+
+		var req SaveLeagueEntryRequest
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+		}
+
+		games, err := leagues.GetWeekGames(db, league.SeasonID, week)
+		if err != nil {
+			return err
+		}
+		if len(games) == 0 {
+			return apperror.New(apperror.GameNotFound, "No games found for this week")
+		}
+		gamesByID := make(map[int]leagues.Game)
+		for _, game := range games {
+			gamesByID[game.ID] = game
+		}
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Lock the entry so concurrent saves from the same member apply one at a time
+		var entryID int
+		var currentTiebreaker *int
+		err = tx.QueryRow(`
+			INSERT INTO league_entries (league_id, member_id, week)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (member_id, week) DO UPDATE SET updated_at = league_entries.updated_at
+			RETURNING id, tiebreaker_total
+		`, league.ID, league.MemberID, week).Scan(&entryID, &currentTiebreaker)
+		if err != nil {
+			return err
+		}
+
+		existing, err := getLeagueEntryPicks(tx, entryID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var validationErrors []LeaguePickError
+		addValidationError := func(index int, gameID int, field string, message string) {
+			validationErrors = append(validationErrors, LeaguePickError{
+				Index:  index,
+				GameID: gameID,
+				Field:  fmt.Sprintf("picks[%d].%s", index, field),
+				Error:  message,
+			})
+		}
+
+		// Locked picks carry over, and submitted picks replace everything else
+		merged := make(map[int]leagues.Pick)
+		for gameID, pick := range existing {
+			if leagues.IsLocked(gamesByID[gameID], now) {
+				merged[gameID] = leagues.Pick{GameID: gameID, PickedTeamID: pick.PickedTeamID, Confidence: pick.Confidence}
+			}
+		}
+
+		seen := make(map[int]bool)
+		for i, pick := range req.Picks {
+			game, exists := gamesByID[pick.GameID]
+			if !exists {
+				addValidationError(i, pick.GameID, "game_id", "Game not found in this week")
+				continue
+			}
+			if seen[pick.GameID] {
+				addValidationError(i, pick.GameID, "game_id", "Game appears more than once")
+				continue
+			}
+			seen[pick.GameID] = true
+
+			if pick.PickedTeamID != game.HomeTeamID && pick.PickedTeamID != game.AwayTeamID {
+				addValidationError(i, pick.GameID, "picked_team_id", "Picked team is not playing in this game")
+				continue
+			}
+			if league.Scoring != leagues.ScoringConfidence {
+				pick.Confidence = nil
+			}
+
+			if leagues.IsLocked(game, now) {
+				previous, picked := existing[pick.GameID]
+				if !picked || previous.PickedTeamID != pick.PickedTeamID || !equalIntPtr(previous.Confidence, pick.Confidence) {
+					addValidationError(i, pick.GameID, "picked_team_id", "Game has started and its pick is locked")
+				}
+				continue
+			}
+
+			merged[pick.GameID] = leagues.Pick{GameID: pick.GameID, PickedTeamID: pick.PickedTeamID, Confidence: pick.Confidence}
+		}
+
+		if len(validationErrors) > 0 {
+			return apperror.New(apperror.ValidationFailed, "Invalid picks").With(LeagueEntryErrorResponse{Details: validationErrors})
+		}
+
+		if league.Scoring == leagues.ScoringConfidence {
+			picks := make([]leagues.Pick, 0, len(merged))
+			for _, pick := range merged {
+				picks = append(picks, pick)
+			}
+			sort.Slice(picks, func(i, j int) bool { return picks[i].GameID < picks[j].GameID })
+			if err := leagues.ValidateConfidence(picks, len(games)); err != nil {
+				return validation.Field("picks", err.Error())
+			}
+		}
+
+		tiebreakerTotal := currentTiebreaker
+		if league.UseTiebreaker {
+			tiebreakerGame := leagues.TiebreakerGame(games)
+			if !equalIntPtr(req.TiebreakerTotal, currentTiebreaker) {
+				if leagues.IsLocked(*tiebreakerGame, now) {
+					return apperror.New(apperror.TiebreakerLocked, "The tiebreaker game has started and the tiebreaker is locked")
+				}
+				if req.TiebreakerTotal != nil && *req.TiebreakerTotal < 0 {
+					return validation.Field("tiebreaker_total", "tiebreaker_total cannot be negative")
+				}
+				tiebreakerTotal = req.TiebreakerTotal
+			}
+		}
+
+		// Locked picks stay as they are; unlocked picks are replaced
+		for gameID := range existing {
+			if leagues.IsLocked(gamesByID[gameID], now) {
+				continue
+			}
+			_, err = tx.Exec(`DELETE FROM league_picks WHERE entry_id = $1 AND game_id = $2`, entryID, gameID)
+			if err != nil {
+				return err
+			}
+		}
+		for gameID, pick := range merged {
+			if leagues.IsLocked(gamesByID[gameID], now) {
+				continue
+			}
+			_, err = tx.Exec(`
+				INSERT INTO league_picks (entry_id, game_id, picked_team_id, confidence)
+				VALUES ($1, $2, $3, $4)
+			`, entryID, gameID, pick.PickedTeamID, pick.Confidence)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`
+			UPDATE league_entries SET tiebreaker_total = $1, updated_at = NOW() WHERE id = $2
+		`, tiebreakerTotal, entryID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		entry, err := buildLeagueEntry(db, league, week, now)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(entry)
+	}
+}
+
+// Lists every member's entry for a week. Picks on games that haven't started yet stay hidden
+// from other members, so nobody can copy a pick before it locks.
+func getLeagueWeekEntries(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		leagueID, week := params.ID("league_id"), params.Int("week", 1, maxWeek)
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		league, err := loadLeagueMembership(db, leagueID, c)
+		if err != nil {
+			return err
+		}
+
+		games, err := leagues.GetWeekGames(db, league.SeasonID, week)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		locked := make(map[int]bool)
+		for _, game := range games {
+			locked[game.ID] = leagues.IsLocked(game, now)
+		}
+		tiebreakerLocked := false
+		if tiebreakerGame := leagues.TiebreakerGame(games); tiebreakerGame != nil {
+			tiebreakerLocked = leagues.IsLocked(*tiebreakerGame, now)
+		}
+
+		results, entryIDs, err := getLeagueWeekResults(db, league.ID, week)
+		if err != nil {
+			return err
+		}
+
+		entries := []LeagueWeekEntryResponse{}
+		for _, standing := range leagues.RankWeek(results) {
+			entryID := entryIDs[standing.MemberID]
+			isOwn := standing.MemberID == league.MemberID
+
+			storedPicks, err := getLeagueEntryPicks(db.Conn, entryID)
+			if err != nil {
+				return err
+			}
+
+			picks := []LeagueWeekPickResponse{}
+			hidden := 0
+			for _, game := range games {
+				pick, exists := storedPicks[game.ID]
+				if !exists {
+					continue
+				}
+				if !isOwn && !locked[game.ID] {
+					hidden++
+					continue
+				}
+				picks = append(picks, LeagueWeekPickResponse{
+					GameID:       game.ID,
+					PickedTeamID: pick.PickedTeamID,
+					Confidence:   pick.Confidence,
+					IsCorrect:    pick.IsCorrect,
+					Points:       pick.Points,
+				})
+			}
+
+			entry := LeagueWeekEntryResponse{
+				MemberID:       standing.MemberID,
+				Username:       standing.Username,
+				Rank:           standing.Rank,
+				Points:         standing.Points,
+				CorrectPicks:   standing.CorrectPicks,
+				GradedPicks:    standing.GradedPicks,
+				TiebreakerDiff: standing.TiebreakerDiff,
+				Picks:          picks,
+				HiddenPicks:    hidden,
+			}
+			if league.UseTiebreaker && (isOwn || tiebreakerLocked) {
+				var tiebreakerTotal *int
+				err := db.Conn.QueryRow(`SELECT tiebreaker_total FROM league_entries WHERE id = $1`, entryID).Scan(&tiebreakerTotal)
+				if err != nil {
+					return err
+				}
+				entry.TiebreakerTotal = tiebreakerTotal
+			}
+			entries = append(entries, entry)
+		}
+
+		return c.JSON(LeagueWeekEntriesResponse{
+			LeagueID: league.ID,
+			Week:     week,
+			Entries:  entries,
+		})
+	}
+}
+
+// Weekly standings with ?week=, otherwise season standings
+func getLeagueStandings(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		leagueID, week := params.ID("league_id"), params.QueryInt("week", 1, maxWeek)
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		league, err := loadLeagueMembership(db, leagueID, c)
+		if err != nil {
+			return err
+		}
+
+		if week != nil {
+			week := *week
+			results, _, err := getLeagueWeekResults(db, league.ID, week)
+			if err != nil {
+				return err
+			}
+
+			standings := []LeagueWeekStandingResponse{}
+			for _, standing := range leagues.RankWeek(results) {
+				standings = append(standings, LeagueWeekStandingResponse{
+					Rank:           standing.Rank,
+					MemberID:       standing.MemberID,
+					Username:       standing.Username,
+					Points:         standing.Points,
+					CorrectPicks:   standing.CorrectPicks,
+					GradedPicks:    standing.GradedPicks,
+					TiebreakerDiff: standing.TiebreakerDiff,
+				})
+			}
+
+			return c.JSON(LeagueWeekStandingsResponse{
+				LeagueID:  league.ID,
+				Week:      week,
+				Scoring:   league.Scoring,
+				Standings: standings,
+			})
+		}
+
+		rows, err := db.Query(`
+			SELECT DISTINCT entry.week
+			FROM league_entries entry
+			WHERE entry.league_id = $1
+		`, league.ID)
+		if err != nil {
+			return err
+		}
+		var weeks []int
+		for rows.Next() {
+			var week int
+			if err := rows.Scan(&week); err != nil {
+				rows.Close()
+				return err
+			}
+			weeks = append(weeks, week)
+		}
+		rows.Close()
+
+		// A week is complete once every game in it is final
+		completeWeeks := make(map[int]bool)
+		rows, err = db.Query(`
+			SELECT week
+			FROM games
+			WHERE season_id = $1 AND week IS NOT NULL
+			GROUP BY week
+			HAVING BOOL_AND(status = 'final')
+		`, league.SeasonID)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var week int
+			if err := rows.Scan(&week); err != nil {
+				rows.Close()
+				return err
+			}
+			completeWeeks[week] = true
+		}
+		rows.Close()
+
+		weekResults := make(map[int][]leagues.EntryResult)
+		for _, week := range weeks {
+			results, _, err := getLeagueWeekResults(db, league.ID, week)
+			if err != nil {
+				return err
+			}
+			weekResults[week] = results
+		}
+
+		standings := []LeagueSeasonStandingResponse{}
+		for _, standing := range leagues.RankSeason(weekResults, completeWeeks) {
+			standings = append(standings, LeagueSeasonStandingResponse{
+				Rank:         standing.Rank,
+				MemberID:     standing.MemberID,
+				Username:     standing.Username,
+				Points:       standing.Points,
+				CorrectPicks: standing.CorrectPicks,
+				GradedPicks:  standing.GradedPicks,
+				WeeksPlayed:  standing.WeeksPlayed,
+				WeeksWon:     standing.WeeksWon,
+			})
+		}
+
+		return c.JSON(LeagueSeasonStandingsResponse{
+			LeagueID:  league.ID,
+			Scoring:   league.Scoring,
+			Standings: standings,
+		})
+	}
+}
+
+// Builds the current member's entry for a week with every game, its lock state, and the member's picks
+func buildLeagueEntry(db *database.DB, league *leagueMembership, week int, now time.Time) (*LeagueEntryResponse, error) {
+	games, err := getLeagueWeekGames(db, league.SeasonID, week)
+	if err != nil {
+		return nil, err
+	}
+	if len(games) == 0 {
+		return nil, apperror.New(apperror.GameNotFound, "No games found for this week")
+	}
+
+	var entryID *int
+	var tiebreakerTotal, tiebreakerDiff *int
+	var points, correctPicks, gradedPicks int
+	err = db.Conn.QueryRow(`
+		SELECT id, tiebreaker_total, points, correct_picks, graded_picks, tiebreaker_diff
+		FROM league_entries
+		WHERE member_id = $1 AND week = $2
+	`, league.MemberID, week).Scan(&entryID, &tiebreakerTotal, &points, &correctPicks, &gradedPicks, &tiebreakerDiff)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	picks := make(map[int]leagueEntryPick)
+	if entryID != nil {
+		picks, err = getLeagueEntryPicks(db.Conn, *entryID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	weekGames := make([]leagues.Game, len(games))
+	for i, game := range games {
+		weekGames[i] = game.Game
+	}
+	tiebreakerGame := leagues.TiebreakerGame(weekGames)
+
+	gameList := []LeagueEntryGameResponse{}
+	for _, game := range games {
+		item := LeagueEntryGameResponse{
+			GameID:     game.Game.ID,
+			StartTime:  game.Game.StartTime,
+			HomeTeamID: game.Game.HomeTeamID,
+			AwayTeamID: game.Game.AwayTeamID,
+			HomeAbbr:   game.HomeAbbr,
+			AwayAbbr:   game.AwayAbbr,
+			HomeScore:  game.Game.HomeScore,
+			AwayScore:  game.Game.AwayScore,
+			IsFinal:    game.Game.IsFinal,
+			IsLocked:   leagues.IsLocked(game.Game, now),
+		}
+		if pick, exists := picks[game.Game.ID]; exists {
+			item.Pick = &LeagueEntryPickResponse{
+				PickedTeamID: pick.PickedTeamID,
+				Confidence:   pick.Confidence,
+				IsCorrect:    pick.IsCorrect,
+				Points:       pick.Points,
+			}
+		}
+		gameList = append(gameList, item)
+	}
+
+	entry := &LeagueEntryResponse{
+		LeagueID:     league.ID,
+		MemberID:     league.MemberID,
+		Week:         week,
+		Scoring:      league.Scoring,
+		Points:       points,
+		CorrectPicks: correctPicks,
+		GradedPicks:  gradedPicks,
+		Games:        gameList,
+	}
+	if league.UseTiebreaker {
+		entry.Tiebreaker = &LeagueTiebreakerResponse{
+			GameID:   tiebreakerGame.ID,
+			Total:    tiebreakerTotal,
+			Diff:     tiebreakerDiff,
+			IsLocked: leagues.IsLocked(*tiebreakerGame, now),
+		}
+	}
+
+	return entry, nil
+}
+
+func getLeagueWeekGames(db *database.DB, seasonID int, week int) ([]leagueWeekGame, error) {
+	rows, err := db.Query(`
+		SELECT
+			game.id, game.week, game.start_time, game.home_team_id, game.away_team_id,
+			game.home_score, game.away_score, game.status,
+			home_team.abbreviation, away_team.abbreviation
+		FROM games game
+		JOIN teams home_team ON game.home_team_id = home_team.id
+		JOIN teams away_team ON game.away_team_id = away_team.id
+		WHERE game.season_id = $1 AND game.week = $2
+		ORDER BY game.start_time, game.id
+	`, seasonID, week)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []leagueWeekGame
+	for rows.Next() {
+		var game leagueWeekGame
+		var status *string
+		err := rows.Scan(
+			&game.Game.ID, &game.Game.Week, &game.Game.StartTime, &game.Game.HomeTeamID, &game.Game.AwayTeamID,
+			&game.Game.HomeScore, &game.Game.AwayScore, &status,
+			&game.HomeAbbr, &game.AwayAbbr,
+		)
+		if err != nil {
+			return nil, err
+		}
+		game.Game.IsFinal = status != nil && *status == "final"
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
+
+func getLeagueEntryPicks(q sqlExecutor, entryID int) (map[int]leagueEntryPick, error) {
+	rows, err := q.Query(`
+		SELECT game_id, picked_team_id, confidence, is_correct, points
+		FROM league_picks
+		WHERE entry_id = $1
+	`, entryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	picks := make(map[int]leagueEntryPick)
+	for rows.Next() {
+		var gameID int
+		var pick leagueEntryPick
+		if err := rows.Scan(&gameID, &pick.PickedTeamID, &pick.Confidence, &pick.IsCorrect, &pick.Points); err != nil {
+			return nil, err
+		}
+		picks[gameID] = pick
+	}
+
+	return picks, rows.Err()
+}
+
+// Gets graded totals for every entry in a league week, with entry IDs by member
+func getLeagueWeekResults(db *database.DB, leagueID int, week int) ([]leagues.EntryResult, map[int]int, error) {
+	rows, err := db.Query(`
+		SELECT entry.id, member.id, member_user.username, entry.points, entry.correct_picks, entry.graded_picks, entry.tiebreaker_diff
+		FROM league_entries entry
+		JOIN league_members member ON entry.member_id = member.id
+		JOIN users member_user ON member.user_id = member_user.id
+		WHERE entry.league_id = $1 AND entry.week = $2
+	`, leagueID, week)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var results []leagues.EntryResult
+	entryIDs := make(map[int]int)
+	for rows.Next() {
+		var entryID int
+		var result leagues.EntryResult
+		err := rows.Scan(&entryID, &result.MemberID, &result.Username, &result.Points, &result.CorrectPicks, &result.GradedPicks, &result.TiebreakerDiff)
+		if err != nil {
+			return nil, nil, err
+		}
+		results = append(results, result)
+		entryIDs[result.MemberID] = entryID
+	}
+
+	return results, entryIDs, rows.Err()
+}
@@ -0,0 +1,165 @@
+// Pick locking for prediction scenarios
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/models"
+)
+
+
+// Scenario modes; prediction scenarios lock each pick when its game starts
+const (
+	ScenarioModeWhatIf     = "what_if"
+	ScenarioModePrediction = "prediction"
+)
+
+// Rejects a change that touches games whose picks are locked
+type PicksLockedResponse struct {
+	apperror.Problem
+	LockedGameIDs	[]int	`json:"locked_game_ids"`
+}
+
+func isValidScenarioMode(mode string) bool {
+	return mode == ScenarioModeWhatIf || mode == ScenarioModePrediction
+}
+
+// Whether a pick on a game is locked, judged against server time
+func isPickLocked(mode string, startTime time.Time, now time.Time) bool {
+	return mode == ScenarioModePrediction && !now.Before(startTime)
+}
+
+// Gets which of the given games have started by server time. Games are stored in UTC without a
+// zone, so the cutoff is passed the same way rather than compared against the database clock.
+func getStartedGameIDs(q sqlExecutor, gameIDs []int, now time.Time) ([]int, error) {
+	if len(gameIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.Query(`
+		SELECT id
+		FROM games
+		WHERE id = ANY($1) AND start_time <= $2::timestamp
+		ORDER BY start_time, id
+	`, pq.Array(gameIDs), now.UTC().Format("2006-01-02 15:04:05.999999"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var started []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		started = append(started, id)
+	}
+
+	return started, rows.Err()
+}
+
+// Gets the games in a season that have started by server time
+func getStartedSeasonGameIDs(q sqlExecutor, seasonID int, now time.Time) ([]int, error) {
+	rows, err := q.Query(`SELECT id FROM games WHERE season_id = $1`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gameIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		gameIDs = append(gameIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return getStartedGameIDs(q, gameIDs, now)
+}
+
+// Gets the games a scenario has regular season picks on that have started by server time
+func getStartedPickGameIDs(q sqlExecutor, scenarioID int, now time.Time) ([]int, error) {
+	rows, err := q.Query(`SELECT game_id FROM picks WHERE scenario_id = $1`, scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gameIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		gameIDs = append(gameIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return getStartedGameIDs(q, gameIDs, now)
+}
+
+// Gets the games among gameIDs whose picks can no longer change in this scenario
+func getLockedGameIDs(q sqlExecutor, access *scenarioAccess, gameIDs []int, now time.Time) ([]int, error) {
+	if access.Mode != ScenarioModePrediction {
+		return nil, nil
+	}
+	return getStartedGameIDs(q, gameIDs, now)
+}
+
+// Gets the games whose picks would change by moving from one scenario state to another
+func getChangedStateGameIDs(current *models.ScenarioState, target *models.ScenarioState) []int {
+	currentPicks := make(map[int]models.StatePick)
+	for _, pick := range current.Picks {
+		currentPicks[pick.GameID] = pick
+	}
+	targetPicks := make(map[int]models.StatePick)
+	for _, pick := range target.Picks {
+		targetPicks[pick.GameID] = pick
+	}
+
+	var changed []int
+	for gameID, pick := range currentPicks {
+		targetPick, exists := targetPicks[gameID]
+		if !exists || !equalIntPtr(pick.PickedTeamID, targetPick.PickedTeamID) ||
+			!equalIntPtr(pick.PredictedHomeScore, targetPick.PredictedHomeScore) ||
+			!equalIntPtr(pick.PredictedAwayScore, targetPick.PredictedAwayScore) ||
+			pick.IsOverride != targetPick.IsOverride {
+			changed = append(changed, gameID)
+		}
+	}
+	for gameID := range targetPicks {
+		if _, exists := currentPicks[gameID]; !exists {
+			changed = append(changed, gameID)
+		}
+	}
+
+	return changed
+}
+
+// Gets the locked games whose picks would change by applying a saved state to a scenario
+func getLockedStateGameIDs(q sqlExecutor, access *scenarioAccess, scenarioID int, target *models.ScenarioState, now time.Time) ([]int, error) {
+	if access.Mode != ScenarioModePrediction {
+		return nil, nil
+	}
+	current, err := captureScenarioState(q, scenarioID, target.AllGames, target.GameIDs, false)
+	if err != nil {
+		return nil, err
+	}
+	return getStartedGameIDs(q, getChangedStateGameIDs(current, target), now)
+}
+
+func picksLockedError(gameIDs []int) error {
+	return apperror.New(apperror.PicksLocked, "Picks are locked for games that have already started").
+		With(PicksLockedResponse{LockedGameIDs: gameIDs})
+}
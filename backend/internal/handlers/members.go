@@ -0,0 +1,522 @@
+// Scenario members, invites, and role-based authorization handlers
+
+package handlers
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/store"
+	"gamescript/internal/validation"
+)
+
+
+// Scenario roles, from least to most access
+const (
+	ScenarioRoleViewer = "viewer"
+	ScenarioRoleEditor = "editor"
+	ScenarioRoleOwner  = "owner"
+)
+
+var scenarioRoleRanks = map[string]int{
+	ScenarioRoleViewer: 1,
+	ScenarioRoleEditor: 2,
+	ScenarioRoleOwner:  3,
+}
+
+// Scenario loaded for an authorized request, with the current user's role on it
+type scenarioAccess struct {
+	ID         int
+	Name       string
+	SportID    int
+	SeasonID   int
+	IsPublic   bool
+	Mode       string
+	ResultMode string
+	Role       string
+}
+
+type CreateInviteRequest struct {
+	Role           string `json:"role"`
+	MaxUses        *int   `json:"max_uses"`
+	ExpiresInHours *int   `json:"expires_in_hours"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role"`
+}
+
+type ScenarioMembersResponse struct {
+	ScenarioID int                      `json:"scenario_id"`
+	Role       string                   `json:"role"`
+	Owner      ScenarioOwnerResponse    `json:"owner"`
+	Members    []ScenarioMemberResponse `json:"members"`
+}
+
+// Username is null for guest sessions
+type ScenarioOwnerResponse struct {
+	UserID   *int    `json:"user_id"`
+	Username *string `json:"username"`
+	IsGuest  bool    `json:"is_guest"`
+}
+
+type ScenarioMemberResponse struct {
+	ID        int       `json:"id"`
+	UserID    *int      `json:"user_id"`
+	Username  *string   `json:"username"`
+	IsGuest   bool      `json:"is_guest"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type UpdateMemberResponse struct {
+	ID         int       `json:"id"`
+	ScenarioID int       `json:"scenario_id"`
+	UserID     *int      `json:"user_id"`
+	Role       string    `json:"role"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Max uses and expiry are null for invites without a limit
+type ScenarioInviteResponse struct {
+	ID         int        `json:"id"`
+	ScenarioID int        `json:"scenario_id"`
+	Token      string     `json:"token"`
+	Role       string     `json:"role"`
+	MaxUses    *int       `json:"max_uses"`
+	Uses       int        `json:"uses"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Member ID is left out when the scenario's owner accepts their own invite
+type AcceptInviteResponse struct {
+	ScenarioID   int    `json:"scenario_id"`
+	ScenarioName string `json:"scenario_name"`
+	Role         string `json:"role"`
+	MemberID     *int   `json:"member_id,omitempty"`
+}
+
+// Authorizes the current user or guest session for at least the required role on a scenario.
+// The scenario's own user or session is always an owner and members have their stored role; anyone
+// else has no role. An empty required role loads the scenario without checking the caller's role.
+func authorizeScenario(db *database.DB, scenarioID int, required string, c *fiber.Ctx) (*scenarioAccess, error) {
+	return authorizeStoredScenario(store.NewPostgres(db), scenarioID, required, c)
+}
+
+// Authorizes the scenario named by the scenario_id path parameter
+func authorizeScenarioParam(db *database.DB, required string, c *fiber.Ctx) (*scenarioAccess, error) {
+	scenarioID, err := validation.PathID(c, "scenario_id")
+	if err != nil {
+		return nil, err
+	}
+	return authorizeScenario(db, scenarioID, required, c)
+}
+
+func authorizeStoredScenario(data store.Store, scenarioID int, required string, c *fiber.Ctx) (*scenarioAccess, error) {
+	isAuthenticated, _ := c.Locals("is_authenticated").(bool)
+	currentUserID, _ := c.Locals("user_id").(int)
+	currentSessionToken, _ := c.Locals("session_token").(string)
+	if isAuthenticated {
+		currentSessionToken = ""
+	} else {
+		currentUserID = 0
+	}
+
+	scenario, err := data.ScenarioOwner(scenarioID, currentUserID, currentSessionToken)
+	if err == store.ErrNotFound {
+		return nil, apperror.New(apperror.ScenarioNotFound, "Scenario not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	access := scenarioAccess{
+		ID:         scenario.ID,
+		Name:       scenario.Name,
+		SportID:    scenario.SportID,
+		SeasonID:   scenario.SeasonID,
+		IsPublic:   scenario.IsPublic,
+		Mode:       scenario.Mode,
+		ResultMode: scenario.ResultMode,
+	}
+	if isScenarioOwner(c, scenario.UserID, scenario.SessionToken) {
+		access.Role = ScenarioRoleOwner
+	} else if scenario.MemberRole != nil {
+		access.Role = *scenario.MemberRole
+	}
+
+	if scenarioRoleRanks[access.Role] < scenarioRoleRanks[required] {
+		return nil, apperror.New(apperror.Forbidden, "Unauthorized")
+	}
+
+	return &access, nil
+}
+
+func getScenarioMembers(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleViewer, c)
+		if err != nil {
+			return err
+		}
+
+		var ownerUserID *int
+		var ownerUsername *string
+		err = db.Conn.QueryRow(`
+			SELECT scenario.user_id, owner.username
+			FROM scenarios scenario
+			LEFT JOIN users owner ON scenario.user_id = owner.id
+			WHERE scenario.id = $1
+		`, access.ID).Scan(&ownerUserID, &ownerUsername)
+		if err != nil {
+			return err
+		}
+
+		rows, err := db.Query(`
+			SELECT member.id, member.user_id, member_user.username, member.role, member.created_at, member.updated_at
+			FROM scenario_members member
+			LEFT JOIN users member_user ON member.user_id = member_user.id
+			WHERE member.scenario_id = $1
+			ORDER BY member.created_at, member.id
+		`, access.ID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		members := []ScenarioMemberResponse{}
+		for rows.Next() {
+			var member ScenarioMemberResponse
+			if err := rows.Scan(&member.ID, &member.UserID, &member.Username, &member.Role, &member.CreatedAt, &member.UpdatedAt); err != nil {
+				return err
+			}
+			member.IsGuest = member.UserID == nil
+			members = append(members, member)
+		}
+
+		return c.JSON(ScenarioMembersResponse{
+			ScenarioID: access.ID,
+			Role:       access.Role,
+			Owner: ScenarioOwnerResponse{
+				UserID:   ownerUserID,
+				Username: ownerUsername,
+				IsGuest:  ownerUserID == nil,
+			},
+			Members: members,
+		})
+	}
+}
+
+func updateScenarioMember(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		scenarioID, memberID := params.ID("scenario_id"), params.ID("member_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		access, err := authorizeScenario(db, scenarioID, ScenarioRoleOwner, c)
+		if err != nil {
+			return err
+		}
+
+		var req UpdateMemberRequest
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+		}
+		if _, valid := scenarioRoleRanks[req.Role]; !valid {
+			return validation.Field("role", "role must be one of: owner, editor, viewer")
+		}
+
+		var id int
+		var userID *int
+		var updatedAt time.Time
+		err = db.Conn.QueryRow(`
+			UPDATE scenario_members
+			SET role = $1, updated_at = NOW()
+			WHERE id = $2 AND scenario_id = $3
+			RETURNING id, user_id, updated_at
+		`, req.Role, memberID, access.ID).Scan(&id, &userID, &updatedAt)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.MemberNotFound, "Member not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(UpdateMemberResponse{
+			ID:         id,
+			ScenarioID: access.ID,
+			UserID:     userID,
+			Role:       req.Role,
+			UpdatedAt:  updatedAt,
+		})
+	}
+}
+
+// Removes a member; owners can remove anyone and members can remove themselves
+func removeScenarioMember(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		scenarioID, memberID := params.ID("scenario_id"), params.ID("member_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		access, err := authorizeScenario(db, scenarioID, ScenarioRoleViewer, c)
+		if err != nil {
+			return err
+		}
+
+		var memberUserID *int
+		var memberSessionToken *string
+		err = db.Conn.QueryRow(`
+			SELECT user_id, session_token
+			FROM scenario_members
+			WHERE id = $1 AND scenario_id = $2
+		`, memberID, access.ID).Scan(&memberUserID, &memberSessionToken)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.MemberNotFound, "Member not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if access.Role != ScenarioRoleOwner && !isScenarioOwner(c, memberUserID, memberSessionToken) {
+			return apperror.New(apperror.Forbidden, "Unauthorized")
+		}
+
+		_, err = db.Conn.Exec(`DELETE FROM scenario_members WHERE id = $1`, memberID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(MessageResponse{Message: "Member removed successfully"})
+	}
+}
+
+func getScenarioInvites(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleOwner, c)
+		if err != nil {
+			return err
+		}
+
+		rows, err := db.Query(`
+			SELECT id, token, role, max_uses, uses, expires_at, created_at
+			FROM scenario_invites
+			WHERE scenario_id = $1
+			ORDER BY created_at DESC, id DESC
+		`, access.ID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		invites := []ScenarioInviteResponse{}
+		for rows.Next() {
+			invite := ScenarioInviteResponse{ScenarioID: access.ID}
+			if err := rows.Scan(&invite.ID, &invite.Token, &invite.Role, &invite.MaxUses, &invite.Uses, &invite.ExpiresAt, &invite.CreatedAt); err != nil {
+				return err
+			}
+			invites = append(invites, invite)
+		}
+
+		return c.JSON(invites)
+	}
+}
+
+func createScenarioInvite(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleOwner, c)
+		if err != nil {
+			return err
+		}
+
+		var req CreateInviteRequest
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+		}
+		var errs validation.Errors
+		if req.Role != ScenarioRoleEditor && req.Role != ScenarioRoleViewer {
+			errs.Add(validation.InBody, "role", "role must be one of: editor, viewer")
+		}
+		if req.MaxUses != nil && *req.MaxUses < 1 {
+			errs.Add(validation.InBody, "max_uses", "max_uses must be at least 1")
+		}
+		if req.ExpiresInHours != nil && (*req.ExpiresInHours < 1 || *req.ExpiresInHours > 24*365) {
+			errs.Add(validation.InBody, "expires_in_hours", "expires_in_hours must be between 1 and 8760")
+		}
+		if err := errs.Err(); err != nil {
+			return err
+		}
+
+		var id int
+		var expiresAt *time.Time
+		var createdAt time.Time
+		token := generateSessionToken()
+		err = db.Conn.QueryRow(`
+			INSERT INTO scenario_invites (scenario_id, token, role, created_by, max_uses, expires_at)
+			VALUES ($1, $2, $3, $4, $5, CASE WHEN $6::int IS NULL THEN NULL ELSE NOW() + make_interval(hours => $6::int) END)
+			RETURNING id, expires_at, created_at
+		`, access.ID, token, req.Role, getRequestUserID(c), req.MaxUses, req.ExpiresInHours).Scan(&id, &expiresAt, &createdAt)
+		if err != nil {
+			return err
+		}
+
+		return c.Status(201).JSON(ScenarioInviteResponse{
+			ID:         id,
+			ScenarioID: access.ID,
+			Token:      token,
+			Role:       req.Role,
+			MaxUses:    req.MaxUses,
+			Uses:       0,
+			ExpiresAt:  expiresAt,
+			CreatedAt:  createdAt,
+		})
+	}
+}
+
+func deleteScenarioInvite(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		scenarioID, inviteID := params.ID("scenario_id"), params.ID("invite_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		access, err := authorizeScenario(db, scenarioID, ScenarioRoleOwner, c)
+		if err != nil {
+			return err
+		}
+
+		result, err := db.Conn.Exec(`
+			DELETE FROM scenario_invites
+			WHERE id = $1 AND scenario_id = $2
+		`, inviteID, access.ID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, _ := result.RowsAffected()
+		if rowsAffected == 0 {
+			return apperror.New(apperror.InviteNotFound, "Invite not found")
+		}
+
+		return c.JSON(MessageResponse{Message: "Invite revoked successfully"})
+	}
+}
+
+// Joins a scenario with an invite token as the current user or guest session.
+// Existing members keep their role unless the invite grants more access.
+func acceptScenarioInvite(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		token := params.Token("token")
+		if err := params.Err(); err != nil {
+			return err
+		}
+		newUserID, newSessionToken := getNewScenarioOwner(c)
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var inviteID, scenarioID, uses int
+		var role, scenarioName string
+		var maxUses *int
+		var isExpired bool
+		var ownerUserID *int
+		var ownerSessionToken *string
+		err = tx.QueryRow(`
+			SELECT
+				invite.id, invite.scenario_id, invite.role, invite.max_uses, invite.uses,
+				invite.expires_at IS NOT NULL AND invite.expires_at < NOW(),
+				scenario.name, scenario.user_id, scenario.session_token
+			FROM scenario_invites invite
+			JOIN scenarios scenario ON invite.scenario_id = scenario.id
+			WHERE invite.token = $1
+			FOR UPDATE OF invite
+		`, token).Scan(&inviteID, &scenarioID, &role, &maxUses, &uses, &isExpired, &scenarioName, &ownerUserID, &ownerSessionToken)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.InviteNotFound, "Invite not found")
+		}
+		if err != nil {
+			return err
+		}
+		if isExpired {
+			return apperror.New(apperror.InviteExpired, "Invite has expired")
+		}
+		if maxUses != nil && uses >= *maxUses {
+			return apperror.New(apperror.InviteUsedUp, "Invite has no uses left")
+		}
+
+		response := AcceptInviteResponse{ScenarioID: scenarioID, ScenarioName: scenarioName}
+
+		if isScenarioOwner(c, ownerUserID, ownerSessionToken) {
+			response.Role = ScenarioRoleOwner
+			return c.JSON(response)
+		}
+
+		var memberID int
+		var currentRole string
+		err = tx.QueryRow(`
+			SELECT id, role
+			FROM scenario_members
+			WHERE scenario_id = $1 AND (user_id = $2 OR session_token = $3)
+			FOR UPDATE
+		`, scenarioID, newUserID, newSessionToken).Scan(&memberID, &currentRole)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		responseStatus := 200
+		if err == sql.ErrNoRows {
+			err = tx.QueryRow(`
+				INSERT INTO scenario_members (scenario_id, user_id, session_token, role, invite_id)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			`, scenarioID, newUserID, newSessionToken, role, inviteID).Scan(&memberID)
+			if err != nil {
+				return err
+			}
+			currentRole = role
+			responseStatus = 201
+		} else if scenarioRoleRanks[role] > scenarioRoleRanks[currentRole] {
+			_, err = tx.Exec(`
+				UPDATE scenario_members
+				SET role = $1, invite_id = $2, updated_at = NOW()
+				WHERE id = $3
+			`, role, inviteID, memberID)
+			if err != nil {
+				return err
+			}
+			currentRole = role
+		} else {
+			// Already a member with at least this role, so the invite isn't used up
+			response.MemberID = &memberID
+			response.Role = currentRole
+			return c.JSON(response)
+		}
+
+		_, err = tx.Exec(`UPDATE scenario_invites SET uses = uses + 1 WHERE id = $1`, inviteID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		response.MemberID = &memberID
+		response.Role = currentRole
+		return c.Status(responseStatus).JSON(response)
+	}
+}
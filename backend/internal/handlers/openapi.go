@@ -0,0 +1,177 @@
+// OpenAPI document for every API route, served at /api/openapi.json
+
+package handlers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/models"
+	"gamescript/internal/openapi"
+	"gamescript/internal/validation"
+)
+
+
+var (
+	apiDocument     *openapi.Document
+	apiDocumentOnce sync.Once
+)
+
+// Builds the document from apiRoutes once; the contract tests keep the two in step with the app
+func APIDocument() *openapi.Document {
+	apiDocumentOnce.Do(func() {
+		builder := openapi.NewBuilder("GameScript API", "1.0.0")
+		builder.DefaultError("Problem details", validation.Response{})
+		for _, route := range apiRoutes() {
+			if err := builder.Add(route); err != nil {
+				panic(fmt.Sprintf("openapi: %v", err))
+			}
+		}
+		apiDocument = builder.Document()
+	})
+	return apiDocument
+}
+
+func getOpenAPIDocument(c *fiber.Ctx) error {
+	return c.JSON(APIDocument())
+}
+
+func queryParam(name string, schemaType string, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: &openapi.Schema{Type: schemaType}}
+}
+
+var (
+	csvBody  = openapi.Raw{ContentType: "text/csv"}
+	tsvBody  = openapi.Raw{ContentType: "text/tab-separated-values"}
+	xlsxBody = openapi.Raw{ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}
+	icsBody  = openapi.Raw{ContentType: "text/calendar", Description: "iCalendar feed"}
+
+	formatParam = queryParam("format", "string", "json, csv, tsv, or xlsx; defaults to the Accept header")
+	tableParam  = queryParam("table", "string", "Export a single table by name")
+)
+
+// Every route registered by cmd/server and SetupRoutes, with the bodies it reads and writes.
+// Statuses not listed return a validation.Response: a problem with field errors for invalid input.
+func apiRoutes() []openapi.Route {
+	return []openapi.Route{
+		// Health, auth, and the document itself are registered in cmd/server
+		{Method: "GET", Path: "/api/health", Summary: "Health check", Tag: "meta", Responses: map[int]interface{}{200: HealthResponse{}}},
+		{Method: "GET", Path: "/api/openapi.json", Summary: "This document", Tag: "meta", Responses: map[int]interface{}{200: openapi.Document{}}},
+		{Method: "POST", Path: "/api/auth/register", Summary: "Create an account", Tag: "auth", Request: RegisterRequest{}, Responses: map[int]interface{}{201: AuthResponse{}, 400: validation.Response{}}},
+		{Method: "POST", Path: "/api/auth/login", Summary: "Log in", Tag: "auth", Request: LoginRequest{}, Responses: map[int]interface{}{200: AuthResponse{}, 423: AccountLockedResponse{}}},
+		{Method: "GET", Path: "/api/auth/me", Summary: "Current user", Tag: "auth", Auth: openapi.AuthRequired, Responses: map[int]interface{}{200: UserResponse{}}},
+		{Method: "PUT", Path: "/api/auth/profile", Summary: "Update the current user", Tag: "auth", Auth: openapi.AuthRequired, Request: UpdateProfileRequest{}, Responses: map[int]interface{}{200: UserResponse{}, 400: validation.Response{}}},
+
+		// Sports, seasons, teams, and games
+		{Method: "GET", Path: "/api/sports", Summary: "List sports", Tag: "catalog", Responses: map[int]interface{}{200: []models.Sport{}}},
+		{Method: "GET", Path: "/api/sports/:sport_id/seasons", Summary: "List a sport's seasons", Tag: "catalog", Responses: map[int]interface{}{200: []models.Season{}}},
+		{Method: "GET", Path: "/api/seasons/:season_id", Summary: "Get a season", Tag: "catalog", Responses: map[int]interface{}{200: models.Season{}}},
+		{Method: "GET", Path: "/api/seasons/:season_id/teams", Summary: "List a season's teams", Tag: "catalog", Responses: map[int]interface{}{200: []TeamResponse{}}},
+		{Method: "GET", Path: "/api/teams/:team_id", Summary: "Get a team", Tag: "catalog", Responses: map[int]interface{}{200: TeamResponse{}}},
+		{Method: "GET", Path: "/api/seasons/:season_id/games", Summary: "List a season's games", Tag: "catalog", Responses: map[int]interface{}{200: []GameResponse{}}},
+		{Method: "GET", Path: "/api/seasons/:season_id/weeks/:week/games", Summary: "List a week's games", Tag: "catalog", Responses: map[int]interface{}{200: []GameResponse{}}},
+		{Method: "GET", Path: "/api/teams/:team_id/games", Summary: "List a team's games", Tag: "catalog", Responses: map[int]interface{}{200: []GameResponse{}}},
+		{Method: "GET", Path: "/api/games/:game_id", Summary: "Get a game", Tag: "catalog", Responses: map[int]interface{}{200: GameResponse{}}},
+		{Method: "GET", Path: "/api/seasons/:season_id/games.ics", Summary: "Season calendar feed", Tag: "catalog",
+			Query:     []openapi.Parameter{queryParam("teams", "string", "Comma-separated team IDs or abbreviations")},
+			Responses: map[int]interface{}{200: icsBody}},
+		{Method: "GET", Path: "/api/teams/:team_id/games.ics", Summary: "Team calendar feed", Tag: "catalog", Responses: map[int]interface{}{200: icsBody}},
+		{Method: "GET", Path: "/api/seasons/:season_id/ratings", Summary: "Team ratings and game win probabilities", Tag: "catalog",
+			Query:     []openapi.Parameter{queryParam("week", "integer", "Only include games from this week")},
+			Responses: map[int]interface{}{200: SeasonRatingsResponse{}}},
+
+		// Scenarios
+		{Method: "GET", Path: "/api/scenarios", Summary: "List the caller's scenarios", Tag: "scenarios", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: []ScenarioResponse{}}},
+		{Method: "POST", Path: "/api/scenarios", Summary: "Create a scenario", Tag: "scenarios", Auth: openapi.AuthOptional, Request: CreateScenarioRequest{}, Responses: map[int]interface{}{201: ScenarioResponse{}}},
+		{Method: "GET", Path: "/api/scenarios/compare", Summary: "Compare two scenarios", Tag: "scenarios", Auth: openapi.AuthOptional,
+			Query:     []openapi.Parameter{queryParam("a", "integer", "First scenario ID"), queryParam("b", "integer", "Second scenario ID")},
+			Responses: map[int]interface{}{200: CompareScenariosResponse{}}},
+		{Method: "POST", Path: "/api/scenarios/import", Summary: "Import a scenario document", Tag: "scenarios", Auth: openapi.AuthOptional, Request: models.ScenarioDocument{},
+			Query: []openapi.Parameter{
+				queryParam("season_id", "integer", "Target season; defaults to the document's sport and start year"),
+				queryParam("strict", "boolean", "Fail instead of skipping picks and teams that don't match"),
+			},
+			Responses: map[int]interface{}{201: ImportScenarioResponse{}, 422: ImportMismatchResponse{}}},
+		{Method: "POST", Path: "/api/scenarios/invites/:token/accept", Summary: "Accept a scenario invite", Tag: "members", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: AcceptInviteResponse{}, 201: AcceptInviteResponse{}}},
+		{Method: "GET", Path: "/api/scenarios/:scenario_id", Summary: "Get a scenario", Tag: "scenarios", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: ScenarioResponse{}}},
+		{Method: "PUT", Path: "/api/scenarios/:scenario_id", Summary: "Update a scenario", Tag: "scenarios", Auth: openapi.AuthOptional, Request: UpdateScenarioRequest{}, Responses: map[int]interface{}{200: ScenarioResponse{}, 423: PicksLockedResponse{}}},
+		{Method: "DELETE", Path: "/api/scenarios/:scenario_id", Summary: "Delete a scenario", Tag: "scenarios", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: MessageResponse{}}},
+		{Method: "POST", Path: "/api/scenarios/:scenario_id/claim", Summary: "Move a guest scenario to the current user", Tag: "scenarios", Auth: openapi.AuthRequired, Responses: map[int]interface{}{200: ClaimScenarioResponse{}}},
+		{Method: "POST", Path: "/api/scenarios/:scenario_id/fork", Summary: "Copy a scenario", Tag: "scenarios", Auth: openapi.AuthOptional, Request: ForkScenarioRequest{}, Responses: map[int]interface{}{201: ScenarioResponse{}, 423: PicksLockedResponse{}}},
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/export", Summary: "Export a scenario document", Tag: "scenarios", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: models.ScenarioDocument{}}},
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/standings", Summary: "Standings, draft order, and playoff seeds", Tag: "standings", Auth: openapi.AuthOptional,
+			Query: []openapi.Parameter{
+				queryParam("as_of_week", "integer", "Only count games through this week"),
+				queryParam("as_of_date", "string", "Only count games through this date, as YYYY-MM-DD"),
+				formatParam,
+				tableParam,
+			},
+			Responses: map[int]interface{}{200: openapi.Negotiated{StandingsResponse{}, csvBody, tsvBody, xlsxBody}}},
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/standings/trajectory", Summary: "Playoff seeds week by week", Tag: "standings", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: StandingsTrajectoryResponse{}}},
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/card.png", Summary: "Shareable image of a scenario", Tag: "scenarios", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: openapi.Raw{ContentType: "image/png"}}},
+		{Method: "POST", Path: "/api/scenarios/:scenario_id/autofill", Summary: "Pick every open game by a strategy", Tag: "picks", Auth: openapi.AuthOptional, Request: AutofillRequest{}, Responses: map[int]interface{}{201: AutofillResponse{}}},
+
+		// History and snapshots
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/history", Summary: "List a scenario's changes", Tag: "history", Auth: openapi.AuthOptional,
+			Query:     []openapi.Parameter{queryParam("limit", "integer", "Most recent changes to return, 1 to 1000")},
+			Responses: map[int]interface{}{200: ScenarioHistoryResponse{}}},
+		{Method: "POST", Path: "/api/scenarios/:scenario_id/undo", Summary: "Undo the latest change", Tag: "history", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: HistoryStepResponse{}, 423: PicksLockedResponse{}}},
+		{Method: "POST", Path: "/api/scenarios/:scenario_id/redo", Summary: "Redo the latest undone change", Tag: "history", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: HistoryStepResponse{}, 423: PicksLockedResponse{}}},
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/snapshots", Summary: "List snapshots", Tag: "history", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: []SnapshotResponse{}}},
+		{Method: "POST", Path: "/api/scenarios/:scenario_id/snapshots", Summary: "Save a snapshot", Tag: "history", Auth: openapi.AuthOptional, Request: CreateSnapshotRequest{}, Responses: map[int]interface{}{201: SnapshotResponse{}}},
+		{Method: "DELETE", Path: "/api/scenarios/:scenario_id/snapshots/:snapshot_id", Summary: "Delete a snapshot", Tag: "history", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: MessageResponse{}}},
+		{Method: "POST", Path: "/api/scenarios/:scenario_id/snapshots/:snapshot_id/restore", Summary: "Restore a snapshot", Tag: "history", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: RestoreSnapshotResponse{}, 423: PicksLockedResponse{}}},
+
+		// Members and invites
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/members", Summary: "List a scenario's members", Tag: "members", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: ScenarioMembersResponse{}}},
+		{Method: "PUT", Path: "/api/scenarios/:scenario_id/members/:member_id", Summary: "Change a member's role", Tag: "members", Auth: openapi.AuthOptional, Request: UpdateMemberRequest{}, Responses: map[int]interface{}{200: UpdateMemberResponse{}}},
+		{Method: "DELETE", Path: "/api/scenarios/:scenario_id/members/:member_id", Summary: "Remove a member", Tag: "members", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: MessageResponse{}}},
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/invites", Summary: "List invites", Tag: "members", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: []ScenarioInviteResponse{}}},
+		{Method: "POST", Path: "/api/scenarios/:scenario_id/invites", Summary: "Create an invite", Tag: "members", Auth: openapi.AuthOptional, Request: CreateInviteRequest{}, Responses: map[int]interface{}{201: ScenarioInviteResponse{}}},
+		{Method: "DELETE", Path: "/api/scenarios/:scenario_id/invites/:invite_id", Summary: "Revoke an invite", Tag: "members", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: MessageResponse{}}},
+
+		// Picks
+		{Method: "GET", Path: "/api/picks/scenarios/:scenario_id", Summary: "List a scenario's picks", Tag: "picks", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: []PickWithGameResponse{}}},
+		{Method: "GET", Path: "/api/picks/scenarios/:scenario_id/export", Summary: "Export picks as a spreadsheet", Tag: "picks", Auth: openapi.AuthOptional,
+			Query:     []openapi.Parameter{formatParam},
+			Responses: map[int]interface{}{200: openapi.Negotiated{csvBody, tsvBody, xlsxBody}}},
+		{Method: "PUT", Path: "/api/picks/scenarios/:scenario_id/batch", Summary: "Upsert and delete picks together", Tag: "picks", Auth: openapi.AuthOptional, Request: BatchPicksRequest{},
+			Responses: map[int]interface{}{200: BatchPicksResponse{}, 400: BatchPicksErrorResponse{}, 409: BatchPicksConflictResponse{}, 423: PicksLockedResponse{}}},
+		{Method: "GET", Path: "/api/picks/scenarios/:scenario_id/games/:game_id", Summary: "Get a pick", Tag: "picks", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: PickResponse{}}},
+		{Method: "POST", Path: "/api/picks/scenarios/:scenario_id/games/:game_id", Summary: "Create a pick", Tag: "picks", Auth: openapi.AuthOptional, Request: CreatePickRequest{},
+			Responses: map[int]interface{}{201: PickResponse{}, 409: PickConflictResponse{}, 422: PickOverrideErrorResponse{}, 423: PicksLockedResponse{}}},
+		{Method: "PUT", Path: "/api/picks/scenarios/:scenario_id/games/:game_id", Summary: "Update a pick", Tag: "picks", Auth: openapi.AuthOptional, Request: UpdatePickRequest{},
+			Responses: map[int]interface{}{200: PickResponse{}, 409: PickConflictResponse{}, 422: PickOverrideErrorResponse{}, 423: PicksLockedResponse{}}},
+		{Method: "DELETE", Path: "/api/picks/scenarios/:scenario_id/games/:game_id", Summary: "Delete a pick", Tag: "picks", Auth: openapi.AuthOptional,
+			Query:     []openapi.Parameter{queryParam("expected_updated_at", "string", "Fail with 409 if the pick changed since this time")},
+			Responses: map[int]interface{}{200: MessageResponse{}, 409: PickConflictResponse{}, 423: PicksLockedResponse{}}},
+
+		// Playoffs
+		{Method: "GET", Path: "/api/playoffs/scenarios/:scenario_id/state", Summary: "Get the playoff bracket state", Tag: "playoffs", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: PlayoffStatusResponse{}}},
+		{Method: "POST", Path: "/api/playoffs/scenarios/:scenario_id/enable", Summary: "Seed the playoff bracket", Tag: "playoffs", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: MessageResponse{}}},
+		{Method: "GET", Path: "/api/playoffs/scenarios/:scenario_id/rounds/:round", Summary: "List a round's matchups or series", Tag: "playoffs", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: []PlayoffRoundEntry{}}},
+		{Method: "PUT", Path: "/api/playoffs/scenarios/:scenario_id/matchups/:matchup_id", Summary: "Pick a matchup or series winner", Tag: "playoffs", Auth: openapi.AuthOptional, Request: UpdatePlayoffPickRequest{}, Responses: map[int]interface{}{200: PlayoffPickUpdate{}}},
+		{Method: "DELETE", Path: "/api/playoffs/scenarios/:scenario_id/matchups/:matchup_id", Summary: "Clear a matchup or series pick", Tag: "playoffs", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: MessageResponse{}}},
+		{Method: "POST", Path: "/api/playoffs/scenarios/:scenario_id/generate", Summary: "Generate the next round", Tag: "playoffs", Auth: openapi.AuthOptional, Responses: map[int]interface{}{200: MessageResponse{}}},
+
+		// Pick'em leagues
+		{Method: "GET", Path: "/api/leagues", Summary: "List the current user's leagues", Tag: "leagues", Auth: openapi.AuthRequired, Responses: map[int]interface{}{200: []LeagueSummaryResponse{}}},
+		{Method: "POST", Path: "/api/leagues", Summary: "Create a league", Tag: "leagues", Auth: openapi.AuthRequired, Request: CreateLeagueRequest{}, Responses: map[int]interface{}{201: CreateLeagueResponse{}}},
+		{Method: "POST", Path: "/api/leagues/join/:invite_code", Summary: "Join a league by invite code", Tag: "leagues", Auth: openapi.AuthRequired, Responses: map[int]interface{}{200: JoinLeagueResponse{}, 201: JoinLeagueResponse{}}},
+		{Method: "GET", Path: "/api/leagues/:league_id", Summary: "Get a league", Tag: "leagues", Auth: openapi.AuthRequired, Responses: map[int]interface{}{200: LeagueResponse{}}},
+		{Method: "DELETE", Path: "/api/leagues/:league_id", Summary: "Delete a league", Tag: "leagues", Auth: openapi.AuthRequired, Responses: map[int]interface{}{200: MessageResponse{}}},
+		{Method: "DELETE", Path: "/api/leagues/:league_id/members/:member_id", Summary: "Remove a member or leave", Tag: "leagues", Auth: openapi.AuthRequired, Responses: map[int]interface{}{200: MessageResponse{}}},
+		{Method: "GET", Path: "/api/leagues/:league_id/standings", Summary: "Season or weekly standings", Tag: "leagues", Auth: openapi.AuthRequired,
+			Query:     []openapi.Parameter{queryParam("week", "integer", "Rank a single week instead of the season")},
+			Responses: map[int]interface{}{200: LeagueStandingsResponse{}}},
+		{Method: "GET", Path: "/api/leagues/:league_id/weeks/:week/entry", Summary: "Get the current member's entry", Tag: "leagues", Auth: openapi.AuthRequired, Responses: map[int]interface{}{200: LeagueEntryResponse{}}},
+		{Method: "PUT", Path: "/api/leagues/:league_id/weeks/:week/entry", Summary: "Save the current member's entry", Tag: "leagues", Auth: openapi.AuthRequired, Request: SaveLeagueEntryRequest{}, Responses: map[int]interface{}{200: LeagueEntryResponse{}, 400: LeagueEntryErrorResponse{}}},
+		{Method: "GET", Path: "/api/leagues/:league_id/weeks/:week/entries", Summary: "List every member's entry", Tag: "leagues", Auth: openapi.AuthRequired, Responses: map[int]interface{}{200: LeagueWeekEntriesResponse{}}},
+
+		// Admin
+		{Method: "POST", Path: "/api/admin/update-schedule/nfl", Summary: "Fetch the NFL schedule now", Tag: "admin", Responses: map[int]interface{}{200: TriggerResponse{}}},
+		{Method: "POST", Path: "/api/admin/update-schedule/nba", Summary: "Fetch the NBA schedule now", Tag: "admin", Responses: map[int]interface{}{200: TriggerResponse{}}},
+	}
+}
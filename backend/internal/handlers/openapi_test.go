@@ -0,0 +1,108 @@
+package handlers
+
+import (
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "sort"
+    "strings"
+    "testing"
+
+    "gamescript/internal/database"
+    "gamescript/internal/openapi"
+
+    "github.com/stretchr/testify/assert"
+)
+
+// Fails the test when a response doesn't match what the OpenAPI document says the route returns
+func checkContract(t *testing.T, method string, path string, resp *http.Response, body []byte) {
+    t.Helper()
+    err := APIDocument().ValidateResponse(method, path, resp.StatusCode, resp.Header.Get("Content-Type"), body)
+    if err != nil {
+        t.Errorf("Response diverges from the OpenAPI document: %v", err)
+    }
+}
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+    // Registering routes doesn't touch the database, so this runs without one
+    app := newTestApp(&database.DB{})
+
+    registered := make(map[string]bool)
+    for _, route := range app.GetRoutes(true) {
+        // Fiber adds a HEAD route for every GET
+        if route.Method == "HEAD" || !strings.HasPrefix(route.Path, "/api/") {
+            continue
+        }
+        registered[route.Method+" "+strings.TrimSuffix(route.Path, "/")] = true
+    }
+
+    documented := make(map[string]bool)
+    for _, route := range apiRoutes() {
+        documented[route.Method+" "+route.Path] = true
+    }
+
+    var undocumented, unregistered []string
+    for route := range registered {
+        if !documented[route] {
+            undocumented = append(undocumented, route)
+        }
+    }
+    for route := range documented {
+        if !registered[route] {
+            unregistered = append(unregistered, route)
+        }
+    }
+    sort.Strings(undocumented)
+    sort.Strings(unregistered)
+
+    assert.Empty(t, undocumented, "Routes missing from apiRoutes")
+    assert.Empty(t, unregistered, "Documented routes that aren't registered")
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+    app := newTestApp(&database.DB{})
+
+    req := httptest.NewRequest("GET", "/api/openapi.json", nil)
+    resp, err := app.Test(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer resp.Body.Close()
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    assert.Equal(t, 200, resp.StatusCode)
+    checkContract(t, "GET", "/api/openapi.json", resp, body)
+
+    var document openapi.Document
+    if err := json.Unmarshal(body, &document); err != nil {
+        t.Fatal(err)
+    }
+    assert.Equal(t, openapi.Version, document.OpenAPI)
+
+    standings := document.Paths["/api/scenarios/{scenario_id}/standings"]["get"]
+    if assert.NotNil(t, standings) {
+        assert.Contains(t, standings.Responses["200"].Content, "application/json")
+        assert.Contains(t, standings.Responses["200"].Content, "text/csv")
+    }
+    // Scenarios and seasons both describe a season's start year, under their established names
+    assert.Contains(t, document.Components.Schemas["ScenarioResponse"].Properties, "season_start_year")
+    assert.Contains(t, document.Components.Schemas["Season"].Properties, "start_year")
+}
+
+func TestContractCatchesDivergence(t *testing.T) {
+    resp := &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {"application/json"}}}
+
+    valid := `{"id": 1, "name": "National Football League", "short_name": "NFL", "created_at": "2025-01-01T00:00:00Z"}`
+    assert.NoError(t, APIDocument().ValidateResponse("GET", "/api/sports", resp.StatusCode, resp.Header.Get("Content-Type"), []byte("["+valid+"]")))
+
+    renamed := `[{"id": 1, "name": "National Football League", "shortName": "NFL", "created_at": "2025-01-01T00:00:00Z"}]`
+    err := APIDocument().ValidateResponse("GET", "/api/sports", resp.StatusCode, resp.Header.Get("Content-Type"), []byte(renamed))
+    if assert.Error(t, err) {
+        assert.Contains(t, err.Error(), `missing required property "short_name"`)
+        assert.Contains(t, err.Error(), `unexpected property "shortName"`)
+    }
+}
@@ -0,0 +1,65 @@
+// Result overrides for picks on games that are already final
+
+package handlers
+
+import (
+	"github.com/lib/pq"
+
+	"gamescript/internal/standings"
+)
+
+
+// Gets which of the given games are final with a real result, matching how standings decide finality
+func getFinalGameIDs(q sqlExecutor, gameIDs []int) (map[int]bool, error) {
+	final := make(map[int]bool)
+	if len(gameIDs) == 0 {
+		return final, nil
+	}
+
+	rows, err := q.Query(`
+		SELECT id
+		FROM games
+		WHERE id = ANY($1) AND status = 'final' AND home_score IS NOT NULL AND away_score IS NOT NULL
+	`, pq.Array(gameIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		final[id] = true
+	}
+
+	return final, rows.Err()
+}
+
+// Checks a pick's override flag against whether its game is final and the scenario's result mode.
+// Returns an empty string when the pick is allowed.
+func checkPickOverride(resultMode string, isFinal bool, isOverride bool) string {
+	if !isFinal {
+		if isOverride {
+			return "is_override only applies to games that are final"
+		}
+		return ""
+	}
+	if resultMode == standings.ResultModeHonorReal {
+		return "Game is final and this scenario honors real results"
+	}
+	if !isOverride {
+		return "Game is final; set is_override to replace its real result"
+	}
+	return ""
+}
+
+// Gets why a single pick conflicts with its game's real result, or an empty string when it is allowed
+func getPickOverrideError(q sqlExecutor, access *scenarioAccess, gameID int, isOverride bool) (string, error) {
+	final, err := getFinalGameIDs(q, []int{gameID})
+	if err != nil {
+		return "", err
+	}
+	return checkPickOverride(access.ResultMode, final[gameID], isOverride), nil
+}
@@ -3,441 +3,839 @@
 package handlers
 
 import (
-	"strconv"
+	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
 
+	"gamescript/internal/apperror"
 	"gamescript/internal/database"
+	"gamescript/internal/history"
+	"gamescript/internal/models"
+	"gamescript/internal/standings"
+	"gamescript/internal/store"
+	"gamescript/internal/validation"
 )
 
 
-func getPicksByScenario(db *database.DB) fiber.Handler {
-    return func(c *fiber.Ctx) error {
-        scenarioID := c.Params("scenario_id")
-        isAuthenticated := c.Locals("is_authenticated").(bool)
+type PickResponse struct {
+	ID					int			`json:"id"`
+	ScenarioID			int			`json:"scenario_id"`
+	GameID				int			`json:"game_id"`
+	PickedTeamID		*int		`json:"picked_team_id"`
+	PredictedHomeScore	*int		`json:"predicted_home_score"`
+	PredictedAwayScore	*int		`json:"predicted_away_score"`
+	Status				*string		`json:"status"`
+	IsOverride			bool		`json:"is_override"`
+	IsLocked			bool		`json:"is_locked"`
+	CreatedAt			time.Time	`json:"created_at"`
+	UpdatedAt			time.Time	`json:"updated_at"`
+}
+
+// A pick with its game, as listed for a scenario
+type PickWithGameResponse struct {
+	PickResponse
+	Game				PickGameResponse	`json:"game"`
+	PickedTeam			*TeamSummary		`json:"picked_team,omitempty"`
+}
+
+// The game fields included with a pick
+type PickGameResponse struct {
+	ESPNID				string				`json:"espn_id"`
+	StartTime			time.Time			`json:"start_time"`
+	Week				*int				`json:"week"`
+	HomeScore			*int				`json:"home_score"`
+	AwayScore			*int				`json:"away_score"`
+	Status				*string				`json:"status"`
+	HomeTeam			GameTeamResponse	`json:"home_team"`
+	AwayTeam			GameTeamResponse	`json:"away_team"`
+}
+
+type CreatePickRequest struct {
+	PickedTeamID *int `json:"picked_team_id"`
+	PredictedHomeScore *int `json:"predicted_home_score"`
+	PredictedAwayScore *int `json:"predicted_away_score"`
+	IsOverride bool `json:"is_override"`
+}
 
-        if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-            return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+type UpdatePickRequest struct {
+	PickedTeamID *int `json:"picked_team_id"`
+	PredictedHomeScore *int `json:"predicted_home_score"`
+	PredictedAwayScore *int `json:"predicted_away_score"`
+	IsOverride bool `json:"is_override"`
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at"`
+}
+
+// Sent when a pick on a final game isn't marked as an override or the scenario doesn't allow one
+type PickOverrideErrorResponse struct {
+	apperror.Problem
+	GameID	int		`json:"game_id"`
+}
+
+// Sent when another editor changed a pick since the client loaded it; current_pick is null if it was deleted
+type PickConflictResponse struct {
+	apperror.Problem
+	CurrentPick	*currentPick	`json:"current_pick"`
+}
+
+func getPicksByScenario(data store.Store) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        scenarioID, err := validation.PathID(c, "scenario_id")
+        if err != nil {
+            return err
+        }
+        access, err := authorizeStoredScenario(data, scenarioID, ScenarioRoleViewer, c)
+        if err != nil {
+            return err
         }
 
-        query := `
-            SELECT
-                pick.id, pick.scenario_id, pick.game_id, pick.picked_team_id, 
-                pick.predicted_home_score, pick.predicted_away_score, 
-                pick.status, pick.created_at, pick.updated_at,
-                game.espn_id, game.start_time, game.week, 
-                game.home_team_id, game.away_team_id, 
-                game.home_score, game.away_score, game.status as game_status,
-                home_team.abbreviation, home_team.city, home_team.name, 
-                home_team.conference, home_team.division, 
-                home_team.primary_color, home_team.secondary_color, home_team.logo_url, home_team.alternate_logo_url,
-                away_team.abbreviation, away_team.city, away_team.name, 
-                away_team.conference, away_team.division, 
-                away_team.primary_color, away_team.secondary_color, away_team.logo_url, away_team.alternate_logo_url
-            FROM picks pick
-            JOIN games game ON pick.game_id = game.id
-            JOIN teams home_team ON game.home_team_id = home_team.id
-            JOIN teams away_team ON game.away_team_id = away_team.id
-            WHERE pick.scenario_id = $1
-            ORDER BY game.start_time
-        `
-
-        rows, err := db.Query(query, scenarioID)
+        picks, err := data.PicksForScenario(access.ID)
         if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+            return err
         }
-        defer rows.Close()
-
-        var picks []map[string]interface{}
-        for rows.Next() {
-            var pickID, scenarioID, gameID, homeTeamID, awayTeamID int
-            var pickedTeamID *int
-            var week *int
-            var predictedHomeScore, predictedAwayScore, homeScore, awayScore *int
-            var gameESPNID, homeAbbr, homeCity, homeName, homePrimaryColor, homeSecondaryColor, awayAbbr, awayCity, awayName, awayPrimaryColor, awaySecondaryColor string
-            var homeConference, homeDivision, homeLogoURL, homeAlternateLogoURL, awayConference, awayDivision, awayLogoURL, awayAlternateLogoURL *string
-            var pickedAbbr, pickedCity, pickedName *string
-            var pickStatus, gameStatus *string
-            var startTime, createdAt, updatedAt time.Time
-
-            err := rows.Scan(
-                &pickID, &scenarioID, &gameID, &pickedTeamID, &predictedHomeScore, &predictedAwayScore, &pickStatus, &createdAt, &updatedAt, 
-                &gameESPNID, &startTime, &week, &homeTeamID, &awayTeamID, &homeScore, &awayScore, &gameStatus,
-                &homeAbbr, &homeCity, &homeName, &homeConference, &homeDivision, &homePrimaryColor, &homeSecondaryColor, &homeLogoURL, &homeAlternateLogoURL,
-                &awayAbbr, &awayCity, &awayName, &awayConference, &awayDivision, &awayPrimaryColor, &awaySecondaryColor, &awayLogoURL, &awayAlternateLogoURL,
-            )
-            if err != nil {
-                continue
-            }
 
-            pick := map[string]interface{}{
-                "id": pickID,
-                "scenario_id": scenarioID,
-                "game_id": gameID,
-                "picked_team_id": pickedTeamID,
-                "predicted_home_score": predictedHomeScore,
-                "predicted_away_score": predictedAwayScore,
-                "status": pickStatus,
-                "created_at": createdAt,
-                "updated_at": updatedAt,
-                "game": map[string]interface{}{
-                    "espn_id": gameESPNID,
-                    "start_time": startTime,
-                    "week": week,
-                    "home_score": homeScore,
-                    "away_score": awayScore,
-                    "status": gameStatus,
-                    "home_team": map[string]interface{}{
-                        "id": homeTeamID,
-                        "abbreviation": homeAbbr,
-                        "city": homeCity,
-                        "name": homeName,
-                        "conference": homeConference,
-                        "division": homeDivision,
-                        "primary_color": homePrimaryColor,
-                        "secondary_color": homeSecondaryColor,
-                        "logo_url": homeLogoURL,
-                        "alternate_logo_url": homeAlternateLogoURL,
-                    },
-                    "away_team": map[string]interface{}{
-                        "id": awayTeamID,
-                        "abbreviation": awayAbbr,
-                        "city": awayCity,
-                        "name": awayName,
-                        "conference": awayConference,
-                        "division": awayDivision,
-                        "primary_color": awayPrimaryColor,
-                        "secondary_color": awaySecondaryColor,
-                        "logo_url": awayLogoURL,
-                        "alternate_logo_url": awayAlternateLogoURL,
-                    },
-                },
-            }
+        now := time.Now()
+        formatted := make([]PickWithGameResponse, 0, len(picks))
+        for _, pick := range picks {
+            formatted = append(formatted, formatPick(pick, isPickLocked(access.Mode, pick.Game.StartTime, now)))
+        }
+        return c.JSON(formatted)
+    }
+}
 
-            if pickedTeamID != nil {
-                pick["picked_team"] = map[string]interface{}{
-                    "id": pickedTeamID,
-                    "abbreviation": pickedAbbr,
-                    "city": pickedCity,
-                    "name": pickedName,
-                }
-            }
+func formatPick(pick models.PickWithGame, isLocked bool) PickWithGameResponse {
+    game := formatGame(pick.Game)
+    formatted := PickWithGameResponse{
+        PickResponse: PickResponse{
+            ID:                 pick.ID,
+            ScenarioID:         pick.ScenarioID,
+            GameID:             pick.GameID,
+            PickedTeamID:       pick.PickedTeamID,
+            PredictedHomeScore: pick.PredictedHomeScore,
+            PredictedAwayScore: pick.PredictedAwayScore,
+            Status:             pick.Status,
+            IsOverride:         pick.IsOverride,
+            IsLocked:           isLocked,
+            CreatedAt:          pick.CreatedAt,
+            UpdatedAt:          pick.UpdatedAt,
+        },
+        Game: PickGameResponse{
+            ESPNID:    game.ESPNID,
+            StartTime: game.StartTime,
+            Week:      game.Week,
+            HomeScore: game.HomeScore,
+            AwayScore: game.AwayScore,
+            Status:    game.Status,
+            HomeTeam:  game.HomeTeam,
+            AwayTeam:  game.AwayTeam,
+        },
+    }
 
-            picks = append(picks, pick)
+    if pick.PickedTeamID != nil {
+        pickedTeam := &TeamSummary{ID: *pick.PickedTeamID}
+        for _, team := range []models.Team{pick.Game.HomeTeam, pick.Game.AwayTeam} {
+            if team.ID == *pick.PickedTeamID {
+                pickedTeam.Abbreviation = team.Abbreviation
+                pickedTeam.City = team.City
+                pickedTeam.Name = team.Name
+            }
         }
-
-        return c.JSON(picks)
+        formatted.PickedTeam = pickedTeam
     }
+
+    return formatted
 }
 
 func getPick(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		gameID := c.Params("game_id")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
+		params := validation.NewParams(c)
+		scenarioID, gameID := params.ID("scenario_id"), params.ID("game_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
 
-		if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+		access, err := authorizeScenario(db, scenarioID, ScenarioRoleViewer, c)
+		if err != nil {
+			return err
 		}
 
 		query := `
 			SELECT
-				pick.id, pick.scenario_id, pick.game_id, pick.picked_team_id, pick.predicted_home_score, pick.predicted_away_score, pick.status, pick.created_at, pick.updated_at
+				pick.id, pick.scenario_id, pick.game_id, pick.picked_team_id, pick.predicted_home_score, pick.predicted_away_score, pick.status, pick.is_override, pick.created_at, pick.updated_at,
+				game.start_time
 			FROM picks pick
+			JOIN games game ON pick.game_id = game.id
 			WHERE pick.scenario_id = $1 AND pick.game_id = $2
 		`
 
-		var id, sID, gID int
-		var pickedTeamID *int
-		var predictedHomeScore, predictedAwayScore *int
-		var status *string
-		var createdAt, updatedAt time.Time
+		var pick PickResponse
+		var startTime time.Time
 
-		err := db.Conn.QueryRow(query, scenarioID, gameID).Scan(
-			&id, &sID, &gID, &pickedTeamID, &predictedHomeScore, &predictedAwayScore, &status, &createdAt, &updatedAt,
+		err = db.Conn.QueryRow(query, scenarioID, gameID).Scan(
+			&pick.ID, &pick.ScenarioID, &pick.GameID, &pick.PickedTeamID, &pick.PredictedHomeScore, &pick.PredictedAwayScore, &pick.Status, &pick.IsOverride, &pick.CreatedAt, &pick.UpdatedAt, &startTime,
 		)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.PickNotFound, "Pick not found")
+		}
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Pick not found"})
+			return err
 		}
+		pick.IsLocked = isPickLocked(access.Mode, startTime, time.Now())
 
-		return c.JSON(map[string]interface{}{
-			"id": id,
-			"scenario_id": sID,
-			"game_id": gID,
-			"picked_team_id": pickedTeamID,
-			"predicted_home_score": predictedHomeScore,
-			"predicted_away_score": predictedAwayScore,
-			"status": status,
-			"created_at": createdAt,
-			"updated_at": updatedAt,
-		})
+		return c.JSON(pick)
 	}
 }
 
 func createPick(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		gameID := c.Params("game_id")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
+		params := validation.NewParams(c)
+		scenarioID, gameID := params.ID("scenario_id"), params.ID("game_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
 
-		if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+		access, err := authorizeScenario(db, scenarioID, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
 		}
 
-		type CreatePickRequest struct {
-			PickedTeamID *int `json:"picked_team_id"`
-			PredictedHomeScore *int `json:"predicted_home_score"`
-			PredictedAwayScore *int `json:"predicted_away_score"`
+		game, err := getScenarioGame(db.Conn, access, gameID)
+		if err != nil {
+			return err
+		}
+		if locked, err := getLockedGameIDs(db.Conn, access, []int{gameID}, time.Now()); err != nil {
+			return err
+		} else if len(locked) > 0 {
+			return picksLockedError(locked)
 		}
 
 		var req CreatePickRequest
 		if err := c.BodyParser(&req); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
 		}
 
-		// If both scores are provided, validate that picked team id matches winning team
-		if req.PredictedHomeScore != nil && req.PredictedAwayScore != nil && req.PickedTeamID != nil {
-			var homeTeamID, awayTeamID int
-			err := db.Conn.QueryRow(`
-				SELECT home_team_id, away_team_id
-				FROM games
-				WHERE id = $1
-			`, gameID).Scan(&homeTeamID, &awayTeamID)
+		// Scores must be in the sport's range and decide the picked team when both are given
+		var errs validation.Errors
+		req.PickedTeamID = errs.Pick("", access.SportID, game.homeTeamID, game.awayTeamID, req.PickedTeamID, req.PredictedHomeScore, req.PredictedAwayScore)
+		if err := errs.Err(); err != nil {
+			return err
+		}
 
-			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve game details"})
-			}
+		// Picks on final games must be marked as overrides, and only where the scenario allows them
+		if message, err := getPickOverrideError(db.Conn, access, gameID, req.IsOverride); err != nil {
+			return err
+		} else if message != "" {
+			return apperror.New(apperror.PickOverrideNotAllowed, message).With(PickOverrideErrorResponse{GameID: gameID})
+		}
 
-			// Determine winner based on scores
-			var expectedPickedTeamID int
-			if *req.PredictedHomeScore > *req.PredictedAwayScore {
-				expectedPickedTeamID = homeTeamID
-			} else if *req.PredictedAwayScore > *req.PredictedHomeScore {
-				expectedPickedTeamID = awayTeamID
-			} else {
-				// Tie scenario - no winner
-				expectedPickedTeamID = 0
-			}
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
 
-			// Override picked team ID with calculated winner
-			req.PickedTeamID = &expectedPickedTeamID
+		// Lock any existing pick; another editor may have picked this game first
+		current, err := getCurrentPick(tx, scenarioID, gameID)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			return apperror.New(apperror.PickAlreadyExists, "A pick already exists for this game").With(PickConflictResponse{CurrentPick: current})
+		}
+
+		// Capture the empty pick for the change log
+		before, err := captureScenarioState(tx, scenarioID, false, []int{gameID}, false)
+		if err != nil {
+			return err
 		}
 
 		query := `
-			INSERT INTO picks (scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status)
-			VALUES ($1, $2, $3, $4, $5, 'pending')
-			RETURNING id, scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, created_at, updated_at
+			INSERT INTO picks (scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override)
+			VALUES ($1, $2, $3, $4, $5, 'pending', $6)
+			ON CONFLICT (scenario_id, game_id) DO NOTHING
+			RETURNING id, scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override, created_at, updated_at
 		`
 
-		var id, sID, gID int
-		var pickedTeamID *int
-		var predictedHomeScore, predictedAwayScore *int
-		var status *string
-		var createdAt, updatedAt time.Time
-
-		err := db.Conn.QueryRow(query, scenarioID, gameID, req.PickedTeamID, req.PredictedHomeScore, req.PredictedAwayScore).Scan(
-			&id, &sID, &gID, &pickedTeamID, &predictedHomeScore, &predictedAwayScore, &status, &createdAt, &updatedAt,
+		// Picks on games that have started were rejected above, so the new pick is unlocked
+		var pick PickResponse
+		err = tx.QueryRow(query, scenarioID, gameID, req.PickedTeamID, req.PredictedHomeScore, req.PredictedAwayScore, req.IsOverride).Scan(
+			&pick.ID, &pick.ScenarioID, &pick.GameID, &pick.PickedTeamID, &pick.PredictedHomeScore, &pick.PredictedAwayScore, &pick.Status, &pick.IsOverride, &pick.CreatedAt, &pick.UpdatedAt,
 		)
+		if err == sql.ErrNoRows {
+			// Another editor's pick was inserted after the check above
+			return pickConflictError(tx, apperror.PickAlreadyExists, "A pick already exists for this game", scenarioID, gameID)
+		}
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
 
-		// Update scenario's updated_at timestamp
-        scenarioIDInt, err := strconv.Atoi(scenarioID)
-        if err == nil {
-            _, updateErr := db.Conn.Exec(`
-                UPDATE scenarios
-                SET updated_at = NOW()
-                WHERE id = $1
-            `, scenarioIDInt)
-            if updateErr != nil {
-                c.Locals("scenario_update_error", updateErr.Error())
-            }
-        }
+		_, err = tx.Exec(`UPDATE scenarios SET updated_at = NOW() WHERE id = $1`, scenarioID)
+		if err != nil {
+			return err
+		}
+
+		err = recordScenarioChange(tx, c, scenarioID, scenarioChange{Action: history.ActionPickCreate, GameID: &gameID, Before: before})
+		if err != nil {
+			return err
+		}
 
-		return c.Status(201).JSON(map[string]interface{}{
-			"id": id,
-			"scenario_id": sID,
-			"game_id": gID,
-			"picked_team_id": pickedTeamID,
-			"predicted_home_score": predictedHomeScore,
-			"predicted_away_score": predictedAwayScore,
-			"status": status,
-			"created_at": createdAt,
-			"updated_at": updatedAt,
-		})
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		standings.InvalidateScenario(scenarioID)
+
+		return c.Status(201).JSON(pick)
 	}
 }
 
 func updatePick(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		gameID := c.Params("game_id")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
+		params := validation.NewParams(c)
+		scenarioID, gameID := params.ID("scenario_id"), params.ID("game_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
 
-		if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+		access, err := authorizeScenario(db, scenarioID, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
 		}
 
-		type UpdatePickRequest struct {
-			PickedTeamID *int `json:"picked_team_id"`
-			PredictedHomeScore *int `json:"predicted_home_score"`
-			PredictedAwayScore *int `json:"predicted_away_score"`
+		game, err := getScenarioGame(db.Conn, access, gameID)
+		if err != nil {
+			return err
+		}
+		if locked, err := getLockedGameIDs(db.Conn, access, []int{gameID}, time.Now()); err != nil {
+			return err
+		} else if len(locked) > 0 {
+			return picksLockedError(locked)
 		}
 
 		var req UpdatePickRequest
 		if err := c.BodyParser(&req); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
 		}
 
-		// If both scores are provided, validate that picked team id matches winning team
-		if req.PredictedHomeScore != nil && req.PredictedAwayScore != nil && req.PickedTeamID != nil {
-			var homeTeamID, awayTeamID int
-			err := db.Conn.QueryRow(`
-				SELECT home_team_id, away_team_id
-				FROM games
-				WHERE id = $1
-			`, gameID).Scan(&homeTeamID, &awayTeamID)
-
-			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve game details"})
-			}
+		// Scores must be in the sport's range and decide the picked team when both are given
+		var errs validation.Errors
+		req.PickedTeamID = errs.Pick("", access.SportID, game.homeTeamID, game.awayTeamID, req.PickedTeamID, req.PredictedHomeScore, req.PredictedAwayScore)
+		if err := errs.Err(); err != nil {
+			return err
+		}
 
-			// Determine winner based on scores
-			var expectedPickedTeamID int
-			if *req.PredictedHomeScore > *req.PredictedAwayScore {
-				expectedPickedTeamID = homeTeamID
-			} else if *req.PredictedAwayScore > *req.PredictedHomeScore {
-				expectedPickedTeamID = awayTeamID
-			} else {
-				// Tie scenario - no winner
-				expectedPickedTeamID = 0
-			}
+		// Picks on final games must be marked as overrides, and only where the scenario allows them
+		if message, err := getPickOverrideError(db.Conn, access, gameID, req.IsOverride); err != nil {
+			return err
+		} else if message != "" {
+			return apperror.New(apperror.PickOverrideNotAllowed, message).With(PickOverrideErrorResponse{GameID: gameID})
+		}
 
-			// Override picked team ID with calculated winner
-			req.PickedTeamID = &expectedPickedTeamID
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
 		}
+		defer tx.Rollback()
 
-		// Check if playoffs exist for this scenario
-		var playoffStateID int
-		err := db.Conn.QueryRow(`
-			SELECT id FROM playoff_states WHERE scenario_id = $1
-		`, scenarioID).Scan(&playoffStateID)
-		if err == nil {
-			// Playoffs exist - delete them since regular season pick is being updated
-			_, err = db.Conn.Exec(`
-				DELETE FROM playoff_matchups WHERE playoff_state_id = $1
-			`, playoffStateID)
-			if err != nil {
-				c.Locals("playoff_matchup_delete_error", err.Error())
-			}
+		// Lock the pick, and reject the edit if it's gone or another editor changed it since the client loaded it
+		current, err := getCurrentPick(tx, scenarioID, gameID)
+		if err != nil {
+			return err
+		}
+		if current == nil && req.ExpectedUpdatedAt == nil {
+			return apperror.New(apperror.PickNotFound, "Pick not found")
+		}
+		if pickChangedSince(current, req.ExpectedUpdatedAt) {
+			return apperror.New(apperror.PickConflict, "Pick was changed by another editor").With(PickConflictResponse{CurrentPick: current})
+		}
 
-			_, err = db.Conn.Exec(`
-				DELETE FROM playoff_states WHERE id = $1
-			`, playoffStateID)
-			if err != nil {
-				c.Locals("playoff_state_delete_error", err.Error())
-			}
+		// Capture the locked pick and the bracket being reset for the change log
+		before, err := captureScenarioState(tx, scenarioID, false, []int{gameID}, true)
+		if err != nil {
+			return err
 		}
 
-		query := `
+		var pick PickResponse
+		err = tx.QueryRow(`
 			UPDATE picks
-			SET picked_team_id = $1, predicted_home_score = $2, predicted_away_score = $3, updated_at = NOW()
+			SET picked_team_id = $1, predicted_home_score = $2, predicted_away_score = $3, is_override = $6, updated_at = NOW()
 			WHERE scenario_id = $4 AND game_id = $5
-			RETURNING id, scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, updated_at
-		`
+			RETURNING id, scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override, created_at, updated_at
+		`, req.PickedTeamID, req.PredictedHomeScore, req.PredictedAwayScore, scenarioID, gameID, req.IsOverride).Scan(
+			&pick.ID, &pick.ScenarioID, &pick.GameID, &pick.PickedTeamID, &pick.PredictedHomeScore, &pick.PredictedAwayScore, &pick.Status, &pick.IsOverride, &pick.CreatedAt, &pick.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
 
-		var id, sID, gID int
-		var pickedTeamID *int
-		var predictedHomeScore, predictedAwayScore *int
-		var status *string
-		var updatedAt time.Time
+		// Regular season pick changed, so any existing playoff bracket is stale
+		_, err = tx.Exec(`DELETE FROM playoff_states WHERE scenario_id = $1`, scenarioID)
+		if err != nil {
+			return err
+		}
 
-		err = db.Conn.QueryRow(query, req.PickedTeamID, req.PredictedHomeScore, req.PredictedAwayScore, scenarioID, gameID).Scan(
-			&id, &sID, &gID, &pickedTeamID, &predictedHomeScore, &predictedAwayScore, &status, &updatedAt,
-		)
+		_, err = tx.Exec(`UPDATE scenarios SET updated_at = NOW() WHERE id = $1`, scenarioID)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
 
-		// Update scenario's updated_at timestamp
-        scenarioIDInt, err := strconv.Atoi(scenarioID)
-        if err == nil {
-            _, updateErr := db.Conn.Exec(`
-                UPDATE scenarios
-                SET updated_at = NOW()
-                WHERE id = $1
-            `, scenarioIDInt)
-            if updateErr != nil {
-                c.Locals("scenario_update_error", updateErr.Error())
-            }
-        }
+		err = recordScenarioChange(tx, c, scenarioID, scenarioChange{Action: history.ActionPickUpdate, GameID: &gameID, Before: before})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		standings.InvalidateScenario(scenarioID)
 
-		return c.JSON(map[string]interface{}{
-			"id": id,
-			"scenario_id": sID,
-			"game_id": gID,
-			"picked_team_id": pickedTeamID,
-			"predicted_home_score": predictedHomeScore,
-			"predicted_away_score": predictedAwayScore,
-			"status": status,
-			"updated_at": updatedAt,
-		})
+		return c.JSON(pick)
 	}
 }
 
 func deletePick(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		gameID := c.Params("game_id")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
+		params := validation.NewParams(c)
+		scenarioID, gameID := params.ID("scenario_id"), params.ID("game_id")
+		// Optional version the client last saw, so a pick changed by another editor isn't deleted
+		expectedUpdatedAt := params.QueryTime("expected_updated_at")
+		if err := params.Err(); err != nil {
+			return err
+		}
 
-		if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+		access, err := authorizeScenario(db, scenarioID, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
 		}
 
-		query := `
-			DELETE FROM picks
-			WHERE scenario_id = $1 AND game_id = $2
-		`
-		_, err := db.Conn.Exec(query, scenarioID, gameID)
+		if locked, err := getLockedGameIDs(db.Conn, access, []int{gameID}, time.Now()); err != nil {
+			return err
+		} else if len(locked) > 0 {
+			return picksLockedError(locked)
+		}
+
+		tx, err := db.Conn.Begin()
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
+		defer tx.Rollback()
 
-		// Update scenario's updated_at timestamp
-        scenarioIDInt, err := strconv.Atoi(scenarioID)
-        if err == nil {
-            _, updateErr := db.Conn.Exec(`
-                UPDATE scenarios
-                SET updated_at = NOW()
-                WHERE id = $1
-            `, scenarioIDInt)
-            if updateErr != nil {
-                c.Locals("scenario_update_error", updateErr.Error())
-            }
-        }
+		// Lock the pick, and refuse the delete if it's gone or another editor changed it since the client loaded it
+		current, err := getCurrentPick(tx, scenarioID, gameID)
+		if err != nil {
+			return err
+		}
+		if current == nil && expectedUpdatedAt == nil {
+			return apperror.New(apperror.PickNotFound, "Pick not found")
+		}
+		if pickChangedSince(current, expectedUpdatedAt) {
+			return apperror.New(apperror.PickConflict, "Pick was changed by another editor").With(PickConflictResponse{CurrentPick: current})
+		}
+
+		// Capture the locked pick for the change log
+		before, err := captureScenarioState(tx, scenarioID, false, []int{gameID}, false)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM picks WHERE scenario_id = $1 AND game_id = $2`, scenarioID, gameID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`UPDATE scenarios SET updated_at = NOW() WHERE id = $1`, scenarioID)
+		if err != nil {
+			return err
+		}
+
+		err = recordScenarioChange(tx, c, scenarioID, scenarioChange{Action: history.ActionPickDelete, GameID: &gameID, Before: before})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		standings.InvalidateScenario(scenarioID)
 
-		return c.JSON(fiber.Map{"message": "Pick deleted successfully"})
+		return c.JSON(MessageResponse{Message: "Pick deleted successfully"})
 	}
 }
 
-func verifyScenarioOwnership(db *database.DB, scenarioID string, isAuthenticated bool, c *fiber.Ctx) bool {
-	var ownerUserID *int
-	var ownerSessionToken *string
+// Teams playing in the game a pick is for
+type pickGame struct {
+	homeTeamID int
+	awayTeamID int
+}
 
-	query := `
-		SELECT user_id, session_token
-		FROM scenarios
+// Loads the game a single-pick request names, which must be in the scenario's season
+func getScenarioGame(q sqlExecutor, access *scenarioAccess, gameID int) (pickGame, error) {
+	var game pickGame
+	var seasonID int
+	err := q.QueryRow(`
+		SELECT season_id, home_team_id, away_team_id
+		FROM games
 		WHERE id = $1
-	`
-	err := db.Conn.QueryRow(query, scenarioID).Scan(&ownerUserID, &ownerSessionToken)
+	`, gameID).Scan(&seasonID, &game.homeTeamID, &game.awayTeamID)
+	if err == sql.ErrNoRows {
+		return game, apperror.New(apperror.GameNotFound, "Game not found")
+	}
 	if err != nil {
+		return game, err
+	}
+	if seasonID != access.SeasonID {
+		var errs validation.Errors
+		errs.Add(validation.InPath, "game_id", "Game not found in scenario's season")
+		return game, errs.Err()
+	}
+	return game, nil
+}
+
+// Maximum number of upserts and deletes accepted in a single batch request
+const maxBatchPickOperations = 2000
+
+type BatchPickUpsert struct {
+	GameID             int        `json:"game_id"`
+	PickedTeamID       *int       `json:"picked_team_id"`
+	PredictedHomeScore *int       `json:"predicted_home_score"`
+	PredictedAwayScore *int       `json:"predicted_away_score"`
+	IsOverride         bool       `json:"is_override"`
+	ExpectedUpdatedAt  *time.Time `json:"expected_updated_at"`
+}
+
+type BatchPicksRequest struct {
+	Upserts []BatchPickUpsert `json:"upserts"`
+	Deletes []int             `json:"deletes"`
+}
+
+type BatchPicksResponse struct {
+	Upserted  []PickResponse    `json:"upserted"`
+	Deleted   int64             `json:"deleted"`
+	Standings StandingsResponse `json:"standings"`
+}
+
+// A batch operation that failed validation, by its index in upserts or deletes. Field names the
+// invalid field when one is to blame, e.g. upserts[2].predicted_home_score.
+type BatchPickError struct {
+	Operation string `json:"operation"`
+	Index     int    `json:"index"`
+	GameID    int    `json:"game_id"`
+	Field     string `json:"field,omitempty"`
+	Error     string `json:"error"`
+}
+
+// Details are only sent when individual operations failed validation
+type BatchPicksErrorResponse struct {
+	apperror.Problem
+	Details []BatchPickError `json:"details,omitempty"`
+}
+
+// An upsert whose pick another editor changed since the client loaded it
+type BatchPickConflict struct {
+	Index       int          `json:"index"`
+	GameID      int          `json:"game_id"`
+	CurrentPick *currentPick `json:"current_pick"`
+}
+
+type BatchPicksConflictResponse struct {
+	apperror.Problem
+	Conflicts []BatchPickConflict `json:"conflicts"`
+}
+
+func batchUpdatePicks(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
+		}
+		sID, seasonID, sportID := access.ID, access.SeasonID, access.SportID
+
+		var req BatchPicksRequest
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+		}
+
+		if len(req.Upserts) == 0 && len(req.Deletes) == 0 {
+			return validation.Field("upserts", "No pick operations provided")
+		}
+		if len(req.Upserts)+len(req.Deletes) > maxBatchPickOperations {
+			return validation.Field("upserts", fmt.Sprintf("Batch cannot contain more than %d operations", maxBatchPickOperations))
+		}
+
+		// Load every referenced game that belongs to the scenario's season
+		gameIDs := make([]int64, 0, len(req.Upserts)+len(req.Deletes))
+		for _, upsert := range req.Upserts {
+			gameIDs = append(gameIDs, int64(upsert.GameID))
+		}
+		for _, gameID := range req.Deletes {
+			gameIDs = append(gameIDs, int64(gameID))
+		}
+
+		type gameTeams struct {
+			pickGame
+			isFinal bool
+		}
+
+		rows, err := db.Query(`
+			SELECT id, home_team_id, away_team_id, status = 'final' AND home_score IS NOT NULL AND away_score IS NOT NULL
+			FROM games
+			WHERE season_id = $1 AND id = ANY($2)
+		`, seasonID, pq.Array(gameIDs))
+		if err != nil {
+			return err
+		}
+		seasonGames := make(map[int]gameTeams)
+		for rows.Next() {
+			var id int
+			var game gameTeams
+			if err := rows.Scan(&id, &game.homeTeamID, &game.awayTeamID, &game.isFinal); err != nil {
+				rows.Close()
+				return err
+			}
+			seasonGames[id] = game
+		}
+		rows.Close()
+
+		// Validate every operation before touching the database
+		var validationErrors []BatchPickError
+		addValidationError := func(operation string, index int, gameID int, field string, message string) {
+			validationErrors = append(validationErrors, BatchPickError{Operation: operation, Index: index, GameID: gameID, Field: field, Error: message})
+		}
+
+		seen := make(map[int]bool)
+		for i := range req.Upserts {
+			upsert := &req.Upserts[i]
+			prefix := fmt.Sprintf("upserts[%d].", i)
+			game, exists := seasonGames[upsert.GameID]
+			if !exists {
+				addValidationError("upsert", i, upsert.GameID, prefix+"game_id", "Game not found in scenario's season")
+				continue
+			}
+			if seen[upsert.GameID] {
+				addValidationError("upsert", i, upsert.GameID, prefix+"game_id", "Game appears more than once in batch")
+				continue
+			}
+			seen[upsert.GameID] = true
+
+			// Scores must be in the sport's range and decide the picked team when both are given
+			var errs validation.Errors
+			upsert.PickedTeamID = errs.Pick(prefix, sportID, game.homeTeamID, game.awayTeamID, upsert.PickedTeamID, upsert.PredictedHomeScore, upsert.PredictedAwayScore)
+			if !errs.Empty() {
+				for _, fieldErr := range errs.List() {
+					addValidationError("upsert", i, upsert.GameID, fieldErr.Field, fieldErr.Message)
+				}
+				continue
+			}
+
+			if upsert.PickedTeamID == nil {
+				addValidationError("upsert", i, upsert.GameID, prefix+"picked_team_id", "picked_team_id or both predicted scores are required")
+				continue
+			}
+			if message := checkPickOverride(access.ResultMode, game.isFinal, upsert.IsOverride); message != "" {
+				addValidationError("upsert", i, upsert.GameID, prefix+"is_override", message)
+			}
+		}
+		for i, gameID := range req.Deletes {
+			field := fmt.Sprintf("deletes[%d]", i)
+			if _, exists := seasonGames[gameID]; !exists {
+				addValidationError("delete", i, gameID, field, "Game not found in scenario's season")
+				continue
+			}
+			if seen[gameID] {
+				addValidationError("delete", i, gameID, field, "Game appears more than once in batch")
+				continue
+			}
+			seen[gameID] = true
+		}
+
+		if len(validationErrors) > 0 {
+			return apperror.New(apperror.ValidationFailed, "Invalid pick operations").With(BatchPicksErrorResponse{Details: validationErrors})
+		}
+
+		// Prediction scenarios reject the whole batch if any game has already started
+		lockCheckIDs := make([]int, 0, len(gameIDs))
+		for _, gameID := range gameIDs {
+			lockCheckIDs = append(lockCheckIDs, int(gameID))
+		}
+		locked, err := getLockedGameIDs(db.Conn, access, lockCheckIDs, time.Now())
+		if err != nil {
+			return err
+		}
+		if len(locked) > 0 {
+			return picksLockedError(locked)
+		}
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// Capture the affected picks and bracket for the change log
+		changedGameIDs := make([]int, 0, len(req.Upserts)+len(req.Deletes))
+		for _, upsert := range req.Upserts {
+			changedGameIDs = append(changedGameIDs, upsert.GameID)
+		}
+		changedGameIDs = append(changedGameIDs, req.Deletes...)
+		before, err := captureScenarioState(tx, sID, false, changedGameIDs, true)
+		if err != nil {
+			return err
+		}
+
+		// Lock the affected picks and reject the whole batch if another editor changed any of them
+		var conflicts []BatchPickConflict
+		for i, upsert := range req.Upserts {
+			if upsert.ExpectedUpdatedAt == nil {
+				continue
+			}
+			current, err := getCurrentPick(tx, sID, upsert.GameID)
+			if err != nil {
+				return err
+			}
+			if pickChangedSince(current, upsert.ExpectedUpdatedAt) {
+				conflicts = append(conflicts, BatchPickConflict{Index: i, GameID: upsert.GameID, CurrentPick: current})
+			}
+		}
+		if len(conflicts) > 0 {
+			return apperror.New(apperror.PickConflict, "Picks were changed by another editor").With(BatchPicksConflictResponse{Conflicts: conflicts})
+		}
+
+		upserted := []PickResponse{}
+		for _, upsert := range req.Upserts {
+			pick := PickResponse{ScenarioID: sID}
+
+			err := tx.QueryRow(`
+				INSERT INTO picks (scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override)
+				VALUES ($1, $2, $3, $4, $5, 'pending', $6)
+				ON CONFLICT (scenario_id, game_id) DO UPDATE SET
+					picked_team_id = EXCLUDED.picked_team_id,
+					predicted_home_score = EXCLUDED.predicted_home_score,
+					predicted_away_score = EXCLUDED.predicted_away_score,
+					is_override = EXCLUDED.is_override,
+					updated_at = NOW()
+				RETURNING id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override, created_at, updated_at
+			`, sID, upsert.GameID, upsert.PickedTeamID, upsert.PredictedHomeScore, upsert.PredictedAwayScore, upsert.IsOverride).Scan(
+				&pick.ID, &pick.GameID, &pick.PickedTeamID, &pick.PredictedHomeScore, &pick.PredictedAwayScore, &pick.Status, &pick.IsOverride, &pick.CreatedAt, &pick.UpdatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			upserted = append(upserted, pick)
+		}
+
+		var deleted int64
+		if len(req.Deletes) > 0 {
+			result, err := tx.Exec(`
+				DELETE FROM picks
+				WHERE scenario_id = $1 AND game_id = ANY($2)
+			`, sID, pq.Array(req.Deletes))
+			if err != nil {
+				return err
+			}
+			deleted, _ = result.RowsAffected()
+		}
+
+		// Regular season picks changed, so any existing playoff bracket is stale
+		_, err = tx.Exec(`DELETE FROM playoff_states WHERE scenario_id = $1`, sID)
+		if err != nil {
+			return err
+		}
+
+		// Update scenario's updated_at timestamp once for the whole batch
+		_, err = tx.Exec(`UPDATE scenarios SET updated_at = NOW() WHERE id = $1`, sID)
+		if err != nil {
+			return err
+		}
+
+		err = recordScenarioChange(tx, c, sID, scenarioChange{Action: history.ActionPicksBatch, Before: before})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		standings.InvalidateScenario(sID)
+
+		standingsResponse, err := buildStandingsResponse(db, sID, seasonID, sportID, standings.Cutoff{})
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(BatchPicksResponse{Upserted: upserted, Deleted: deleted, Standings: standingsResponse})
+	}
+}
+
+// Returns the winning team for a predicted score, or 0 for a tie
+func winnerFromScores(homeTeamID int, awayTeamID int, homeScore int, awayScore int) int {
+	if homeScore > awayScore {
+		return homeTeamID
+	} else if awayScore > homeScore {
+		return awayTeamID
+	}
+	return 0
+}
+
+// Pick as currently stored, returned with conflicts so the client can show what changed
+type currentPick struct {
+	ID                 int       `json:"id"`
+	GameID             int       `json:"game_id"`
+	PickedTeamID       *int      `json:"picked_team_id"`
+	PredictedHomeScore *int      `json:"predicted_home_score"`
+	PredictedAwayScore *int      `json:"predicted_away_score"`
+	Status             *string   `json:"status"`
+	IsOverride         bool      `json:"is_override"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// Gets and locks a scenario's pick for a game when run in a transaction, or nil when there is none
+func getCurrentPick(q sqlExecutor, scenarioID interface{}, gameID interface{}) (*currentPick, error) {
+	var pick currentPick
+	err := q.QueryRow(`
+		SELECT id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override, updated_at
+		FROM picks
+		WHERE scenario_id = $1 AND game_id = $2
+		FOR UPDATE
+	`, scenarioID, gameID).Scan(
+		&pick.ID, &pick.GameID, &pick.PickedTeamID, &pick.PredictedHomeScore, &pick.PredictedAwayScore, &pick.Status, &pick.IsOverride, &pick.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pick, nil
+}
+
+// Whether a pick no longer matches the updated_at a client last saw; a deleted pick always counts as changed
+func pickChangedSince(pick *currentPick, expected *time.Time) bool {
+	if expected == nil {
 		return false
 	}
+	if pick == nil {
+		return true
+	}
+	// Postgres keeps microseconds, and timestamps are stored in UTC without a zone
+	stored := time.Date(pick.UpdatedAt.Year(), pick.UpdatedAt.Month(), pick.UpdatedAt.Day(),
+		pick.UpdatedAt.Hour(), pick.UpdatedAt.Minute(), pick.UpdatedAt.Second(), pick.UpdatedAt.Nanosecond(), time.UTC)
+	return !stored.Truncate(time.Microsecond).Equal(expected.UTC().Truncate(time.Microsecond))
+}
+
+// Conflict error carrying the pick as it is now, so the client can reconcile
+func pickConflictError(q sqlExecutor, code apperror.Code, detail string, scenarioID int, gameID int) error {
+	current, err := getCurrentPick(q, scenarioID, gameID)
+	if err != nil {
+		return err
+	}
+	return apperror.New(code, detail).With(PickConflictResponse{CurrentPick: current})
+}
 
+// Checks whether the current user or guest session owns a scenario with the given owner columns
+func isScenarioOwner(c *fiber.Ctx, ownerUserID *int, ownerSessionToken *string) bool {
 	// Get current user info from locals
+	isAuthenticated, _ := c.Locals("is_authenticated").(bool)
 	currentUserID, _ := c.Locals("user_id").(int)
 	currentSessionToken, _ := c.Locals("session_token").(string)
 
@@ -3,30 +3,114 @@
 package handlers
 
 import (
-	"strconv"
+	"database/sql"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"gamescript/internal/apperror"
 	"gamescript/internal/database"
+	"gamescript/internal/history"
+	"gamescript/internal/models"
 	"gamescript/internal/playoffs"
+	"gamescript/internal/validation"
 )
 
 
-func getPlayoffState(db *database.DB) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
+// A scenario's bracket, null until playoffs are enabled, and whether they can be
+type PlayoffStatusResponse struct {
+	PlayoffState	*models.PlayoffState	`json:"playoff_state"`
+	CanEnable		bool					`json:"can_enable"`
+}
 
-		if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
-		}
+// A single game of an NFL round or NBA play-in
+type PlayoffMatchupResponse struct {
+	ID							int					`json:"id"`
+	Round						int					`json:"round"`
+	MatchupOrder				int					`json:"matchup_order"`
+	GameNumber					*int				`json:"game_number"`
+	Conference					*string				`json:"conference"`
+	HigherSeed					int					`json:"higher_seed"`
+	LowerSeed					int					`json:"lower_seed"`
+	HigherSeedTeamID			int					`json:"higher_seed_team_id"`
+	LowerSeedTeamID				int					`json:"lower_seed_team_id"`
+	PickedTeamID				*int				`json:"picked_team_id"`
+	PredictedHigherSeedScore	*int				`json:"predicted_higher_seed_score"`
+	PredictedLowerSeedScore		*int				`json:"predicted_lower_seed_score"`
+	Status						*string				`json:"status"`
+	CreatedAt					time.Time			`json:"created_at"`
+	UpdatedAt					time.Time			`json:"updated_at"`
+	HigherSeedTeam				PlayoffTeamResponse	`json:"higher_seed_team"`
+	LowerSeedTeam				PlayoffTeamResponse	`json:"lower_seed_team"`
+}
+
+// A best-of series of an NBA round
+type PlayoffSeriesResponse struct {
+	ID						int					`json:"id"`
+	Round					int					`json:"round"`
+	SeriesOrder				int					`json:"series_order"`
+	Conference				*string				`json:"conference"`
+	HigherSeed				int					`json:"higher_seed"`
+	LowerSeed				int					`json:"lower_seed"`
+	HigherSeedTeamID		int					`json:"higher_seed_team_id"`
+	LowerSeedTeamID			int					`json:"lower_seed_team_id"`
+	PickedTeamID			*int				`json:"picked_team_id"`
+	PredictedHigherSeedWins	*int				`json:"predicted_higher_seed_wins"`
+	PredictedLowerSeedWins	*int				`json:"predicted_lower_seed_wins"`
+	BestOf					int					`json:"best_of"`
+	Status					*string				`json:"status"`
+	CreatedAt				time.Time			`json:"created_at"`
+	UpdatedAt				time.Time			`json:"updated_at"`
+	HigherSeedTeam			PlayoffTeamResponse	`json:"higher_seed_team"`
+	LowerSeedTeam			PlayoffTeamResponse	`json:"lower_seed_team"`
+}
 
-		sID, _ := strconv.Atoi(scenarioID)
-		var seasonID, sportID int
-		err := db.Conn.QueryRow("SELECT season_id, sport_id FROM scenarios WHERE id = $1", sID).Scan(&seasonID, &sportID)
+type PlayoffTeamResponse struct {
+	ID					int		`json:"id"`
+	Abbreviation		string	`json:"abbreviation"`
+	City				string	`json:"city"`
+	Name				string	`json:"name"`
+	LogoURL				*string	`json:"logo_url"`
+	AlternateLogoURL	*string	`json:"alternate_logo_url"`
+	PrimaryColor		string	`json:"primary_color"`
+	SecondaryColor		string	`json:"secondary_color"`
+}
+
+// Entry of a round's listing: matchups for single-game rounds, series for NBA best-of rounds
+type PlayoffRoundEntry struct{}
+
+func (PlayoffRoundEntry) OneOf() []interface{} {
+	return []interface{}{PlayoffMatchupResponse{}, PlayoffSeriesResponse{}}
+}
+
+type PlayoffPickResponse struct {
+	ID							int		`json:"id"`
+	PickedTeamID				*int	`json:"picked_team_id"`
+	PredictedHigherSeedScore	*int	`json:"predicted_higher_seed_score"`
+	PredictedLowerSeedScore		*int	`json:"predicted_lower_seed_score"`
+}
+
+type PlayoffSeriesPickResponse struct {
+	ID						int		`json:"id"`
+	PickedTeamID			*int	`json:"picked_team_id"`
+	PredictedHigherSeedWins	*int	`json:"predicted_higher_seed_wins"`
+	PredictedLowerSeedWins	*int	`json:"predicted_lower_seed_wins"`
+}
+
+// Updating a pick returns the matchup or series pick, depending on the round
+type PlayoffPickUpdate struct{}
+
+func (PlayoffPickUpdate) OneOf() []interface{} {
+	return []interface{}{PlayoffPickResponse{}, PlayoffSeriesPickResponse{}}
+}
+
+func getPlayoffState(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleViewer, c)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Scenario not found"})
+			return err
 		}
+		sID, seasonID, sportID := access.ID, access.SeasonID, access.SportID
 
 		if sportID == 1 {
 			generator := playoffs.NewNFLPlayoffGenerator(db)
@@ -34,7 +118,7 @@ func getPlayoffState(db *database.DB) fiber.Handler {
 			// Check if all regular season games are complete
 			allComplete, err := generator.CheckAndEnableNFLPlayoffs(sID, seasonID)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
 
 			// Try to get existing playoff state
@@ -44,30 +128,18 @@ func getPlayoffState(db *database.DB) fiber.Handler {
 				WHERE scenario_id = $1
 			`
 
-			var playoffState map[string]interface{}
-			var id, currentRound int
-			var isEnabled bool
-			var createdAt, updatedAt string
-
-			err = db.Conn.QueryRow(query, sID).Scan(&id, &sID, &currentRound, &isEnabled, &createdAt, &updatedAt)
+			var state models.PlayoffState
+			err = db.Conn.QueryRow(query, sID).Scan(&state.ID, &state.ScenarioID, &state.CurrentRound, &state.IsEnabled, &state.CreatedAt, &state.UpdatedAt)
 
+			// No playoff state exists yet
+			var playoffState *models.PlayoffState
 			if err == nil {
-				playoffState = map[string]interface{}{
-					"id":            id,
-					"scenario_id":   sID,
-					"current_round": currentRound,
-					"is_enabled":    isEnabled,
-					"created_at":    createdAt,
-					"updated_at":    updatedAt,
-				}
-			} else {
-				// No playoff state exists yet
-				playoffState = nil
+				playoffState = &state
 			}
 
-			return c.JSON(fiber.Map{
-				"playoff_state": playoffState,
-				"can_enable":    allComplete,
+			return c.JSON(PlayoffStatusResponse{
+				PlayoffState: playoffState,
+				CanEnable:    allComplete,
 			})
 		} else if sportID == 2 {
 			generator := playoffs.NewNBAPlayoffGenerator(db)
@@ -75,7 +147,7 @@ func getPlayoffState(db *database.DB) fiber.Handler {
 			// Check if all regular season games are complete
 			allComplete, err := generator.CheckAndEnableNBAPlayoffs(sID, seasonID)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
 
 			// Try to get existing playoff state
@@ -85,51 +157,37 @@ func getPlayoffState(db *database.DB) fiber.Handler {
 				WHERE scenario_id = $1
 			`
 
-			var playoffState map[string]interface{}
-			var id, currentRound int
-			var isEnabled bool
-			var createdAt, updatedAt string
-
-			err = db.Conn.QueryRow(query, sID).Scan(&id, &sID, &currentRound, &isEnabled, &createdAt, &updatedAt)
+			var state models.PlayoffState
+			err = db.Conn.QueryRow(query, sID).Scan(&state.ID, &state.ScenarioID, &state.CurrentRound, &state.IsEnabled, &state.CreatedAt, &state.UpdatedAt)
 
+			// No playoff state exists yet
+			var playoffState *models.PlayoffState
 			if err == nil {
-				playoffState = map[string]interface{}{
-					"id":            id,
-					"scenario_id":   sID,
-					"current_round": currentRound,
-					"is_enabled":    isEnabled,
-					"created_at":    createdAt,
-					"updated_at":    updatedAt,
-				}
-			} else {
-				// No playoff state exists yet
-				playoffState = nil
+				playoffState = &state
 			}
 
-			return c.JSON(fiber.Map{
-				"playoff_state": playoffState,
-				"can_enable":    allComplete,
+			return c.JSON(PlayoffStatusResponse{
+				PlayoffState: playoffState,
+				CanEnable:    allComplete,
 			})
 		}
 		
-		return c.Status(400).JSON(fiber.Map{"error": "Playoffs not supported for this sport"})
+		return apperror.New(apperror.UnsupportedSport, "Playoffs not supported for this sport")
 	}
 }
 
 func enablePlayoffs(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
-
-		if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+		access, err := authorizeScenarioParam(db, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
 		}
+		sID, seasonID, sportID := access.ID, access.SeasonID, access.SportID
 
-		sID, _ := strconv.Atoi(scenarioID)
-		var seasonID, sportID int
-		err := db.Conn.QueryRow("SELECT season_id, sport_id FROM scenarios WHERE id = $1", sID).Scan(&seasonID, &sportID)
+		// Capture the bracket being replaced for the change log
+		before, err := captureScenarioState(db.Conn, sID, false, nil, true)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Scenario not found"})
+			return err
 		}
 
 		if sportID == 1 {
@@ -138,70 +196,70 @@ func enablePlayoffs(db *database.DB) fiber.Handler {
 			// Verify all games are complete
 			allComplete, err := generator.CheckAndEnableNFLPlayoffs(sID, seasonID)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
 			if !allComplete {
-				return c.Status(400).JSON(fiber.Map{"error": "Not all regular season games are complete"})
+				return apperror.New(apperror.RegularSeasonIncomplete, "Not all regular season games are complete")
 			}
 
 			// Generate wild card round
 			err = generator.GenerateNFLWildCardRound(sID, seasonID, sportID)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
 
-			return c.JSON(fiber.Map{"message": "NFL playoffs enabled successfully"})
+			logScenarioChange(db, c, sID, scenarioChange{Action: history.ActionPlayoffsEnable, Before: before})
+
+			return c.JSON(MessageResponse{Message: "NFL playoffs enabled successfully"})
 		} else if sportID == 2 {
 			generator := playoffs.NewNBAPlayoffGenerator(db)
 
 			// Verify all games are complete
 			allComplete, err := generator.CheckAndEnableNBAPlayoffs(sID, seasonID)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
 			if !allComplete {
-				return c.Status(400).JSON(fiber.Map{"error": "Not all regular season games are complete"})
+				return apperror.New(apperror.RegularSeasonIncomplete, "Not all regular season games are complete")
 			}
 
 			// Generate play-in round A
 			err = generator.GenerateNBAPlayInRoundA(sID, seasonID)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
 
-			return c.JSON(fiber.Map{"message": "NBA playoffs enabled successfully"})
+			logScenarioChange(db, c, sID, scenarioChange{Action: history.ActionPlayoffsEnable, Before: before})
+
+			return c.JSON(MessageResponse{Message: "NBA playoffs enabled successfully"})
 		}
 		
-		return c.Status(400).JSON(fiber.Map{"error": "Playoffs not supported for this sport"})
+		return apperror.New(apperror.UnsupportedSport, "Playoffs not supported for this sport")
 	}
 }
 
 func getPlayoffMatchups(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		roundStr := c.Params("round")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
-
-		if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+		params := validation.NewParams(c)
+		sID := params.ID("scenario_id")
+		round := params.Int("round", playoffs.RoundWildCard, playoffs.RoundNBAFinals)
+		if err := params.Err(); err != nil {
+			return err
 		}
 
-		// Get playoff round
-		round, err := strconv.Atoi(roundStr)
-		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid round"})
+		if _, err := authorizeScenario(db, sID, ScenarioRoleViewer, c); err != nil {
+			return err
 		}
 
-		sID, _ := strconv.Atoi(scenarioID)
 		var playoffStateID, sportID int
-		err = db.Conn.QueryRow(`
+		err := db.Conn.QueryRow(`
 			SELECT ps.id, s.sport_id
 			FROM playoff_states ps
 			JOIN scenarios s ON ps.scenario_id = s.id
 			WHERE ps.scenario_id = $1
 		`, sID).Scan(&playoffStateID, &sportID)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Playoffs not enabled for this scenario"})
+			return apperror.New(apperror.PlayoffsNotEnabled, "Playoffs not enabled for this scenario")
 		}
 
 		// For NBA, check if this is a series round
@@ -230,16 +288,16 @@ func getPlayoffMatchups(db *database.DB) fiber.Handler {
 
 		rows, err := db.Query(query, playoffStateID, round)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
 		defer rows.Close()
 
-		var matchups []map[string]interface{}
+		matchups := []PlayoffMatchupResponse{}
 		for rows.Next() {
 			var id, round, matchupOrder, higherSeed, lowerSeed, higherTeamID, lowerTeamID int
 			var pickedTeamID, predictedHigherScore, predictedLowerScore, gameNumber *int
 			var conference, status *string
-			var createdAt, updatedAt string
+			var createdAt, updatedAt time.Time
 			var higherAbbr, higherCity, higherName, higherColor, higherSecondary string
 			var lowerAbbr, lowerCity, lowerName, lowerColor, lowerSecondary string
 			var higherLogo, higherAltLogo, lowerLogo, lowerAltLogo *string
@@ -257,41 +315,41 @@ func getPlayoffMatchups(db *database.DB) fiber.Handler {
 				continue
 			}
 
-			matchups = append(matchups, map[string]interface{}{
-				"id":                          id,
-				"round":                       round,
-				"matchup_order":               matchupOrder,
-				"game_number":                 gameNumber,
-				"conference":                  conference,
-				"higher_seed":                 higherSeed,
-				"lower_seed":                  lowerSeed,
-				"higher_seed_team_id":         higherTeamID,
-				"lower_seed_team_id":          lowerTeamID,
-				"picked_team_id":              pickedTeamID,
-				"predicted_higher_seed_score": predictedHigherScore,
-				"predicted_lower_seed_score":  predictedLowerScore,
-				"status":                      status,
-				"created_at":                  createdAt,
-				"updated_at":                  updatedAt,
-				"higher_seed_team": map[string]interface{}{
-					"id":              higherTeamID,
-					"abbreviation":    higherAbbr,
-					"city":            higherCity,
-					"name":            higherName,
-					"logo_url":        higherLogo,
-					"alternate_logo_url": higherAltLogo,
-					"primary_color":   higherColor,
-					"secondary_color": higherSecondary,
+			matchups = append(matchups, PlayoffMatchupResponse{
+				ID:                       id,
+				Round:                    round,
+				MatchupOrder:             matchupOrder,
+				GameNumber:               gameNumber,
+				Conference:               conference,
+				HigherSeed:               higherSeed,
+				LowerSeed:                lowerSeed,
+				HigherSeedTeamID:         higherTeamID,
+				LowerSeedTeamID:          lowerTeamID,
+				PickedTeamID:             pickedTeamID,
+				PredictedHigherSeedScore: predictedHigherScore,
+				PredictedLowerSeedScore:  predictedLowerScore,
+				Status:                   status,
+				CreatedAt:                createdAt,
+				UpdatedAt:                updatedAt,
+				HigherSeedTeam: PlayoffTeamResponse{
+					ID:               higherTeamID,
+					Abbreviation:     higherAbbr,
+					City:             higherCity,
+					Name:             higherName,
+					LogoURL:          higherLogo,
+					AlternateLogoURL: higherAltLogo,
+					PrimaryColor:     higherColor,
+					SecondaryColor:   higherSecondary,
 				},
-				"lower_seed_team": map[string]interface{}{
-					"id":              lowerTeamID,
-					"abbreviation":    lowerAbbr,
-					"city":            lowerCity,
-					"name":            lowerName,
-					"logo_url":        lowerLogo,
-					"alternate_logo_url": lowerAltLogo,
-					"primary_color":   lowerColor,
-					"secondary_color": lowerSecondary,
+				LowerSeedTeam: PlayoffTeamResponse{
+					ID:               lowerTeamID,
+					Abbreviation:     lowerAbbr,
+					City:             lowerCity,
+					Name:             lowerName,
+					LogoURL:          lowerLogo,
+					AlternateLogoURL: lowerAltLogo,
+					PrimaryColor:     lowerColor,
+					SecondaryColor:   lowerSecondary,
 				},
 			})
 		}
@@ -321,16 +379,16 @@ func getPlayoffSeries(db *database.DB, c *fiber.Ctx, playoffStateID int, round i
 
 	rows, err := db.Query(query, playoffStateID, round)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	defer rows.Close()
 
-	var series []map[string]interface{}
+	series := []PlayoffSeriesResponse{}
 	for rows.Next() {
 		var id, round, seriesOrder, higherSeed, lowerSeed, higherTeamID, lowerTeamID, bestOf int
 		var pickedTeamID, predictedHigherWins, predictedLowerWins *int
 		var conference, status *string
-		var createdAt, updatedAt string
+		var createdAt, updatedAt time.Time
 		var higherAbbr, higherCity, higherName, higherColor, higherSecondary string
 		var lowerAbbr, lowerCity, lowerName, lowerColor, lowerSecondary string
 		var higherLogo, higherAltLogo, lowerLogo, lowerAltLogo *string
@@ -348,41 +406,41 @@ func getPlayoffSeries(db *database.DB, c *fiber.Ctx, playoffStateID int, round i
 			continue
 		}
 
-		series = append(series, map[string]interface{}{
-			"id":                         id,
-			"round":                      round,
-			"series_order":               seriesOrder,
-			"conference":                 conference,
-			"higher_seed":                higherSeed,
-			"lower_seed":                 lowerSeed,
-			"higher_seed_team_id":        higherTeamID,
-			"lower_seed_team_id":         lowerTeamID,
-			"picked_team_id":             pickedTeamID,
-			"predicted_higher_seed_wins": predictedHigherWins,
-			"predicted_lower_seed_wins":  predictedLowerWins,
-			"best_of":                    bestOf,
-			"status":                     status,
-			"created_at":                 createdAt,
-			"updated_at":                 updatedAt,
-			"higher_seed_team": map[string]interface{}{
-				"id":              higherTeamID,
-				"abbreviation":    higherAbbr,
-				"city":            higherCity,
-				"name":            higherName,
-				"logo_url":        higherLogo,
-				"alternate_logo_url": higherAltLogo,
-				"primary_color":   higherColor,
-				"secondary_color": higherSecondary,
+		series = append(series, PlayoffSeriesResponse{
+			ID:                      id,
+			Round:                   round,
+			SeriesOrder:             seriesOrder,
+			Conference:              conference,
+			HigherSeed:              higherSeed,
+			LowerSeed:               lowerSeed,
+			HigherSeedTeamID:        higherTeamID,
+			LowerSeedTeamID:         lowerTeamID,
+			PickedTeamID:            pickedTeamID,
+			PredictedHigherSeedWins: predictedHigherWins,
+			PredictedLowerSeedWins:  predictedLowerWins,
+			BestOf:                  bestOf,
+			Status:                  status,
+			CreatedAt:               createdAt,
+			UpdatedAt:               updatedAt,
+			HigherSeedTeam: PlayoffTeamResponse{
+				ID:               higherTeamID,
+				Abbreviation:     higherAbbr,
+				City:             higherCity,
+				Name:             higherName,
+				LogoURL:          higherLogo,
+				AlternateLogoURL: higherAltLogo,
+				PrimaryColor:     higherColor,
+				SecondaryColor:   higherSecondary,
 			},
-			"lower_seed_team": map[string]interface{}{
-				"id":              lowerTeamID,
-				"abbreviation":    lowerAbbr,
-				"city":            lowerCity,
-				"name":            lowerName,
-				"logo_url":        lowerLogo,
-				"alternate_logo_url": lowerAltLogo,
-				"primary_color":   lowerColor,
-				"secondary_color": lowerSecondary,
+			LowerSeedTeam: PlayoffTeamResponse{
+				ID:               lowerTeamID,
+				Abbreviation:     lowerAbbr,
+				City:             lowerCity,
+				Name:             lowerName,
+				LogoURL:          lowerLogo,
+				AlternateLogoURL: lowerAltLogo,
+				PrimaryColor:     lowerColor,
+				SecondaryColor:   lowerSecondary,
 			},
 		})
 	}
@@ -400,70 +458,67 @@ type UpdatePlayoffPickRequest struct {
 
 func updatePlayoffPick(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		itemID := c.Params("matchup_id") // This can be matchup or series ID
-		isAuthenticated := c.Locals("is_authenticated").(bool)
+		params := validation.NewParams(c)
+		sID := params.ID("scenario_id")
+		mID := params.ID("matchup_id") // This can be matchup or series ID
+		if err := params.Err(); err != nil {
+			return err
+		}
 
-		if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+		access, err := authorizeScenario(db, sID, ScenarioRoleEditor, c)
+		if err != nil {
+			return err
 		}
+		sportID := access.SportID
 
 		var req UpdatePlayoffPickRequest
 		if err := c.BodyParser(&req); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
 		}
 
-		mID, _ := strconv.Atoi(itemID)
-		sID, _ := strconv.Atoi(scenarioID)
-		var sportID, currentRound int
-		err := db.Conn.QueryRow(`
-			SELECT s.sport_id, ps.round
+		// Capture the bracket before the pick and the rounds it resets, for the change log
+		before, err := captureScenarioState(db.Conn, sID, false, nil, true)
+		if err != nil {
+			return err
+		}
+
+		// Series and matchups must belong to this scenario's bracket
+		var currentRound int
+		err = db.Conn.QueryRow(`
+			SELECT ps.round
 			FROM playoff_series ps
 			JOIN playoff_states pst ON ps.playoff_state_id = pst.id
-			JOIN scenarios s ON pst.scenario_id = s.id
-			WHERE ps.id = $1
-		`, mID).Scan(&sportID, &currentRound)
+			WHERE ps.id = $1 AND pst.scenario_id = $2
+		`, mID, sID).Scan(&currentRound)
 		if err == nil {
 			// This is a playoff series
-			return updatePlayoffSeriesPick(db, c, sID, mID, currentRound, &req)
+			return updatePlayoffSeriesPick(db, c, sID, mID, currentRound, &req, before)
+		}
+		if err != sql.ErrNoRows {
+			return err
 		}
 
 		// If not a series, look for matchup
+		var higherSeedTeamID, lowerSeedTeamID int
 		err = db.Conn.QueryRow(`
-			SELECT s.sport_id, pm.round
+			SELECT pm.round, pm.higher_seed_team_id, pm.lower_seed_team_id
 			FROM playoff_matchups pm
 			JOIN playoff_states ps ON pm.playoff_state_id = ps.id
-			JOIN scenarios s ON ps.scenario_id = s.id
-			WHERE pm.id = $1
-		`, mID).Scan(&sportID, &currentRound)
+			WHERE pm.id = $1 AND ps.scenario_id = $2
+		`, mID, sID).Scan(&currentRound, &higherSeedTeamID, &lowerSeedTeamID)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.MatchupNotFound, "Matchup or series not found")
+		}
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Matchup or series not found"})
+			return err
 		}
 
 		// Single elimination game logic
-		// If both scores are provided, validate that picked team id matches the winning team
-		if req.PredictedHigherSeedScore != nil && req.PredictedLowerSeedScore != nil && req.PickedTeamID != nil {
-			var higherSeedTeamID, lowerSeedTeamID int
-			err := db.Conn.QueryRow(`
-				SELECT higher_seed_team_id, lower_seed_team_id
-				FROM playoff_matchups
-				WHERE id = $1
-			`, itemID).Scan(&higherSeedTeamID, &lowerSeedTeamID)
-
-			if err != nil {
-				return c.Status(404).JSON(fiber.Map{"error": "Matchup not found"})
-			}
-
-			// Determine winner based on scores
-			var expectedTeamID int
-			if *req.PredictedHigherSeedScore > *req.PredictedLowerSeedScore {
-				expectedTeamID = higherSeedTeamID
-			} else {
-				expectedTeamID = lowerSeedTeamID
-			}
-
-			// Override the picked team ID with calculated winner
-			req.PickedTeamID = &expectedTeamID
+		// Scores must be in the sport's range and decide the picked team when both are given
+		var errs validation.Errors
+		req.PickedTeamID = errs.PlayoffPick(sportID, higherSeedTeamID, lowerSeedTeamID, req.PickedTeamID, req.PredictedHigherSeedScore, req.PredictedLowerSeedScore)
+		if err := errs.Err(); err != nil {
+			return err
 		}
 
 		// Delete subsequent rounds since we're modifying an earlier round
@@ -471,13 +526,13 @@ func updatePlayoffPick(db *database.DB) fiber.Handler {
 			generator := playoffs.NewNFLPlayoffGenerator(db)
 			err = generator.DeleteSubsequentNFLRounds(sID, currentRound)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": "Failed to reset subsequent rounds"})
+				return apperror.New(apperror.Internal, "Failed to reset subsequent rounds").WithCause(err)
 			}
 		} else if sportID == 2 {
 			generator := playoffs.NewNBAPlayoffGenerator(db)
 			err = generator.DeleteNBASubsequentRounds(sID, currentRound)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": "Failed to reset subsequent rounds"})
+				return apperror.New(apperror.Internal, "Failed to reset subsequent rounds").WithCause(err)
 			}
 		}
 
@@ -500,7 +555,7 @@ func updatePlayoffPick(db *database.DB) fiber.Handler {
 			&id, &pickedTeamID, &predictedHigherScore, &predictedLowerScore,
 		)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
 
 		// Update scenario's updated_at timestamp
@@ -513,55 +568,67 @@ func updatePlayoffPick(db *database.DB) fiber.Handler {
 			c.Locals("scenario_update_error", updateErr.Error())
 		}
 
-		return c.JSON(map[string]interface{}{
-			"id":                          id,
-			"picked_team_id":              pickedTeamID,
-			"predicted_higher_seed_score": predictedHigherScore,
-			"predicted_lower_seed_score":  predictedLowerScore,
+		logScenarioChange(db, c, sID, scenarioChange{Action: history.ActionPlayoffPickUpdate, Before: before})
+
+		return c.JSON(PlayoffPickResponse{
+			ID:                       id,
+			PickedTeamID:             pickedTeamID,
+			PredictedHigherSeedScore: predictedHigherScore,
+			PredictedLowerSeedScore:  predictedLowerScore,
 		})
 	}
 }
 
-func updatePlayoffSeriesPick(db *database.DB, c *fiber.Ctx, scenarioID int, seriesID int, currentRound int, req *UpdatePlayoffPickRequest) error {
-	// Validate series prediction
-	if req.PredictedHigherSeedWins != nil && req.PredictedLowerSeedWins != nil && req.PickedTeamID != nil {
-		if *req.PredictedHigherSeedWins < 0 || *req.PredictedLowerSeedWins < 0 {
-			return c.Status(400).JSON(fiber.Map{"error": "Predicted wins cannot be negative"})
-		}
-		if *req.PredictedHigherSeedWins > 4 || *req.PredictedLowerSeedWins > 4 {
-			return c.Status(400).JSON(fiber.Map{"error": "Predicted wins cannot exceed 4"})
-		}
-		if *req.PredictedHigherSeedWins == 4 && *req.PredictedLowerSeedWins == 4 {
-			return c.Status(400).JSON(fiber.Map{"error": "Both teams cannot have 4 wins"})
-		}
-		if *req.PredictedHigherSeedWins < 4 && *req.PredictedLowerSeedWins < 4 {
-			return c.Status(400).JSON(fiber.Map{"error": "One team must have 4 wins"})
-		}
-
-		// Determine winner based on predicted wins
-		var higherSeedTeamID, lowerSeedTeamID int
-		err := db.Conn.QueryRow(`
-			SELECT higher_seed_team_id, lower_seed_team_id
-			FROM playoff_series
-			WHERE id = $1
-		`, seriesID).Scan(&higherSeedTeamID, &lowerSeedTeamID)
-		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Series not found"})
-		}
+// Games a team must win to take a best-of-seven series
+const seriesWinsNeeded = 4
+
+func updatePlayoffSeriesPick(db *database.DB, c *fiber.Ctx, scenarioID int, seriesID int, currentRound int, req *UpdatePlayoffPickRequest, before *models.ScenarioState) error {
+	var higherSeedTeamID, lowerSeedTeamID int
+	err := db.Conn.QueryRow(`
+		SELECT higher_seed_team_id, lower_seed_team_id
+		FROM playoff_series
+		WHERE id = $1
+	`, seriesID).Scan(&higherSeedTeamID, &lowerSeedTeamID)
+	if err == sql.ErrNoRows {
+		return apperror.New(apperror.MatchupNotFound, "Series not found")
+	}
+	if err != nil {
+		return err
+	}
 
-		// Override the picked team ID with calculated winner
-		if *req.PredictedHigherSeedWins == 4 {
-			req.PickedTeamID = &higherSeedTeamID
-		} else {
-			req.PickedTeamID = &lowerSeedTeamID
+	// Validate series prediction; when both win counts are given they decide the picked team
+	var errs validation.Errors
+	if req.PredictedHigherSeedWins != nil && req.PredictedLowerSeedWins != nil {
+		higherWins, lowerWins := *req.PredictedHigherSeedWins, *req.PredictedLowerSeedWins
+		if higherWins < 0 || higherWins > seriesWinsNeeded {
+			errs.Addf(validation.InBody, "predicted_higher_seed_wins", "Predicted wins must be between 0 and %d", seriesWinsNeeded)
+		}
+		if lowerWins < 0 || lowerWins > seriesWinsNeeded {
+			errs.Addf(validation.InBody, "predicted_lower_seed_wins", "Predicted wins must be between 0 and %d", seriesWinsNeeded)
+		}
+		if errs.Empty() {
+			if higherWins == seriesWinsNeeded && lowerWins == seriesWinsNeeded {
+				errs.Add(validation.InBody, "predicted_lower_seed_wins", "Both teams cannot have 4 wins")
+			} else if higherWins < seriesWinsNeeded && lowerWins < seriesWinsNeeded {
+				errs.Add(validation.InBody, "predicted_lower_seed_wins", "One team must have 4 wins")
+			} else if higherWins == seriesWinsNeeded {
+				req.PickedTeamID = &higherSeedTeamID
+			} else {
+				req.PickedTeamID = &lowerSeedTeamID
+			}
 		}
+	} else if req.PickedTeamID != nil && *req.PickedTeamID != higherSeedTeamID && *req.PickedTeamID != lowerSeedTeamID {
+		errs.Add(validation.InBody, "picked_team_id", "Picked team is not playing in this series")
+	}
+	if err := errs.Err(); err != nil {
+		return err
 	}
 
 	// Delete subsequent rounds
 	generator := playoffs.NewNBAPlayoffGenerator(db)
-	err := generator.DeleteNBASubsequentRounds(scenarioID, currentRound)
+	err = generator.DeleteNBASubsequentRounds(scenarioID, currentRound)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to reset subsequent rounds"})
+		return apperror.New(apperror.Internal, "Failed to reset subsequent rounds").WithCause(err)
 	}
 
 	// Update the series pick
@@ -581,39 +648,45 @@ func updatePlayoffSeriesPick(db *database.DB, c *fiber.Ctx, scenarioID int, seri
 
 	err = db.Conn.QueryRow(query, req.PickedTeamID, req.PredictedHigherSeedWins, req.PredictedLowerSeedWins, seriesID).Scan(&id, &pickedTeamID, &predictedHigherWins, &predictedLowerWins)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	// Update scenario's updated_at timestamp
 	db.Conn.Exec(`UPDATE scenarios SET updated_at = NOW() WHERE id = $1`, scenarioID)
 
-	return c.JSON(map[string]interface{}{
-		"id":                         id,
-		"picked_team_id":             pickedTeamID,
-		"predicted_higher_seed_wins": predictedHigherWins,
-		"predicted_lower_seed_wins":  predictedLowerWins,
+	logScenarioChange(db, c, scenarioID, scenarioChange{Action: history.ActionPlayoffPickUpdate, Before: before})
+
+	return c.JSON(PlayoffSeriesPickResponse{
+		ID:                      id,
+		PickedTeamID:            pickedTeamID,
+		PredictedHigherSeedWins: predictedHigherWins,
+		PredictedLowerSeedWins:  predictedLowerWins,
 	})
 }
 
 func generateNextPlayoffRound(db *database.DB) fiber.Handler {
     return func(c *fiber.Ctx) error {
-        scenarioID := c.Params("scenario_id")
-        isAuthenticated := c.Locals("is_authenticated").(bool)
-
-        if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-            return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+        access, err := authorizeScenarioParam(db, ScenarioRoleEditor, c)
+        if err != nil {
+            return err
         }
 
-        sID, _ := strconv.Atoi(scenarioID)
+        sID := access.ID
         var currentRound, sportID, seasonID int
-        err := db.Conn.QueryRow(`
+        err = db.Conn.QueryRow(`
             SELECT ps.current_round, s.sport_id, s.season_id
             FROM playoff_states ps
             JOIN scenarios s ON ps.scenario_id = s.id
             WHERE ps.scenario_id = $1
         `, sID).Scan(&currentRound, &sportID, &seasonID)
         if err != nil {
-            return c.Status(404).JSON(fiber.Map{"error": "Playoff state not found"})
+            return apperror.New(apperror.PlayoffsNotEnabled, "Playoff state not found")
+        }
+
+        // Capture the bracket before the new round for the change log
+        before, err := captureScenarioState(db.Conn, sID, false, nil, true)
+        if err != nil {
+            return err
         }
 
 		if sportID == 1 {
@@ -622,28 +695,30 @@ func generateNextPlayoffRound(db *database.DB) fiber.Handler {
 			// Check if current round is complete
 			isComplete, err := generator.CheckNFLRoundComplete(sID, currentRound)
 			if err != nil || !isComplete {
-				return c.Status(400).JSON(fiber.Map{"error": "Current round is not complete"})
+				return apperror.New(apperror.RoundIncomplete, "Current round is not complete")
 			}
 
 			// Generate next round based on current round
 			if currentRound < playoffs.RoundSuperBowl {
 				err = generator.GenerateNFLNextRound(sID, seasonID, currentRound)
 			} else {
-				return c.Status(400).JSON(fiber.Map{"error": "No more rounds to generate"})
+				return apperror.New(apperror.NoMoreRounds, "No more rounds to generate")
 			}
 
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
 
-			return c.JSON(fiber.Map{"message": "Next round generated successfully"})
+			logScenarioChange(db, c, sID, scenarioChange{Action: history.ActionPlayoffRoundGenerate, Before: before})
+
+			return c.JSON(MessageResponse{Message: "Next round generated successfully"})
 		} else if sportID == 2 {
             generator := playoffs.NewNBAPlayoffGenerator(db)
             
             // Check if current round is complete
             isComplete, err := generator.CheckNBARoundComplete(sID, currentRound)
             if err != nil || !isComplete {
-                return c.Status(400).JSON(fiber.Map{"error": "Current round is not complete"})
+                return apperror.New(apperror.RoundIncomplete, "Current round is not complete")
             }
 
             // Generate next round based on current round
@@ -654,44 +729,53 @@ func generateNextPlayoffRound(db *database.DB) fiber.Handler {
             } else if currentRound < playoffs.RoundNBAFinals {
                 err = generator.GenerateNBANextRound(sID, currentRound)
             } else {
-                return c.Status(400).JSON(fiber.Map{"error": "No more rounds to generate"})
+                return apperror.New(apperror.NoMoreRounds, "No more rounds to generate")
             }
 
             if err != nil {
-                return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+                return err
             }
 
-            return c.JSON(fiber.Map{"message": "Next round generated successfully"})
+            logScenarioChange(db, c, sID, scenarioChange{Action: history.ActionPlayoffRoundGenerate, Before: before})
+
+            return c.JSON(MessageResponse{Message: "Next round generated successfully"})
         }
 
-        return c.Status(400).JSON(fiber.Map{"error": "Unsupported sport"})
+        return apperror.New(apperror.UnsupportedSport, "Unsupported sport")
     }
 }
 
 func deletePlayoffPick(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		matchupID := c.Params("matchup_id")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
+		params := validation.NewParams(c)
+		sID, mID := params.ID("scenario_id"), params.ID("matchup_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
 
-		if !verifyScenarioOwnership(db, scenarioID, isAuthenticated, c) {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+		if _, err := authorizeScenario(db, sID, ScenarioRoleEditor, c); err != nil {
+			return err
 		}
 
-		mID, err := strconv.Atoi(matchupID)
+		// Check if this is a series or single matchup, in this scenario's bracket
+		var playoffSeriesID *int
+		err := db.Conn.QueryRow(`
+			SELECT pm.playoff_series_id
+			FROM playoff_matchups pm
+			JOIN playoff_states ps ON pm.playoff_state_id = ps.id
+			WHERE pm.id = $1 AND ps.scenario_id = $2
+		`, mID, sID).Scan(&playoffSeriesID)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.MatchupNotFound, "Matchup not found")
+		}
 		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid matchup ID"})
+			return err
 		}
 
-		// Check if this is a series or single matchup
-		var playoffSeriesID *int
-		err = db.Conn.QueryRow(`
-			SELECT playoff_series_id
-			FROM playoff_matchups
-			WHERE id = $1
-		`, mID).Scan(&playoffSeriesID)
+		// Capture the bracket before the pick is cleared for the change log
+		before, err := captureScenarioState(db.Conn, sID, false, nil, true)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Matchup not found"})
+			return err
 		}
 
 		if playoffSeriesID != nil {
@@ -709,7 +793,7 @@ func deletePlayoffPick(db *database.DB) fiber.Handler {
 			var id int
 			err = db.Conn.QueryRow(query, *playoffSeriesID).Scan(&id)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": "Failed to delete playoff series pick"})
+				return apperror.New(apperror.Internal, "Failed to delete playoff series pick").WithCause(err)
 			}
 		} else {
 			// Set pick fields to NULL instead of deleting the row
@@ -726,23 +810,22 @@ func deletePlayoffPick(db *database.DB) fiber.Handler {
 			var id int
 			err = db.Conn.QueryRow(query, mID).Scan(&id)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": "Failed to delete playoff pick"})
+				return apperror.New(apperror.Internal, "Failed to delete playoff pick").WithCause(err)
 			}
 		}
 
 		// Update scenario's updated_at timestamp
-		scenarioIDInt, err := strconv.Atoi(scenarioID)
-		if err == nil {
-			_, updateErr := db.Conn.Exec(`
-                UPDATE scenarios
-                SET updated_at = NOW()
-                WHERE id = $1
-            `, scenarioIDInt)
-			if updateErr != nil {
-				c.Locals("scenario_update_error", updateErr.Error())
-			}
+		_, updateErr := db.Conn.Exec(`
+			UPDATE scenarios
+			SET updated_at = NOW()
+			WHERE id = $1
+		`, sID)
+		if updateErr != nil {
+			c.Locals("scenario_update_error", updateErr.Error())
 		}
 
-		return c.JSON(fiber.Map{"message": "Playoff pick deleted successfully"})
+		logScenarioChange(db, c, sID, scenarioChange{Action: history.ActionPlayoffPickDelete, Before: before})
+
+		return c.JSON(MessageResponse{Message: "Playoff pick deleted successfully"})
 	}
 }
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+    "fmt"
+    "testing"
+
+    "gamescript/internal/database"
+    "gamescript/internal/playoffs"
+    "gamescript/internal/testdb"
+
+    "github.com/stretchr/testify/assert"
+)
+
+// Fills the rest of a season with home team wins and enables its playoffs
+func enablePlayoffsForTest(t *testing.T, client *apiClient, db *database.DB, sport string) int {
+    t.Helper()
+    sportID, seasonID := testdb.Season(t, db, sport)
+    id := client.createScenario(sportID, seasonID, sport+" Bracket", false)
+
+    var state map[string]interface{}
+    assert.Equal(t, 200, client.do("GET", fmt.Sprintf("/api/playoffs/scenarios/%d/state", id), nil, &state))
+    assert.Equal(t, false, state["can_enable"], "Playoffs shouldn't be available with games left unpicked")
+
+    var body map[string]interface{}
+    assert.Equal(t, 409, client.do("POST", fmt.Sprintf("/api/playoffs/scenarios/%d/enable", id), nil, &body))
+    assert.Equal(t, "REGULAR_SEASON_INCOMPLETE", body["code"])
+
+    var autofill map[string]interface{}
+    status := client.do("POST", fmt.Sprintf("/api/scenarios/%d/autofill", id), map[string]interface{}{"strategy": AutofillHomeTeam}, &autofill)
+    if status != 201 {
+        t.Fatalf("failed to autofill: %d %v", status, autofill)
+    }
+
+    assert.Equal(t, 200, client.do("GET", fmt.Sprintf("/api/playoffs/scenarios/%d/state", id), nil, &state))
+    assert.Equal(t, true, state["can_enable"])
+    if status := client.do("POST", fmt.Sprintf("/api/playoffs/scenarios/%d/enable", id), nil, &body); status != 200 {
+        t.Fatalf("failed to enable playoffs: %d %v", status, body)
+    }
+    return id
+}
+
+// Picks the higher seed in every matchup of a round and returns the round's matchups
+func pickHigherSeeds(t *testing.T, client *apiClient, scenarioID int, round int, pick map[string]interface{}) []map[string]interface{} {
+    t.Helper()
+    var matchups []map[string]interface{}
+    status := client.do("GET", fmt.Sprintf("/api/playoffs/scenarios/%d/rounds/%d", scenarioID, round), nil, &matchups)
+    if status != 200 {
+        t.Fatalf("failed to get round %d: %d", round, status)
+    }
+
+    for _, matchup := range matchups {
+        body := map[string]interface{}{"picked_team_id": matchup["higher_seed_team_id"]}
+        for key, value := range pick {
+            body[key] = value
+        }
+        var result map[string]interface{}
+        path := fmt.Sprintf("/api/playoffs/scenarios/%d/matchups/%d", scenarioID, int(matchup["id"].(float64)))
+        if status := client.do("PUT", path, body, &result); status != 200 {
+            t.Fatalf("failed to pick round %d matchup: %d %v", round, status, result)
+        }
+        assert.Equal(t, matchup["higher_seed_team_id"], result["picked_team_id"])
+    }
+    return matchups
+}
+
+// Plays every round of a bracket with the higher seed winning and returns the champion's team ID
+func playBracket(t *testing.T, client *apiClient, scenarioID int, roundSizes map[int]int, lastRound int, pick func(round int) map[string]interface{}) float64 {
+    t.Helper()
+    var final []map[string]interface{}
+    for round := 1; round <= lastRound; round++ {
+        matchups := pickHigherSeeds(t, client, scenarioID, round, pick(round))
+        if !assert.Len(t, matchups, roundSizes[round], "Round %d", round) {
+            t.FailNow()
+        }
+
+        var body map[string]interface{}
+        status := client.do("POST", fmt.Sprintf("/api/playoffs/scenarios/%d/generate", scenarioID), nil, &body)
+        if round < lastRound {
+            if status != 200 {
+                t.Fatalf("failed to generate round %d: %d %v", round+1, status, body)
+            }
+        } else {
+            assert.Equal(t, 409, status)
+            assert.Equal(t, "NO_MORE_ROUNDS", body["code"])
+            assert.Equal(t, "No more rounds to generate", body["detail"])
+        }
+        final = matchups
+    }
+
+    var state map[string]interface{}
+    client.do("GET", fmt.Sprintf("/api/playoffs/scenarios/%d/state", scenarioID), nil, &state)
+    playoffState, _ := state["playoff_state"].(map[string]interface{})
+    assert.Equal(t, float64(lastRound), playoffState["current_round"])
+
+    // With the higher seed winning every time, the top seeds of each conference meet in the final
+    var picked []map[string]interface{}
+    client.do("GET", fmt.Sprintf("/api/playoffs/scenarios/%d/rounds/%d", scenarioID, lastRound), nil, &picked)
+    if !assert.Len(t, picked, 1) {
+        t.FailNow()
+    }
+    assert.Equal(t, float64(1), picked[0]["higher_seed"])
+    assert.Equal(t, float64(1), picked[0]["lower_seed"])
+    assert.Equal(t, final[0]["higher_seed_team_id"], picked[0]["picked_team_id"])
+    return picked[0]["picked_team_id"].(float64)
+}
+
+func TestNFLPlayoffsToChampion(t *testing.T) {
+    app, db := setupTestApp(t)
+    guest := newAPIClient(t, app)
+    id := enablePlayoffsForTest(t, guest, db, "NFL")
+
+    roundSizes := map[int]int{
+        playoffs.RoundWildCard:               6,
+        playoffs.RoundDivisional:             4,
+        playoffs.RoundConferenceChampionship: 2,
+        playoffs.RoundSuperBowl:              1,
+    }
+    champion := playBracket(t, guest, id, roundSizes, playoffs.RoundSuperBowl, func(round int) map[string]interface{} {
+        return nil
+    })
+    assert.NotZero(t, champion)
+}
+
+func TestNBAPlayoffsToChampion(t *testing.T) {
+    app, db := setupTestApp(t)
+    guest := newAPIClient(t, app)
+    id := enablePlayoffsForTest(t, guest, db, "NBA")
+
+    roundSizes := map[int]int{
+        playoffs.RoundPlayInA:                 4,
+        playoffs.RoundPlayInB:                 2,
+        playoffs.RoundConferenceQuarterfinals: 8,
+        playoffs.RoundConferenceSemifinals:    4,
+        playoffs.RoundConferenceFinals:        2,
+        playoffs.RoundNBAFinals:               1,
+    }
+    champion := playBracket(t, guest, id, roundSizes, playoffs.RoundNBAFinals, func(round int) map[string]interface{} {
+        if round < playoffs.RoundConferenceQuarterfinals {
+            return nil
+        }
+        // Series need a result, with the winner taking four games
+        return map[string]interface{}{"predicted_higher_seed_wins": 4, "predicted_lower_seed_wins": 2}
+    })
+    assert.NotZero(t, champion)
+}
+
+func TestFailedPickUpdateKeepsBracket(t *testing.T) {
+    app, db := setupTestApp(t)
+    guest := newAPIClient(t, app)
+    id := enablePlayoffsForTest(t, guest, db, "NFL")
+
+    _, seasonID := testdb.Season(t, db, "NFL")
+    game := guest.findGame(seasonID, "upcoming")
+    pickPath := fmt.Sprintf("/api/picks/scenarios/%d/games/%d", id, int(game["id"].(float64)))
+    update := map[string]interface{}{"picked_team_id": game["away_team_id"], "expected_updated_at": "2000-01-01T00:00:00Z"}
+
+    var body map[string]interface{}
+    assert.Equal(t, 409, guest.do("PUT", pickPath, update, &body))
+    assert.Equal(t, "PICK_CONFLICT", body["code"])
+
+    assert.Equal(t, 200, guest.do("DELETE", pickPath, nil, &body))
+    assert.Equal(t, 404, guest.do("PUT", pickPath, map[string]interface{}{"picked_team_id": game["away_team_id"]}, &body))
+    assert.Equal(t, "PICK_NOT_FOUND", body["code"])
+
+    var state map[string]interface{}
+    assert.Equal(t, 200, guest.do("GET", fmt.Sprintf("/api/playoffs/scenarios/%d/state", id), nil, &state))
+    assert.NotNil(t, state["playoff_state"], "Rejected pick updates shouldn't reset the bracket")
+}
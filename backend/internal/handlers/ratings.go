@@ -0,0 +1,223 @@
+// Team ratings handlers
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/ratings"
+	"gamescript/internal/validation"
+)
+
+
+// A season's team ratings and game win probabilities; updated_at is null until ratings are computed
+type SeasonRatingsResponse struct {
+	SeasonID	int							`json:"season_id"`
+	UpdatedAt	*time.Time					`json:"updated_at"`
+	Teams		[]TeamRatingResponse		`json:"teams"`
+	Games		[]GameProbabilityResponse	`json:"games"`
+}
+
+type TeamRatingResponse struct {
+	Rank			int		`json:"rank"`
+	TeamID			int		`json:"team_id"`
+	Abbreviation	string	`json:"abbreviation"`
+	City			string	`json:"city"`
+	Name			string	`json:"name"`
+	Conference		*string	`json:"conference"`
+	Division		*string	`json:"division"`
+	Rating			float64	`json:"rating"`
+	PreseasonRating	float64	`json:"preseason_rating"`
+	GamesPlayed		int		`json:"games_played"`
+}
+
+type GameProbabilityResponse struct {
+	GameID				int			`json:"game_id"`
+	Week				*int		`json:"week"`
+	StartTime			time.Time	`json:"start_time"`
+	Status				*string		`json:"status"`
+	HomeTeamID			int			`json:"home_team_id"`
+	HomeTeamAbbr		string		`json:"home_team_abbr"`
+	AwayTeamID			int			`json:"away_team_id"`
+	AwayTeamAbbr		string		`json:"away_team_abbr"`
+	HomeScore			*int		`json:"home_score"`
+	AwayScore			*int		`json:"away_score"`
+	HomeRating			float64		`json:"home_rating"`
+	AwayRating			float64		`json:"away_rating"`
+	HomeWinProbability	float64		`json:"home_win_probability"`
+	AwayWinProbability	float64		`json:"away_win_probability"`
+}
+
+func getSeasonRatings(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := validation.NewParams(c)
+		seasonID := params.ID("season_id")
+		week := params.QueryInt("week", 1, maxWeek)
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		var exists bool
+		err := db.Conn.QueryRow(`SELECT EXISTS(SELECT 1 FROM seasons WHERE id = $1)`, seasonID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return apperror.New(apperror.SeasonNotFound, "Season not found")
+		}
+
+		// Calculate ratings on first request if the scheduler hasn't stored them yet
+		var ratedTeams int
+		err = db.Conn.QueryRow(`SELECT COUNT(*) FROM team_ratings WHERE season_id = $1`, seasonID).Scan(&ratedTeams)
+		if err != nil {
+			return err
+		}
+		if ratedTeams == 0 {
+			if err := ratings.RefreshSeason(db, seasonID); err != nil {
+				return err
+			}
+		}
+
+		teams, updatedAt, err := getTeamRatings(db, seasonID)
+		if err != nil {
+			return err
+		}
+
+		games, err := getGameWinProbabilities(db, seasonID, week)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(SeasonRatingsResponse{
+			SeasonID:  seasonID,
+			UpdatedAt: updatedAt,
+			Teams:     teams,
+			Games:     games,
+		})
+	}
+}
+
+// Gets stored team ratings for a season, best rating first
+func getTeamRatings(db *database.DB, seasonID int) ([]TeamRatingResponse, *time.Time, error) {
+	rows, err := db.Query(`
+		SELECT
+			rating.team_id, team.abbreviation, team.city, team.name, team.conference, team.division,
+			rating.rating, rating.preseason_rating, rating.games_played, rating.updated_at
+		FROM team_ratings rating
+		JOIN teams team ON rating.team_id = team.id
+		WHERE rating.season_id = $1
+		ORDER BY rating.rating DESC, team.abbreviation
+	`, seasonID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	teams := []TeamRatingResponse{}
+	var latestUpdate *time.Time
+	for rows.Next() {
+		var teamID, gamesPlayed int
+		var abbreviation, city, name string
+		var conference, division *string
+		var rating, preseasonRating float64
+		var updatedAt time.Time
+
+		err := rows.Scan(
+			&teamID, &abbreviation, &city, &name, &conference, &division,
+			&rating, &preseasonRating, &gamesPlayed, &updatedAt,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if latestUpdate == nil || updatedAt.After(*latestUpdate) {
+			latestUpdate = &updatedAt
+		}
+
+		teams = append(teams, TeamRatingResponse{
+			Rank:            len(teams) + 1,
+			TeamID:          teamID,
+			Abbreviation:    abbreviation,
+			City:            city,
+			Name:            name,
+			Conference:      conference,
+			Division:        division,
+			Rating:          rating,
+			PreseasonRating: preseasonRating,
+			GamesPlayed:     gamesPlayed,
+		})
+	}
+
+	return teams, latestUpdate, rows.Err()
+}
+
+// Gets stored pre-game ratings and win probabilities for a season's games
+func getGameWinProbabilities(db *database.DB, seasonID int, week *int) ([]GameProbabilityResponse, error) {
+	query := `
+		SELECT
+			game.id, game.week, game.start_time, game.status,
+			game.home_team_id, ht.abbreviation, game.away_team_id, at.abbreviation,
+			game.home_score, game.away_score,
+			probability.home_rating, probability.away_rating, probability.home_win_probability
+		FROM game_win_probabilities probability
+		JOIN games game ON probability.game_id = game.id
+		JOIN teams ht ON game.home_team_id = ht.id
+		JOIN teams at ON game.away_team_id = at.id
+		WHERE probability.season_id = $1
+	`
+	args := []interface{}{seasonID}
+	if week != nil {
+		query += ` AND game.week = $2`
+		args = append(args, *week)
+	}
+	query += ` ORDER BY game.start_time, game.id`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	games := []GameProbabilityResponse{}
+	for rows.Next() {
+		var gameID, homeTeamID, awayTeamID int
+		var gameWeek, homeScore, awayScore *int
+		var startTime time.Time
+		var status *string
+		var homeAbbr, awayAbbr string
+		var homeRating, awayRating, homeWinProbability float64
+
+		err := rows.Scan(
+			&gameID, &gameWeek, &startTime, &status,
+			&homeTeamID, &homeAbbr, &awayTeamID, &awayAbbr,
+			&homeScore, &awayScore,
+			&homeRating, &awayRating, &homeWinProbability,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		games = append(games, GameProbabilityResponse{
+			GameID:             gameID,
+			Week:               gameWeek,
+			StartTime:          startTime,
+			Status:             status,
+			HomeTeamID:         homeTeamID,
+			HomeTeamAbbr:       homeAbbr,
+			AwayTeamID:         awayTeamID,
+			AwayTeamAbbr:       awayAbbr,
+			HomeScore:          homeScore,
+			AwayScore:          awayScore,
+			HomeRating:         homeRating,
+			AwayRating:         awayRating,
+			HomeWinProbability: homeWinProbability,
+			AwayWinProbability: 1 - homeWinProbability,
+		})
+	}
+
+	return games, rows.Err()
+}
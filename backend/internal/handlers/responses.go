@@ -0,0 +1,26 @@
+// Response bodies shared across handlers
+
+package handlers
+
+// Confirms a change that has nothing else to return, such as a delete
+type MessageResponse struct {
+	Message	string	`json:"message"`
+}
+
+type HealthResponse struct {
+	Status	string	`json:"status"`
+}
+
+// Reply to admin routes that start background work
+type TriggerResponse struct {
+	Status	string	`json:"status"`
+	Message	string	`json:"message"`
+}
+
+// The team fields included with a game, pick, or playoff matchup
+type TeamSummary struct {
+	ID					int		`json:"id"`
+	Abbreviation		string	`json:"abbreviation"`
+	City				string	`json:"city"`
+	Name				string	`json:"name"`
+}
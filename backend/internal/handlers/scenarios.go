@@ -5,14 +5,77 @@ package handlers
 import (
 	"time"
 	"crypto/rand"
+	"database/sql"
 	"encoding/hex"
+	"fmt"
 
 	"github.com/gofiber/fiber/v2"
 
+	"gamescript/internal/apperror"
 	"gamescript/internal/database"
+	"gamescript/internal/models"
+	"gamescript/internal/standings"
+	"gamescript/internal/validation"
 )
 
 
+// A scenario with its sport and season, and the caller's role in it
+type ScenarioResponse struct {
+	ID				int			`json:"id"`
+	Name			string		`json:"name"`
+	SportID			int			`json:"sport_id"`
+	SeasonID		int			`json:"season_id"`
+	SportShortName	string		`json:"sport_short_name"`
+	SeasonStartYear	int			`json:"season_start_year"`
+	SeasonEndYear	*int		`json:"season_end_year"`
+	IsPublic		bool		`json:"is_public"`
+	Mode			string		`json:"mode"`
+	ResultMode		string		`json:"result_mode"`
+	Role			string		`json:"role"`
+	ForkedFromID	*int		`json:"forked_from_id,omitempty"`
+	CreatedAt		time.Time	`json:"created_at"`
+	UpdatedAt		time.Time	`json:"updated_at"`
+}
+
+type CreateScenarioRequest struct {
+	Name       string `json:"name"`
+	SportID    int    `json:"sport_id"`
+	SeasonID   int    `json:"season_id"`
+	IsPublic   bool   `json:"is_public"`
+	Mode       string `json:"mode"`
+	ResultMode string `json:"result_mode"`
+}
+
+type UpdateScenarioRequest struct {
+	Name       *string `json:"name"`
+	IsPublic   *bool   `json:"is_public"`
+	Mode       *string `json:"mode"`
+	ResultMode *string `json:"result_mode"`
+}
+
+// Every field is optional; the copy is named after the source scenario unless one is given
+type ForkScenarioRequest struct {
+	Name       *string `json:"name"`
+	IsPublic   *bool   `json:"is_public"`
+	Mode       *string `json:"mode"`
+	ResultMode *string `json:"result_mode"`
+}
+
+type ClaimScenarioResponse struct {
+	Message	string	`json:"message"`
+	ID		int		`json:"id"`
+}
+
+// Fills in the sport and season fields of a scenario response from its IDs
+func loadScenarioSeason(db *database.DB, scenario *ScenarioResponse) error {
+	return db.Conn.QueryRow(`
+		SELECT sport.short_name, season.start_year, season.end_year
+		FROM sports sport
+		JOIN seasons season ON sport.id = season.sport_id
+		WHERE sport.id = $1 AND season.id = $2
+	`, scenario.SportID, scenario.SeasonID).Scan(&scenario.SportShortName, &scenario.SeasonStartYear, &scenario.SeasonEndYear)
+}
+
 func getScenarios(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Determine authentication status
@@ -31,7 +94,7 @@ func getScenarios(db *database.DB) fiber.Handler {
 			sessionToken = val
 		}
 
-		var scenarios []map[string]interface{}
+		scenarios := []ScenarioResponse{}
 		var query string
 		var args []interface{}
 
@@ -39,14 +102,16 @@ func getScenarios(db *database.DB) fiber.Handler {
 		if isAuthenticated && userID > 0 {
 			query = `
 				SELECT
-					scenario.id, scenario.name, scenario.sport_id, scenario.season_id, scenario.is_public, scenario.created_at, scenario.updated_at,
-					sport.short_name as sport_short_name, season.start_year AS season_start_year, season.end_year AS season_end_year
+					scenario.id, scenario.name, scenario.sport_id, scenario.season_id, scenario.is_public, scenario.mode, scenario.result_mode, scenario.created_at, scenario.updated_at,
+					sport.short_name as sport_short_name, season.start_year AS season_start_year, season.end_year AS season_end_year,
+					CASE WHEN scenario.user_id = $1 THEN 'owner' ELSE member.role END AS role
 				FROM
 					scenarios scenario
 					JOIN sports sport ON scenario.sport_id = sport.id
 					JOIN seasons season ON scenario.season_id = season.id
+					LEFT JOIN scenario_members member ON member.scenario_id = scenario.id AND member.user_id = $1
 				WHERE
-					scenario.user_id = $1
+					scenario.user_id = $1 OR member.id IS NOT NULL
 				ORDER BY
 					scenario.created_at DESC
 				`
@@ -54,87 +119,56 @@ func getScenarios(db *database.DB) fiber.Handler {
 		} else if sessionToken != "" {
 			query = `
 				SELECT
-					scenario.id, scenario.name, scenario.sport_id, scenario.season_id, scenario.is_public, scenario.created_at, scenario.updated_at,
-					sport.short_name as sport_short_name, season.start_year AS season_start_year, season.end_year AS season_end_year
+					scenario.id, scenario.name, scenario.sport_id, scenario.season_id, scenario.is_public, scenario.mode, scenario.result_mode, scenario.created_at, scenario.updated_at,
+					sport.short_name as sport_short_name, season.start_year AS season_start_year, season.end_year AS season_end_year,
+					CASE WHEN scenario.session_token = $1 THEN 'owner' ELSE member.role END AS role
 				FROM
 					scenarios scenario
 					JOIN sports sport ON scenario.sport_id = sport.id
 					JOIN seasons season ON scenario.season_id = season.id
+					LEFT JOIN scenario_members member ON member.scenario_id = scenario.id AND member.session_token = $1
 				WHERE
-					scenario.session_token = $1
+					scenario.session_token = $1 OR member.id IS NOT NULL
 				ORDER BY
 					scenario.updated_at DESC
 			`
 			args = []interface{}{sessionToken}
 		} else {
-			return c.JSON([]map[string]interface{}{})
+			return c.JSON(scenarios)
 		}
 
 		rows, err := db.Query(query, args...)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
 		defer rows.Close()
 
 		for rows.Next() {
-			var scenario map[string]interface{}
-			var id, sportID, seasonID, startYear int
-			var endYear *int
-			var name, sportShortName string
-			var isPublic bool
-			var createdAt, updatedAt time.Time
-
-			err := rows.Scan(&id, &name, &sportID, &seasonID, &isPublic, &createdAt, &updatedAt, &sportShortName, &startYear, &endYear)
+			var scenario ScenarioResponse
+			err := rows.Scan(
+				&scenario.ID, &scenario.Name, &scenario.SportID, &scenario.SeasonID, &scenario.IsPublic, &scenario.Mode, &scenario.ResultMode, &scenario.CreatedAt, &scenario.UpdatedAt,
+				&scenario.SportShortName, &scenario.SeasonStartYear, &scenario.SeasonEndYear, &scenario.Role,
+			)
 			if err != nil {
 				continue
 			}
-
-			scenario = map[string]interface{}{
-				"id": id,
-				"name": name,
-				"sport_id": sportID,
-				"season_id": seasonID,
-				"season_start_year": startYear,
-				"season_end_year": endYear,
-				"is_public": isPublic,
-				"created_at": createdAt,
-				"updated_at": updatedAt,
-				"sport_short_name": sportShortName,
-			}
 			scenarios = append(scenarios, scenario)
 		}
 
-		if scenarios == nil {
-			scenarios = []map[string]interface{}{}
-		}
-
 		return c.JSON(scenarios)
 	}
 }
 
 func getScenario(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-
-		// Determine authentication status
-		isAuthenticated := false
-		if val, ok := c.Locals("is_authenticated").(bool); ok {
-			isAuthenticated = val
-		}
-
-		// Get user ID or session token
-		userID := 0
-		if val, ok := c.Locals("user_id").(int); ok {
-			userID = val
-		}
-		sessionToken := ""
-		if val, ok := c.Locals("session_token").(string); ok {
-			sessionToken = val
+		access, err := authorizeScenarioParam(db, ScenarioRoleViewer, c)
+		if err != nil {
+			return err
 		}
 
 		query := `
 			SELECT
-				scenario.id, scenario.user_id, scenario.session_token, scenario.name, scenario.sport_id, scenario.season_id, scenario.is_public, scenario.created_at, scenario.updated_at,
+				scenario.created_at, scenario.updated_at,
 				sport.short_name as sport_short_name, season.start_year AS season_start_year, season.end_year AS season_end_year
 			FROM
 				scenarios scenario
@@ -144,67 +178,78 @@ func getScenario(db *database.DB) fiber.Handler {
 				scenario.id = $1
 		`
 
-		var id, sportID, seasonID, startYear int
-		var ownerUserID, endYear *int
-		var ownerSessionToken *string
-		var name, sportShortName string
-		var isPublic bool
-		var createdAt, updatedAt time.Time
-
-		err := db.Conn.QueryRow(query, scenarioID).Scan(
-			&id, &ownerUserID, &ownerSessionToken, &name, &sportID, &seasonID, &isPublic, &createdAt, &updatedAt,
-			&sportShortName, &startYear, &endYear,
+		scenario := ScenarioResponse{
+			ID:         access.ID,
+			Name:       access.Name,
+			SportID:    access.SportID,
+			SeasonID:   access.SeasonID,
+			IsPublic:   access.IsPublic,
+			Mode:       access.Mode,
+			ResultMode: access.ResultMode,
+			Role:       access.Role,
+		}
+		err = db.Conn.QueryRow(query, access.ID).Scan(
+			&scenario.CreatedAt, &scenario.UpdatedAt, &scenario.SportShortName, &scenario.SeasonStartYear, &scenario.SeasonEndYear,
 		)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.ScenarioNotFound, "Scenario not found")
+		}
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Scenario not found"})
+			return err
 		}
 
-		// Verify ownership
-		if isAuthenticated && userID > 0 {
-			if ownerUserID == nil || *ownerUserID != userID {
-				return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
-			}
-		} else if sessionToken != "" {
-			currentSessionToken := c.Locals("session_token").(string)
-			if ownerSessionToken == nil || *ownerSessionToken != currentSessionToken {
-				return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
-			}
-		} else {
-			return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
-		}
-
-		return c.JSON(map[string]interface{}{
-			"id": id,
-			"name": name,
-			"sport_id": sportID,
-			"season_id": seasonID,
-			"season_start_year": startYear,
-			"season_end_year": endYear,
-			"is_public": isPublic,
-			"created_at": createdAt,
-			"updated_at": updatedAt,
-			"sport_short_name": sportShortName,
-		})
+		return c.JSON(scenario)
 	}
 }
 
 func createScenario(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		type CreateScenarioRequest struct {
-			Name     string `json:"name"`
-			SportID  int    `json:"sport_id"`
-			SeasonID int    `json:"season_id"`
-			IsPublic bool   `json:"is_public"`
-		}
-
 		var req CreateScenarioRequest
 		if err := c.BodyParser(&req); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
 		}
 
 		// Validate required fields
-		if req.Name == "" || req.SportID == 0 || req.SeasonID == 0 {
-			return c.Status(400).JSON(fiber.Map{"error": "Missing required fields"})
+		var required validation.Errors
+		if req.Name == "" {
+			required.Add(validation.InBody, "name", "name is required")
+		}
+		if req.SportID <= 0 {
+			required.Add(validation.InBody, "sport_id", "sport_id is required")
+		}
+		if req.SeasonID <= 0 {
+			required.Add(validation.InBody, "season_id", "season_id is required")
+		}
+		if err := required.ErrWithDetail("Missing required fields"); err != nil {
+			return err
+		}
+
+		var errs validation.Errors
+		if req.Mode == "" {
+			req.Mode = ScenarioModeWhatIf
+		}
+		if !isValidScenarioMode(req.Mode) {
+			errs.Add(validation.InBody, "mode", "Invalid mode. Must be what_if or prediction")
+		}
+		if req.ResultMode == "" {
+			req.ResultMode = standings.ResultModeAlternateHistory
+		}
+		if !standings.IsValidResultMode(req.ResultMode) {
+			errs.Add(validation.InBody, "result_mode", "Invalid result_mode. Must be alternate_history or honor_real")
+		}
+
+		// The season must be one of the sport's
+		var seasonSportID int
+		err := db.Conn.QueryRow(`SELECT sport_id FROM seasons WHERE id = $1`, req.SeasonID).Scan(&seasonSportID)
+		if err == sql.ErrNoRows {
+			errs.Add(validation.InBody, "season_id", "Season not found")
+		} else if err != nil {
+			return err
+		} else if seasonSportID != req.SportID {
+			errs.Add(validation.InBody, "season_id", "Season is not for the given sport")
+		}
+		if err := errs.Err(); err != nil {
+			return err
 		}
 
 		// Determine authentication status
@@ -220,85 +265,48 @@ func createScenario(db *database.DB) fiber.Handler {
 		if isAuthenticated {
 			userID := c.Locals("user_id").(int)
 			query = `
-				INSERT INTO scenarios (user_id, name, sport_id, season_id, is_public)
-				VALUES ($1, $2, $3, $4, $5)
-				RETURNING id, user_id, name, sport_id, season_id, is_public, created_at, updated_at
+				INSERT INTO scenarios (user_id, name, sport_id, season_id, is_public, mode, result_mode)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id, user_id, name, sport_id, season_id, is_public, mode, result_mode, created_at, updated_at
 			`
-			args = []interface{}{userID, req.Name, req.SportID, req.SeasonID, req.IsPublic}
+			args = []interface{}{userID, req.Name, req.SportID, req.SeasonID, req.IsPublic, req.Mode, req.ResultMode}
 		} else {
 			// Generate session token for guest
-			sessionToken := c.Cookies("session_token")
-			if sessionToken == "" {
-				sessionToken = generateSessionToken()
-				c.Cookie(&fiber.Cookie{
-					Name:     "session_token",
-					Value:    sessionToken,
-					MaxAge:  7 * 24 * 60 * 60, // 7 days
-					HTTPOnly: true,
-					SameSite: "Lax",
-				})
-			}
-			c.Locals("session_token", sessionToken)
+			sessionToken := getOrCreateSessionToken(c)
 
 			query = `
-				INSERT INTO scenarios (session_token, name, sport_id, season_id, is_public)
-				VALUES ($1, $2, $3, $4, $5)
-				RETURNING id, session_token, name, sport_id, season_id, is_public, created_at, updated_at
+				INSERT INTO scenarios (session_token, name, sport_id, season_id, is_public, mode, result_mode)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id, session_token, name, sport_id, season_id, is_public, mode, result_mode, created_at, updated_at
 			`
-			args = []interface{}{sessionToken, req.Name, req.SportID, req.SeasonID, req.IsPublic}
+			args = []interface{}{sessionToken, req.Name, req.SportID, req.SeasonID, req.IsPublic, req.Mode, req.ResultMode}
 		}
 
-		var id, sportID, seasonID int
 		var userID *int
 		var sessionToken *string
-		var name string
-		var isPublic bool
-		var createdAt, updatedAt time.Time
+		scenario := ScenarioResponse{Role: ScenarioRoleOwner}
 
 		if isAuthenticated{
 			err := db.Conn.QueryRow(query, args...).Scan(
-				&id, &userID, &name, &sportID, &seasonID, &isPublic, &createdAt, &updatedAt,
+				&scenario.ID, &userID, &scenario.Name, &scenario.SportID, &scenario.SeasonID, &scenario.IsPublic, &scenario.Mode, &scenario.ResultMode, &scenario.CreatedAt, &scenario.UpdatedAt,
 			)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
 		} else {
 			err := db.Conn.QueryRow(query, args...).Scan(
-				&id, &sessionToken, &name, &sportID, &seasonID, &isPublic, &createdAt, &updatedAt,
+				&scenario.ID, &sessionToken, &scenario.Name, &scenario.SportID, &scenario.SeasonID, &scenario.IsPublic, &scenario.Mode, &scenario.ResultMode, &scenario.CreatedAt, &scenario.UpdatedAt,
 			)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
 		}
 
-		var sportShortName string
-		var startYear int
-		var endYear *int
-
-		seasonQuery := `
-			SELECT sport.short_name, season.start_year, season.end_year
-			FROM sports sport
-			JOIN seasons season ON sport.id = season.sport_id
-			WHERE sport.id = $1 AND season.id = $2
-		`
+		if err := loadScenarioSeason(db, &scenario); err != nil {
+			return apperror.New(apperror.Internal, "Failed to retrieve sport information").WithCause(err)
+		}
 
-		err := db.Conn.QueryRow(seasonQuery, sportID, seasonID).Scan(&sportShortName, &startYear, &endYear)
-		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to retrieve sport information"})
-		}
-
-		return c.Status(201).JSON(map[string]interface{}{
-			"id": id,
-			"name": name,
-			"sport_id": sportID,
-			"season_id": seasonID,
-			"sport_short_name": sportShortName,
-			"season_start_year": startYear,
-			"season_end_year": endYear,
-			"is_public": isPublic,
-			"created_at": createdAt,
-			"updated_at": updatedAt,
-		})
+		return c.Status(201).JSON(scenario)
 	}
 }
 
@@ -308,44 +316,83 @@ func generateSessionToken() string {
 	return hex.EncodeToString(b)
 }
 
+// Returns the guest session token from the request cookie, issuing a new one if needed
+func getOrCreateSessionToken(c *fiber.Ctx) string {
+	sessionToken := c.Cookies("session_token")
+	if sessionToken == "" {
+		sessionToken = generateSessionToken()
+		c.Cookie(&fiber.Cookie{
+			Name:     "session_token",
+			Value:    sessionToken,
+			MaxAge:  7 * 24 * 60 * 60, // 7 days
+			HTTPOnly: true,
+			SameSite: "Lax",
+		})
+	}
+	c.Locals("session_token", sessionToken)
+	return sessionToken
+}
+
+// Returns the owner for a scenario created by this request: the current user, or the guest session
+func getNewScenarioOwner(c *fiber.Ctx) (*int, *string) {
+	isAuthenticated := false
+	if val, ok := c.Locals("is_authenticated").(bool); ok {
+		isAuthenticated = val
+	}
+	currentUserID, _ := c.Locals("user_id").(int)
+
+	if isAuthenticated && currentUserID > 0 {
+		return &currentUserID, nil
+	}
+
+	sessionToken := getOrCreateSessionToken(c)
+	return nil, &sessionToken
+}
+
 func updateScenario(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
-
-		type UpdateScenarioRequest struct {
-			Name     *string `json:"name"`
-			IsPublic *bool   `json:"is_public"`
+		scenarioID, err := validation.PathID(c, "scenario_id")
+		if err != nil {
+			return err
 		}
 
 		var req UpdateScenarioRequest
 		if err := c.BodyParser(&req); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+		}
+		var errs validation.Errors
+		if req.Name != nil && *req.Name == "" {
+			errs.Add(validation.InBody, "name", "name cannot be empty")
+		}
+		if req.Mode != nil && !isValidScenarioMode(*req.Mode) {
+			errs.Add(validation.InBody, "mode", "Invalid mode. Must be what_if or prediction")
+		}
+		if req.ResultMode != nil && !standings.IsValidResultMode(*req.ResultMode) {
+			errs.Add(validation.InBody, "result_mode", "Invalid result_mode. Must be alternate_history or honor_real")
+		}
+		if err := errs.Err(); err != nil {
+			return err
 		}
 
-		// Verify ownership
-		var ownerUserID *int
-		var ownerSessionToken *string
-		verifyQuery := `
-			SELECT user_id, session_token
-			FROM scenarios
-			WHERE id = $1
-		`
-		err := db.Conn.QueryRow(verifyQuery, scenarioID).Scan(&ownerUserID, &ownerSessionToken)
+		// Editors can rename a scenario, only the owner can change who sees it or how picks apply
+		required := ScenarioRoleEditor
+		if req.IsPublic != nil || req.Mode != nil || req.ResultMode != nil {
+			required = ScenarioRoleOwner
+		}
+		access, err := authorizeScenario(db, scenarioID, required, c)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Scenario not found"})
+			return err
 		}
 
-		// Check if user is authenticated
-		if isAuthenticated {
-			currentUserID := c.Locals("user_id").(int)
-			if ownerUserID == nil || *ownerUserID != currentUserID {
-				return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+		// Leaving prediction mode would unlock picks on games that have already started
+		if req.Mode != nil && *req.Mode != ScenarioModePrediction && access.Mode == ScenarioModePrediction {
+			started, err := getStartedSeasonGameIDs(db.Conn, access.SeasonID, time.Now())
+			if err != nil {
+				return err
 			}
-		} else {
-			currentSessionToken := c.Locals("session_token").(string)
-			if ownerSessionToken == nil || *ownerSessionToken != currentSessionToken {
-				return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
+			if len(started) > 0 {
+				return apperror.New(apperror.PicksLocked, "Mode can't leave prediction once games have started").
+					With(PicksLockedResponse{LockedGameIDs: started})
 			}
 		}
 
@@ -364,8 +411,18 @@ func updateScenario(db *database.DB) fiber.Handler {
 			args = append(args, *req.IsPublic)
 			argCount++
 		}
+		if req.Mode != nil {
+			updateFields = append(updateFields, "mode = $"+string(rune('0'+argCount)))
+			args = append(args, *req.Mode)
+			argCount++
+		}
+		if req.ResultMode != nil {
+			updateFields = append(updateFields, "result_mode = $"+string(rune('0'+argCount)))
+			args = append(args, *req.ResultMode)
+			argCount++
+		}
 		if len(updateFields) == 0 {
-			return c.Status(400).JSON(fiber.Map{"error": "No fields to update"})
+			return apperror.New(apperror.ValidationFailed, "No fields to update")
 		}
 		updateFields = append(updateFields, "updated_at = NOW()")
 		args = append(args, scenarioID)
@@ -374,82 +431,61 @@ func updateScenario(db *database.DB) fiber.Handler {
 		for i := 1; i < len(updateFields); i++ {
 			query += ", " + updateFields[i]
 		}
-		query += ` WHERE id = $` + string(rune('0'+argCount)) + ` RETURNING id, name, sport_id, season_id, is_public, created_at, updated_at`
-
-		var id, sportID, seasonID int
-		var name string
-		var isPublic bool
-		var createdAt, updatedAt time.Time
+		query += ` WHERE id = $` + string(rune('0'+argCount)) + ` RETURNING id, name, sport_id, season_id, is_public, mode, result_mode, created_at, updated_at`
 
-		err = db.Conn.QueryRow(query, args...).Scan(&id, &name, &sportID, &seasonID, &isPublic, &createdAt, &updatedAt)
+		scenario := ScenarioResponse{Role: access.Role}
+		err = db.Conn.QueryRow(query, args...).Scan(
+			&scenario.ID, &scenario.Name, &scenario.SportID, &scenario.SeasonID, &scenario.IsPublic, &scenario.Mode, &scenario.ResultMode, &scenario.CreatedAt, &scenario.UpdatedAt,
+		)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
 
-		return c.JSON(map[string]interface{}{
-			"id": id,
-			"name": name,
-			"sport_id": sportID,
-			"season_id": seasonID,
-			"is_public": isPublic,
-			"created_at": createdAt,
-			"updated_at": updatedAt,
-		})
+		// Result mode decides which picks count toward standings
+		if req.ResultMode != nil {
+			standings.InvalidateScenario(scenario.ID)
+		}
+
+		if err := loadScenarioSeason(db, &scenario); err != nil {
+			return apperror.New(apperror.Internal, "Failed to retrieve sport information").WithCause(err)
+		}
+
+		return c.JSON(scenario)
 	}
 }
 
 func deleteScenario(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		isAuthenticated := c.Locals("is_authenticated").(bool)
-
-		// Verify ownership
-		var ownerUserID *int
-		var ownerSessionToken *string
-		verifyQuery := `
-			SELECT user_id, session_token
-			FROM scenarios
-			WHERE id = $1
-		`
-		err := db.Conn.QueryRow(verifyQuery, scenarioID).Scan(&ownerUserID, &ownerSessionToken)
+		access, err := authorizeScenarioParam(db, ScenarioRoleOwner, c)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Scenario not found"})
-		}
-
-		// Check if user is authenticated
-		if isAuthenticated {
-			currentUserID := c.Locals("user_id").(int)
-			if ownerUserID == nil || *ownerUserID != currentUserID {
-				return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
-			}
-		} else {
-			currentSessionToken := c.Locals("session_token").(string)
-			if ownerSessionToken == nil || *ownerSessionToken != currentSessionToken {
-				return c.Status(403).JSON(fiber.Map{"error": "Unauthorized"})
-			}
+			return err
 		}
 
 		deleteQuery := `
 			DELETE FROM scenarios
 			WHERE id = $1
 		`
-		_, err = db.Conn.Exec(deleteQuery, scenarioID)
+		_, err = db.Conn.Exec(deleteQuery, access.ID)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
+		standings.InvalidateScenario(access.ID)
 
-		return c.JSON(fiber.Map{"message": "Scenario deleted successfully"})
+		return c.JSON(MessageResponse{Message: "Scenario deleted successfully"})
 	}
 }
 
 func claimScenario(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
+		scenarioID, err := validation.PathID(c, "scenario_id")
+		if err != nil {
+			return err
+		}
 		sessionToken := c.Cookies("session_token")
 		userID := c.Locals("user_id").(int)
 
 		if sessionToken == "" {
-			return c.Status(400).JSON(fiber.Map{"error": "Session token required to claim scenario"})
+			return apperror.New(apperror.SessionRequired, "Session token required to claim scenario")
 		}
 
 		// Update scenario to assign to user and clear session token
@@ -461,14 +497,287 @@ func claimScenario(db *database.DB) fiber.Handler {
 		`
 
 		var id int
-		err := db.Conn.QueryRow(updateQuery, userID, scenarioID, sessionToken).Scan(&id)
+		err = db.Conn.QueryRow(updateQuery, userID, scenarioID, sessionToken).Scan(&id)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.ScenarioNotFound, "Scenario not found or already claimed")
+		}
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Scenario not found or already claimed"})
+			return err
 		}
 
-		return c.JSON(fiber.Map{
-			"message": "Scenario claimed successfully",
-			"id":      id,
-		})
+		return c.JSON(ClaimScenarioResponse{Message: "Scenario claimed successfully", ID: id})
 	}
-}
\ No newline at end of file
+}
+func forkScenario(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sourceID, err := validation.PathID(c, "scenario_id")
+		if err != nil {
+			return err
+		}
+
+		// Body is optional, so only parse it when one was sent
+		var req ForkScenarioRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+			}
+		}
+
+		// Anyone can fork a public scenario, private scenarios only by their owner and members
+		source, err := authorizeScenario(db, sourceID, "", c)
+		if err != nil {
+			return err
+		}
+		if source.Role == "" && !source.IsPublic {
+			return apperror.New(apperror.Forbidden, "Unauthorized")
+		}
+		sourceName, sportID, seasonID, isPublic, mode := source.Name, source.SportID, source.SeasonID, source.IsPublic, source.Mode
+		resultMode := source.ResultMode
+
+		// New scenario belongs to the current user or guest session
+		newUserID, newSessionToken := getNewScenarioOwner(c)
+
+		name := sourceName + " (Copy)"
+		if req.Name != nil && *req.Name != "" {
+			name = *req.Name
+		}
+		if req.IsPublic != nil {
+			isPublic = *req.IsPublic
+		}
+		var errs validation.Errors
+		if req.Mode != nil {
+			if !isValidScenarioMode(*req.Mode) {
+				errs.Add(validation.InBody, "mode", "Invalid mode. Must be what_if or prediction")
+			}
+			mode = *req.Mode
+		}
+		if req.ResultMode != nil {
+			if !standings.IsValidResultMode(*req.ResultMode) {
+				errs.Add(validation.InBody, "result_mode", "Invalid result_mode. Must be alternate_history or honor_real")
+			}
+			resultMode = *req.ResultMode
+		}
+		if err := errs.Err(); err != nil {
+			return err
+		}
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		// A prediction fork of a what-if scenario would lock in picks made after their games started
+		if mode == ScenarioModePrediction && source.Mode != ScenarioModePrediction {
+			started, err := getStartedPickGameIDs(tx, sourceID, time.Now())
+			if err != nil {
+				return err
+			}
+			if len(started) > 0 {
+				return apperror.New(apperror.PicksLocked, "Can't fork into prediction mode with picks on games that have started").
+					With(PicksLockedResponse{LockedGameIDs: started})
+			}
+		}
+
+		var id int
+		var createdAt, updatedAt time.Time
+		err = tx.QueryRow(`
+			INSERT INTO scenarios (user_id, session_token, name, sport_id, season_id, is_public, mode, result_mode)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, created_at, updated_at
+		`, newUserID, newSessionToken, name, sportID, seasonID, isPublic, mode, resultMode).Scan(&id, &createdAt, &updatedAt)
+		if err != nil {
+			return err
+		}
+
+		if err := copyScenarioContents(tx, sourceID, id); err != nil {
+			return apperror.New(apperror.Internal, "Failed to copy scenario").WithCause(err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		scenario := ScenarioResponse{
+			ID:           id,
+			Name:         name,
+			SportID:      sportID,
+			SeasonID:     seasonID,
+			IsPublic:     isPublic,
+			Mode:         mode,
+			ResultMode:   resultMode,
+			Role:         ScenarioRoleOwner,
+			ForkedFromID: &sourceID,
+			CreatedAt:    createdAt,
+			UpdatedAt:    updatedAt,
+		}
+		if err := loadScenarioSeason(db, &scenario); err != nil {
+			return apperror.New(apperror.Internal, "Failed to retrieve sport information").WithCause(err)
+		}
+
+		return c.Status(201).JSON(scenario)
+	}
+}
+
+// Copies picks and the playoff bracket of one scenario into another, remapping playoff IDs
+func copyScenarioContents(tx *sql.Tx, sourceID int, targetID int) error {
+	// Copy regular season picks
+	_, err := tx.Exec(`
+		INSERT INTO picks (scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override)
+		SELECT $1, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override
+		FROM picks
+		WHERE scenario_id = $2
+	`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("error copying picks: %w", err)
+	}
+
+	// Copy playoff state if one exists
+	var sourceStateID, currentRound int
+	var isEnabled bool
+	err = tx.QueryRow(`
+		SELECT id, current_round, is_enabled
+		FROM playoff_states
+		WHERE scenario_id = $1
+	`, sourceID).Scan(&sourceStateID, &currentRound, &isEnabled)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading playoff state: %w", err)
+	}
+
+	var targetStateID int
+	err = tx.QueryRow(`
+		INSERT INTO playoff_states (scenario_id, current_round, is_enabled)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, targetID, currentRound, isEnabled).Scan(&targetStateID)
+	if err != nil {
+		return fmt.Errorf("error copying playoff state: %w", err)
+	}
+
+	// Read all series first since the connection can't run inserts while rows are open
+	seriesRows, err := tx.Query(`
+		SELECT
+			id, round, series_order, conference,
+			higher_seed_team_id, lower_seed_team_id, higher_seed, lower_seed,
+			picked_team_id, predicted_higher_seed_wins, predicted_lower_seed_wins,
+			best_of, status
+		FROM playoff_series
+		WHERE playoff_state_id = $1
+		ORDER BY id
+	`, sourceStateID)
+	if err != nil {
+		return fmt.Errorf("error reading playoff series: %w", err)
+	}
+
+	var series []models.PlayoffSeries
+	for seriesRows.Next() {
+		var s models.PlayoffSeries
+		var higherSeedTeamID, lowerSeedTeamID, higherSeed, lowerSeed int
+		err := seriesRows.Scan(
+			&s.ID, &s.Round, &s.SeriesOrder, &s.Conference,
+			&higherSeedTeamID, &lowerSeedTeamID, &higherSeed, &lowerSeed,
+			&s.PickedTeamID, &s.PredictedHigherSeedWins, &s.PredictedLowerSeedWins,
+			&s.BestOf, &s.Status,
+		)
+		if err != nil {
+			seriesRows.Close()
+			return fmt.Errorf("error scanning playoff series: %w", err)
+		}
+		s.HigherSeedTeamID = &higherSeedTeamID
+		s.LowerSeedTeamID = &lowerSeedTeamID
+		s.HigherSeed = &higherSeed
+		s.LowerSeed = &lowerSeed
+		series = append(series, s)
+	}
+	seriesRows.Close()
+
+	// Insert series and remember old ID -> new ID for the matchups
+	seriesIDMap := make(map[int]int)
+	for _, s := range series {
+		var newID int
+		err := tx.QueryRow(`
+			INSERT INTO playoff_series (
+				playoff_state_id, round, series_order, conference,
+				higher_seed_team_id, lower_seed_team_id, higher_seed, lower_seed,
+				picked_team_id, predicted_higher_seed_wins, predicted_lower_seed_wins,
+				best_of, status
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			RETURNING id
+		`, targetStateID, s.Round, s.SeriesOrder, s.Conference,
+			s.HigherSeedTeamID, s.LowerSeedTeamID, s.HigherSeed, s.LowerSeed,
+			s.PickedTeamID, s.PredictedHigherSeedWins, s.PredictedLowerSeedWins,
+			s.BestOf, s.Status).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("error copying playoff series: %w", err)
+		}
+		seriesIDMap[s.ID] = newID
+	}
+
+	matchupRows, err := tx.Query(`
+		SELECT
+			playoff_series_id, round, matchup_order, game_number, conference,
+			higher_seed_team_id, lower_seed_team_id, higher_seed, lower_seed,
+			picked_team_id, predicted_higher_seed_score, predicted_lower_seed_score, status
+		FROM playoff_matchups
+		WHERE playoff_state_id = $1
+		ORDER BY id
+	`, sourceStateID)
+	if err != nil {
+		return fmt.Errorf("error reading playoff matchups: %w", err)
+	}
+
+	type matchupCopy struct {
+		seriesID *int
+		matchup  models.PlayoffMatchup
+	}
+
+	var matchups []matchupCopy
+	for matchupRows.Next() {
+		var m matchupCopy
+		var higherSeedTeamID, lowerSeedTeamID, higherSeed, lowerSeed int
+		err := matchupRows.Scan(
+			&m.seriesID, &m.matchup.Round, &m.matchup.MatchupOrder, &m.matchup.GameNumber, &m.matchup.Conference,
+			&higherSeedTeamID, &lowerSeedTeamID, &higherSeed, &lowerSeed,
+			&m.matchup.PickedTeamID, &m.matchup.PredictedHigherSeedScore, &m.matchup.PredictedLowerSeedScore, &m.matchup.Status,
+		)
+		if err != nil {
+			matchupRows.Close()
+			return fmt.Errorf("error scanning playoff matchup: %w", err)
+		}
+		m.matchup.HigherSeedTeamID = &higherSeedTeamID
+		m.matchup.LowerSeedTeamID = &lowerSeedTeamID
+		m.matchup.HigherSeed = &higherSeed
+		m.matchup.LowerSeed = &lowerSeed
+		matchups = append(matchups, m)
+	}
+	matchupRows.Close()
+
+	for _, m := range matchups {
+		var newSeriesID *int
+		if m.seriesID != nil {
+			mapped, ok := seriesIDMap[*m.seriesID]
+			if !ok {
+				return fmt.Errorf("playoff matchup references unknown series %d", *m.seriesID)
+			}
+			newSeriesID = &mapped
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO playoff_matchups (
+				playoff_state_id, playoff_series_id, round, matchup_order, game_number, conference,
+				higher_seed_team_id, lower_seed_team_id, higher_seed, lower_seed,
+				picked_team_id, predicted_higher_seed_score, predicted_lower_seed_score, status
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`, targetStateID, newSeriesID, m.matchup.Round, m.matchup.MatchupOrder, m.matchup.GameNumber, m.matchup.Conference,
+			m.matchup.HigherSeedTeamID, m.matchup.LowerSeedTeamID, m.matchup.HigherSeed, m.matchup.LowerSeed,
+			m.matchup.PickedTeamID, m.matchup.PredictedHigherSeedScore, m.matchup.PredictedLowerSeedScore, m.matchup.Status)
+		if err != nil {
+			return fmt.Errorf("error copying playoff matchup: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -3,68 +3,350 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
-	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 
+	"gamescript/internal/apperror"
 	"gamescript/internal/database"
 	"gamescript/internal/standings"
+	"gamescript/internal/validation"
 )
 
 
+// NFL or NBA standings, whichever sport the scenario is for
+type StandingsResponse struct {
+	NFL	*NFLStandingsResponse
+	NBA	*NBAStandingsResponse
+}
+
+func (response StandingsResponse) MarshalJSON() ([]byte, error) {
+	if response.NBA != nil {
+		return json.Marshal(response.NBA)
+	}
+	return json.Marshal(response.NFL)
+}
+
+func (StandingsResponse) OneOf() []interface{} {
+	return []interface{}{NFLStandingsResponse{}, NBAStandingsResponse{}}
+}
+
+type NFLStandingsResponse struct {
+	AFC						NFLConferenceResponse			`json:"afc"`
+	NFC						NFLConferenceResponse			`json:"nfc"`
+	DraftOrder				[]DraftPickResponse				`json:"draft_order"`
+	CounterfactualResults	[]CounterfactualResultResponse	`json:"counterfactual_results"`
+	AsOf					*StandingsCutoffResponse		`json:"as_of,omitempty"`
+}
+
+type NFLConferenceResponse struct {
+	Divisions		map[string][]NFLStandingsTeam	`json:"divisions"`
+	PlayoffSeeds	[]NFLStandingsTeam				`json:"playoff_seeds"`
+}
+
+// A team's record and seed, both in its division and in the conference's seed list
+type NFLStandingsTeam struct {
+	Seed					int		`json:"seed"`
+	TeamID					int		`json:"team_id"`
+	TeamName				string	`json:"team_name"`
+	TeamCity				string	`json:"team_city"`
+	TeamAbbr				string	`json:"team_abbr"`
+	Wins					int		`json:"wins"`
+	Losses					int		`json:"losses"`
+	Ties					int		`json:"ties"`
+	WinPct					float64	`json:"win_pct"`
+	HomeWins				int		`json:"home_wins"`
+	HomeLosses				int		`json:"home_losses"`
+	HomeTies				int		`json:"home_ties"`
+	AwayWins				int		`json:"away_wins"`
+	AwayLosses				int		`json:"away_losses"`
+	AwayTies				int		`json:"away_ties"`
+	DivisionWins			int		`json:"division_wins"`
+	DivisionLosses			int		`json:"division_losses"`
+	DivisionTies			int		`json:"division_ties"`
+	ConferenceWins			int		`json:"conference_wins"`
+	ConferenceLosses		int		`json:"conference_losses"`
+	ConferenceTies			int		`json:"conference_ties"`
+	DivisionGamesBack		float64	`json:"division_games_back"`
+	ConferenceGamesBack		float64	`json:"conference_games_back"`
+	PointsFor				int		`json:"points_for"`
+	PointsAgainst			int		`json:"points_against"`
+	PointDiff				int		`json:"point_diff"`
+	StrengthOfSchedule		float64	`json:"strength_of_schedule"`
+	StrengthOfVictory		float64	`json:"strength_of_victory"`
+	IsDivisionWinner		bool	`json:"is_division_winner"`
+	LogoURL					string	`json:"logo_url"`
+	TeamPrimaryColor		string	`json:"team_primary_color"`
+	TeamSecondaryColor		string	`json:"team_secondary_color"`
+}
+
+type NBAStandingsResponse struct {
+	Eastern					NBAConferenceResponse			`json:"eastern"`
+	Western					NBAConferenceResponse			`json:"western"`
+	DraftOrder				[]DraftPickResponse				`json:"draft_order"`
+	CounterfactualResults	[]CounterfactualResultResponse	`json:"counterfactual_results"`
+	AsOf					*StandingsCutoffResponse		`json:"as_of,omitempty"`
+}
+
+type NBAConferenceResponse struct {
+	Divisions		map[string][]NBAStandingsTeam	`json:"divisions"`
+	PlayoffSeeds	[]NBAStandingsTeam				`json:"playoff_seeds"`
+}
+
+// NBA games can't tie, and point differential is left to the client since not every game has scores
+type NBAStandingsTeam struct {
+	Seed					int		`json:"seed"`
+	TeamID					int		`json:"team_id"`
+	TeamName				string	`json:"team_name"`
+	TeamCity				string	`json:"team_city"`
+	TeamAbbr				string	`json:"team_abbr"`
+	Wins					int		`json:"wins"`
+	Losses					int		`json:"losses"`
+	WinPct					float64	`json:"win_pct"`
+	HomeWins				int		`json:"home_wins"`
+	HomeLosses				int		`json:"home_losses"`
+	AwayWins				int		`json:"away_wins"`
+	AwayLosses				int		`json:"away_losses"`
+	DivisionWins			int		`json:"division_wins"`
+	DivisionLosses			int		`json:"division_losses"`
+	ConferenceWins			int		`json:"conference_wins"`
+	ConferenceLosses		int		`json:"conference_losses"`
+	DivisionGamesBack		float64	`json:"division_games_back"`
+	ConferenceGamesBack		float64	`json:"conference_games_back"`
+	PointsFor				int		`json:"points_for"`
+	PointsAgainst			int		`json:"points_against"`
+	GamesWithScores			int		`json:"games_with_scores"`
+	StrengthOfSchedule		float64	`json:"strength_of_schedule"`
+	StrengthOfVictory		float64	`json:"strength_of_victory"`
+	IsDivisionWinner		bool	`json:"is_division_winner"`
+	LogoURL					string	`json:"logo_url"`
+	TeamPrimaryColor		string	`json:"team_primary_color"`
+	TeamSecondaryColor		string	`json:"team_secondary_color"`
+}
+
+// Record is W-L-T for the NFL and W-L for the NBA
+type DraftPickResponse struct {
+	Pick				int		`json:"pick"`
+	TeamID				int		`json:"team_id"`
+	TeamName			string	`json:"team_name"`
+	TeamAbbr			string	`json:"team_abbr"`
+	Record				string	`json:"record"`
+	LogoURL				string	`json:"logo_url"`
+	TeamPrimaryColor	string	`json:"team_primary_color"`
+	TeamSecondaryColor	string	`json:"team_secondary_color"`
+}
+
+type CounterfactualResultResponse struct {
+	GameID				int		`json:"game_id"`
+	Week				int		`json:"week"`
+	HomeTeamID			int		`json:"home_team_id"`
+	AwayTeamID			int		`json:"away_team_id"`
+	ActualHomeScore		int		`json:"actual_home_score"`
+	ActualAwayScore		int		`json:"actual_away_score"`
+	PickedTeamID		int		`json:"picked_team_id"`
+	PredictedHomeScore	*int	`json:"predicted_home_score"`
+	PredictedAwayScore	*int	`json:"predicted_away_score"`
+}
+
+// The checkpoint standings were calculated as of; date is the last day counted
+type StandingsCutoffResponse struct {
+	Week	*int	`json:"week"`
+	Date	*string	`json:"date"`
+}
+
+type StandingsTrajectoryResponse struct {
+	Weeks	[]int						`json:"weeks"`
+	Teams	[]SeedTrajectoryResponse	`json:"teams"`
+}
+
+type SeedTrajectoryResponse struct {
+	TeamID		int						`json:"team_id"`
+	TeamAbbr	string					`json:"team_abbr"`
+	TeamCity	string					`json:"team_city"`
+	TeamName	string					`json:"team_name"`
+	Conference	string					`json:"conference"`
+	Seeds		[]WeeklySeedResponse	`json:"seeds"`
+}
+
+type WeeklySeedResponse struct {
+	Week	int	`json:"week"`
+	Seed	int	`json:"seed"`
+	Wins	int	`json:"wins"`
+	Losses	int	`json:"losses"`
+	Ties	int	`json:"ties"`
+}
+
 func getStandings(db *database.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		scenarioID := c.Params("scenario_id")
-		sID, err := strconv.Atoi(scenarioID)
+		sID, err := validation.PathID(c, "scenario_id")
 		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "Invalid scenario ID"})
+			return err
 		}
 
-		var seasonID int
-		var sportID int
-		query := `SELECT season_id, sport_id FROM scenarios WHERE id = $1`
-		err = db.Conn.QueryRow(query, sID).Scan(&seasonID, &sportID)
+		// Only visible to the scenario's owner and members
+		scenario, err := loadComparedScenario(db, sID, c)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Scenario not found"})
+			return err
 		}
+		seasonID := scenario.SeasonID
+		sportID := scenario.SportID
 
-		// Calculate standings based on sport
-		var response map[string]interface{}
-		if sportID == 1 {
-			nflStandings, err := standings.CalculateNFLStandings(db, sID, seasonID)
-			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
-			}
-			response = formatNFLStandings(nflStandings)
-		} else if sportID == 2 {
-			nbaStandings, err := standings.CalculateNBAStandings(db, sID, seasonID)
+		cutoff, err := getStandingsCutoff(c)
+		if err != nil {
+			return err
+		}
+
+		format, err := getExportFormat(c)
+		if err != nil {
+			return err
+		}
+
+		// Flat tables for spreadsheet exports
+		if format != "" {
+			tables, err := buildStandingsTables(db, sID, seasonID, sportID, cutoff)
 			if err != nil {
-				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				return err
 			}
-			response = formatNBAStandings(nbaStandings)
+			return sendTables(c, format, fmt.Sprintf("scenario-%d-standings", sID), tables)
+		}
+
+		response, err := buildStandingsResponse(db, sID, seasonID, sportID, cutoff)
+		if err != nil {
+			return err
 		}
 
 		return c.JSON(response)
 	}
 }
 
-func formatNFLStandings(standings *standings.NFLStandings) map[string]interface{} {
-	return map[string]interface{}{
-		"afc": map[string]interface{}{
-			"divisions":     formatNFLDivisionsAsSeeds(standings.AFC.Divisions, standings.AFC.PlayoffSeeds),
-			"playoff_seeds": formatNFLPlayoffSeeds(standings.AFC.PlayoffSeeds),
+func getStandingsTrajectory(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sID, err := validation.PathID(c, "scenario_id")
+		if err != nil {
+			return err
+		}
+
+		scenario, err := loadComparedScenario(db, sID, c)
+		if err != nil {
+			return err
+		}
+
+		var trajectories *standings.SeedTrajectories
+		if scenario.SportID == 1 {
+			trajectories, err = standings.CalculateNFLSeedTrajectories(db, sID, scenario.SeasonID)
+		} else if scenario.SportID == 2 {
+			trajectories, err = standings.CalculateNBASeedTrajectories(db, sID, scenario.SeasonID)
+		} else {
+			return apperror.New(apperror.UnsupportedSport, "Standings not supported for this sport")
+		}
+		if err != nil {
+			return err
+		}
+
+		weeks := trajectories.Weeks
+		if weeks == nil {
+			weeks = []int{}
+		}
+		return c.JSON(StandingsTrajectoryResponse{Weeks: weeks, Teams: formatSeedTrajectories(trajectories.Teams)})
+	}
+}
+
+// Parses the as_of_week and as_of_date checkpoint parameters into a standings cutoff
+func getStandingsCutoff(c *fiber.Ctx) (standings.Cutoff, error) {
+	var cutoff standings.Cutoff
+
+	params := validation.NewParams(c)
+	cutoff.Week = params.QueryInt("as_of_week", 1, maxWeek)
+	if date := params.QueryDate("as_of_date"); date != nil {
+		before := standings.EndOfDate(*date)
+		cutoff.Before = &before
+	}
+
+	return cutoff, params.Err()
+}
+
+// Calculates standings for a scenario as of a checkpoint and formats them based on sport
+func buildStandingsResponse(db *database.DB, scenarioID int, seasonID int, sportID int, cutoff standings.Cutoff) (StandingsResponse, error) {
+	var response StandingsResponse
+	var asOf *StandingsCutoffResponse
+	if cutoff.IsSet() {
+		asOf = formatStandingsCutoff(cutoff)
+	}
+
+	if sportID == 1 {
+		nflStandings, err := standings.CalculateNFLStandingsAsOf(db, scenarioID, seasonID, cutoff)
+		if err != nil {
+			return response, err
+		}
+		formatted := FormatNFLStandings(nflStandings)
+		formatted.AsOf = asOf
+		response.NFL = &formatted
+	} else if sportID == 2 {
+		nbaStandings, err := standings.CalculateNBAStandingsAsOf(db, scenarioID, seasonID, cutoff)
+		if err != nil {
+			return response, err
+		}
+		formatted := FormatNBAStandings(nbaStandings)
+		formatted.AsOf = asOf
+		response.NBA = &formatted
+	}
+
+	return response, nil
+}
+
+func formatStandingsCutoff(cutoff standings.Cutoff) *StandingsCutoffResponse {
+	asOf := &StandingsCutoffResponse{Week: cutoff.Week}
+	if cutoff.Before != nil {
+		date := cutoff.Before.AddDate(0, 0, -1).Format("2006-01-02")
+		asOf.Date = &date
+	}
+	return asOf
+}
+
+func formatSeedTrajectories(trajectories []standings.SeedTrajectory) []SeedTrajectoryResponse {
+	result := []SeedTrajectoryResponse{}
+	for _, trajectory := range trajectories {
+		seeds := []WeeklySeedResponse{}
+		for _, seed := range trajectory.Seeds {
+			seeds = append(seeds, WeeklySeedResponse{
+				Week:   seed.Week,
+				Seed:   seed.Seed,
+				Wins:   seed.Wins,
+				Losses: seed.Losses,
+				Ties:   seed.Ties,
+			})
+		}
+		result = append(result, SeedTrajectoryResponse{
+			TeamID:     trajectory.TeamID,
+			TeamAbbr:   trajectory.TeamAbbr,
+			TeamCity:   trajectory.TeamCity,
+			TeamName:   trajectory.TeamName,
+			Conference: trajectory.Conference,
+			Seeds:      seeds,
+		})
+	}
+	return result
+}
+
+// Formats standings the way the API returns them, which the gamescript standings command also prints as JSON
+func FormatNFLStandings(standings *standings.NFLStandings) NFLStandingsResponse {
+	return NFLStandingsResponse{
+		AFC: NFLConferenceResponse{
+			Divisions:    formatNFLDivisionsAsSeeds(standings.AFC.Divisions, standings.AFC.PlayoffSeeds),
+			PlayoffSeeds: formatNFLPlayoffSeeds(standings.AFC.PlayoffSeeds),
 		},
-		"nfc": map[string]interface{}{
-			"divisions":     formatNFLDivisionsAsSeeds(standings.NFC.Divisions, standings.NFC.PlayoffSeeds),
-			"playoff_seeds": formatNFLPlayoffSeeds(standings.NFC.PlayoffSeeds),
+		NFC: NFLConferenceResponse{
+			Divisions:    formatNFLDivisionsAsSeeds(standings.NFC.Divisions, standings.NFC.PlayoffSeeds),
+			PlayoffSeeds: formatNFLPlayoffSeeds(standings.NFC.PlayoffSeeds),
 		},
-		"draft_order": formatNFLDraftOrder(standings.DraftOrder),
+		DraftOrder:            formatNFLDraftOrder(standings.DraftOrder),
+		CounterfactualResults: formatCounterfactualResults(standings.Counterfactuals),
 	}
 }
 
-func formatNFLDivisionsAsSeeds(divisions map[string][]standings.NFLTeamRecord, allSeeds []standings.NFLPlayoffSeed) map[string]interface{} {
-	result := make(map[string]interface{})
+func formatNFLDivisionsAsSeeds(divisions map[string][]standings.NFLTeamRecord, allSeeds []standings.NFLPlayoffSeed) map[string][]NFLStandingsTeam {
+	result := make(map[string][]NFLStandingsTeam)
 
 	// Create a map of team_id to seed for quick lookup
 	seedMap := make(map[int]standings.NFLPlayoffSeed)
@@ -73,48 +355,14 @@ func formatNFLDivisionsAsSeeds(divisions map[string][]standings.NFLTeamRecord, a
 	}
 
 	for divName, teams := range divisions {
-		formattedTeams := []map[string]interface{}{}
+		formattedTeams := []NFLStandingsTeam{}
 		for _, team := range teams {
 			// Find the corresponding seed
 			seed, exists := seedMap[team.TeamID]
 			if !exists {
 				continue
 			}
-
-			formattedTeams = append(formattedTeams, map[string]interface{}{
-				"seed":                  seed.Seed,
-				"team_id":               team.TeamID,
-				"team_name":             team.TeamName,
-				"team_city":             team.TeamCity,
-				"team_abbr":             team.TeamAbbr,
-				"wins":                  team.Wins,
-				"losses":                team.Losses,
-				"ties":                  team.Ties,
-				"win_pct":               team.WinPct,
-                "home_wins":             team.HomeWins,
-				"home_losses":           team.HomeLosses,
-				"home_ties":             team.HomeTies,
-				"away_wins":             team.AwayWins,
-				"away_losses":           team.AwayLosses,
-				"away_ties":             team.AwayTies,
-                "division_wins":         team.DivisionWins,
-				"division_losses":       team.DivisionLosses,
-				"division_ties":         team.DivisionTies,
-				"conference_wins":       team.ConferenceWins,
-				"conference_losses":     team.ConferenceLosses,
-				"conference_ties":       team.ConferenceTies,
-				"division_games_back":   team.DivisionGamesBack,
-				"conference_games_back": team.ConferenceGamesBack,
-				"points_for":            team.PointsFor,
-				"points_against":        team.PointsAgainst,
-				"point_diff":            team.PointsFor - team.PointsAgainst,
-				"strength_of_schedule":  team.StrengthOfSchedule,
-				"strength_of_victory":   team.StrengthOfVictory,
-				"is_division_winner":    seed.IsDivisionWinner,
-				"logo_url":              team.LogoURL,
-				"team_primary_color":    team.TeamPrimaryColor,
-				"team_secondary_color":  team.TeamSecondaryColor,
-			})
+			formattedTeams = append(formattedTeams, formatNFLStandingsTeam(team, seed))
 		}
 		result[divName] = formattedTeams
 	}
@@ -122,84 +370,90 @@ func formatNFLDivisionsAsSeeds(divisions map[string][]standings.NFLTeamRecord, a
 	return result
 }
 
-func formatNFLPlayoffSeeds(seeds []standings.NFLPlayoffSeed) []map[string]interface{} {
-	result := []map[string]interface{}{}
+func formatNFLPlayoffSeeds(seeds []standings.NFLPlayoffSeed) []NFLStandingsTeam {
+	result := []NFLStandingsTeam{}
 
 	for _, seed := range seeds {
-		result = append(result, map[string]interface{}{
-			"seed":                  seed.Seed,
-			"team_id":               seed.Team.TeamID,
-			"team_name":             seed.Team.TeamName,
-			"team_city":             seed.Team.TeamCity,
-			"team_abbr":             seed.Team.TeamAbbr,
-			"wins":                  seed.Team.Wins,
-			"losses":                seed.Team.Losses,
-			"ties":                  seed.Team.Ties,
-			"win_pct":               seed.Team.WinPct,
-			"home_wins":             seed.Team.HomeWins,
-			"home_losses":           seed.Team.HomeLosses,
-			"home_ties":             seed.Team.HomeTies,
-			"away_wins":             seed.Team.AwayWins,
-			"away_losses":           seed.Team.AwayLosses,
-			"away_ties":             seed.Team.AwayTies,
-			"division_wins":         seed.Team.DivisionWins,
-			"division_losses":       seed.Team.DivisionLosses,
-			"division_ties":         seed.Team.DivisionTies,
-			"conference_wins":       seed.Team.ConferenceWins,
-			"conference_losses":     seed.Team.ConferenceLosses,
-			"conference_ties":       seed.Team.ConferenceTies,
-			"division_games_back":   seed.Team.DivisionGamesBack,
-			"conference_games_back": seed.Team.ConferenceGamesBack,
-			"points_for":            seed.Team.PointsFor,
-			"points_against":        seed.Team.PointsAgainst,
-			"point_diff":            seed.Team.PointsFor - seed.Team.PointsAgainst,
-			"strength_of_schedule":  seed.Team.StrengthOfSchedule,
-			"strength_of_victory":   seed.Team.StrengthOfVictory,
-            "is_division_winner":    seed.IsDivisionWinner,
-			"logo_url":              seed.Team.LogoURL,
-			"team_primary_color":    seed.Team.TeamPrimaryColor,
-			"team_secondary_color":  seed.Team.TeamSecondaryColor,
-		})
+		result = append(result, formatNFLStandingsTeam(seed.Team, seed))
 	}
 
 	return result
 }
 
-func formatNFLDraftOrder(picks []standings.NFLDraftPick) []map[string]interface{} {
-	result := []map[string]interface{}{}
+func formatNFLStandingsTeam(team standings.NFLTeamRecord, seed standings.NFLPlayoffSeed) NFLStandingsTeam {
+	return NFLStandingsTeam{
+		Seed:                seed.Seed,
+		TeamID:              team.TeamID,
+		TeamName:            team.TeamName,
+		TeamCity:            team.TeamCity,
+		TeamAbbr:            team.TeamAbbr,
+		Wins:                team.Wins,
+		Losses:              team.Losses,
+		Ties:                team.Ties,
+		WinPct:              team.WinPct,
+		HomeWins:            team.HomeWins,
+		HomeLosses:          team.HomeLosses,
+		HomeTies:            team.HomeTies,
+		AwayWins:            team.AwayWins,
+		AwayLosses:          team.AwayLosses,
+		AwayTies:            team.AwayTies,
+		DivisionWins:        team.DivisionWins,
+		DivisionLosses:      team.DivisionLosses,
+		DivisionTies:        team.DivisionTies,
+		ConferenceWins:      team.ConferenceWins,
+		ConferenceLosses:    team.ConferenceLosses,
+		ConferenceTies:      team.ConferenceTies,
+		DivisionGamesBack:   team.DivisionGamesBack,
+		ConferenceGamesBack: team.ConferenceGamesBack,
+		PointsFor:           team.PointsFor,
+		PointsAgainst:       team.PointsAgainst,
+		PointDiff:           team.PointsFor - team.PointsAgainst,
+		StrengthOfSchedule:  team.StrengthOfSchedule,
+		StrengthOfVictory:   team.StrengthOfVictory,
+		IsDivisionWinner:    seed.IsDivisionWinner,
+		LogoURL:             team.LogoURL,
+		TeamPrimaryColor:    team.TeamPrimaryColor,
+		TeamSecondaryColor:  team.TeamSecondaryColor,
+	}
+}
+
+func formatNFLDraftOrder(picks []standings.NFLDraftPick) []DraftPickResponse {
+	result := []DraftPickResponse{}
 
 	for _, pick := range picks {
-		result = append(result, map[string]interface{}{
-			"pick":                 pick.Pick,
-			"team_id":              pick.Team.TeamID,
-			"team_name":            pick.Team.TeamName,
-			"team_abbr":            pick.Team.TeamAbbr,
-			"record":               fmt.Sprintf("%d-%d-%d", pick.Team.Wins, pick.Team.Losses, pick.Team.Ties),
-			"logo_url":             pick.Team.LogoURL,
-			"team_primary_color":   pick.Team.TeamPrimaryColor,
-			"team_secondary_color": pick.Team.TeamSecondaryColor,
+		result = append(result, DraftPickResponse{
+			Pick:               pick.Pick,
+			TeamID:             pick.Team.TeamID,
+			TeamName:           pick.Team.TeamName,
+			TeamAbbr:           pick.Team.TeamAbbr,
+			Record:             fmt.Sprintf("%d-%d-%d", pick.Team.Wins, pick.Team.Losses, pick.Team.Ties),
+			LogoURL:            pick.Team.LogoURL,
+			TeamPrimaryColor:   pick.Team.TeamPrimaryColor,
+			TeamSecondaryColor: pick.Team.TeamSecondaryColor,
 		})
 	}
 
 	return result
 }
 
-func formatNBAStandings(standings *standings.NBAStandings) map[string]interface{} {
-	return map[string]interface{}{
-		"eastern": map[string]interface{}{
-			"divisions":     formatNBADivisionsAsSeeds(standings.Eastern.Divisions, standings.Eastern.PlayoffSeeds),
-			"playoff_seeds": formatNBAPlayoffSeeds(standings.Eastern.PlayoffSeeds),
+// Formats standings the way the API returns them, which the gamescript standings command also prints as JSON
+func FormatNBAStandings(standings *standings.NBAStandings) NBAStandingsResponse {
+	return NBAStandingsResponse{
+		Eastern: NBAConferenceResponse{
+			Divisions:    formatNBADivisionsAsSeeds(standings.Eastern.Divisions, standings.Eastern.PlayoffSeeds),
+			PlayoffSeeds: formatNBAPlayoffSeeds(standings.Eastern.PlayoffSeeds),
 		},
-		"western": map[string]interface{}{
-			"divisions":     formatNBADivisionsAsSeeds(standings.Western.Divisions, standings.Western.PlayoffSeeds),
-			"playoff_seeds": formatNBAPlayoffSeeds(standings.Western.PlayoffSeeds),
+		Western: NBAConferenceResponse{
+			Divisions:    formatNBADivisionsAsSeeds(standings.Western.Divisions, standings.Western.PlayoffSeeds),
+			PlayoffSeeds: formatNBAPlayoffSeeds(standings.Western.PlayoffSeeds),
 		},
-		"draft_order": formatNBADraftOrder(standings.DraftOrder),
+		DraftOrder:            formatNBADraftOrder(standings.DraftOrder),
+		CounterfactualResults: formatCounterfactualResults(standings.Counterfactuals),
 	}
 }
 
-func formatNBADivisionsAsSeeds(divisions map[string][]standings.NBATeamRecord, allSeeds []standings.NBAPlayoffSeed) map[string]interface{} {
-    result := make(map[string]interface{})
+func formatNBADivisionsAsSeeds(divisions map[string][]standings.NBATeamRecord, allSeeds []standings.NBAPlayoffSeed) map[string][]NBAStandingsTeam {
+    result := make(map[string][]NBAStandingsTeam)
 
     // Create a map of team_id to seed for quick lookup
     seedMap := make(map[int]standings.NBAPlayoffSeed)
@@ -208,43 +462,14 @@ func formatNBADivisionsAsSeeds(divisions map[string][]standings.NBATeamRecord, a
     }
 
     for divName, teams := range divisions {
-        formattedTeams := []map[string]interface{}{}
+        formattedTeams := []NBAStandingsTeam{}
         for _, team := range teams {
             // Find the corresponding seed
             seed, exists := seedMap[team.TeamID]
             if !exists {
                 continue
             }
-
-            formattedTeams = append(formattedTeams, map[string]interface{}{
-                "seed":                 seed.Seed,
-                "team_id":              team.TeamID,
-                "team_name":            team.TeamName,
-                "team_city":            team.TeamCity,
-                "team_abbr":            team.TeamAbbr,
-                "wins":                 team.Wins,
-                "losses":               team.Losses,
-                "win_pct":              team.WinPct,
-                "home_wins":            team.HomeWins,
-                "home_losses":          team.HomeLosses,
-                "away_wins":            team.AwayWins,
-                "away_losses":          team.AwayLosses,
-                "division_wins":        team.DivisionWins,
-                "division_losses":      team.DivisionLosses,
-                "conference_wins":      team.ConferenceWins,
-                "conference_losses":    team.ConferenceLosses,
-                "division_games_back":  team.DivisionGamesBack,
-                "conference_games_back": team.ConferenceGamesBack,
-                "points_for":           team.PointsFor,
-                "points_against":       team.PointsAgainst,
-                "games_with_scores":    team.GamesWithScores,
-                "strength_of_schedule": team.StrengthOfSchedule,
-                "strength_of_victory":  team.StrengthOfVictory,
-                "is_division_winner":   seed.IsDivisionWinner,
-                "logo_url":             team.LogoURL,
-                "team_primary_color":   team.TeamPrimaryColor,
-                "team_secondary_color": team.TeamSecondaryColor,
-            })
+            formattedTeams = append(formattedTeams, formatNBAStandingsTeam(team, seed))
         }
         result[divName] = formattedTeams
     }
@@ -252,59 +477,84 @@ func formatNBADivisionsAsSeeds(divisions map[string][]standings.NBATeamRecord, a
     return result
 }
 
-func formatNBAPlayoffSeeds(seeds []standings.NBAPlayoffSeed) []map[string]interface{} {
-    result := []map[string]interface{}{}
+func formatNBAPlayoffSeeds(seeds []standings.NBAPlayoffSeed) []NBAStandingsTeam {
+    result := []NBAStandingsTeam{}
 
     for _, seed := range seeds {
-        result = append(result, map[string]interface{}{
-            "seed":                 seed.Seed,
-            "team_id":              seed.Team.TeamID,
-            "team_name":            seed.Team.TeamName,
-            "team_city":            seed.Team.TeamCity,
-            "team_abbr":            seed.Team.TeamAbbr,
-            "wins":                 seed.Team.Wins,
-            "losses":               seed.Team.Losses,
-            "win_pct":              seed.Team.WinPct,
-            "home_wins":            seed.Team.HomeWins,
-            "home_losses":          seed.Team.HomeLosses,
-            "away_wins":            seed.Team.AwayWins,
-            "away_losses":          seed.Team.AwayLosses,
-            "division_wins":        seed.Team.DivisionWins,
-            "division_losses":      seed.Team.DivisionLosses,
-            "conference_wins":      seed.Team.ConferenceWins,
-            "conference_losses":    seed.Team.ConferenceLosses,
-            "division_games_back":  seed.Team.DivisionGamesBack,
-            "conference_games_back": seed.Team.ConferenceGamesBack,
-            "points_for":           seed.Team.PointsFor,
-            "points_against":       seed.Team.PointsAgainst,
-            "games_with_scores":    seed.Team.GamesWithScores,
-            "strength_of_schedule": seed.Team.StrengthOfSchedule,
-            "strength_of_victory":  seed.Team.StrengthOfVictory,
-            "is_division_winner":   seed.IsDivisionWinner,
-            "logo_url":             seed.Team.LogoURL,
-            "team_primary_color":   seed.Team.TeamPrimaryColor,
-            "team_secondary_color": seed.Team.TeamSecondaryColor,
-        })
+        result = append(result, formatNBAStandingsTeam(seed.Team, seed))
     }
 
     return result
 }
 
-func formatNBADraftOrder(picks []standings.NBADraftPick) []map[string]interface{} {
-    result := []map[string]interface{}{}
+func formatNBAStandingsTeam(team standings.NBATeamRecord, seed standings.NBAPlayoffSeed) NBAStandingsTeam {
+    return NBAStandingsTeam{
+        Seed:                seed.Seed,
+        TeamID:              team.TeamID,
+        TeamName:            team.TeamName,
+        TeamCity:            team.TeamCity,
+        TeamAbbr:            team.TeamAbbr,
+        Wins:                team.Wins,
+        Losses:              team.Losses,
+        WinPct:              team.WinPct,
+        HomeWins:            team.HomeWins,
+        HomeLosses:          team.HomeLosses,
+        AwayWins:            team.AwayWins,
+        AwayLosses:          team.AwayLosses,
+        DivisionWins:        team.DivisionWins,
+        DivisionLosses:      team.DivisionLosses,
+        ConferenceWins:      team.ConferenceWins,
+        ConferenceLosses:    team.ConferenceLosses,
+        DivisionGamesBack:   team.DivisionGamesBack,
+        ConferenceGamesBack: team.ConferenceGamesBack,
+        PointsFor:           team.PointsFor,
+        PointsAgainst:       team.PointsAgainst,
+        GamesWithScores:     team.GamesWithScores,
+        StrengthOfSchedule:  team.StrengthOfSchedule,
+        StrengthOfVictory:   team.StrengthOfVictory,
+        IsDivisionWinner:    seed.IsDivisionWinner,
+        LogoURL:             team.LogoURL,
+        TeamPrimaryColor:    team.TeamPrimaryColor,
+        TeamSecondaryColor:  team.TeamSecondaryColor,
+    }
+}
+
+func formatNBADraftOrder(picks []standings.NBADraftPick) []DraftPickResponse {
+    result := []DraftPickResponse{}
 
     for _, pick := range picks {
-        result = append(result, map[string]interface{}{
-            "pick":                 pick.Pick,
-            "team_id":              pick.Team.TeamID,
-            "team_name":            pick.Team.TeamName,
-            "team_abbr":            pick.Team.TeamAbbr,
-            "record":               fmt.Sprintf("%d-%d", pick.Team.Wins, pick.Team.Losses),
-            "logo_url":             pick.Team.LogoURL,
-            "team_primary_color":   pick.Team.TeamPrimaryColor,
-            "team_secondary_color": pick.Team.TeamSecondaryColor,
+        result = append(result, DraftPickResponse{
+            Pick:               pick.Pick,
+            TeamID:             pick.Team.TeamID,
+            TeamName:           pick.Team.TeamName,
+            TeamAbbr:           pick.Team.TeamAbbr,
+            Record:             fmt.Sprintf("%d-%d", pick.Team.Wins, pick.Team.Losses),
+            LogoURL:            pick.Team.LogoURL,
+            TeamPrimaryColor:   pick.Team.TeamPrimaryColor,
+            TeamSecondaryColor: pick.Team.TeamSecondaryColor,
         })
     }
 
     return result
-}
\ No newline at end of file
+}
+
+// Lists the final games whose real results were replaced by override picks
+func formatCounterfactualResults(results []standings.CounterfactualResult) []CounterfactualResultResponse {
+	formatted := []CounterfactualResultResponse{}
+
+	for _, result := range results {
+		formatted = append(formatted, CounterfactualResultResponse{
+			GameID:             result.GameID,
+			Week:               result.Week,
+			HomeTeamID:         result.HomeTeamID,
+			AwayTeamID:         result.AwayTeamID,
+			ActualHomeScore:    result.ActualHomeScore,
+			ActualAwayScore:    result.ActualAwayScore,
+			PickedTeamID:       result.PickedTeamID,
+			PredictedHomeScore: result.PredictedHomeScore,
+			PredictedAwayScore: result.PredictedAwayScore,
+		})
+	}
+
+	return formatted
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+    "fmt"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "gamescript/internal/apperror"
+    "gamescript/internal/scheduler"
+    "gamescript/internal/standings"
+    "gamescript/internal/testdb"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// Sets up the app with a guest scenario on the fixture NFL season, skipping when no database is available
+func setupStandingsBenchmark(b *testing.B) (*fiber.App, int) {
+    db := testdb.New(b)
+    sportID, seasonID := testdb.Season(b, db, "NFL")
+
+    var scenarioID int
+    err := db.Conn.QueryRow(`
+        INSERT INTO scenarios (session_token, name, sport_id, season_id, is_public)
+        VALUES ('standings-benchmark', 'Standings Benchmark', $1, $2, TRUE)
+        RETURNING id
+    `, sportID, seasonID).Scan(&scenarioID)
+    if err != nil {
+        b.Fatal(err)
+    }
+
+    app := fiber.New(fiber.Config{ErrorHandler: apperror.Handler})
+    SetupRoutes(app, db, scheduler.NewScheduler(db))
+
+    return app, scenarioID
+}
+
+func benchmarkGetStandings(b *testing.B, cached bool) {
+    app, scenarioID := setupStandingsBenchmark(b)
+
+    standings.SetCacheEnabled(cached)
+    b.Cleanup(func() { standings.SetCacheEnabled(true) })
+
+    url := fmt.Sprintf("/api/scenarios/%d/standings", scenarioID)
+
+    // Concurrent requests against one scenario, like viewers refreshing a shared link
+    b.ResetTimer()
+    b.RunParallel(func(pb *testing.PB) {
+        for pb.Next() {
+            req := httptest.NewRequest("GET", url, nil)
+            req.AddCookie(&http.Cookie{Name: "session_token", Value: "standings-benchmark"})
+            resp, err := app.Test(req, -1)
+            if err != nil {
+                b.Error(err)
+                return
+            }
+            io.Copy(io.Discard, resp.Body)
+            resp.Body.Close()
+            if resp.StatusCode != 200 {
+                b.Errorf("GET %s returned %d", url, resp.StatusCode)
+                return
+            }
+        }
+    })
+    b.StopTimer()
+
+    stats := standings.GetCacheStats()
+    b.ReportMetric(float64(stats.Hits), "cache-hits")
+    b.ReportMetric(float64(stats.Misses), "cache-misses")
+}
+
+func BenchmarkGetStandingsUncached(b *testing.B) {
+    benchmarkGetStandings(b, false)
+}
+
+func BenchmarkGetStandingsCached(b *testing.B) {
+    benchmarkGetStandings(b, true)
+}
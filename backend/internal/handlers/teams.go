@@ -3,112 +3,84 @@
 package handlers
 
 import (
+
 	"github.com/gofiber/fiber/v2"
 
-	"gamescript/internal/database"
+	"gamescript/internal/apperror"
+	"gamescript/internal/models"
+	"gamescript/internal/store"
+	"gamescript/internal/validation"
 )
 
 
-func getTeamsBySeason(db *database.DB) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		seasonID := c.Params("season_id")
-
-		query := `
-			SELECT
-				team.id, team.sport_id, team.season_id, team.espn_id,
-				team.abbreviation, team.city, team.name, 
-				team.conference, team.division,
-				team.primary_color, team.secondary_color, team.logo_url, team.alternate_logo_url
-			FROM teams team
-			WHERE team.season_id = $1
-			ORDER BY team.name
-		`
+type TeamResponse struct {
+	ID					int			`json:"id"`
+	SportID				int			`json:"sport_id"`
+	SeasonID			int			`json:"season_id"`
+	ESPNID				string		`json:"espn_id"`
+	Abbreviation		string		`json:"abbreviation"`
+	City				string		`json:"city"`
+	Name				string		`json:"name"`
+	Conference			*string		`json:"conference"`
+	Division			*string		`json:"division"`
+	PrimaryColor		string		`json:"primary_color"`
+	SecondaryColor		string		`json:"secondary_color"`
+	LogoURL				*string		`json:"logo_url"`
+	AlternateLogoURL	*string		`json:"alternate_logo_url"`
+}
 
-		rows, err := db.Query(query, seasonID)
+func getTeamsBySeason(data store.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		seasonID, err := validation.PathID(c, "season_id")
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
-		defer rows.Close()
 
-		var teams []map[string]interface {}
-		for rows.Next() {
-			var id, sportID, seasonID int
-			var espnID, abbreviation, city, name, primaryColor, secondaryColor string
-			var conference, division, logoURL, alternateLogoURL *string
-
-			err := rows.Scan(
-				&id, &sportID, &seasonID, &espnID,
-				&abbreviation, &city, &name,
-				&conference, &division,
-				&primaryColor, &secondaryColor, &logoURL, &alternateLogoURL,
-			)
-			if err != nil {
-				continue
-			}
-
-			teams = append(teams, map[string]interface{}{
-				"id": id,
-				"sport_id": sportID,
-				"season_id": seasonID,
-				"espn_id": espnID,
-				"abbreviation": abbreviation,
-				"city": city,
-				"name": name,
-				"conference": conference,
-				"division": division,
-				"primary_color": primaryColor,
-				"secondary_color": secondaryColor,
-				"logo_url": logoURL,
-				"alternate_logo_url": alternateLogoURL,
-			})
+		teams, err := data.TeamsBySeason(seasonID)
+		if err != nil {
+			return err
 		}
 
-		return c.JSON(teams)
+		formatted := make([]TeamResponse, 0, len(teams))
+		for _, team := range teams {
+			formatted = append(formatted, formatTeam(team))
+		}
+		return c.JSON(formatted)
 	}
 }
 
-func getTeam(db *database.DB) fiber.Handler {
+func getTeam(data store.Store) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		teamID := c.Params("team_id")
-
-		query := `
-			SELECT
-				team.id, team.sport_id, team.season_id, team.espn_id,
-				team.abbreviation, team.city, team.name, 
-				team.conference, team.division,
-				team.primary_color, team.secondary_color, team.logo_url, team.alternate_logo_url
-			FROM teams team
-			WHERE team.id = $1
-		`
-
-		var id, sportID, seasonID int
-		var espnID, abbreviation, city, name, primaryColor, secondaryColor string
-		var conference, division, logoURL, alternateLogoURL *string
+		teamID, err := validation.PathID(c, "team_id")
+		if err != nil {
+			return err
+		}
 
-		err := db.Conn.QueryRow(query, teamID).Scan(
-			&id, &sportID, &seasonID, &espnID,
-			&abbreviation, &city, &name,
-			&conference, &division,
-			&primaryColor, &secondaryColor, &logoURL, &alternateLogoURL,
-		)
+		team, err := data.Team(teamID)
+		if err == store.ErrNotFound {
+			return apperror.New(apperror.TeamNotFound, "Team not found")
+		}
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "Team not found"})
+			return err
 		}
+		return c.JSON(formatTeam(*team))
+	}
+}
 
-		return c.JSON(map[string]interface{}{
-			"id": id,
-			"sport_id": sportID,
-			"season_id": seasonID,
-			"espn_id": espnID,
-			"abbreviation": abbreviation,
-			"city": city,
-			"name": name,
-			"conference": conference,
-			"division": division,
-			"primary_color": primaryColor,
-			"secondary_color": secondaryColor,
-			"logo_url": logoURL,
-			"alternate_logo_url": alternateLogoURL,
-		})
+func formatTeam(team models.Team) TeamResponse {
+	return TeamResponse{
+		ID:					team.ID,
+		SportID:			team.SportID,
+		SeasonID:			team.SeasonID,
+		ESPNID:				team.ESPNID,
+		Abbreviation:		team.Abbreviation,
+		City:				team.City,
+		Name:				team.Name,
+		Conference:			team.Conference,
+		Division:			team.Division,
+		PrimaryColor:		team.PrimaryColor,
+		SecondaryColor:		team.SecondaryColor,
+		LogoURL:			team.LogoURL,
+		AlternateLogoURL:	team.AlternateLogoURL,
 	}
-}
\ No newline at end of file
+}
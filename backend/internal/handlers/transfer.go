@@ -0,0 +1,703 @@
+// Scenario export and import handlers
+
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/apperror"
+	"gamescript/internal/database"
+	"gamescript/internal/models"
+	"gamescript/internal/standings"
+	"gamescript/internal/validation"
+)
+
+
+// Teams of a season indexed by database ID, ESPN ID, and abbreviation
+type seasonTeamIndex struct {
+	byID           map[int]models.DocumentTeam
+	byESPNID       map[string]int
+	byAbbreviation map[string]int
+}
+
+// The new scenario and what could not be matched from the document
+type ImportScenarioResponse struct {
+	Scenario			ScenarioResponse		`json:"scenario"`
+	ImportedPicks		int						`json:"imported_picks"`
+	UnmatchedGames		[]UnmatchedGame			`json:"unmatched_games"`
+	PlayoffsImported	bool					`json:"playoffs_imported"`
+	UnmatchedTeams		[]models.DocumentTeam	`json:"unmatched_teams"`
+}
+
+// Sent instead of importing when strict is set and anything fails to match
+type ImportMismatchResponse struct {
+	apperror.Problem
+	UnmatchedGames	[]UnmatchedGame			`json:"unmatched_games"`
+	UnmatchedTeams	[]models.DocumentTeam	`json:"unmatched_teams"`
+}
+
+// A document pick whose game isn't in the target season, with the reason
+type UnmatchedGame struct {
+	ESPNID		string	`json:"espn_id"`
+	Week		*int	`json:"week"`
+	HomeTeam	string	`json:"home_team"`
+	AwayTeam	string	`json:"away_team"`
+	Reason		string	`json:"reason"`
+}
+
+// Game of a season matched by ESPN ID during import
+type importGame struct {
+	ID         int
+	HomeTeamID int
+	AwayTeamID int
+	IsFinal    bool
+}
+
+func exportScenario(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		access, err := authorizeScenarioParam(db, ScenarioRoleViewer, c)
+		if err != nil {
+			return err
+		}
+		scenarioID := access.ID
+		name, seasonID, isPublic, mode, resultMode := access.Name, access.SeasonID, access.IsPublic, access.Mode, access.ResultMode
+
+		var sportShortName string
+		var startYear int
+		var endYear *int
+		err = db.Conn.QueryRow(`
+			SELECT sport.short_name, season.start_year, season.end_year
+			FROM scenarios scenario
+			JOIN sports sport ON scenario.sport_id = sport.id
+			JOIN seasons season ON scenario.season_id = season.id
+			WHERE scenario.id = $1
+		`, scenarioID).Scan(&sportShortName, &startYear, &endYear)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.ScenarioNotFound, "Scenario not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		teams, err := getSeasonTeamIndex(db.Conn, seasonID)
+		if err != nil {
+			return err
+		}
+
+		picks, err := exportPicks(db, scenarioID, teams)
+		if err != nil {
+			return err
+		}
+
+		playoffs, err := exportPlayoffs(db.Conn, scenarioID, teams)
+		if err != nil {
+			return err
+		}
+
+		document := models.ScenarioDocument{
+			Format:     models.ScenarioDocumentFormat,
+			Version:    models.ScenarioDocumentVersion,
+			ExportedAt: time.Now().UTC(),
+			Sport:      sportShortName,
+			Season:     models.DocumentSeason{StartYear: startYear, EndYear: endYear},
+			Scenario:   models.DocumentScenario{Name: name, IsPublic: isPublic, Mode: mode, ResultMode: resultMode},
+			Picks:      picks,
+			Playoffs:   playoffs,
+		}
+
+		c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="scenario-%d.json"`, scenarioID))
+		return c.JSON(document)
+	}
+}
+
+func importScenario(db *database.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var document models.ScenarioDocument
+		if err := c.BodyParser(&document); err != nil {
+			return apperror.New(apperror.InvalidRequestBody, "Invalid request body")
+		}
+
+		if document.Format != models.ScenarioDocumentFormat {
+			return apperror.New(apperror.UnsupportedDocument, "Unrecognized document format")
+		}
+		if document.Version < 1 || document.Version > models.ScenarioDocumentVersion {
+			return apperror.Newf(apperror.UnsupportedDocument, "Unsupported document version %d", document.Version)
+		}
+
+		var errs validation.Errors
+		if document.Scenario.Name == "" {
+			errs.Add(validation.InBody, "scenario.name", "Scenario name is required")
+		}
+		// Documents exported before scenario modes existed are what-if scenarios
+		if document.Scenario.Mode == "" {
+			document.Scenario.Mode = ScenarioModeWhatIf
+		}
+		if !isValidScenarioMode(document.Scenario.Mode) {
+			errs.Add(validation.InBody, "scenario.mode", "Invalid scenario mode")
+		}
+		if document.Scenario.ResultMode == "" {
+			document.Scenario.ResultMode = standings.ResultModeAlternateHistory
+		}
+		if !standings.IsValidResultMode(document.Scenario.ResultMode) {
+			errs.Add(validation.InBody, "scenario.result_mode", "Invalid scenario result mode")
+		}
+		if err := errs.Err(); err != nil {
+			return err
+		}
+
+		params := validation.NewParams(c)
+		targetSeasonID := params.OptionalQueryID("season_id")
+		if err := params.Err(); err != nil {
+			return err
+		}
+
+		// Target season is given explicitly, or matched by sport and start year
+		var seasonID, sportID, startYear int
+		var sportShortName string
+		var endYear *int
+		var err error
+		if targetSeasonID != nil {
+			seasonID = *targetSeasonID
+			err = db.Conn.QueryRow(`
+				SELECT season.sport_id, sport.short_name, season.start_year, season.end_year
+				FROM seasons season
+				JOIN sports sport ON season.sport_id = sport.id
+				WHERE season.id = $1
+			`, seasonID).Scan(&sportID, &sportShortName, &startYear, &endYear)
+			if err == sql.ErrNoRows {
+				return apperror.New(apperror.SeasonNotFound, "Season not found")
+			}
+			if err != nil {
+				return err
+			}
+			if sportShortName != document.Sport {
+				return apperror.Newf(apperror.DocumentMismatch, "Document is for %s but target season is %s", document.Sport, sportShortName)
+			}
+		} else {
+			err = db.Conn.QueryRow(`
+				SELECT season.id, season.sport_id, sport.short_name, season.start_year, season.end_year
+				FROM seasons season
+				JOIN sports sport ON season.sport_id = sport.id
+				WHERE sport.short_name = $1 AND season.start_year = $2
+			`, document.Sport, document.Season.StartYear).Scan(&seasonID, &sportID, &sportShortName, &startYear, &endYear)
+			if err != nil {
+				return apperror.Newf(apperror.SeasonNotFound, "No %s season starting in %d", document.Sport, document.Season.StartYear)
+			}
+		}
+
+		// Predicted scores must be in the target sport's range
+		for i, pick := range document.Picks {
+			prefix := fmt.Sprintf("picks[%d].", i)
+			errs.Score(prefix+"predicted_home_score", sportID, pick.PredictedHomeScore)
+			errs.Score(prefix+"predicted_away_score", sportID, pick.PredictedAwayScore)
+		}
+		if err := errs.Err(); err != nil {
+			return err
+		}
+
+		teams, err := getSeasonTeamIndex(db.Conn, seasonID)
+		if err != nil {
+			return err
+		}
+
+		games, err := getImportGames(db, seasonID)
+		if err != nil {
+			return err
+		}
+
+		// Match every pick to a game in the target season, collecting the ones that don't match
+		type matchedPick struct {
+			GameID             int
+			PickedTeamID       *int
+			PredictedHomeScore *int
+			PredictedAwayScore *int
+			Status             *string
+			IsOverride         bool
+		}
+		var matchedPicks []matchedPick
+		unmatchedGames := []UnmatchedGame{}
+		for i, pick := range document.Picks {
+			game, reason := matchImportGame(pick.Game, games, teams)
+			if reason == "" {
+				var pickedTeamID *int
+				if pick.IsTie {
+					tie := 0
+					pickedTeamID = &tie
+				} else if pick.PickedTeam != nil {
+					teamID, ok := teams.resolve(*pick.PickedTeam)
+					if !ok || (teamID != game.HomeTeamID && teamID != game.AwayTeamID) {
+						reason = "Picked team is not playing in this game"
+					} else {
+						pickedTeamID = &teamID
+					}
+				}
+
+				// Same checks as picks made through the API: scores decide the picked team, and ties only where the sport allows them
+				if reason == "" {
+					var pickErrs validation.Errors
+					pickedTeamID = pickErrs.Pick(fmt.Sprintf("picks[%d].", i), sportID, game.HomeTeamID, game.AwayTeamID, pickedTeamID, pick.PredictedHomeScore, pick.PredictedAwayScore)
+					if !pickErrs.Empty() {
+						reason = pickErrs.List()[0].Message
+					}
+				}
+
+				if reason == "" {
+					matchedPicks = append(matchedPicks, matchedPick{
+						GameID:             game.ID,
+						PickedTeamID:       pickedTeamID,
+						PredictedHomeScore: pick.PredictedHomeScore,
+						PredictedAwayScore: pick.PredictedAwayScore,
+						Status:             pick.Status,
+						// Documents without the flag predate overrides, when picks always replaced real results
+						IsOverride:         game.IsFinal && (pick.IsOverride == nil || *pick.IsOverride),
+					})
+					continue
+				}
+			}
+
+			unmatchedGames = append(unmatchedGames, UnmatchedGame{
+				ESPNID:   pick.Game.ESPNID,
+				Week:     pick.Game.Week,
+				HomeTeam: pick.Game.HomeTeam.Abbreviation,
+				AwayTeam: pick.Game.AwayTeam.Abbreviation,
+				Reason:   reason,
+			})
+		}
+
+		// Bracket is only imported when every team in it can be matched
+		unmatchedTeams := findUnmatchedPlayoffTeams(document.Playoffs, teams)
+		importPlayoffs := document.Playoffs != nil && len(unmatchedTeams) == 0
+
+		if c.QueryBool("strict") && (len(unmatchedGames) > 0 || len(unmatchedTeams) > 0) {
+			return apperror.New(apperror.ImportMismatch, "Document does not fully match target season").With(ImportMismatchResponse{
+				UnmatchedGames: unmatchedGames,
+				UnmatchedTeams: unmatchedTeams,
+			})
+		}
+
+		newUserID, newSessionToken := getNewScenarioOwner(c)
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var id int
+		var createdAt, updatedAt time.Time
+		err = tx.QueryRow(`
+			INSERT INTO scenarios (user_id, session_token, name, sport_id, season_id, is_public, mode, result_mode)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, created_at, updated_at
+		`, newUserID, newSessionToken, document.Scenario.Name, sportID, seasonID, document.Scenario.IsPublic, document.Scenario.Mode, document.Scenario.ResultMode).Scan(&id, &createdAt, &updatedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, pick := range matchedPicks {
+			status := "pending"
+			if pick.Status != nil && *pick.Status != "" {
+				status = *pick.Status
+			}
+			_, err := tx.Exec(`
+				INSERT INTO picks (scenario_id, game_id, picked_team_id, predicted_home_score, predicted_away_score, status, is_override)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT (scenario_id, game_id) DO NOTHING
+			`, id, pick.GameID, pick.PickedTeamID, pick.PredictedHomeScore, pick.PredictedAwayScore, status, pick.IsOverride)
+			if err != nil {
+				return err
+			}
+		}
+
+		if importPlayoffs {
+			if err := importPlayoffBracket(tx, id, document.Playoffs, teams); err != nil {
+				return apperror.New(apperror.Internal, "Failed to import playoffs").WithCause(err)
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		return c.Status(201).JSON(ImportScenarioResponse{
+			Scenario: ScenarioResponse{
+				ID:              id,
+				Name:            document.Scenario.Name,
+				SportID:         sportID,
+				SeasonID:        seasonID,
+				SportShortName:  sportShortName,
+				SeasonStartYear: startYear,
+				SeasonEndYear:   endYear,
+				IsPublic:        document.Scenario.IsPublic,
+				Mode:            document.Scenario.Mode,
+				ResultMode:      document.Scenario.ResultMode,
+				Role:            ScenarioRoleOwner,
+				CreatedAt:       createdAt,
+				UpdatedAt:       updatedAt,
+			},
+			ImportedPicks:    len(matchedPicks),
+			UnmatchedGames:   unmatchedGames,
+			PlayoffsImported: importPlayoffs,
+			UnmatchedTeams:   unmatchedTeams,
+		})
+	}
+}
+
+func getSeasonTeamIndex(q sqlExecutor, seasonID int) (*seasonTeamIndex, error) {
+	rows, err := q.Query(`SELECT id, espn_id, abbreviation FROM teams WHERE season_id = $1`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	index := &seasonTeamIndex{
+		byID:           make(map[int]models.DocumentTeam),
+		byESPNID:       make(map[string]int),
+		byAbbreviation: make(map[string]int),
+	}
+	for rows.Next() {
+		var id int
+		var espnID *string
+		var abbreviation string
+		if err := rows.Scan(&id, &espnID, &abbreviation); err != nil {
+			return nil, err
+		}
+
+		team := models.DocumentTeam{Abbreviation: abbreviation}
+		if espnID != nil {
+			team.ESPNID = *espnID
+			index.byESPNID[*espnID] = id
+		}
+		index.byID[id] = team
+		index.byAbbreviation[abbreviation] = id
+	}
+
+	return index, rows.Err()
+}
+
+// Resolves a document team by ESPN ID, falling back to abbreviation
+func (index *seasonTeamIndex) resolve(team models.DocumentTeam) (int, bool) {
+	if team.ESPNID != "" {
+		if id, exists := index.byESPNID[team.ESPNID]; exists {
+			return id, true
+		}
+	}
+	if team.Abbreviation != "" {
+		if id, exists := index.byAbbreviation[team.Abbreviation]; exists {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Returns the document reference for a team ID, or nil for no team or a tie
+func (index *seasonTeamIndex) reference(teamID *int) *models.DocumentTeam {
+	if teamID == nil || *teamID == 0 {
+		return nil
+	}
+	team := index.byID[*teamID]
+	return &team
+}
+
+func exportPicks(db *database.DB, scenarioID int, teams *seasonTeamIndex) ([]models.DocumentPick, error) {
+	rows, err := db.Query(`
+		SELECT game.espn_id, game.week, game.home_team_id, game.away_team_id,
+			pick.picked_team_id, pick.predicted_home_score, pick.predicted_away_score, pick.status, pick.is_override
+		FROM picks pick
+		JOIN games game ON pick.game_id = game.id
+		WHERE pick.scenario_id = $1
+		ORDER BY game.start_time, game.id
+	`, scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	picks := []models.DocumentPick{}
+	for rows.Next() {
+		var espnID, status *string
+		var week, pickedTeamID, predictedHomeScore, predictedAwayScore *int
+		var homeTeamID, awayTeamID int
+		var isOverride bool
+		err := rows.Scan(&espnID, &week, &homeTeamID, &awayTeamID, &pickedTeamID, &predictedHomeScore, &predictedAwayScore, &status, &isOverride)
+		if err != nil {
+			return nil, err
+		}
+
+		game := models.DocumentGame{
+			Week:     week,
+			HomeTeam: teams.byID[homeTeamID],
+			AwayTeam: teams.byID[awayTeamID],
+		}
+		if espnID != nil {
+			game.ESPNID = *espnID
+		}
+
+		picks = append(picks, models.DocumentPick{
+			Game:               game,
+			PickedTeam:         teams.reference(pickedTeamID),
+			IsTie:              pickedTeamID != nil && *pickedTeamID == 0,
+			PredictedHomeScore: predictedHomeScore,
+			PredictedAwayScore: predictedAwayScore,
+			Status:             status,
+			IsOverride:         &isOverride,
+		})
+	}
+
+	return picks, rows.Err()
+}
+
+func exportPlayoffs(q sqlExecutor, scenarioID int, teams *seasonTeamIndex) (*models.DocumentPlayoffs, error) {
+	var playoffStateID int
+	playoffs := &models.DocumentPlayoffs{
+		Series:   []models.DocumentPlayoffSeries{},
+		Matchups: []models.DocumentPlayoffMatchup{},
+	}
+	err := q.QueryRow(`
+		SELECT id, current_round, is_enabled FROM playoff_states WHERE scenario_id = $1
+	`, scenarioID).Scan(&playoffStateID, &playoffs.CurrentRound, &playoffs.IsEnabled)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seriesRows, err := q.Query(`
+		SELECT id, round, series_order, conference, higher_seed_team_id, lower_seed_team_id,
+			higher_seed, lower_seed, picked_team_id, predicted_higher_seed_wins, predicted_lower_seed_wins,
+			best_of, status
+		FROM playoff_series
+		WHERE playoff_state_id = $1
+		ORDER BY round, conference, series_order
+	`, playoffStateID)
+	if err != nil {
+		return nil, err
+	}
+	seriesKeys := make(map[int]models.DocumentSeriesKey)
+	for seriesRows.Next() {
+		var id, higherSeedTeamID, lowerSeedTeamID int
+		var pickedTeamID *int
+		var series models.DocumentPlayoffSeries
+		err := seriesRows.Scan(
+			&id, &series.Round, &series.SeriesOrder, &series.Conference, &higherSeedTeamID, &lowerSeedTeamID,
+			&series.HigherSeed, &series.LowerSeed, &pickedTeamID, &series.PredictedHigherSeedWins, &series.PredictedLowerSeedWins,
+			&series.BestOf, &series.Status,
+		)
+		if err != nil {
+			seriesRows.Close()
+			return nil, err
+		}
+		series.HigherSeedTeam = teams.byID[higherSeedTeamID]
+		series.LowerSeedTeam = teams.byID[lowerSeedTeamID]
+		series.PickedTeam = teams.reference(pickedTeamID)
+
+		seriesKeys[id] = models.DocumentSeriesKey{Round: series.Round, SeriesOrder: series.SeriesOrder, Conference: series.Conference}
+		playoffs.Series = append(playoffs.Series, series)
+	}
+	seriesRows.Close()
+
+	matchupRows, err := q.Query(`
+		SELECT playoff_series_id, round, matchup_order, game_number, conference, higher_seed_team_id, lower_seed_team_id,
+			higher_seed, lower_seed, picked_team_id, predicted_higher_seed_score, predicted_lower_seed_score, status
+		FROM playoff_matchups
+		WHERE playoff_state_id = $1
+		ORDER BY round, conference, matchup_order, game_number
+	`, playoffStateID)
+	if err != nil {
+		return nil, err
+	}
+	defer matchupRows.Close()
+
+	for matchupRows.Next() {
+		var playoffSeriesID, pickedTeamID *int
+		var higherSeedTeamID, lowerSeedTeamID int
+		var matchup models.DocumentPlayoffMatchup
+		err := matchupRows.Scan(
+			&playoffSeriesID, &matchup.Round, &matchup.MatchupOrder, &matchup.GameNumber, &matchup.Conference, &higherSeedTeamID, &lowerSeedTeamID,
+			&matchup.HigherSeed, &matchup.LowerSeed, &pickedTeamID, &matchup.PredictedHigherSeedScore, &matchup.PredictedLowerSeedScore, &matchup.Status,
+		)
+		if err != nil {
+			return nil, err
+		}
+		matchup.HigherSeedTeam = teams.byID[higherSeedTeamID]
+		matchup.LowerSeedTeam = teams.byID[lowerSeedTeamID]
+		matchup.PickedTeam = teams.reference(pickedTeamID)
+		if playoffSeriesID != nil {
+			if key, exists := seriesKeys[*playoffSeriesID]; exists {
+				matchup.Series = &key
+			}
+		}
+
+		playoffs.Matchups = append(playoffs.Matchups, matchup)
+	}
+
+	return playoffs, matchupRows.Err()
+}
+
+// Gets every game in a season that has an ESPN ID, keyed by ESPN ID
+func getImportGames(db *database.DB, seasonID int) (map[string]importGame, error) {
+	rows, err := db.Query(`
+		SELECT id, espn_id, home_team_id, away_team_id, status = 'final' AND home_score IS NOT NULL AND away_score IS NOT NULL
+		FROM games
+		WHERE season_id = $1 AND espn_id IS NOT NULL
+	`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	games := make(map[string]importGame)
+	for rows.Next() {
+		var game importGame
+		var espnID string
+		if err := rows.Scan(&game.ID, &espnID, &game.HomeTeamID, &game.AwayTeamID, &game.IsFinal); err != nil {
+			return nil, err
+		}
+		games[espnID] = game
+	}
+
+	return games, rows.Err()
+}
+
+// Matches a document game to a season game, returning the reason when it can't be matched
+func matchImportGame(documentGame models.DocumentGame, games map[string]importGame, teams *seasonTeamIndex) (importGame, string) {
+	if documentGame.ESPNID == "" {
+		return importGame{}, "Game has no ESPN ID"
+	}
+
+	game, exists := games[documentGame.ESPNID]
+	if !exists {
+		return importGame{}, "Game not found in target season"
+	}
+
+	homeTeamID, homeOK := teams.resolve(documentGame.HomeTeam)
+	awayTeamID, awayOK := teams.resolve(documentGame.AwayTeam)
+	if !homeOK || !awayOK || homeTeamID != game.HomeTeamID || awayTeamID != game.AwayTeamID {
+		return importGame{}, "Teams do not match target season game"
+	}
+
+	return game, ""
+}
+
+// Lists playoff teams in a document that don't exist in the target season
+func findUnmatchedPlayoffTeams(playoffs *models.DocumentPlayoffs, teams *seasonTeamIndex) []models.DocumentTeam {
+	unmatched := []models.DocumentTeam{}
+	if playoffs == nil {
+		return unmatched
+	}
+
+	seen := make(map[models.DocumentTeam]bool)
+	check := func(team *models.DocumentTeam) {
+		if team == nil || seen[*team] {
+			return
+		}
+		seen[*team] = true
+		if _, ok := teams.resolve(*team); !ok {
+			unmatched = append(unmatched, *team)
+		}
+	}
+
+	for i := range playoffs.Series {
+		check(&playoffs.Series[i].HigherSeedTeam)
+		check(&playoffs.Series[i].LowerSeedTeam)
+		check(playoffs.Series[i].PickedTeam)
+	}
+	for i := range playoffs.Matchups {
+		check(&playoffs.Matchups[i].HigherSeedTeam)
+		check(&playoffs.Matchups[i].LowerSeedTeam)
+		check(playoffs.Matchups[i].PickedTeam)
+	}
+
+	return unmatched
+}
+
+// Inserts a document's playoff state, series, and matchups for a scenario
+func importPlayoffBracket(tx *sql.Tx, scenarioID int, playoffs *models.DocumentPlayoffs, teams *seasonTeamIndex) error {
+	var playoffStateID int
+	err := tx.QueryRow(`
+		INSERT INTO playoff_states (scenario_id, current_round, is_enabled)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, scenarioID, playoffs.CurrentRound, playoffs.IsEnabled).Scan(&playoffStateID)
+	if err != nil {
+		return err
+	}
+
+	resolvePicked := func(team *models.DocumentTeam) *int {
+		if team == nil {
+			return nil
+		}
+		id, _ := teams.resolve(*team)
+		return &id
+	}
+
+	seriesIDs := make(map[string]int)
+	for _, series := range playoffs.Series {
+		higherSeedTeamID, _ := teams.resolve(series.HigherSeedTeam)
+		lowerSeedTeamID, _ := teams.resolve(series.LowerSeedTeam)
+
+		var id int
+		err := tx.QueryRow(`
+			INSERT INTO playoff_series (
+				playoff_state_id, round, series_order, conference, higher_seed_team_id, lower_seed_team_id,
+				higher_seed, lower_seed, picked_team_id, predicted_higher_seed_wins, predicted_lower_seed_wins,
+				best_of, status
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, 0), COALESCE($11, 0), COALESCE($12, 7), COALESCE($13, 'pending'))
+			RETURNING id
+		`,
+			playoffStateID, series.Round, series.SeriesOrder, series.Conference, higherSeedTeamID, lowerSeedTeamID,
+			series.HigherSeed, series.LowerSeed, resolvePicked(series.PickedTeam), series.PredictedHigherSeedWins, series.PredictedLowerSeedWins,
+			series.BestOf, series.Status,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		seriesIDs[documentSeriesKey(series.Round, series.SeriesOrder, series.Conference)] = id
+	}
+
+	for _, matchup := range playoffs.Matchups {
+		higherSeedTeamID, _ := teams.resolve(matchup.HigherSeedTeam)
+		lowerSeedTeamID, _ := teams.resolve(matchup.LowerSeedTeam)
+
+		var playoffSeriesID *int
+		if matchup.Series != nil {
+			id, exists := seriesIDs[documentSeriesKey(matchup.Series.Round, matchup.Series.SeriesOrder, matchup.Series.Conference)]
+			if !exists {
+				return fmt.Errorf("matchup in round %d references a series that is not in the document", matchup.Round)
+			}
+			playoffSeriesID = &id
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO playoff_matchups (
+				playoff_state_id, playoff_series_id, round, matchup_order, game_number, conference,
+				higher_seed_team_id, lower_seed_team_id, higher_seed, lower_seed,
+				picked_team_id, predicted_higher_seed_score, predicted_lower_seed_score, status
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, COALESCE($14, 'pending'))
+		`,
+			playoffStateID, playoffSeriesID, matchup.Round, matchup.MatchupOrder, matchup.GameNumber, matchup.Conference,
+			higherSeedTeamID, lowerSeedTeamID, matchup.HigherSeed, matchup.LowerSeed,
+			resolvePicked(matchup.PickedTeam), matchup.PredictedHigherSeedScore, matchup.PredictedLowerSeedScore, matchup.Status,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func documentSeriesKey(round int, seriesOrder int, conference *string) string {
+	if conference == nil {
+		return fmt.Sprintf("%d|%d|", round, seriesOrder)
+	}
+	return fmt.Sprintf("%d|%d|%s", round, seriesOrder, *conference)
+}
@@ -0,0 +1,51 @@
+// Scenario change log replay for undo and redo
+
+package history
+
+
+// Logged scenario actions
+const (
+	ActionPickCreate           = "pick_create"
+	ActionPickUpdate           = "pick_update"
+	ActionPickDelete           = "pick_delete"
+	ActionPicksBatch           = "picks_batch"
+	ActionPicksAutofill        = "picks_autofill"
+	ActionPlayoffsEnable       = "playoffs_enable"
+	ActionPlayoffRoundGenerate = "playoff_round_generate"
+	ActionPlayoffPickUpdate    = "playoff_pick_update"
+	ActionPlayoffPickDelete    = "playoff_pick_delete"
+	ActionSnapshotRestore      = "snapshot_restore"
+	ActionUndo                 = "undo"
+	ActionRedo                 = "redo"
+)
+
+// Change log entry; TargetID is the change an undo or redo applied to
+type Entry struct {
+	ID       int
+	Action   string
+	TargetID *int
+}
+
+// Replays a change log in order and returns the change IDs that can be undone and redone, next in line last.
+// A new change clears the redo stack, like an editor.
+func Stacks(entries []Entry) (undo []int, redo []int) {
+	for _, entry := range entries {
+		switch entry.Action {
+		case ActionUndo:
+			if len(undo) > 0 {
+				redo = append(redo, undo[len(undo)-1])
+				undo = undo[:len(undo)-1]
+			}
+		case ActionRedo:
+			if len(redo) > 0 {
+				undo = append(undo, redo[len(redo)-1])
+				redo = redo[:len(redo)-1]
+			}
+		default:
+			undo = append(undo, entry.ID)
+			redo = nil
+		}
+	}
+
+	return undo, redo
+}
@@ -0,0 +1,88 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestStacks(t *testing.T) {
+	tests := []struct {
+		name         string
+		entries      []Entry
+		expectedUndo []int
+		expectedRedo []int
+	}{
+		{
+			name:         "No changes",
+			entries:      nil,
+			expectedUndo: nil,
+			expectedRedo: nil,
+		},
+		{
+			name: "Changes only",
+			entries: []Entry{
+				{ID: 1, Action: ActionPickCreate},
+				{ID: 2, Action: ActionPickUpdate},
+			},
+			expectedUndo: []int{1, 2},
+			expectedRedo: nil,
+		},
+		{
+			name: "Undo moves the latest change to redo",
+			entries: []Entry{
+				{ID: 1, Action: ActionPickCreate},
+				{ID: 2, Action: ActionPickUpdate},
+				{ID: 3, Action: ActionUndo, TargetID: intPtr(2)},
+			},
+			expectedUndo: []int{1},
+			expectedRedo: []int{2},
+		},
+		{
+			name: "Undo twice then redo once",
+			entries: []Entry{
+				{ID: 1, Action: ActionPickCreate},
+				{ID: 2, Action: ActionPickUpdate},
+				{ID: 3, Action: ActionUndo, TargetID: intPtr(2)},
+				{ID: 4, Action: ActionUndo, TargetID: intPtr(1)},
+				{ID: 5, Action: ActionRedo, TargetID: intPtr(1)},
+			},
+			expectedUndo: []int{1},
+			expectedRedo: []int{2},
+		},
+		{
+			name: "New change clears redo",
+			entries: []Entry{
+				{ID: 1, Action: ActionPickCreate},
+				{ID: 2, Action: ActionUndo, TargetID: intPtr(1)},
+				{ID: 3, Action: ActionPlayoffPickUpdate},
+			},
+			expectedUndo: []int{3},
+			expectedRedo: nil,
+		},
+		{
+			name: "Undo with empty stack is ignored",
+			entries: []Entry{
+				{ID: 1, Action: ActionUndo},
+				{ID: 2, Action: ActionSnapshotRestore},
+			},
+			expectedUndo: []int{2},
+			expectedRedo: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			undo, redo := Stacks(tt.entries)
+			if !reflect.DeepEqual(undo, tt.expectedUndo) {
+				t.Errorf("undo = %v; want %v", undo, tt.expectedUndo)
+			}
+			if !reflect.DeepEqual(redo, tt.expectedRedo) {
+				t.Errorf("redo = %v; want %v", redo, tt.expectedRedo)
+			}
+		})
+	}
+}
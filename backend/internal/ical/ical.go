@@ -0,0 +1,134 @@
+// RFC 5545 iCalendar writer for game schedules
+
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+
+const (
+	productID      = "-//GameScript//Schedule//EN"
+	maxLineOctets  = 75
+	dateTimeFormat = "20060102T150405Z"
+)
+
+type Calendar struct {
+	Name        string
+	Description string
+	Events      []Event
+}
+
+// Single calendar event; times are written in UTC
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+	Categories  []string
+}
+
+// Writes a calendar with CRLF line endings, escaped text, and folded long lines
+func Write(w io.Writer, calendar Calendar, now time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + productID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+	if calendar.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+EscapeText(calendar.Name))
+	}
+	if calendar.Description != "" {
+		lines = append(lines, "X-WR-CALDESC:"+EscapeText(calendar.Description))
+	}
+
+	stamp := formatTime(now)
+	for _, event := range calendar.Events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+formatTime(event.Start),
+			"DTEND:"+formatTime(event.End),
+			"SUMMARY:"+EscapeText(event.Summary),
+		)
+		if event.Location != "" {
+			lines = append(lines, "LOCATION:"+EscapeText(event.Location))
+		}
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+EscapeText(event.Description))
+		}
+		if len(event.Categories) > 0 {
+			escaped := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				escaped[i] = EscapeText(category)
+			}
+			lines = append(lines, "CATEGORIES:"+strings.Join(escaped, ","))
+		}
+		lines = append(lines, "STATUS:CONFIRMED", "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, FoldLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Escapes backslashes, semicolons, commas, and newlines in TEXT values
+func EscapeText(text string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(text)
+}
+
+// Splits lines longer than 75 octets, continuing with a single leading space, without splitting UTF-8 characters
+func FoldLine(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+
+	var folded strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+
+		// Continuation lines lose one octet to the leading space
+		limit = maxLineOctets - 1
+	}
+	folded.WriteString(line)
+
+	return folded.String()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// Builds a stable event UID from a source ID so updated events replace earlier copies
+func UID(kind string, id string) string {
+	return fmt.Sprintf("%s-%s@gamescript.live", kind, id)
+}
@@ -0,0 +1,97 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"DAL @ PHI", "DAL @ PHI"},
+		{"Lincoln Financial Field, Philadelphia", `Lincoln Financial Field\, Philadelphia`},
+		{"Week 1; NBC", `Week 1\; NBC`},
+		{"Line one\nLine two", `Line one\nLine two`},
+		{`C:\path`, `C:\\path`},
+	}
+
+	for _, tt := range tests {
+		if result := EscapeText(tt.input); result != tt.expected {
+			t.Errorf("EscapeText(%q) = %q; want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	short := "SUMMARY:DAL @ PHI"
+	if FoldLine(short) != short {
+		t.Errorf("Short line should not be folded")
+	}
+
+	long := "DESCRIPTION:" + strings.Repeat("a", 200)
+	folded := FoldLine(long)
+	for i, line := range strings.Split(folded, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Folded line %d is %d octets; want at most 75", i, len(line))
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("Continuation line %d does not start with a space", i)
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != long {
+		t.Errorf("Unfolding did not restore the original line")
+	}
+
+	// Multi-byte characters are never split across lines
+	accented := "LOCATION:" + strings.Repeat("é", 60)
+	for _, line := range strings.Split(FoldLine(accented), "\r\n") {
+		if !strings.HasPrefix(strings.TrimPrefix(line, " "), "LOCATION") && !strings.HasPrefix(strings.TrimPrefix(line, " "), "é") {
+			t.Errorf("Folded line starts in the middle of a character: %q", line)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	start := time.Date(2025, 9, 5, 0, 20, 0, 0, time.UTC)
+	calendar := Calendar{
+		Name: "Philadelphia Eagles",
+		Events: []Event{
+			{
+				UID:        UID("nfl", "401772510"),
+				Start:      start,
+				End:        start.Add(3 * time.Hour),
+				Summary:    "DAL @ PHI",
+				Location:   "Lincoln Financial Field",
+				Categories: []string{"TNF"},
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(&buffer, calendar, time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	output := buffer.String()
+
+	expectedLines := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Philadelphia Eagles\r\n",
+		"UID:nfl-401772510@gamescript.live\r\n",
+		"DTSTAMP:20250801T120000Z\r\n",
+		"DTSTART:20250905T002000Z\r\n",
+		"DTEND:20250905T032000Z\r\n",
+		"LOCATION:Lincoln Financial Field\r\n",
+		"CATEGORIES:TNF\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(output, line) {
+			t.Errorf("Calendar output missing %q", line)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Reads and writes fetched ESPN data files and upserts their teams and games into the database
+
+package importer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gamescript/internal/models"
+)
+
+
+// Sports with data files and ESPN fetchers
+const (
+	SportNFL = "nfl"
+	SportNBA = "nba"
+)
+
+// Runs statements against either the connection pool or a transaction
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Season start year in progress at a given time. NFL seasons run into February and NBA seasons into June.
+func CurrentSeasonYear(sport string, now time.Time) int {
+	lastMonth := time.February
+	if sport == SportNBA {
+		lastMonth = time.June
+	}
+
+	if now.Month() <= lastMonth {
+		return now.Year() - 1
+	}
+	return now.Year()
+}
+
+// Path of a sport's teams file for a season, like database/nfl/teams/nfl_teams_2025.json
+func TeamsFile(dataDir string, sport string, year int) string {
+	return filepath.Join(dataDir, sport, "teams", fmt.Sprintf("%s_teams_%d.json", sport, year))
+}
+
+// Path of a sport's schedule file for a season, or for a single week when week is positive. NBA files are
+// named by both years of the season, like database/nba/schedules/nba_schedule_2025-2026_full.json.
+func ScheduleFile(dataDir string, sport string, year int, week int) string {
+	season := fmt.Sprintf("%d", year)
+	if sport == SportNBA {
+		season = fmt.Sprintf("%d-%d", year, year+1)
+	}
+
+	name := fmt.Sprintf("%s_schedule_%s_full.json", sport, season)
+	if week > 0 {
+		name = fmt.Sprintf("%s_schedule_%s_week_%d.json", sport, season, week)
+	}
+	return filepath.Join(dataDir, sport, "schedules", name)
+}
+
+func ReadTeams(path string) ([]models.Team, error) {
+	var teams []models.Team
+	if err := readJSON(path, &teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func ReadGames(path string) ([]models.Game, error) {
+	var games []models.Game
+	if err := readJSON(path, &games); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Writes indented JSON, creating the file's directory if needed
+func WriteJSON(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Upserts teams into a season, returning how many were inserted or had their logos changed
+func UpsertTeams(exec Execer, sportID int, seasonID int, teams []models.Team) (int, error) {
+	stmt := `
+		INSERT INTO teams (
+			sport_id, season_id, espn_id, abbreviation, city, name,
+			conference, division, primary_color, secondary_color, logo_url, alternate_logo_url,
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6,
+			$7, $8, $9, $10, $11, $12, $13
+		)
+		ON CONFLICT (season_id, espn_id) DO UPDATE SET
+			alternate_logo_url = EXCLUDED.alternate_logo_url,
+			logo_url = EXCLUDED.logo_url
+		WHERE (teams.logo_url, teams.alternate_logo_url) IS DISTINCT FROM (EXCLUDED.logo_url, EXCLUDED.alternate_logo_url)
+	`
+
+	changed := 0
+	for _, team := range teams {
+		result, err := exec.Exec(
+			stmt,
+			sportID,
+			seasonID,
+			team.ESPNID,
+			team.Abbreviation,
+			team.City,
+			team.Name,
+			team.Conference,
+			team.Division,
+			team.PrimaryColor,
+			team.SecondaryColor,
+			team.LogoURL,
+			team.AlternateLogoURL,
+			time.Now(),
+		)
+		if err != nil {
+			return changed, fmt.Errorf("error upserting team %s: %w", team.Name, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return changed, fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rowsAffected > 0 {
+			changed++
+		}
+	}
+
+	return changed, nil
+}
+
+// Upserts a game from the schedule into a season, returning whether the row was inserted or any of its fields changed
+func UpsertGame(exec Execer, seasonID int, game models.Game) (bool, error) {
+	if game.HomeTeamESPNID == nil || game.AwayTeamESPNID == nil {
+		return false, fmt.Errorf("game %s is missing a team ESPN ID", game.ESPNID)
+	}
+
+	stmt := `
+		INSERT INTO games (
+			season_id, espn_id, home_team_id, away_team_id, start_time,
+			day_of_week, week, location, primetime, network,
+			home_score, away_score, status
+		) VALUES (
+			$1, $2,
+			(SELECT id FROM teams WHERE season_id = $1 AND espn_id = $3),
+			(SELECT id FROM teams WHERE season_id = $1 AND espn_id = $4),
+			$5, $6, $7, $8, $9, $10, $11, $12, $13
+		)
+		ON CONFLICT (season_id, espn_id) DO UPDATE SET
+			start_time = EXCLUDED.start_time,
+			day_of_week = EXCLUDED.day_of_week,
+			week = EXCLUDED.week,
+			location = EXCLUDED.location,
+			primetime = EXCLUDED.primetime,
+			network = EXCLUDED.network,
+			home_score = EXCLUDED.home_score,
+			away_score = EXCLUDED.away_score,
+			status = EXCLUDED.status
+		WHERE (
+			games.start_time, games.day_of_week, games.week, games.location, games.primetime,
+			games.network, games.home_score, games.away_score, games.status
+		) IS DISTINCT FROM (
+			EXCLUDED.start_time, EXCLUDED.day_of_week, EXCLUDED.week, EXCLUDED.location, EXCLUDED.primetime,
+			EXCLUDED.network, EXCLUDED.home_score, EXCLUDED.away_score, EXCLUDED.status
+		)
+	`
+
+	// Update scores only if game is final
+	var homeScore, awayScore *int
+	if game.Status != nil && *game.Status == "final" {
+		homeScore = game.HomeScore
+		awayScore = game.AwayScore
+	}
+
+	result, err := exec.Exec(
+		stmt,
+		seasonID,
+		game.ESPNID,
+		*game.HomeTeamESPNID,
+		*game.AwayTeamESPNID,
+		game.StartTime,
+		game.DayOfWeek,
+		game.Week,
+		game.Location,
+		game.Primetime,
+		game.Network,
+		homeScore, // Will be NULL for upcoming games
+		awayScore, // Will be NULL for upcoming games
+		game.Status,
+	)
+	if err != nil {
+		return false, fmt.Errorf("database error: %w", err)
+	}
+
+	// Games already matching the schedule are skipped by the upsert and affect no rows
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCurrentSeasonYear(t *testing.T) {
+	tests := []struct {
+		sport    string
+		now      time.Time
+		expected int
+	}{
+		{SportNFL, time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), 2025},
+		{SportNFL, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 2026},
+		{SportNBA, time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC), 2025},
+		{SportNBA, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), 2026},
+	}
+
+	for _, tt := range tests {
+		result := CurrentSeasonYear(tt.sport, tt.now)
+		if result != tt.expected {
+			t.Errorf("CurrentSeasonYear(%q, %s) = %d; want %d", tt.sport, tt.now.Format("2006-01-02"), result, tt.expected)
+		}
+	}
+}
+
+// The default paths have to line up with the data files checked into database/
+func TestDataFilePaths(t *testing.T) {
+	dataDir := filepath.Join("..", "..", "database")
+
+	tests := []struct {
+		name  string
+		path  string
+		teams bool
+	}{
+		{"NFL teams", TeamsFile(dataDir, SportNFL, 2025), true},
+		{"NBA teams", TeamsFile(dataDir, SportNBA, 2025), true},
+		{"NFL schedule", ScheduleFile(dataDir, SportNFL, 2025, 0), false},
+		{"NBA schedule", ScheduleFile(dataDir, SportNBA, 2025, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := os.Stat(tt.path); err != nil {
+				t.Fatalf("%s file %s: %v", tt.name, tt.path, err)
+			}
+
+			var count int
+			if tt.teams {
+				teams, err := ReadTeams(tt.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				count = len(teams)
+			} else {
+				games, err := ReadGames(tt.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				count = len(games)
+			}
+			if count == 0 {
+				t.Errorf("%s file %s is empty", tt.name, tt.path)
+			}
+		})
+	}
+}
+
+func TestScheduleFileWeek(t *testing.T) {
+	expected := filepath.Join("database", "nfl", "schedules", "nfl_schedule_2025_week_3.json")
+	if path := ScheduleFile("database", SportNFL, 2025, 3); path != expected {
+		t.Errorf("ScheduleFile(database, nfl, 2025, 3) = %s; want %s", path, expected)
+	}
+}
@@ -0,0 +1,282 @@
+// Standings inputs built straight from data files, for inspecting a season without a database
+
+package importer
+
+import (
+	"fmt"
+
+	"gamescript/internal/models"
+	"gamescript/internal/standings"
+)
+
+
+// A season read from data files. Teams and games have no database IDs there, so they are numbered by
+// their position in the files and matched to picks by ESPN ID.
+type FileSeason struct {
+	Teams []models.Team
+	Games []models.Game
+
+	teamsByESPNID       map[string]int
+	teamsByAbbreviation map[string]int
+	gamesByESPNID       map[string]int // ESPN ID to the game's position in Games
+	picks               map[int]filePick
+	resultMode          string
+}
+
+// A pick from a scenario document, resolved against the file season
+type filePick struct {
+	pickedTeamID       int // 0 for a tie
+	predictedHomeScore *int
+	predictedAwayScore *int
+	isOverride         bool
+}
+
+// Reads a season's teams and schedule, assigning team and game IDs starting at 1
+func LoadFileSeason(teamsPath string, schedulePath string) (*FileSeason, error) {
+	teams, err := ReadTeams(teamsPath)
+	if err != nil {
+		return nil, err
+	}
+	games, err := ReadGames(schedulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	season := &FileSeason{
+		Teams:               teams,
+		Games:               games,
+		teamsByESPNID:       make(map[string]int),
+		teamsByAbbreviation: make(map[string]int),
+		gamesByESPNID:       make(map[string]int),
+		picks:               make(map[int]filePick),
+		resultMode:          standings.ResultModeAlternateHistory,
+	}
+	for i := range season.Teams {
+		season.Teams[i].ID = i + 1
+		season.teamsByESPNID[season.Teams[i].ESPNID] = i + 1
+		season.teamsByAbbreviation[season.Teams[i].Abbreviation] = i + 1
+	}
+
+	for i := range season.Games {
+		game := &season.Games[i]
+		game.ID = i + 1
+		if game.HomeTeamESPNID == nil || game.AwayTeamESPNID == nil {
+			return nil, fmt.Errorf("game %s in %s is missing its teams", game.ESPNID, schedulePath)
+		}
+
+		var homeOK, awayOK bool
+		game.HomeTeamID, homeOK = season.teamsByESPNID[*game.HomeTeamESPNID]
+		game.AwayTeamID, awayOK = season.teamsByESPNID[*game.AwayTeamESPNID]
+		if !homeOK || !awayOK {
+			return nil, fmt.Errorf("game %s in %s has a team that isn't in %s", game.ESPNID, schedulePath, teamsPath)
+		}
+		season.gamesByESPNID[game.ESPNID] = i
+	}
+
+	return season, nil
+}
+
+// Reads a scenario exported with GET /api/scenarios/:scenario_id/export
+func ReadScenarioDocument(path string) (*models.ScenarioDocument, error) {
+	var document models.ScenarioDocument
+	if err := readJSON(path, &document); err != nil {
+		return nil, err
+	}
+	if document.Format != models.ScenarioDocumentFormat {
+		return nil, fmt.Errorf("%s is not a scenario document", path)
+	}
+	if document.Version < 1 || document.Version > models.ScenarioDocumentVersion {
+		return nil, fmt.Errorf("%s has unsupported document version %d", path, document.Version)
+	}
+	return &document, nil
+}
+
+// Applies a scenario document's picks the way importing it would, replacing any picks applied before.
+// Returns a description of each pick that doesn't match a game in the season.
+func (season *FileSeason) ApplyPicks(document *models.ScenarioDocument) ([]string, error) {
+	resultMode := document.Scenario.ResultMode
+	if resultMode == "" {
+		resultMode = standings.ResultModeAlternateHistory
+	}
+	if !standings.IsValidResultMode(resultMode) {
+		return nil, fmt.Errorf("invalid scenario result mode %q", resultMode)
+	}
+
+	season.resultMode = resultMode
+	season.picks = make(map[int]filePick)
+	var unmatched []string
+	for _, pick := range document.Picks {
+		game, reason := season.matchPick(pick)
+		if reason != "" {
+			unmatched = append(unmatched, fmt.Sprintf("%s at %s (game %s): %s",
+				pick.Game.AwayTeam.Abbreviation, pick.Game.HomeTeam.Abbreviation, pick.Game.ESPNID, reason))
+			continue
+		}
+
+		pickedTeamID := 0
+		if !pick.IsTie {
+			if pick.PickedTeam == nil {
+				continue
+			}
+			pickedTeamID, _ = season.resolveTeam(*pick.PickedTeam)
+		}
+		season.picks[game.ID] = filePick{
+			pickedTeamID:       pickedTeamID,
+			predictedHomeScore: pick.PredictedHomeScore,
+			predictedAwayScore: pick.PredictedAwayScore,
+			// Documents without the flag predate overrides, when picks always replaced real results
+			isOverride:         isFinal(game) && (pick.IsOverride == nil || *pick.IsOverride),
+		}
+	}
+
+	return unmatched, nil
+}
+
+func (season *FileSeason) matchPick(pick models.DocumentPick) (models.Game, string) {
+	position, exists := season.gamesByESPNID[pick.Game.ESPNID]
+	if !exists {
+		return models.Game{}, "Game not found in schedule"
+	}
+
+	game := season.Games[position]
+	homeTeamID, homeOK := season.resolveTeam(pick.Game.HomeTeam)
+	awayTeamID, awayOK := season.resolveTeam(pick.Game.AwayTeam)
+	if !homeOK || !awayOK || homeTeamID != game.HomeTeamID || awayTeamID != game.AwayTeamID {
+		return models.Game{}, "Teams do not match schedule"
+	}
+
+	if !pick.IsTie && pick.PickedTeam != nil {
+		teamID, ok := season.resolveTeam(*pick.PickedTeam)
+		if !ok || (teamID != game.HomeTeamID && teamID != game.AwayTeamID) {
+			return models.Game{}, "Picked team is not playing in this game"
+		}
+	}
+
+	return game, ""
+}
+
+// Resolves a document team by ESPN ID, falling back to abbreviation
+func (season *FileSeason) resolveTeam(team models.DocumentTeam) (int, bool) {
+	if id, exists := season.teamsByESPNID[team.ESPNID]; exists && team.ESPNID != "" {
+		return id, true
+	}
+	id, exists := season.teamsByAbbreviation[team.Abbreviation]
+	return id, exists && team.Abbreviation != ""
+}
+
+func isFinal(game models.Game) bool {
+	return game.Status != nil && *game.Status == "final" && game.HomeScore != nil && game.AwayScore != nil
+}
+
+// Gets a game's score from its deciding pick or its real result, like the standings queries do. Picks
+// without predicted scores count as 1-0 wins or 0-0 ties, and games that are neither picked nor final
+// aren't decided.
+func (season *FileSeason) result(game models.Game) (homeScore int, awayScore int, realScores bool, decided bool) {
+	final := isFinal(game)
+	if pick, exists := season.picks[game.ID]; exists && standings.PickDecidesGame(final, pick.isOverride, season.resultMode) {
+		if pick.predictedHomeScore != nil && pick.predictedAwayScore != nil {
+			return *pick.predictedHomeScore, *pick.predictedAwayScore, true, true
+		}
+		switch pick.pickedTeamID {
+		case game.HomeTeamID:
+			return 1, 0, false, true
+		case game.AwayTeamID:
+			return 0, 1, false, true
+		default:
+			return 0, 0, false, true
+		}
+	}
+
+	if final {
+		return *game.HomeScore, *game.AwayScore, true, true
+	}
+	return 0, 0, false, false
+}
+
+func weekOf(game models.Game) int {
+	if game.Week == nil {
+		return 0
+	}
+	return *game.Week
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// Teams and decided games in the form the standings engine takes
+func (season *FileSeason) NFLResults() ([]standings.NFLTeamRecord, []standings.NFLGameResult) {
+	teams := make([]standings.NFLTeamRecord, 0, len(season.Teams))
+	for _, team := range season.Teams {
+		teams = append(teams, standings.NFLTeamRecord{
+			TeamID:             team.ID,
+			TeamCity:           team.City,
+			TeamName:           team.Name,
+			TeamAbbr:           team.Abbreviation,
+			Conference:         stringValue(team.Conference),
+			Division:           stringValue(team.Division),
+			LogoURL:            stringValue(team.LogoURL),
+			TeamPrimaryColor:   team.PrimaryColor,
+			TeamSecondaryColor: team.SecondaryColor,
+		})
+	}
+
+	var games []standings.NFLGameResult
+	for _, game := range season.Games {
+		homeScore, awayScore, _, decided := season.result(game)
+		if !decided {
+			continue
+		}
+		games = append(games, standings.NFLGameResult{
+			GameID:     game.ID,
+			HomeTeamID: game.HomeTeamID,
+			AwayTeamID: game.AwayTeamID,
+			HomeScore:  homeScore,
+			AwayScore:  awayScore,
+			Week:       weekOf(game),
+		})
+	}
+
+	return teams, games
+}
+
+// Teams and decided games in the form the standings engine takes. NBA games can't end tied, so a tie pick
+// without predicted scores leaves its game undecided, like in the standings query.
+func (season *FileSeason) NBAResults() ([]standings.NBATeamRecord, []standings.NBAGameResult) {
+	teams := make([]standings.NBATeamRecord, 0, len(season.Teams))
+	for _, team := range season.Teams {
+		teams = append(teams, standings.NBATeamRecord{
+			TeamID:             team.ID,
+			TeamCity:           team.City,
+			TeamName:           team.Name,
+			TeamAbbr:           team.Abbreviation,
+			Conference:         stringValue(team.Conference),
+			Division:           stringValue(team.Division),
+			LogoURL:            stringValue(team.LogoURL),
+			TeamPrimaryColor:   team.PrimaryColor,
+			TeamSecondaryColor: team.SecondaryColor,
+		})
+	}
+
+	var games []standings.NBAGameResult
+	for _, game := range season.Games {
+		homeScore, awayScore, realScores, decided := season.result(game)
+		if !decided || (!realScores && homeScore == awayScore) {
+			continue
+		}
+		games = append(games, standings.NBAGameResult{
+			GameID:        game.ID,
+			HomeTeamID:    game.HomeTeamID,
+			AwayTeamID:    game.AwayTeamID,
+			HomeScore:     homeScore,
+			AwayScore:     awayScore,
+			Week:          weekOf(game),
+			HasRealScores: realScores,
+		})
+	}
+
+	return teams, games
+}
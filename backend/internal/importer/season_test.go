@@ -0,0 +1,109 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gamescript/internal/models"
+	"gamescript/internal/standings"
+)
+
+func loadNFLFileSeason(t *testing.T) *FileSeason {
+	t.Helper()
+	dataDir := filepath.Join("..", "..", "database")
+	season, err := LoadFileSeason(TeamsFile(dataDir, SportNFL, 2025), ScheduleFile(dataDir, SportNFL, 2025, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return season
+}
+
+// Finds the first game that is or isn't final, as a document reference
+func findDocumentGame(t *testing.T, season *FileSeason, final bool) (models.Game, models.DocumentGame) {
+	t.Helper()
+	reference := func(teamID int) models.DocumentTeam {
+		team := season.Teams[teamID-1]
+		return models.DocumentTeam{ESPNID: team.ESPNID, Abbreviation: team.Abbreviation}
+	}
+	for _, game := range season.Games {
+		if isFinal(game) == final {
+			return game, models.DocumentGame{
+				ESPNID:   game.ESPNID,
+				Week:     game.Week,
+				HomeTeam: reference(game.HomeTeamID),
+				AwayTeam: reference(game.AwayTeamID),
+			}
+		}
+	}
+	t.Fatalf("no game with final = %t", final)
+	return models.Game{}, models.DocumentGame{}
+}
+
+func findResult(games []standings.NFLGameResult, gameID int) *standings.NFLGameResult {
+	for i := range games {
+		if games[i].GameID == gameID {
+			return &games[i]
+		}
+	}
+	return nil
+}
+
+func TestFileSeasonResults(t *testing.T) {
+	season := loadNFLFileSeason(t)
+
+	finals := 0
+	for _, game := range season.Games {
+		if isFinal(game) {
+			finals++
+		}
+	}
+
+	teams, games := season.NFLResults()
+	if len(teams) != 32 {
+		t.Errorf("NFLResults() returned %d teams; want 32", len(teams))
+	}
+	if len(games) != finals {
+		t.Errorf("NFLResults() returned %d games; want the %d final games", len(games), finals)
+	}
+}
+
+func TestFileSeasonApplyPicks(t *testing.T) {
+	season := loadNFLFileSeason(t)
+	upcoming, upcomingGame := findDocumentGame(t, season, false)
+	final, finalGame := findDocumentGame(t, season, true)
+
+	// Pick the away team in an upcoming game, and flip a final game with a 0-0 tie
+	document := &models.ScenarioDocument{
+		Picks: []models.DocumentPick{
+			{Game: upcomingGame, PickedTeam: &upcomingGame.AwayTeam},
+			{Game: finalGame, IsTie: true},
+			{Game: models.DocumentGame{ESPNID: "missing"}, IsTie: true},
+		},
+	}
+
+	unmatched, err := season.ApplyPicks(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unmatched) != 1 {
+		t.Errorf("ApplyPicks() reported %d unmatched picks; want 1", len(unmatched))
+	}
+
+	_, games := season.NFLResults()
+	if result := findResult(games, upcoming.ID); result == nil || result.HomeScore != 0 || result.AwayScore != 1 {
+		t.Errorf("upcoming game result = %+v; want a 0-1 away win", result)
+	}
+	if result := findResult(games, final.ID); result == nil || result.HomeScore != 0 || result.AwayScore != 0 {
+		t.Errorf("final game result = %+v; want the picked 0-0 tie", result)
+	}
+
+	// Honoring real results keeps the final game's score
+	document.Scenario.ResultMode = standings.ResultModeHonorReal
+	if _, err := season.ApplyPicks(document); err != nil {
+		t.Fatal(err)
+	}
+	_, games = season.NFLResults()
+	if result := findResult(games, final.ID); result == nil || result.HomeScore != *final.HomeScore || result.AwayScore != *final.AwayScore {
+		t.Errorf("final game result = %+v; want the real %d-%d", result, *final.HomeScore, *final.AwayScore)
+	}
+}
@@ -0,0 +1,223 @@
+// Loads league games and entries and stores graded results
+
+package leagues
+
+import (
+	"gamescript/internal/database"
+)
+
+
+type storedEntry struct {
+	ID              int
+	LeagueID        int
+	Week            int
+	TiebreakerTotal *int
+}
+
+type storedPick struct {
+	ID      int
+	EntryID int
+	Pick    Pick
+}
+
+// Grades every league entry for a season against the current game results. Grading is
+// recalculated from scratch each time, so corrected scores and reopened games are picked up,
+// but only picks and entries whose results changed are written. Returns the number of entries graded.
+func GradeSeason(db *database.DB, seasonID int) (int, error) {
+	games, err := getSeasonGames(db, seasonID)
+	if err != nil {
+		return 0, err
+	}
+
+	gamesByID := make(map[int]Game)
+	gamesByWeek := make(map[int][]Game)
+	for _, game := range games {
+		gamesByID[game.ID] = game
+		gamesByWeek[game.Week] = append(gamesByWeek[game.Week], game)
+	}
+
+	scoring := make(map[int]string)
+	useTiebreaker := make(map[int]bool)
+	rows, err := db.Query(`SELECT id, scoring, use_tiebreaker FROM leagues WHERE season_id = $1`, seasonID)
+	if err != nil {
+		return 0, err
+	}
+	for rows.Next() {
+		var id int
+		var mode string
+		var tiebreaker bool
+		if err := rows.Scan(&id, &mode, &tiebreaker); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		scoring[id] = mode
+		useTiebreaker[id] = tiebreaker
+	}
+	rows.Close()
+	if len(scoring) == 0 {
+		return 0, nil
+	}
+
+	entries, err := getSeasonEntries(db, seasonID)
+	if err != nil {
+		return 0, err
+	}
+	picks, err := getSeasonPicks(db, seasonID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.Conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	totals := make(map[int]*EntryResult)
+	leagueOfEntry := make(map[int]int)
+	for _, entry := range entries {
+		totals[entry.ID] = &EntryResult{}
+		leagueOfEntry[entry.ID] = entry.LeagueID
+	}
+
+	for _, pick := range picks {
+		game, exists := gamesByID[pick.Pick.GameID]
+		if !exists {
+			continue
+		}
+
+		result := GradePick(scoring[leagueOfEntry[pick.EntryID]], pick.Pick, game)
+		if !result.Graded {
+			_, err = tx.Exec(`
+				UPDATE league_picks SET is_correct = NULL, points = NULL
+				WHERE id = $1 AND (is_correct IS NOT NULL OR points IS NOT NULL)
+			`, pick.ID)
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		_, err = tx.Exec(`
+			UPDATE league_picks SET is_correct = $1, points = $2
+			WHERE id = $3 AND (is_correct, points) IS DISTINCT FROM ($1, $2)
+		`, result.IsCorrect, result.Points, pick.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		total := totals[pick.EntryID]
+		total.GradedPicks++
+		total.Points += result.Points
+		if result.IsCorrect {
+			total.CorrectPicks++
+		}
+	}
+
+	for _, entry := range entries {
+		total := totals[entry.ID]
+		if useTiebreaker[entry.LeagueID] {
+			total.TiebreakerDiff = TiebreakerDiff(entry.TiebreakerTotal, TiebreakerGame(gamesByWeek[entry.Week]))
+		}
+
+		_, err = tx.Exec(`
+			UPDATE league_entries
+			SET points = $1, correct_picks = $2, graded_picks = $3, tiebreaker_diff = $4, graded_at = NOW()
+			WHERE id = $5 AND (graded_at IS NULL OR (points, correct_picks, graded_picks, tiebreaker_diff) IS DISTINCT FROM ($1, $2, $3, $4))
+		`, total.Points, total.CorrectPicks, total.GradedPicks, total.TiebreakerDiff, entry.ID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(entries), nil
+}
+
+// Gets a week's games in start order
+func GetWeekGames(db *database.DB, seasonID int, week int) ([]Game, error) {
+	return queryGames(db, `season_id = $1 AND week = $2`, seasonID, week)
+}
+
+func getSeasonGames(db *database.DB, seasonID int) ([]Game, error) {
+	return queryGames(db, `season_id = $1 AND week IS NOT NULL`, seasonID)
+}
+
+func queryGames(db *database.DB, where string, args ...interface{}) ([]Game, error) {
+	rows, err := db.Query(`
+		SELECT id, week, start_time, home_team_id, away_team_id, home_score, away_score, status
+		FROM games
+		WHERE `+where+`
+		ORDER BY start_time, id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []Game
+	for rows.Next() {
+		var game Game
+		var status *string
+		err := rows.Scan(&game.ID, &game.Week, &game.StartTime, &game.HomeTeamID, &game.AwayTeamID, &game.HomeScore, &game.AwayScore, &status)
+		if err != nil {
+			return nil, err
+		}
+		game.IsFinal = status != nil && *status == "final"
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
+
+func getSeasonEntries(db *database.DB, seasonID int) ([]storedEntry, error) {
+	rows, err := db.Query(`
+		SELECT entry.id, entry.league_id, entry.week, entry.tiebreaker_total
+		FROM league_entries entry
+		JOIN leagues league ON entry.league_id = league.id
+		WHERE league.season_id = $1
+	`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []storedEntry
+	for rows.Next() {
+		var entry storedEntry
+		if err := rows.Scan(&entry.ID, &entry.LeagueID, &entry.Week, &entry.TiebreakerTotal); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func getSeasonPicks(db *database.DB, seasonID int) ([]storedPick, error) {
+	rows, err := db.Query(`
+		SELECT pick.id, pick.entry_id, pick.game_id, pick.picked_team_id, pick.confidence
+		FROM league_picks pick
+		JOIN league_entries entry ON pick.entry_id = entry.id
+		JOIN leagues league ON entry.league_id = league.id
+		WHERE league.season_id = $1
+	`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var picks []storedPick
+	for rows.Next() {
+		var pick storedPick
+		if err := rows.Scan(&pick.ID, &pick.EntryID, &pick.Pick.GameID, &pick.Pick.PickedTeamID, &pick.Pick.Confidence); err != nil {
+			return nil, err
+		}
+		picks = append(picks, pick)
+	}
+
+	return picks, rows.Err()
+}
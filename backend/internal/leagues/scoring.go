@@ -0,0 +1,255 @@
+// Pick'em league scoring, confidence validation, and weekly and season rankings
+
+package leagues
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+
+// League scoring modes
+const (
+	ScoringStraightUp = "straight_up" // One point per correct pick
+	ScoringConfidence = "confidence"  // Each pick is worth its confidence value when correct
+)
+
+// Real game a league pick is made on
+type Game struct {
+	ID         int
+	Week       int
+	StartTime  time.Time
+	HomeTeamID int
+	AwayTeamID int
+	HomeScore  *int
+	AwayScore  *int
+	IsFinal    bool
+}
+
+// Member's pick for a single game
+type Pick struct {
+	GameID       int
+	PickedTeamID int
+	Confidence   *int
+}
+
+// Graded pick; Graded is false until the game is final
+type PickResult struct {
+	GameID    int
+	Graded    bool
+	IsCorrect bool
+	Points    int
+}
+
+// Graded totals for one member's entry in one week
+type EntryResult struct {
+	MemberID       int
+	Username       string
+	Points         int
+	CorrectPicks   int
+	GradedPicks    int
+	TiebreakerDiff *int
+}
+
+// Ranked entry in a week
+type WeekStanding struct {
+	EntryResult
+	Rank int
+}
+
+// Ranked member across every week of a season
+type SeasonStanding struct {
+	MemberID     int
+	Username     string
+	Rank         int
+	Points       int
+	CorrectPicks int
+	GradedPicks  int
+	WeeksPlayed  int
+	WeeksWon     int
+}
+
+func IsValidScoring(scoring string) bool {
+	return scoring == ScoringStraightUp || scoring == ScoringConfidence
+}
+
+// Whether a game has started, after which picks on it can't change
+func IsLocked(game Game, now time.Time) bool {
+	return !now.Before(game.StartTime)
+}
+
+// Grades a pick against a game. Ties are wrong for every pick, since pick'em picks a winner.
+func GradePick(scoring string, pick Pick, game Game) PickResult {
+	result := PickResult{GameID: pick.GameID}
+	if !game.IsFinal || game.HomeScore == nil || game.AwayScore == nil {
+		return result
+	}
+
+	result.Graded = true
+	winner := 0
+	if *game.HomeScore > *game.AwayScore {
+		winner = game.HomeTeamID
+	} else if *game.AwayScore > *game.HomeScore {
+		winner = game.AwayTeamID
+	}
+	result.IsCorrect = winner != 0 && pick.PickedTeamID == winner
+
+	if result.IsCorrect {
+		result.Points = 1
+		if scoring == ScoringConfidence && pick.Confidence != nil {
+			result.Points = *pick.Confidence
+		}
+	}
+
+	return result
+}
+
+// Checks that confidence values are present and distinct, from 1 up to the number of games in the week
+func ValidateConfidence(picks []Pick, gameCount int) error {
+	used := make(map[int]int)
+	for _, pick := range picks {
+		if pick.Confidence == nil {
+			return fmt.Errorf("Confidence is required for game %d", pick.GameID)
+		}
+		confidence := *pick.Confidence
+		if confidence < 1 || confidence > gameCount {
+			return fmt.Errorf("Confidence for game %d must be between 1 and %d", pick.GameID, gameCount)
+		}
+		if other, exists := used[confidence]; exists {
+			return fmt.Errorf("Confidence %d is used for both game %d and game %d", confidence, other, pick.GameID)
+		}
+		used[confidence] = pick.GameID
+	}
+	return nil
+}
+
+// Tiebreaker game for a week: the last game to start, by start time then ID
+func TiebreakerGame(games []Game) *Game {
+	var last *Game
+	for i := range games {
+		game := &games[i]
+		if last == nil || game.StartTime.After(last.StartTime) || (game.StartTime.Equal(last.StartTime) && game.ID > last.ID) {
+			last = game
+		}
+	}
+	return last
+}
+
+// Distance between a predicted total and the tiebreaker game's final combined score, or nil until it is known
+func TiebreakerDiff(predictedTotal *int, game *Game) *int {
+	if predictedTotal == nil || game == nil || !game.IsFinal || game.HomeScore == nil || game.AwayScore == nil {
+		return nil
+	}
+	diff := *predictedTotal - (*game.HomeScore + *game.AwayScore)
+	if diff < 0 {
+		diff = -diff
+	}
+	return &diff
+}
+
+// Ranks a week's entries by points, then the closest tiebreaker, then correct picks.
+// Entries equal on all three share a rank.
+func RankWeek(results []EntryResult) []WeekStanding {
+	standings := make([]WeekStanding, len(results))
+	for i, result := range results {
+		standings[i] = WeekStanding{EntryResult: result}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if compare := compareEntries(standings[i].EntryResult, standings[j].EntryResult); compare != 0 {
+			return compare < 0
+		}
+		return standings[i].Username < standings[j].Username
+	})
+
+	for i := range standings {
+		if i > 0 && compareEntries(standings[i-1].EntryResult, standings[i].EntryResult) == 0 {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+
+	return standings
+}
+
+// Ranks members across a season by total points, then correct picks, then weeks won.
+// Weeks are only counted as won once complete, and every entry ranked first in a week wins it.
+func RankSeason(weeks map[int][]EntryResult, completeWeeks map[int]bool) []SeasonStanding {
+	byMember := make(map[int]*SeasonStanding)
+	for week, results := range weeks {
+		for _, result := range results {
+			standing, exists := byMember[result.MemberID]
+			if !exists {
+				standing = &SeasonStanding{MemberID: result.MemberID, Username: result.Username}
+				byMember[result.MemberID] = standing
+			}
+			standing.Points += result.Points
+			standing.CorrectPicks += result.CorrectPicks
+			standing.GradedPicks += result.GradedPicks
+			standing.WeeksPlayed++
+		}
+
+		if !completeWeeks[week] {
+			continue
+		}
+		for _, weekStanding := range RankWeek(results) {
+			if weekStanding.Rank == 1 {
+				byMember[weekStanding.MemberID].WeeksWon++
+			}
+		}
+	}
+
+	standings := make([]SeasonStanding, 0, len(byMember))
+	for _, standing := range byMember {
+		standings = append(standings, *standing)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		if compare := compareSeasonStandings(standings[i], standings[j]); compare != 0 {
+			return compare < 0
+		}
+		return standings[i].Username < standings[j].Username
+	})
+
+	for i := range standings {
+		if i > 0 && compareSeasonStandings(standings[i-1], standings[i]) == 0 {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+
+	return standings
+}
+
+// Negative when a ranks ahead of b, zero when they are tied on every criterion
+func compareEntries(a EntryResult, b EntryResult) int {
+	if a.Points != b.Points {
+		return b.Points - a.Points
+	}
+	if a.TiebreakerDiff != nil || b.TiebreakerDiff != nil {
+		if a.TiebreakerDiff == nil {
+			return 1
+		}
+		if b.TiebreakerDiff == nil {
+			return -1
+		}
+		if *a.TiebreakerDiff != *b.TiebreakerDiff {
+			return *a.TiebreakerDiff - *b.TiebreakerDiff
+		}
+	}
+	return b.CorrectPicks - a.CorrectPicks
+}
+
+// Negative when a ranks ahead of b across the season, zero when they are tied
+func compareSeasonStandings(a SeasonStanding, b SeasonStanding) int {
+	if a.Points != b.Points {
+		return b.Points - a.Points
+	}
+	if a.CorrectPicks != b.CorrectPicks {
+		return b.CorrectPicks - a.CorrectPicks
+	}
+	return b.WeeksWon - a.WeeksWon
+}
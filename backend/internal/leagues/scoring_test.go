@@ -0,0 +1,198 @@
+package leagues
+
+import (
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func finalGame(id int, homeScore int, awayScore int) Game {
+	return Game{ID: id, HomeTeamID: 1, AwayTeamID: 2, HomeScore: intPtr(homeScore), AwayScore: intPtr(awayScore), IsFinal: true}
+}
+
+func TestGradePick(t *testing.T) {
+	tests := []struct {
+		name            string
+		scoring         string
+		pick            Pick
+		game            Game
+		expectedGraded  bool
+		expectedCorrect bool
+		expectedPoints  int
+	}{
+		{
+			name:           "Game not final",
+			scoring:        ScoringStraightUp,
+			pick:           Pick{GameID: 1, PickedTeamID: 1},
+			game:           Game{ID: 1, HomeTeamID: 1, AwayTeamID: 2},
+			expectedGraded: false,
+		},
+		{
+			name:            "Straight up correct",
+			scoring:         ScoringStraightUp,
+			pick:            Pick{GameID: 1, PickedTeamID: 1, Confidence: intPtr(9)},
+			game:            finalGame(1, 24, 17),
+			expectedGraded:  true,
+			expectedCorrect: true,
+			expectedPoints:  1,
+		},
+		{
+			name:            "Straight up wrong",
+			scoring:         ScoringStraightUp,
+			pick:            Pick{GameID: 1, PickedTeamID: 1},
+			game:            finalGame(1, 10, 17),
+			expectedGraded:  true,
+			expectedCorrect: false,
+			expectedPoints:  0,
+		},
+		{
+			name:            "Confidence correct",
+			scoring:         ScoringConfidence,
+			pick:            Pick{GameID: 1, PickedTeamID: 2, Confidence: intPtr(7)},
+			game:            finalGame(1, 10, 17),
+			expectedGraded:  true,
+			expectedCorrect: true,
+			expectedPoints:  7,
+		},
+		{
+			name:            "Tie is wrong for every pick",
+			scoring:         ScoringConfidence,
+			pick:            Pick{GameID: 1, PickedTeamID: 1, Confidence: intPtr(7)},
+			game:            finalGame(1, 20, 20),
+			expectedGraded:  true,
+			expectedCorrect: false,
+			expectedPoints:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GradePick(tt.scoring, tt.pick, tt.game)
+			if result.Graded != tt.expectedGraded || result.IsCorrect != tt.expectedCorrect || result.Points != tt.expectedPoints {
+				t.Errorf("GradePick() = %+v, expected graded %v, correct %v, points %d",
+					result, tt.expectedGraded, tt.expectedCorrect, tt.expectedPoints)
+			}
+		})
+	}
+}
+
+func TestValidateConfidence(t *testing.T) {
+	tests := []struct {
+		name      string
+		picks     []Pick
+		gameCount int
+		expectErr bool
+	}{
+		{
+			name:      "Distinct values in range",
+			picks:     []Pick{{GameID: 1, Confidence: intPtr(1)}, {GameID: 2, Confidence: intPtr(3)}},
+			gameCount: 3,
+			expectErr: false,
+		},
+		{
+			name:      "Missing confidence",
+			picks:     []Pick{{GameID: 1}},
+			gameCount: 3,
+			expectErr: true,
+		},
+		{
+			name:      "Out of range",
+			picks:     []Pick{{GameID: 1, Confidence: intPtr(4)}},
+			gameCount: 3,
+			expectErr: true,
+		},
+		{
+			name:      "Duplicate values",
+			picks:     []Pick{{GameID: 1, Confidence: intPtr(2)}, {GameID: 2, Confidence: intPtr(2)}},
+			gameCount: 3,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConfidence(tt.picks, tt.gameCount)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("ValidateConfidence() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestTiebreaker(t *testing.T) {
+	start := time.Date(2025, 9, 7, 17, 0, 0, 0, time.UTC)
+	games := []Game{
+		{ID: 3, StartTime: start.Add(3 * time.Hour), HomeScore: intPtr(30), AwayScore: intPtr(21), IsFinal: true},
+		{ID: 1, StartTime: start},
+		{ID: 2, StartTime: start.Add(3 * time.Hour)},
+	}
+
+	game := TiebreakerGame(games)
+	if game == nil || game.ID != 3 {
+		t.Fatalf("TiebreakerGame() = %+v, expected game 3", game)
+	}
+
+	if diff := TiebreakerDiff(intPtr(45), game); diff == nil || *diff != 6 {
+		t.Errorf("TiebreakerDiff() = %v, expected 6", diff)
+	}
+	if diff := TiebreakerDiff(nil, game); diff != nil {
+		t.Errorf("TiebreakerDiff() without a prediction = %v, expected nil", *diff)
+	}
+	if diff := TiebreakerDiff(intPtr(45), &games[1]); diff != nil {
+		t.Errorf("TiebreakerDiff() before the game is final = %v, expected nil", *diff)
+	}
+}
+
+func TestRankWeek(t *testing.T) {
+	results := []EntryResult{
+		{MemberID: 1, Username: "alex", Points: 10, CorrectPicks: 10, TiebreakerDiff: intPtr(8)},
+		{MemberID: 2, Username: "blake", Points: 12, CorrectPicks: 12},
+		{MemberID: 3, Username: "casey", Points: 10, CorrectPicks: 10, TiebreakerDiff: intPtr(3)},
+		{MemberID: 4, Username: "drew", Points: 10, CorrectPicks: 10},
+		{MemberID: 5, Username: "emery", Points: 10, CorrectPicks: 10},
+	}
+
+	standings := RankWeek(results)
+
+	expectedOrder := []int{2, 3, 1, 4, 5}
+	expectedRanks := []int{1, 2, 3, 4, 4}
+	for i, standing := range standings {
+		if standing.MemberID != expectedOrder[i] || standing.Rank != expectedRanks[i] {
+			t.Errorf("position %d = member %d rank %d, expected member %d rank %d",
+				i, standing.MemberID, standing.Rank, expectedOrder[i], expectedRanks[i])
+		}
+	}
+}
+
+func TestRankSeason(t *testing.T) {
+	weeks := map[int][]EntryResult{
+		1: {
+			{MemberID: 1, Username: "alex", Points: 10, CorrectPicks: 10},
+			{MemberID: 2, Username: "blake", Points: 8, CorrectPicks: 8},
+		},
+		2: {
+			{MemberID: 1, Username: "alex", Points: 6, CorrectPicks: 6},
+			{MemberID: 2, Username: "blake", Points: 9, CorrectPicks: 9},
+		},
+		3: {
+			{MemberID: 2, Username: "blake", Points: 3, CorrectPicks: 3},
+		},
+	}
+	completeWeeks := map[int]bool{1: true, 2: true}
+
+	standings := RankSeason(weeks, completeWeeks)
+	if len(standings) != 2 {
+		t.Fatalf("expected 2 standings, got %d", len(standings))
+	}
+
+	blake, alex := standings[0], standings[1]
+	if blake.MemberID != 2 || blake.Points != 20 || blake.WeeksPlayed != 3 || blake.WeeksWon != 1 || blake.Rank != 1 {
+		t.Errorf("first = %+v, expected blake with 20 points over 3 weeks and 1 week won", blake)
+	}
+	if alex.MemberID != 1 || alex.Points != 16 || alex.WeeksPlayed != 2 || alex.WeeksWon != 1 || alex.Rank != 2 {
+		t.Errorf("second = %+v, expected alex with 16 points over 2 weeks and 1 week won", alex)
+	}
+}
@@ -11,6 +11,8 @@ import (
 
     "github.com/gofiber/fiber/v2"
     "github.com/golang-jwt/jwt/v5"
+
+    "gamescript/internal/apperror"
 )
 
 type Claims struct {
@@ -24,13 +26,13 @@ func AuthMiddleware(c *fiber.Ctx) error {
     // Get token from Authorization header
     authHeader := c.Get("Authorization")
     if authHeader == "" {
-        return c.Status(401).JSON(fiber.Map{"error": "Missing authorization token"})
+        return apperror.New(apperror.AuthenticationRequired, "Missing authorization token")
     }
 
     // Extract token from "Bearer <token>"
     tokenString := strings.TrimPrefix(authHeader, "Bearer ")
     if tokenString == authHeader {
-        return c.Status(401).JSON(fiber.Map{"error": "Invalid authorization format. Use 'Bearer <token>'"})
+        return apperror.New(apperror.InvalidToken, "Invalid authorization format. Use 'Bearer <token>'")
     }
 
     // Get JWT secret
@@ -49,19 +51,19 @@ func AuthMiddleware(c *fiber.Ctx) error {
     })
     if err != nil {
         log.Printf("Token parsing error: %v", err)
-        return c.Status(401).JSON(fiber.Map{"error": "Invalid or expired token"})
+        return apperror.New(apperror.InvalidToken, "Invalid or expired token")
     }
 
     // Extract claims
     claims, ok := token.Claims.(*Claims)
     if !ok || !token.Valid {
         log.Printf("Invalid token claims or token not valid")
-        return c.Status(401).JSON(fiber.Map{"error": "Invalid token claims"})
+        return apperror.New(apperror.InvalidToken, "Invalid token claims")
     }
 
     // Check if token is expired
     // if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
-    //     return c.Status(401).JSON(fiber.Map{"error": "Token has expired"})
+    //     return apperror.New(apperror.InvalidToken, "Token has expired")
     // }
 
     // Store user info in context
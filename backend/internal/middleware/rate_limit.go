@@ -7,6 +7,8 @@ import (
     "time"
 
     "github.com/gofiber/fiber/v2"
+
+    "gamescript/internal/apperror"
 )
 
 
@@ -68,9 +70,7 @@ func RateLimitAuth(maxRequests int, window time.Duration) fiber.Handler {
         // Check if limit exceeded
         if v.count > maxRequests {
             mu.Unlock()
-            return c.Status(429).JSON(fiber.Map{
-                "error": "Too many requests. Please try again later.",
-            })
+            return apperror.New(apperror.RateLimited, "Too many requests. Please try again later.")
         }
         
         mu.Unlock()
@@ -0,0 +1,338 @@
+// Embedded, ordered schema migrations tracked in a schema_migrations table
+
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gamescript/internal/database"
+)
+
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// Arbitrary key for the advisory lock that keeps two processes from migrating at once
+const lockKey = 4362117
+
+// Migration file names look like 0001_create_core_tables.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Unknown   bool // Applied to the database but not embedded in this build
+}
+
+// Returns every embedded migration ordered by version
+func All() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %v", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		version, name, direction, err := parseFileName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := files.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %v", entry.Name(), err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func parseFileName(fileName string) (int, string, string, error) {
+	match := fileNamePattern.FindStringSubmatch(fileName)
+	if match == nil {
+		return 0, "", "", fmt.Errorf("invalid migration file name %s, expected <version>_<name>.up.sql or .down.sql", fileName)
+	}
+
+	version, err := strconv.Atoi(match[1])
+	if err != nil || version < 1 {
+		return 0, "", "", fmt.Errorf("invalid migration version in %s", fileName)
+	}
+
+	return version, match[2], match[3], nil
+}
+
+// Applies every pending migration in order, each in its own transaction
+func Up(db *database.DB) ([]Migration, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	err = withLock(db, func(conn *sql.Conn) error {
+		appliedAt, err := getAppliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if _, exists := appliedAt[migration.Version]; exists {
+				continue
+			}
+
+			err := runInTransaction(conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %v", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Rolls back the most recently applied migrations, newest first
+func Down(db *database.DB, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1")
+	}
+
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+
+	var rolledBack []Migration
+	err = withLock(db, func(conn *sql.Conn) error {
+		appliedAt, err := getAppliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			migration := migrations[i]
+			if _, exists := appliedAt[migration.Version]; !exists {
+				continue
+			}
+
+			err := runInTransaction(conn, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %d_%s: %v", migration.Version, migration.Name, err)
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// Lists every migration, embedded or applied, with whether and when it was applied
+func GetStatus(db *database.DB) ([]Status, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	err = withConn(db, func(conn *sql.Conn) error {
+		if err := createMigrationsTable(conn); err != nil {
+			return err
+		}
+
+		appliedAt, err := getAppliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int]bool)
+		for _, migration := range migrations {
+			known[migration.Version] = true
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if at, exists := appliedAt[migration.Version]; exists {
+				status.Applied = true
+				status.AppliedAt = &at
+			}
+			statuses = append(statuses, status)
+		}
+
+		// Versions applied by a newer build
+		rows, err := conn.QueryContext(context.Background(), "SELECT version, name, applied_at FROM schema_migrations ORDER BY version")
+		if err != nil {
+			return fmt.Errorf("failed to query applied migrations: %v", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var status Status
+			var at time.Time
+			if err := rows.Scan(&status.Version, &status.Name, &at); err != nil {
+				return fmt.Errorf("failed to scan applied migration: %v", err)
+			}
+			if !known[status.Version] {
+				status.Applied = true
+				status.AppliedAt = &at
+				status.Unknown = true
+				statuses = append(statuses, status)
+			}
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// Returns embedded migrations that haven't been applied yet
+func Pending(db *database.DB) ([]Migration, error) {
+	statuses, err := GetStatus(db)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[int]bool)
+	for _, status := range statuses {
+		applied[status.Version] = status.Applied
+	}
+
+	var pending []Migration
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Runs fn on a single connection holding the migration lock. The lock is session-scoped, so every
+// statement has to go through the same connection rather than the pool.
+func withLock(db *database.DB, fn func(conn *sql.Conn) error) error {
+	return withConn(db, func(conn *sql.Conn) error {
+		ctx := context.Background()
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %v", err)
+		}
+		defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockKey)
+
+		if err := createMigrationsTable(conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func withConn(db *database.DB, fn func(conn *sql.Conn) error) error {
+	conn, err := db.Conn.Conn(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %v", err)
+	}
+	defer conn.Close()
+
+	return fn(conn)
+}
+
+func createMigrationsTable(conn *sql.Conn) error {
+	_, err := conn.ExecContext(context.Background(), `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %v", err)
+	}
+	return nil
+}
+
+func getAppliedVersions(conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(context.Background(), "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %v", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// Runs a migration script and its schema_migrations bookkeeping together, so a failed script leaves no trace
+func runInTransaction(conn *sql.Conn, script string, bookkeeping string, args ...interface{}) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		fileName  string
+		version   int
+		name      string
+		direction string
+		valid     bool
+	}{
+		{"0001_create_core_tables.up.sql", 1, "create_core_tables", "up", true},
+		{"0012_add_index.down.sql", 12, "add_index", "down", true},
+		{"0000_zero.up.sql", 0, "", "", false},
+		{"create_core_tables.up.sql", 0, "", "", false},
+		{"0001_create_core_tables.sql", 0, "", "", false},
+		{"0001_create core tables.up.sql", 0, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			version, name, direction, err := parseFileName(tt.fileName)
+			if (err == nil) != tt.valid {
+				t.Fatalf("parseFileName(%q) error = %v; want valid %v", tt.fileName, err, tt.valid)
+			}
+			if version != tt.version || name != tt.name || direction != tt.direction {
+				t.Errorf("parseFileName(%q) = %d, %q, %q; want %d, %q, %q",
+					tt.fileName, version, name, direction, tt.version, tt.name, tt.direction)
+			}
+		})
+	}
+}
+
+func TestAllMigrations(t *testing.T) {
+	migrations, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	for i, migration := range migrations {
+		// Versions count up from 1 without gaps, so a missing file shows up here
+		if migration.Version != i+1 {
+			t.Errorf("migration %d_%s has version %d; want %d", migration.Version, migration.Name, migration.Version, i+1)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s has an empty up or down script", migration.Version, migration.Name)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS playoff_matchups;
+DROP TABLE IF EXISTS playoff_series;
+DROP TABLE IF EXISTS playoff_states;
+DROP TABLE IF EXISTS picks;
+DROP TABLE IF EXISTS scenarios;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS games;
+DROP TABLE IF EXISTS teams;
+DROP TABLE IF EXISTS seasons;
+DROP TABLE IF EXISTS sports;
@@ -1,5 +1,7 @@
+-- Tables from the original schema. IF NOT EXISTS lets databases created from schema.sql adopt migrations.
+
 -- SPORTS
-CREATE TABLE sports (
+CREATE TABLE IF NOT EXISTS sports (
     id SERIAL PRIMARY KEY,
     name VARCHAR(100) NOT NULL UNIQUE,
     short_name VARCHAR(10) NOT NULL,
@@ -7,7 +9,7 @@ CREATE TABLE sports (
 );
 
 -- SEASONS
-CREATE TABLE seasons (
+CREATE TABLE IF NOT EXISTS seasons (
     id SERIAL PRIMARY KEY,
     sport_id INTEGER NOT NULL REFERENCES sports(id) ON DELETE CASCADE,
     start_year INTEGER NOT NULL,
@@ -17,7 +19,7 @@ CREATE TABLE seasons (
 );
 
 -- TEAMS
-CREATE TABLE teams (
+CREATE TABLE IF NOT EXISTS teams (
     id SERIAL PRIMARY KEY,
     sport_id INTEGER NOT NULL REFERENCES sports(id) ON DELETE CASCADE,
     season_id INTEGER NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
@@ -36,7 +38,7 @@ CREATE TABLE teams (
 );
 
 -- GAMES
-CREATE TABLE games (
+CREATE TABLE IF NOT EXISTS games (
     id SERIAL PRIMARY KEY,
     season_id INTEGER NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
     espn_id VARCHAR(32),
@@ -56,15 +58,13 @@ CREATE TABLE games (
 );
 
 -- USERS
-CREATE TABLE users (
+CREATE TABLE IF NOT EXISTS users (
     id SERIAL PRIMARY KEY,
     email VARCHAR(255) NOT NULL UNIQUE,
     username VARCHAR(50) NOT NULL UNIQUE,
     password_hash VARCHAR(255) NOT NULL,
     is_admin BOOLEAN DEFAULT FALSE,
     avatar_url VARCHAR(255),
-    failed_login_attempts INTEGER DEFAULT 0,
-    locked_until TIMESTAMPTZ,
     last_login TIMESTAMPTZ,
     password_changed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
     created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
@@ -72,20 +72,19 @@ CREATE TABLE users (
 );
 
 -- SCENARIOS
-CREATE TABLE scenarios (
+CREATE TABLE IF NOT EXISTS scenarios (
     id SERIAL PRIMARY KEY,
     user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
     name VARCHAR(100) NOT NULL,
     sport_id INTEGER NOT NULL REFERENCES sports(id) ON DELETE CASCADE,
     season_id INTEGER NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
     is_public BOOLEAN DEFAULT TRUE,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    session_token VARCHAR(255)
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
 );
 
 -- PICKS
-CREATE TABLE picks (
+CREATE TABLE IF NOT EXISTS picks (
     id SERIAL PRIMARY KEY,
     scenario_id INTEGER NOT NULL REFERENCES scenarios(id) ON DELETE CASCADE,
     game_id INTEGER NOT NULL REFERENCES games(id) ON DELETE CASCADE,
@@ -99,7 +98,7 @@ CREATE TABLE picks (
 );
 
 -- PLAYOFF STATES
-CREATE TABLE playoff_states (
+CREATE TABLE IF NOT EXISTS playoff_states (
     id SERIAL PRIMARY KEY,
     scenario_id INTEGER NOT NULL REFERENCES scenarios(id) ON DELETE CASCADE,
     current_round INTEGER DEFAULT 0,
@@ -110,7 +109,7 @@ CREATE TABLE playoff_states (
 );
 
 -- PLAYOFF SERIES
-CREATE TABLE playoff_series (
+CREATE TABLE IF NOT EXISTS playoff_series (
     id SERIAL PRIMARY KEY,
     playoff_state_id INTEGER NOT NULL REFERENCES playoff_states(id) ON DELETE CASCADE,
     round INTEGER NOT NULL,
@@ -131,7 +130,7 @@ CREATE TABLE playoff_series (
 );
 
 -- PLAYOFF MATCHUPS
-CREATE TABLE playoff_matchups (
+CREATE TABLE IF NOT EXISTS playoff_matchups (
     id SERIAL PRIMARY KEY,
     playoff_state_id INTEGER NOT NULL REFERENCES playoff_states(id) ON DELETE CASCADE,
     playoff_series_id INTEGER REFERENCES playoff_series(id) ON DELETE CASCADE,
@@ -152,16 +151,14 @@ CREATE TABLE playoff_matchups (
     UNIQUE(playoff_state_id, round, matchup_order, conference, game_number)
 );
 
--- Indexes for performance optimization
-CREATE INDEX idx_games_season ON games(season_id);
-CREATE INDEX idx_teams_sport ON teams(sport_id);
-CREATE INDEX idx_scenarios_user_id ON scenarios(user_id);
-CREATE INDEX idx_picks_scenario ON picks(scenario_id);
-CREATE INDEX idx_picks_game ON picks(game_id);
-CREATE INDEX idx_scenarios_session_token ON scenarios(session_token);
-CREATE INDEX idx_playoff_states_scenario ON playoff_states(scenario_id);
-CREATE INDEX idx_playoff_series_state ON playoff_series(playoff_state_id);
-CREATE INDEX idx_playoff_series_round ON playoff_series(playoff_state_id, round);
-CREATE INDEX idx_playoff_matchups_state ON playoff_matchups(playoff_state_id);
-CREATE INDEX idx_playoff_matchups_round ON playoff_matchups(playoff_state_id, round);
-CREATE INDEX idx_playoff_matchups_series ON playoff_matchups(playoff_series_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_games_season ON games(season_id);
+CREATE INDEX IF NOT EXISTS idx_teams_sport ON teams(sport_id);
+CREATE INDEX IF NOT EXISTS idx_scenarios_user_id ON scenarios(user_id);
+CREATE INDEX IF NOT EXISTS idx_picks_scenario ON picks(scenario_id);
+CREATE INDEX IF NOT EXISTS idx_picks_game ON picks(game_id);
+CREATE INDEX IF NOT EXISTS idx_playoff_states_scenario ON playoff_states(scenario_id);
+CREATE INDEX IF NOT EXISTS idx_playoff_series_state ON playoff_series(playoff_state_id);
+CREATE INDEX IF NOT EXISTS idx_playoff_series_round ON playoff_series(playoff_state_id, round);
+CREATE INDEX IF NOT EXISTS idx_playoff_matchups_state ON playoff_matchups(playoff_state_id);
+CREATE INDEX IF NOT EXISTS idx_playoff_matchups_round ON playoff_matchups(playoff_state_id, round);
+CREATE INDEX IF NOT EXISTS idx_playoff_matchups_series ON playoff_matchups(playoff_series_id);
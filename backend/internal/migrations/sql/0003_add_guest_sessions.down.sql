@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scenarios_session_token;
+ALTER TABLE scenarios DROP COLUMN IF EXISTS session_token;
@@ -0,0 +1,4 @@
+-- Guest scenarios are owned by a session token instead of a user
+ALTER TABLE scenarios ADD COLUMN IF NOT EXISTS session_token VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_scenarios_session_token ON scenarios(session_token);
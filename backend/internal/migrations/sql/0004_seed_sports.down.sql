@@ -0,0 +1 @@
+-- Seed data is left in place, since deleting a sport cascades to every season, team, game, and scenario under it
@@ -0,0 +1,6 @@
+-- Sports, safe to rerun against a database seeded from sports.sql
+INSERT INTO sports (name, short_name) VALUES
+    ('National Football League', 'NFL'),
+    ('National Basketball Association', 'NBA'),
+    ('College Football', 'CFB')
+ON CONFLICT (name) DO NOTHING;
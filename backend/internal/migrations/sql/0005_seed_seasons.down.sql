@@ -0,0 +1 @@
+-- Seed data is left in place, since deleting a season cascades to its teams, games, and scenarios
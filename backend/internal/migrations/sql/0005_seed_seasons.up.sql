@@ -0,0 +1,8 @@
+-- Active 2025-26 seasons, safe to rerun against a database seeded from seasons.sql
+INSERT INTO seasons (sport_id, start_year, end_year, is_active)
+SELECT sports.id, 2025, 2026, TRUE
+FROM sports
+WHERE sports.short_name IN ('NFL', 'NBA', 'CFB')
+  AND NOT EXISTS (
+      SELECT 1 FROM seasons WHERE seasons.sport_id = sports.id AND seasons.start_year = 2025
+  );
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS game_win_probabilities;
+DROP TABLE IF EXISTS team_ratings;
@@ -0,0 +1,24 @@
+-- TEAM RATINGS
+CREATE TABLE IF NOT EXISTS team_ratings (
+    id SERIAL PRIMARY KEY,
+    season_id INTEGER NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    rating DOUBLE PRECISION NOT NULL,
+    preseason_rating DOUBLE PRECISION NOT NULL,
+    games_played INTEGER DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(team_id)
+);
+
+-- GAME WIN PROBABILITIES
+CREATE TABLE IF NOT EXISTS game_win_probabilities (
+    game_id INTEGER PRIMARY KEY REFERENCES games(id) ON DELETE CASCADE,
+    season_id INTEGER NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    home_rating DOUBLE PRECISION NOT NULL,
+    away_rating DOUBLE PRECISION NOT NULL,
+    home_win_probability DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_ratings_season ON team_ratings(season_id);
+CREATE INDEX IF NOT EXISTS idx_game_win_probabilities_season ON game_win_probabilities(season_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scenario_snapshots;
+DROP TABLE IF EXISTS scenario_changes;
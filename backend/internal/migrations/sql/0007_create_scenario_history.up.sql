@@ -0,0 +1,26 @@
+-- SCENARIO CHANGES (append-only history; undo and redo are logged as their own entries)
+CREATE TABLE IF NOT EXISTS scenario_changes (
+    id SERIAL PRIMARY KEY,
+    scenario_id INTEGER NOT NULL REFERENCES scenarios(id) ON DELETE CASCADE,
+    action VARCHAR(50) NOT NULL,
+    game_id INTEGER REFERENCES games(id) ON DELETE SET NULL,
+    snapshot_id INTEGER,
+    target_change_id INTEGER REFERENCES scenario_changes(id) ON DELETE CASCADE,
+    before_state JSONB,
+    after_state JSONB,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- SCENARIO SNAPSHOTS
+CREATE TABLE IF NOT EXISTS scenario_snapshots (
+    id SERIAL PRIMARY KEY,
+    scenario_id INTEGER NOT NULL REFERENCES scenarios(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    state JSONB NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scenario_changes_scenario ON scenario_changes(scenario_id, id);
+CREATE INDEX IF NOT EXISTS idx_scenario_snapshots_scenario ON scenario_snapshots(scenario_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scenario_members;
+DROP TABLE IF EXISTS scenario_invites;
@@ -0,0 +1,30 @@
+-- SCENARIO INVITES
+CREATE TABLE IF NOT EXISTS scenario_invites (
+    id SERIAL PRIMARY KEY,
+    scenario_id INTEGER NOT NULL REFERENCES scenarios(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('editor', 'viewer')),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    max_uses INTEGER,
+    uses INTEGER DEFAULT 0,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- SCENARIO MEMBERS (the scenario's user_id or session_token is always an owner as well)
+CREATE TABLE IF NOT EXISTS scenario_members (
+    id SERIAL PRIMARY KEY,
+    scenario_id INTEGER NOT NULL REFERENCES scenarios(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    session_token VARCHAR(255),
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    invite_id INTEGER REFERENCES scenario_invites(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (user_id IS NOT NULL OR session_token IS NOT NULL),
+    UNIQUE(scenario_id, user_id),
+    UNIQUE(scenario_id, session_token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scenario_members_user ON scenario_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_scenario_members_session ON scenario_members(session_token);
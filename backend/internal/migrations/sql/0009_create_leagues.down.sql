@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS league_picks;
+DROP TABLE IF EXISTS league_entries;
+DROP TABLE IF EXISTS league_members;
+DROP TABLE IF EXISTS leagues;
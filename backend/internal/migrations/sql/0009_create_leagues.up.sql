@@ -0,0 +1,59 @@
+-- LEAGUES (pick'em contests on the real schedule)
+CREATE TABLE IF NOT EXISTS leagues (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    sport_id INTEGER NOT NULL REFERENCES sports(id) ON DELETE CASCADE,
+    season_id INTEGER NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    owner_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scoring VARCHAR(20) NOT NULL DEFAULT 'straight_up' CHECK (scoring IN ('straight_up', 'confidence')),
+    use_tiebreaker BOOLEAN NOT NULL DEFAULT FALSE,
+    invite_code VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- LEAGUE MEMBERS
+CREATE TABLE IF NOT EXISTS league_members (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'member')),
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(league_id, user_id)
+);
+
+-- LEAGUE ENTRIES (one per member per week; totals are filled in by grading)
+CREATE TABLE IF NOT EXISTS league_entries (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    member_id INTEGER NOT NULL REFERENCES league_members(id) ON DELETE CASCADE,
+    week INTEGER NOT NULL,
+    tiebreaker_total INTEGER,
+    points INTEGER NOT NULL DEFAULT 0,
+    correct_picks INTEGER NOT NULL DEFAULT 0,
+    graded_picks INTEGER NOT NULL DEFAULT 0,
+    tiebreaker_diff INTEGER,
+    graded_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(member_id, week)
+);
+
+-- LEAGUE PICKS
+CREATE TABLE IF NOT EXISTS league_picks (
+    id SERIAL PRIMARY KEY,
+    entry_id INTEGER NOT NULL REFERENCES league_entries(id) ON DELETE CASCADE,
+    game_id INTEGER NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+    picked_team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    confidence INTEGER,
+    is_correct BOOLEAN,
+    points INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(entry_id, game_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_leagues_season ON leagues(season_id);
+CREATE INDEX IF NOT EXISTS idx_league_members_user ON league_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_league_entries_league_week ON league_entries(league_id, week);
+CREATE INDEX IF NOT EXISTS idx_league_picks_game ON league_picks(game_id);
@@ -0,0 +1,3 @@
+ALTER TABLE picks DROP COLUMN IF EXISTS is_override;
+ALTER TABLE scenarios DROP COLUMN IF EXISTS result_mode;
+ALTER TABLE scenarios DROP COLUMN IF EXISTS mode;
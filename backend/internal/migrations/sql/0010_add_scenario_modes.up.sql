@@ -0,0 +1,14 @@
+-- Prediction scenarios lock picks when each game starts
+ALTER TABLE scenarios ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT 'what_if' CHECK (mode IN ('what_if', 'prediction'));
+ALTER TABLE scenarios ADD COLUMN IF NOT EXISTS result_mode VARCHAR(20) NOT NULL DEFAULT 'alternate_history' CHECK (result_mode IN ('alternate_history', 'honor_real'));
+
+-- Picks already on final games replaced the real result before overrides existed, so they're flagged as
+-- overrides to keep existing standings unchanged. Only done when the column is new, so databases that
+-- added it by hand keep the flags their users have set since.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'picks' AND column_name = 'is_override') THEN
+        ALTER TABLE picks ADD COLUMN is_override BOOLEAN NOT NULL DEFAULT FALSE;
+        UPDATE picks SET is_override = TRUE FROM games WHERE picks.game_id = games.id AND games.status = 'final';
+    END IF;
+END $$;
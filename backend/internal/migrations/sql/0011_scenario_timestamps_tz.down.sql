@@ -0,0 +1,2 @@
+-- Production has used TIMESTAMPTZ since before migrations existed, so there is nothing to undo.
+SELECT 1;
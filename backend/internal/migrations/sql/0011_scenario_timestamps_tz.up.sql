@@ -0,0 +1,11 @@
+-- Scenario timestamps are stored with time zones. Databases created from schema.sql or an
+-- earlier 0001 still have plain timestamps, which were written in UTC.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'scenarios' AND column_name = 'created_at' AND data_type = 'timestamp without time zone') THEN
+        ALTER TABLE scenarios ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'scenarios' AND column_name = 'updated_at' AND data_type = 'timestamp without time zone') THEN
+        ALTER TABLE scenarios ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
+    END IF;
+END $$;
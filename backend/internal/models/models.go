@@ -59,6 +59,13 @@ type Game struct {
 	AwayTeamESPNID  *string  	`json:"away_team_espn_id"`
 }
 
+// A game with both of its teams
+type GameWithTeams struct {
+	Game
+	HomeTeam		Team      	`json:"home_team"`
+	AwayTeam		Team      	`json:"away_team"`
+}
+
 type Scenario struct {
 	ID	   			int       	`json:"id"`
 	UserID 			*int      	`json:"user_id"`
@@ -67,22 +74,37 @@ type Scenario struct {
 	SportID 		int       	`json:"sport_id"`
 	SeasonID 		int       	`json:"season_id"`
 	IsPublic		bool     	`json:"is_public"`
+	Mode			string    	`json:"mode"`
+	ResultMode		string    	`json:"result_mode"`
 	CreatedAt		time.Time 	`json:"created_at"`
 	UpdatedAt		time.Time 	`json:"updated_at"`
 }
 
+// A scenario with the role of the user or guest session that looked it up, if they're a member
+type ScenarioOwner struct {
+	Scenario
+	MemberRole		*string   	`json:"member_role"`
+}
+
 type Pick struct {
 	ID	   			int       	`json:"id"`
 	ScenarioID 		int       	`json:"scenario_id"`
 	GameID			int      	`json:"game_id"`
-	PickedTeamID 	int      	`json:"picked_team_id"`
+	PickedTeamID 	*int      	`json:"picked_team_id"`
 	PredictedHomeScore *int     `json:"predicted_home_score"`
 	PredictedAwayScore *int     `json:"predicted_away_score"`
 	Status			*string   	`json:"status"`
+	IsOverride		bool     	`json:"is_override"`
 	CreatedAt		time.Time 	`json:"created_at"`
 	UpdatedAt		time.Time 	`json:"updated_at"`
 }
 
+// A pick with the game it's for
+type PickWithGame struct {
+	Pick
+	Game			GameWithTeams	`json:"game"`
+}
+
 type PlayoffState struct {
 	ID	   			int       	`json:"id"`
 	ScenarioID 		int       	`json:"scenario_id"`
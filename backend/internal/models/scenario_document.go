@@ -0,0 +1,102 @@
+// Portable scenario document used for export and import
+
+package models
+
+import "time"
+
+const (
+	ScenarioDocumentFormat	= "gamescript.scenario"
+	ScenarioDocumentVersion	= 1
+)
+
+// Scenario referenced by stable ESPN IDs and abbreviations instead of database IDs
+type ScenarioDocument struct {
+	Format			string    				`json:"format"`
+	Version			int       				`json:"version"`
+	ExportedAt		time.Time 				`json:"exported_at"`
+	Sport			string    				`json:"sport"`
+	Season			DocumentSeason			`json:"season"`
+	Scenario		DocumentScenario		`json:"scenario"`
+	Picks			[]DocumentPick			`json:"picks"`
+	Playoffs		*DocumentPlayoffs		`json:"playoffs"`
+}
+
+type DocumentSeason struct {
+	StartYear		int       	`json:"start_year"`
+	EndYear			*int      	`json:"end_year"`
+}
+
+type DocumentScenario struct {
+	Name			string    	`json:"name"`
+	IsPublic		bool     	`json:"is_public"`
+	Mode			string    	`json:"mode,omitempty"`
+	ResultMode		string    	`json:"result_mode,omitempty"`
+}
+
+type DocumentTeam struct {
+	ESPNID			string    	`json:"espn_id"`
+	Abbreviation	string    	`json:"abbreviation"`
+}
+
+type DocumentGame struct {
+	ESPNID			string    	`json:"espn_id"`
+	Week			*int      	`json:"week"`
+	HomeTeam		DocumentTeam	`json:"home_team"`
+	AwayTeam		DocumentTeam	`json:"away_team"`
+}
+
+// Picked team is nil with IsTie set for a tie pick
+type DocumentPick struct {
+	Game			DocumentGame	`json:"game"`
+	PickedTeam		*DocumentTeam	`json:"picked_team"`
+	IsTie			bool     		`json:"is_tie"`
+	PredictedHomeScore *int     	`json:"predicted_home_score"`
+	PredictedAwayScore *int     	`json:"predicted_away_score"`
+	Status			*string   		`json:"status"`
+	IsOverride		*bool     		`json:"is_override,omitempty"`
+}
+
+type DocumentPlayoffs struct {
+	CurrentRound	int       				`json:"current_round"`
+	IsEnabled		bool     				`json:"is_enabled"`
+	Series			[]DocumentPlayoffSeries	`json:"series"`
+	Matchups		[]DocumentPlayoffMatchup	`json:"matchups"`
+}
+
+// Identifies a playoff series within a bracket
+type DocumentSeriesKey struct {
+	Round			int       	`json:"round"`
+	SeriesOrder		int       	`json:"series_order"`
+	Conference		*string   	`json:"conference"`
+}
+
+type DocumentPlayoffSeries struct {
+	Round			int       		`json:"round"`
+	SeriesOrder		int       		`json:"series_order"`
+	Conference		*string   		`json:"conference"`
+	HigherSeedTeam	DocumentTeam	`json:"higher_seed_team"`
+	LowerSeedTeam	DocumentTeam	`json:"lower_seed_team"`
+	HigherSeed		int       		`json:"higher_seed"`
+	LowerSeed		int       		`json:"lower_seed"`
+	PickedTeam		*DocumentTeam	`json:"picked_team"`
+	PredictedHigherSeedWins *int    `json:"predicted_higher_seed_wins"`
+	PredictedLowerSeedWins *int     `json:"predicted_lower_seed_wins"`
+	BestOf			*int      		`json:"best_of"`
+	Status			*string   		`json:"status"`
+}
+
+type DocumentPlayoffMatchup struct {
+	Series			*DocumentSeriesKey	`json:"series"`
+	Round			int       		`json:"round"`
+	MatchupOrder	int       		`json:"matchup_order"`
+	GameNumber		*int      		`json:"game_number"`
+	Conference		*string   		`json:"conference"`
+	HigherSeedTeam	DocumentTeam	`json:"higher_seed_team"`
+	LowerSeedTeam	DocumentTeam	`json:"lower_seed_team"`
+	HigherSeed		int       		`json:"higher_seed"`
+	LowerSeed		int       		`json:"lower_seed"`
+	PickedTeam		*DocumentTeam	`json:"picked_team"`
+	PredictedHigherSeedScore *int   `json:"predicted_higher_seed_score"`
+	PredictedLowerSeedScore *int    `json:"predicted_lower_seed_score"`
+	Status			*string   		`json:"status"`
+}
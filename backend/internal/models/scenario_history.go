@@ -0,0 +1,23 @@
+// Scenario state stored in the change log and in named snapshots
+
+package models
+
+// Picks for a set of games and, optionally, the playoff bracket at one point in time.
+// Games listed in GameIDs without a pick had no pick; AllGames covers every game in the season.
+type ScenarioState struct {
+	AllGames		bool      			`json:"all_games"`
+	GameIDs			[]int     			`json:"game_ids"`
+	Picks			[]StatePick			`json:"picks"`
+	IncludesPlayoffs bool     			`json:"includes_playoffs"`
+	Playoffs		*DocumentPlayoffs	`json:"playoffs"`
+}
+
+// Regular season pick within a scenario state, keyed by game ID
+type StatePick struct {
+	GameID			int       	`json:"game_id"`
+	PickedTeamID	*int      	`json:"picked_team_id"`
+	PredictedHomeScore *int   	`json:"predicted_home_score"`
+	PredictedAwayScore *int   	`json:"predicted_away_score"`
+	Status			*string   	`json:"status"`
+	IsOverride		bool      	`json:"is_override"`
+}
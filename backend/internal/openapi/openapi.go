@@ -0,0 +1,456 @@
+// OpenAPI 3 documents built from route descriptions and the Go types handlers respond with
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+
+// Version of the OpenAPI specification documents are written against
+const Version = "3.0.3"
+
+// How a route authenticates its caller
+const (
+	AuthNone     = ""
+	AuthOptional = "optional" // Bearer token, guest session cookie, or neither
+	AuthRequired = "required" // Bearer token
+)
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Operations on a path keyed by lowercase HTTP method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// Subset of JSON Schema used by OpenAPI 3.0; AdditionalProperties is a bool or a *Schema
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// Implemented by types that marshal as one of several other types, such as standings for either sport
+type Union interface {
+	OneOf() []interface{}
+}
+
+// Implemented by JSON body types sent with a media type other than application/json, such as problem details
+type MediaTyper interface {
+	MediaType() string
+}
+
+// Implemented by string types with a fixed set of values, such as error codes
+type Enum interface {
+	EnumValues() []interface{}
+}
+
+// Response body that isn't JSON, such as a calendar feed or an image
+type Raw struct {
+	ContentType string
+	Description string
+}
+
+// Response body chosen by content negotiation, such as standings as JSON or a spreadsheet;
+// each member is a JSON body type or a Raw
+type Negotiated []interface{}
+
+// A route as registered with Fiber, along with the types it reads and writes
+type Route struct {
+	Method  string
+	Path    string // Fiber syntax, e.g. /api/scenarios/:scenario_id
+	Summary string
+	Tag     string
+	Auth    string
+	Query   []Parameter
+
+	// Zero value of the JSON request body type, or nil for routes without a body
+	Request interface{}
+
+	// Zero value of the response body type for each status; a Raw for non-JSON bodies, a Negotiated
+	// for bodies with several content types, or nil for no body
+	Responses map[int]interface{}
+}
+
+// Body type documented for error statuses a route doesn't list itself
+type defaultResponse struct {
+	description string
+	body        interface{}
+}
+
+type Builder struct {
+	document     *Document
+	names        map[reflect.Type]string
+	defaultError *defaultResponse
+}
+
+func NewBuilder(title, version string) *Builder {
+	return &Builder{
+		document: &Document{
+			OpenAPI: Version,
+			Info:    Info{Title: title, Version: version},
+			Paths:   map[string]PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{},
+				SecuritySchemes: map[string]SecurityScheme{
+					"bearerAuth":   {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+					"guestSession": {Type: "apiKey", In: "cookie", Name: "session_token"},
+				},
+			},
+		},
+		names: map[reflect.Type]string{},
+	}
+}
+
+// Documents body as the "default" response of every route added afterwards
+func (b *Builder) DefaultError(description string, body interface{}) {
+	b.defaultError = &defaultResponse{description: description, body: body}
+}
+
+func (b *Builder) Add(route Route) error {
+	path, params := convertPath(route.Path)
+	method := strings.ToLower(route.Method)
+	if item, ok := b.document.Paths[path]; ok && item[method] != nil {
+		return fmt.Errorf("%s %s is documented twice", route.Method, route.Path)
+	}
+
+	operation := &Operation{
+		OperationID: operationID(route.Method, path),
+		Summary:     route.Summary,
+		Parameters:  append(params, route.Query...),
+		Responses:   map[string]*Response{},
+	}
+	if route.Tag != "" {
+		operation.Tags = []string{route.Tag}
+	}
+	switch route.Auth {
+	case AuthOptional:
+		operation.Security = []map[string][]string{{"bearerAuth": {}}, {"guestSession": {}}, {}}
+	case AuthRequired:
+		operation.Security = []map[string][]string{{"bearerAuth": {}}}
+	}
+
+	if route.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: b.Schema(reflect.TypeOf(route.Request))}},
+		}
+	}
+
+	if len(route.Responses) == 0 {
+		return fmt.Errorf("%s %s has no responses", route.Method, route.Path)
+	}
+	for status, body := range route.Responses {
+		operation.Responses[strconv.Itoa(status)] = b.response(statusDescription(status), body)
+	}
+	if b.defaultError != nil {
+		operation.Responses["default"] = b.response(b.defaultError.description, b.defaultError.body)
+	}
+
+	if b.document.Paths[path] == nil {
+		b.document.Paths[path] = PathItem{}
+	}
+	b.document.Paths[path][method] = operation
+	return nil
+}
+
+func (b *Builder) Document() *Document {
+	return b.document
+}
+
+func (b *Builder) response(description string, body interface{}) *Response {
+	switch body := body.(type) {
+	case nil:
+		return &Response{Description: description}
+	case Negotiated:
+		response := &Response{Description: description, Content: map[string]MediaType{}}
+		for _, member := range body {
+			for contentType, media := range b.response(description, member).Content {
+				response.Content[contentType] = media
+			}
+		}
+		return response
+	case Raw:
+		if body.Description != "" {
+			description = body.Description
+		}
+		return &Response{
+			Description: description,
+			Content:     map[string]MediaType{body.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}
+	}
+	contentType := "application/json"
+	if typed, ok := body.(MediaTyper); ok {
+		contentType = typed.MediaType()
+	}
+	return &Response{
+		Description: description,
+		Content:     map[string]MediaType{contentType: {Schema: b.Schema(reflect.TypeOf(body))}},
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+var unionType = reflect.TypeOf((*Union)(nil)).Elem()
+var enumType = reflect.TypeOf((*Enum)(nil)).Elem()
+
+// Returns the schema for a Go type, adding named structs to the document's components
+func (b *Builder) Schema(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface && t.Implements(unionType):
+		union := reflect.Zero(t).Interface().(Union)
+		schema := &Schema{}
+		for _, member := range union.OneOf() {
+			schema.OneOf = append(schema.OneOf, b.Schema(reflect.TypeOf(member)))
+		}
+		return schema
+	case t.Kind() == reflect.String && t.Implements(enumType):
+		return &Schema{Type: "string", Enum: reflect.Zero(t).Interface().(Enum).EnumValues()}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := b.Schema(t.Elem())
+		if schema.Ref != "" {
+			// Siblings of $ref are ignored, so nullable references are wrapped
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.Schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.Schema(t.Elem())}
+	case reflect.Struct:
+		return b.structRef(t)
+	}
+	// Interfaces can hold anything
+	return &Schema{}
+}
+
+func (b *Builder) structRef(t reflect.Type) *Schema {
+	if name, ok := b.names[t]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	name := schemaName(t)
+	if _, taken := b.document.Components.Schemas[name]; taken {
+		// Same type name in two packages
+		name = capitalize(pathBase(t.PkgPath())) + name
+	}
+	b.names[t] = name
+
+	// Reserve the name before walking fields so recursive types terminate
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	b.document.Components.Schemas[name] = schema
+	b.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Adds a struct's JSON fields to an object schema, flattening embedded structs like encoding/json does
+func (b *Builder) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := jsonField(field)
+		if skip {
+			continue
+		}
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				b.addFields(schema, fieldType)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = b.Schema(fieldType)
+		if !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func jsonField(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
+
+// Go type name with the first letter capitalized, e.g. currentPick becomes CurrentPick
+func schemaName(t reflect.Type) string {
+	return capitalize(t.Name())
+}
+
+func capitalize(word string) string {
+	if word == "" {
+		return ""
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
+
+func pathBase(pkgPath string) string {
+	return pkgPath[strings.LastIndex(pkgPath, "/")+1:]
+}
+
+// Converts a Fiber path to an OpenAPI template and its path parameters
+func convertPath(path string) (string, []Parameter) {
+	segments := strings.Split(path, "/")
+	var params []Parameter
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		// Parameters can share a segment with a suffix, as in :season_id.ics
+		name, suffix := segment[1:], ""
+		if dot := strings.Index(name, "."); dot >= 0 {
+			name, suffix = name[:dot], name[dot:]
+		}
+		segments[i] = "{" + name + "}" + suffix
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: pathParamSchema(name)})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// IDs and numbered parameters like week and round are integers; tokens and codes are strings
+func pathParamSchema(name string) *Schema {
+	if strings.HasSuffix(name, "_id") || name == "week" || name == "round" {
+		return &Schema{Type: "integer"}
+	}
+	return &Schema{Type: "string"}
+}
+
+// Builds an operation ID like getScenariosByScenarioIdStandings from the method and path
+func operationID(method, path string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/api"), "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "{") {
+			id.WriteString("By")
+			segment = strings.Trim(segment[:strings.Index(segment, "}")+1], "{}")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+			id.WriteString(capitalize(word))
+		}
+	}
+	return id.String()
+}
+
+func statusDescription(status int) string {
+	switch status {
+	case 200:
+		return "OK"
+	case 201:
+		return "Created"
+	case 204:
+		return "No Content"
+	}
+	return fmt.Sprintf("Status %d", status)
+}
@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sampleTeam struct {
+	ID      int     `json:"id"`
+	LogoURL *string `json:"logo_url"`
+}
+
+type sampleBase struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type samplePick struct {
+	sampleBase
+	Team       *sampleTeam      `json:"team"`
+	Scores     []int            `json:"scores"`
+	Divisions  map[string][]int `json:"divisions"`
+	Note       string           `json:"note,omitempty"`
+	Secret     string           `json:"-"`
+	unexported int
+}
+
+type sampleMessage struct {
+	Message string `json:"message"`
+}
+
+type sampleCode string
+
+func (sampleCode) EnumValues() []interface{} {
+	return []interface{}{"NOT_FOUND", "CONFLICT"}
+}
+
+type sampleProblem struct {
+	Detail string     `json:"detail"`
+	Code   sampleCode `json:"code,omitempty"`
+}
+
+func (sampleProblem) MediaType() string {
+	return "application/problem+json"
+}
+
+func sampleDocument(t *testing.T) *Document {
+	t.Helper()
+	builder := NewBuilder("Sample", "1.0.0")
+	builder.DefaultError("Error", sampleProblem{})
+	routes := []Route{
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/picks", Auth: AuthOptional, Responses: map[int]interface{}{200: []samplePick{}}},
+		{Method: "GET", Path: "/api/scenarios/compare", Responses: map[int]interface{}{200: sampleMessage{}}},
+		{Method: "GET", Path: "/api/teams/:team_id/games.ics", Responses: map[int]interface{}{200: Raw{ContentType: "text/calendar"}}},
+		{Method: "GET", Path: "/api/scenarios/:scenario_id/standings", Responses: map[int]interface{}{200: Negotiated{sampleMessage{}, Raw{ContentType: "text/csv"}}}},
+		{Method: "POST", Path: "/api/invites/:token/accept", Request: sampleMessage{}, Responses: map[int]interface{}{201: samplePick{}, 204: nil}},
+	}
+	for _, route := range routes {
+		if err := builder.Add(route); err != nil {
+			t.Fatalf("Add returned error: %v", err)
+		}
+	}
+	return builder.Document()
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	document := sampleDocument(t)
+
+	pick, ok := document.Components.Schemas["SamplePick"]
+	if !ok {
+		t.Fatalf("SamplePick schema missing; have %v", reflect.ValueOf(document.Components.Schemas).MapKeys())
+	}
+
+	// Embedded fields are flattened, and omitempty, ignored, and unexported fields aren't required
+	expectedRequired := []string{"created_at", "divisions", "id", "scores", "team"}
+	if !reflect.DeepEqual(pick.Required, expectedRequired) {
+		t.Errorf("Required = %v; want %v", pick.Required, expectedRequired)
+	}
+	for _, name := range []string{"Secret", "unexported"} {
+		if _, ok := pick.Properties[name]; ok {
+			t.Errorf("Property %s should not be documented", name)
+		}
+	}
+	if pick.Properties["created_at"].Format != "date-time" {
+		t.Errorf("created_at format = %q; want date-time", pick.Properties["created_at"].Format)
+	}
+	if team := pick.Properties["team"]; !team.Nullable || len(team.AllOf) != 1 || team.AllOf[0].Ref != "#/components/schemas/SampleTeam" {
+		t.Errorf("team should be a nullable reference to SampleTeam, got %+v", team)
+	}
+	if divisions := pick.Properties["divisions"]; divisions.Type != "object" || divisions.AdditionalProperties.(*Schema).Type != "array" {
+		t.Errorf("divisions should be an object of arrays, got %+v", divisions)
+	}
+	if pick.AdditionalProperties != false {
+		t.Errorf("Structs should not allow additional properties")
+	}
+
+	code := document.Components.Schemas["SampleProblem"].Properties["code"]
+	if code.Type != "string" || !reflect.DeepEqual(code.Enum, []interface{}{"NOT_FOUND", "CONFLICT"}) {
+		t.Errorf("code should be a string enum, got %+v", code)
+	}
+}
+
+func TestPathsAndParameters(t *testing.T) {
+	document := sampleDocument(t)
+
+	operation := document.Paths["/api/scenarios/{scenario_id}/picks"]["get"]
+	if operation == nil {
+		t.Fatalf("Operation missing; have %v", reflect.ValueOf(document.Paths).MapKeys())
+	}
+	if operation.OperationID != "getScenariosByScenarioIdPicks" {
+		t.Errorf("OperationID = %q", operation.OperationID)
+	}
+	if len(operation.Parameters) != 1 || operation.Parameters[0].Schema.Type != "integer" {
+		t.Errorf("scenario_id should be an integer path parameter, got %+v", operation.Parameters)
+	}
+	if len(operation.Security) != 3 {
+		t.Errorf("Optional auth should allow a token, a session, or neither, got %v", operation.Security)
+	}
+	if _, ok := operation.Responses["default"]; !ok {
+		t.Errorf("Default error response missing")
+	}
+
+	accept := document.Paths["/api/invites/{token}/accept"]["post"]
+	if accept == nil || accept.Parameters[0].Schema.Type != "string" {
+		t.Errorf("token should be a string path parameter")
+	}
+	if accept != nil && accept.RequestBody == nil {
+		t.Errorf("Request body missing")
+	}
+	if _, ok := document.Paths["/api/teams/{team_id}/games.ics"]; !ok {
+		t.Errorf("Parameters with a suffix should keep it")
+	}
+}
+
+func TestAddRejectsDuplicates(t *testing.T) {
+	builder := NewBuilder("Sample", "1.0.0")
+	route := Route{Method: "GET", Path: "/api/sports", Responses: map[int]interface{}{200: sampleMessage{}}}
+	if err := builder.Add(route); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+	if err := builder.Add(route); err == nil {
+		t.Errorf("Adding a route twice should fail")
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	document := sampleDocument(t)
+	validPick := `{"id": 1, "created_at": "2025-09-07T17:00:00Z", "team": null, "scores": [24, 17], "divisions": {"East": [1]}}`
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		contentType string
+		body        string
+		problem     string
+	}{
+		{"valid", "GET", "/api/scenarios/5/picks", 200, "application/json", "[" + validPick + "]", ""},
+		{"literal path wins", "GET", "/api/scenarios/compare", 200, "application/json", `{"message": "ok"}`, ""},
+		{"default error", "GET", "/api/scenarios/5/picks", 404, "application/problem+json", `{"detail": "Scenario not found"}`, ""},
+		{"default error checked", "GET", "/api/scenarios/5/picks", 404, "application/problem+json", `{"error": "Scenario not found"}`, "unexpected property \"error\""},
+		{"unknown enum value", "GET", "/api/scenarios/5/picks", 404, "application/problem+json", `{"detail": "Scenario not found", "code": "MISSING"}`, "MISSING is not one of"},
+		{"default error media type", "GET", "/api/scenarios/5/picks", 404, "application/json", `{"detail": "Scenario not found"}`, "content type"},
+		{"query string ignored", "GET", "/api/scenarios/5/picks?week=2", 200, "application/json", "[]", ""},
+		{"raw body", "GET", "/api/teams/3/games.ics", 200, "text/calendar; charset=utf-8", "BEGIN:VCALENDAR", ""},
+		{"no body", "POST", "/api/invites/abc/accept", 204, "", "", ""},
+		{"negotiated JSON", "GET", "/api/scenarios/5/standings", 200, "application/json", `{"message": "ok"}`, ""},
+		{"negotiated raw", "GET", "/api/scenarios/5/standings?format=csv", 200, "text/csv; charset=utf-8", "team,wins", ""},
+		{"negotiated JSON checked", "GET", "/api/scenarios/5/standings", 200, "application/json", `{}`, "missing required property \"message\""},
+		{"undocumented route", "GET", "/api/sports", 200, "application/json", "[]", "not documented"},
+		{"wrong content type", "GET", "/api/teams/3/games.ics", 200, "application/json", "{}", "content type"},
+		{"extra property", "GET", "/api/scenarios/5/picks", 200, "application/json", `[{"id": 1, "created_at": "2025-09-07T17:00:00Z", "team": null, "scores": [], "divisions": {}, "extra": 1}]`, "unexpected property \"extra\""},
+		{"wrong type", "GET", "/api/scenarios/5/picks", 200, "application/json", `[{"id": "1", "created_at": "2025-09-07T17:00:00Z", "team": null, "scores": [], "divisions": {}}]`, "$[0].id: expected integer, got string"},
+		{"null array", "GET", "/api/scenarios/5/picks", 200, "application/json", `[{"id": 1, "created_at": "2025-09-07T17:00:00Z", "team": null, "scores": null, "divisions": {}}]`, "$[0].scores: null is not allowed"},
+		{"nested", "GET", "/api/scenarios/5/picks", 200, "application/json", `[{"id": 1, "created_at": "yesterday", "team": {"id": 1.5}, "scores": [], "divisions": {"East": ["a"]}}]`, "$[0].team: missing required property \"logo_url\""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := document.ValidateResponse(test.method, test.path, test.status, test.contentType, []byte(test.body))
+			if test.problem == "" {
+				if err != nil {
+					t.Errorf("ValidateResponse returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.problem) {
+				t.Errorf("ValidateResponse error = %v; want it to contain %q", err, test.problem)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	document := sampleDocument(t)
+	body := `[{"id": 1, "created_at": "yesterday", "team": {"id": 1.5}, "scores": [], "divisions": {"East": ["a"]}}]`
+
+	err := document.ValidateResponse("GET", "/api/scenarios/5/picks", 200, "application/json", []byte(body))
+	if err == nil {
+		t.Fatal("ValidateResponse should fail")
+	}
+	for _, problem := range []string{
+		`$[0].created_at: expected date-time, got "yesterday"`,
+		"$[0].team.id: expected integer, got number",
+		`$[0].team: missing required property "logo_url"`,
+		"$[0].divisions.East[0]: expected integer, got string",
+	} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Error %q should contain %q", err, problem)
+		}
+	}
+}
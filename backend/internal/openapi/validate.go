@@ -0,0 +1,285 @@
+// Checks responses against a document so tests catch handlers drifting from the spec
+
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+
+// Finds the operation documented for a request, returning it with its path template
+func (d *Document) Operation(method, path string) (*Operation, string, bool) {
+	method = strings.ToLower(method)
+	var match *Operation
+	var matchTemplate string
+	matchLiterals := -1
+
+	for template, item := range d.Paths {
+		operation, ok := item[method]
+		if !ok {
+			continue
+		}
+		// Literal segments win over parameters, so /scenarios/compare beats /scenarios/{scenario_id}
+		literals, ok := matchPath(template, path)
+		if ok && literals > matchLiterals {
+			match, matchTemplate, matchLiterals = operation, template, literals
+		}
+	}
+	return match, matchTemplate, match != nil
+}
+
+// Returns how many literal segments of a template matched the path
+func matchPath(template, path string) (int, bool) {
+	templateSegments := strings.Split(strings.TrimSuffix(template, "/"), "/")
+	pathSegments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(templateSegments) != len(pathSegments) {
+		return 0, false
+	}
+
+	literals := 0
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") {
+			suffix := segment[strings.Index(segment, "}")+1:]
+			value := strings.TrimSuffix(pathSegments[i], suffix)
+			if value == "" || !strings.HasSuffix(pathSegments[i], suffix) {
+				return 0, false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return 0, false
+		}
+		literals++
+	}
+	return literals, true
+}
+
+// Checks that a response's status, content type, and body are what the document says the route returns
+func (d *Document) ValidateResponse(method, path string, status int, contentType string, body []byte) error {
+	if index := strings.IndexByte(path, '?'); index >= 0 {
+		path = path[:index]
+	}
+	operation, template, ok := d.Operation(method, path)
+	if !ok {
+		return fmt.Errorf("%s %s is not documented", method, path)
+	}
+
+	response, ok := operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = operation.Responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("%s %s: status %d is not documented", method, template, status)
+	}
+	if len(response.Content) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("%s %s: status %d is documented without a body", method, template, status)
+		}
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := response.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("%s %s: content type %q is not documented for status %d", method, template, contentType, status)
+	}
+	if !isJSON(mediaType) || media.Schema == nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("%s %s: invalid JSON body: %w", method, template, err)
+	}
+	if err := d.Validate(media.Schema, value); err != nil {
+		return fmt.Errorf("%s %s: status %d: %w", method, template, status, err)
+	}
+	return nil
+}
+
+// Checks a decoded JSON value against a schema, reporting every mismatch
+func (d *Document) Validate(schema *Schema, value interface{}) error {
+	var problems []error
+	d.validate(schema, value, "$", &problems)
+	return errors.Join(problems...)
+}
+
+func (d *Document) validate(schema *Schema, value interface{}, location string, problems *[]error) {
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, fmt.Errorf("%s: %s", location, fmt.Sprintf(format, args...)))
+	}
+
+	if value == nil {
+		if !schema.Nullable && !d.nullableRef(schema) {
+			fail("null is not allowed")
+		}
+		return
+	}
+
+	if schema.Ref != "" {
+		resolved, ok := d.resolve(schema.Ref)
+		if !ok {
+			fail("unknown schema %s", schema.Ref)
+			return
+		}
+		d.validate(resolved, value, location, problems)
+		return
+	}
+	for _, part := range schema.AllOf {
+		d.validate(part, value, location, problems)
+	}
+	if len(schema.OneOf) > 0 {
+		d.validateOneOf(schema.OneOf, value, location, problems)
+	}
+
+	switch schema.Type {
+	case "":
+		// Any value
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean, got %s", jsonType(value))
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			fail("expected integer, got %s", jsonType(value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			fail("expected number, got %s", jsonType(value))
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			fail("expected string, got %s", jsonType(value))
+			return
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, text); err != nil {
+				fail("expected date-time, got %q", text)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("expected array, got %s", jsonType(value))
+			return
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", location, i), problems)
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object, got %s", jsonType(value))
+			return
+		}
+		d.validateObject(schema, object, location, problems)
+	}
+
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if allowed == value {
+				return
+			}
+		}
+		fail("%v is not one of %v", value, schema.Enum)
+	}
+}
+
+// Passes when exactly one alternative matches, otherwise reports the problems with the closest one
+func (d *Document) validateOneOf(alternatives []*Schema, value interface{}, location string, problems *[]error) {
+	var closest []error
+	matches := 0
+	for i, alternative := range alternatives {
+		var alternativeProblems []error
+		d.validate(alternative, value, location, &alternativeProblems)
+		if len(alternativeProblems) == 0 {
+			matches++
+		} else if i == 0 || len(alternativeProblems) < len(closest) {
+			closest = alternativeProblems
+		}
+	}
+	switch {
+	case matches == 0:
+		*problems = append(*problems, closest...)
+	case matches > 1:
+		*problems = append(*problems, fmt.Errorf("%s: matches %d alternatives, expected exactly one", location, matches))
+	}
+}
+
+func (d *Document) validateObject(schema *Schema, object map[string]interface{}, location string, problems *[]error) {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			*problems = append(*problems, fmt.Errorf("%s: missing required property %q", location, name))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := schema.Properties[name]; ok {
+			d.validate(property, object[name], location+"."+name, problems)
+			continue
+		}
+		switch additional := schema.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				*problems = append(*problems, fmt.Errorf("%s: unexpected property %q", location, name))
+			}
+		case *Schema:
+			d.validate(additional, object[name], location+"."+name, problems)
+		}
+	}
+}
+
+func (d *Document) resolve(ref string) (*Schema, bool) {
+	schema, ok := d.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+	return schema, ok
+}
+
+// Whether a wrapped reference like {allOf: [{$ref}], nullable: true} allows null
+func (d *Document) nullableRef(schema *Schema) bool {
+	if schema.Ref == "" {
+		return false
+	}
+	resolved, ok := d.resolve(schema.Ref)
+	return ok && resolved.Nullable
+}
+
+// JSON and structured syntax suffixes like application/problem+json
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,174 @@
+// Margin-aware Elo ratings calculated from final game results
+
+package ratings
+
+import (
+	"math"
+	"os"
+	"strconv"
+)
+
+
+// Rating configuration for a sport
+type Config struct {
+	InitialRating       float64 // Mean rating every team regresses toward
+	KFactor             float64 // Maximum rating change for a single game before margin adjustment
+	HomeFieldAdvantage  float64 // Rating points added to the home team when calculating win probability
+	PreseasonRegression float64 // Fraction (0-1) of the distance to the mean removed from last season's rating
+	MarginOfVictory     bool    // Whether larger wins move ratings more
+}
+
+// Final game result or upcoming game used for ratings
+type Game struct {
+	ID         int
+	HomeTeamID int
+	AwayTeamID int
+	HomeScore  *int
+	AwayScore  *int
+	IsFinal    bool
+}
+
+// Pre-game ratings and win probability for a single game
+type GameProbability struct {
+	GameID             int
+	HomeTeamID         int
+	AwayTeamID         int
+	HomeRating         float64
+	AwayRating         float64
+	HomeWinProbability float64
+}
+
+// Ratings after processing every final game in a season
+type Result struct {
+	Ratings          map[int]float64
+	PreseasonRatings map[int]float64
+	GamesPlayed      map[int]int
+	Games            []GameProbability
+}
+
+// Returns default rating configuration for a sport, allowing preseason regression to be overridden by RATINGS_PRESEASON_REGRESSION
+func DefaultConfig(sportID int) Config {
+	config := Config{
+		InitialRating:       1500,
+		KFactor:             20,
+		HomeFieldAdvantage:  48,
+		PreseasonRegression: 1.0 / 3.0,
+		MarginOfVictory:     true,
+	}
+
+	// NBA home court is worth more in rating points, and teams carry over more between seasons
+	if sportID == 2 {
+		config.HomeFieldAdvantage = 100
+		config.PreseasonRegression = 0.25
+	}
+
+	if value := os.Getenv("RATINGS_PRESEASON_REGRESSION"); value != "" {
+		if regression, err := strconv.ParseFloat(value, 64); err == nil && regression >= 0 && regression <= 1 {
+			config.PreseasonRegression = regression
+		}
+	}
+
+	return config
+}
+
+// Regresses last season's ratings toward the mean, using the initial rating for teams without one
+func PreseasonRatings(teamIDs []int, previous map[int]float64, config Config) map[int]float64 {
+	preseason := make(map[int]float64, len(teamIDs))
+	for _, teamID := range teamIDs {
+		rating, exists := previous[teamID]
+		if !exists {
+			preseason[teamID] = config.InitialRating
+			continue
+		}
+		preseason[teamID] = config.InitialRating + (rating-config.InitialRating)*(1-config.PreseasonRegression)
+	}
+	return preseason
+}
+
+// Processes games in order, recording pre-game win probabilities and updating ratings after each final game
+func Calculate(teamIDs []int, preseason map[int]float64, games []Game, config Config) Result {
+	result := Result{
+		Ratings:          make(map[int]float64, len(teamIDs)),
+		PreseasonRatings: make(map[int]float64, len(teamIDs)),
+		GamesPlayed:      make(map[int]int, len(teamIDs)),
+		Games:            make([]GameProbability, 0, len(games)),
+	}
+
+	for _, teamID := range teamIDs {
+		rating, exists := preseason[teamID]
+		if !exists {
+			rating = config.InitialRating
+		}
+		result.Ratings[teamID] = rating
+		result.PreseasonRatings[teamID] = rating
+	}
+
+	for _, game := range games {
+		homeRating := ratingOrInitial(result.Ratings, game.HomeTeamID, config)
+		awayRating := ratingOrInitial(result.Ratings, game.AwayTeamID, config)
+		homeWinProbability := WinProbability(homeRating, awayRating, config.HomeFieldAdvantage)
+
+		result.Games = append(result.Games, GameProbability{
+			GameID:             game.ID,
+			HomeTeamID:         game.HomeTeamID,
+			AwayTeamID:         game.AwayTeamID,
+			HomeRating:         homeRating,
+			AwayRating:         awayRating,
+			HomeWinProbability: homeWinProbability,
+		})
+
+		if !game.IsFinal || game.HomeScore == nil || game.AwayScore == nil {
+			continue
+		}
+
+		// Actual result from home team's perspective
+		margin := *game.HomeScore - *game.AwayScore
+		actual := 0.5
+		if margin > 0 {
+			actual = 1
+		} else if margin < 0 {
+			actual = 0
+		}
+
+		multiplier := 1.0
+		if config.MarginOfVictory {
+			multiplier = marginMultiplier(margin, homeRating+config.HomeFieldAdvantage-awayRating)
+		}
+
+		shift := config.KFactor * multiplier * (actual - homeWinProbability)
+		result.Ratings[game.HomeTeamID] = homeRating + shift
+		result.Ratings[game.AwayTeamID] = awayRating - shift
+		result.GamesPlayed[game.HomeTeamID]++
+		result.GamesPlayed[game.AwayTeamID]++
+	}
+
+	return result
+}
+
+// Probability that the home team wins given both ratings and the home-field adjustment
+func WinProbability(homeRating float64, awayRating float64, homeFieldAdvantage float64) float64 {
+	return 1 / (1 + math.Pow(10, -(homeRating+homeFieldAdvantage-awayRating)/400))
+}
+
+// Scales rating changes by margin of victory, damped when the favorite wins to avoid autocorrelation
+func marginMultiplier(margin int, homeRatingDiff float64) float64 {
+	if margin == 0 {
+		return 1
+	}
+
+	// Rating difference from the winner's perspective
+	winnerRatingDiff := homeRatingDiff
+	if margin < 0 {
+		margin = -margin
+		winnerRatingDiff = -homeRatingDiff
+	}
+
+	return math.Log(float64(margin)+1) * (2.2 / (winnerRatingDiff*0.001 + 2.2))
+}
+
+func ratingOrInitial(ratings map[int]float64, teamID int, config Config) float64 {
+	if rating, exists := ratings[teamID]; exists {
+		return rating
+	}
+	return config.InitialRating
+}
@@ -0,0 +1,92 @@
+package ratings
+
+import (
+	"math"
+	"testing"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestWinProbability(t *testing.T) {
+	tests := []struct {
+		name     string
+		home     float64
+		away     float64
+		hfa      float64
+		expected float64
+	}{
+		{"Even teams, no home field", 1500, 1500, 0, 0.5},
+		{"400 point favorite", 1900, 1500, 0, 10.0 / 11.0},
+		{"Home field only", 1500, 1500, 48, 0.568641},
+		{"Home underdog", 1400, 1500, 48, 0.425720},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := WinProbability(tt.home, tt.away, tt.hfa)
+			if math.Abs(result-tt.expected) > 0.000001 {
+				t.Errorf("WinProbability(%f, %f, %f) = %f; want %f", tt.home, tt.away, tt.hfa, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPreseasonRatings(t *testing.T) {
+	config := Config{InitialRating: 1500, PreseasonRegression: 0.25}
+	previous := map[int]float64{1: 1700, 2: 1300}
+
+	result := PreseasonRatings([]int{1, 2, 3}, previous, config)
+
+	expected := map[int]float64{1: 1650, 2: 1350, 3: 1500}
+	for teamID, rating := range expected {
+		if math.Abs(result[teamID]-rating) > 0.000001 {
+			t.Errorf("Team %d preseason rating = %f; want %f", teamID, result[teamID], rating)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	config := Config{InitialRating: 1500, KFactor: 20, HomeFieldAdvantage: 0, MarginOfVictory: false}
+	games := []Game{
+		{ID: 1, HomeTeamID: 1, AwayTeamID: 2, HomeScore: intPtr(24), AwayScore: intPtr(10), IsFinal: true},
+		{ID: 2, HomeTeamID: 2, AwayTeamID: 1, IsFinal: false},
+	}
+
+	result := Calculate([]int{1, 2}, nil, games, config)
+
+	// Even teams: winner gains K * 0.5 and loser loses the same
+	if result.Ratings[1] != 1510 || result.Ratings[2] != 1490 {
+		t.Errorf("Ratings after one game = %v; want team 1 at 1510 and team 2 at 1490", result.Ratings)
+	}
+
+	if result.GamesPlayed[1] != 1 || result.GamesPlayed[2] != 1 {
+		t.Errorf("Games played = %v; want 1 each", result.GamesPlayed)
+	}
+
+	if len(result.Games) != 2 {
+		t.Fatalf("Expected probabilities for 2 games, got %d", len(result.Games))
+	}
+	if result.Games[0].HomeWinProbability != 0.5 {
+		t.Errorf("First game home win probability = %f; want 0.5", result.Games[0].HomeWinProbability)
+	}
+
+	// Upcoming game uses ratings after the first game
+	if result.Games[1].HomeRating != 1490 || result.Games[1].AwayRating != 1510 {
+		t.Errorf("Upcoming game ratings = %f vs %f; want 1490 vs 1510", result.Games[1].HomeRating, result.Games[1].AwayRating)
+	}
+}
+
+func TestMarginOfVictoryScalesShift(t *testing.T) {
+	config := Config{InitialRating: 1500, KFactor: 20, HomeFieldAdvantage: 0, MarginOfVictory: true}
+	close := []Game{{ID: 1, HomeTeamID: 1, AwayTeamID: 2, HomeScore: intPtr(21), AwayScore: intPtr(20), IsFinal: true}}
+	blowout := []Game{{ID: 1, HomeTeamID: 1, AwayTeamID: 2, HomeScore: intPtr(45), AwayScore: intPtr(3), IsFinal: true}}
+
+	closeResult := Calculate([]int{1, 2}, nil, close, config)
+	blowoutResult := Calculate([]int{1, 2}, nil, blowout, config)
+
+	if blowoutResult.Ratings[1] <= closeResult.Ratings[1] {
+		t.Errorf("Blowout win rating %f should exceed close win rating %f", blowoutResult.Ratings[1], closeResult.Ratings[1])
+	}
+}
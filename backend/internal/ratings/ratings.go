@@ -0,0 +1,179 @@
+// Loads games for ratings calculations and stores calculated ratings
+
+package ratings
+
+import (
+	"database/sql"
+	"fmt"
+
+	"gamescript/internal/database"
+)
+
+
+// Recalculates and stores team ratings and per-game win probabilities for a season
+func RefreshSeason(db *database.DB, seasonID int) error {
+	var sportID, startYear int
+	err := db.Conn.QueryRow(`
+		SELECT sport_id, start_year FROM seasons WHERE id = $1
+	`, seasonID).Scan(&sportID, &startYear)
+	if err != nil {
+		return fmt.Errorf("season %d not found: %w", seasonID, err)
+	}
+
+	config := DefaultConfig(sportID)
+
+	teamIDs, err := getSeasonTeamIDs(db, seasonID)
+	if err != nil {
+		return err
+	}
+
+	preseason, err := getPreseasonRatings(db, seasonID, sportID, startYear, teamIDs, config)
+	if err != nil {
+		return err
+	}
+
+	games, err := getSeasonGames(db, seasonID)
+	if err != nil {
+		return err
+	}
+
+	result := Calculate(teamIDs, preseason, games, config)
+
+	return saveResult(db, seasonID, result)
+}
+
+// Carries over last season's final ratings by ESPN ID and regresses them toward the mean
+func getPreseasonRatings(db *database.DB, seasonID int, sportID int, startYear int, teamIDs []int, config Config) (map[int]float64, error) {
+	var previousSeasonID int
+	err := db.Conn.QueryRow(`
+		SELECT id FROM seasons WHERE sport_id = $1 AND start_year = $2
+	`, sportID, startYear-1).Scan(&previousSeasonID)
+	if err == sql.ErrNoRows {
+		return PreseasonRatings(teamIDs, nil, config), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	previousTeamIDs, err := getSeasonTeamIDs(db, previousSeasonID)
+	if err != nil {
+		return nil, err
+	}
+	previousGames, err := getSeasonGames(db, previousSeasonID)
+	if err != nil {
+		return nil, err
+	}
+	previousResult := Calculate(previousTeamIDs, nil, previousGames, config)
+
+	// Map last season's team IDs to this season's teams
+	rows, err := db.Query(`
+		SELECT current.id, previous.id
+		FROM teams current
+		JOIN teams previous ON previous.espn_id = current.espn_id AND previous.season_id = $2
+		WHERE current.season_id = $1
+	`, seasonID, previousSeasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	previous := make(map[int]float64)
+	for rows.Next() {
+		var currentTeamID, previousTeamID int
+		if err := rows.Scan(&currentTeamID, &previousTeamID); err != nil {
+			return nil, err
+		}
+		if rating, exists := previousResult.Ratings[previousTeamID]; exists {
+			previous[currentTeamID] = rating
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return PreseasonRatings(teamIDs, previous, config), nil
+}
+
+func getSeasonTeamIDs(db *database.DB, seasonID int) ([]int, error) {
+	rows, err := db.Query(`SELECT id FROM teams WHERE season_id = $1 ORDER BY id`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teamIDs []int
+	for rows.Next() {
+		var teamID int
+		if err := rows.Scan(&teamID); err != nil {
+			return nil, err
+		}
+		teamIDs = append(teamIDs, teamID)
+	}
+
+	return teamIDs, rows.Err()
+}
+
+// Gets every game in a season in chronological order
+func getSeasonGames(db *database.DB, seasonID int) ([]Game, error) {
+	rows, err := db.Query(`
+		SELECT id, home_team_id, away_team_id, home_score, away_score, status
+		FROM games
+		WHERE season_id = $1
+		ORDER BY start_time, id
+	`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []Game
+	for rows.Next() {
+		var game Game
+		var status *string
+		if err := rows.Scan(&game.ID, &game.HomeTeamID, &game.AwayTeamID, &game.HomeScore, &game.AwayScore, &status); err != nil {
+			return nil, err
+		}
+		game.IsFinal = status != nil && *status == "final"
+		games = append(games, game)
+	}
+
+	return games, rows.Err()
+}
+
+// Replaces a season's stored ratings and win probabilities in one transaction
+func saveResult(db *database.DB, seasonID int, result Result) error {
+	tx, err := db.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM team_ratings WHERE season_id = $1`, seasonID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM game_win_probabilities WHERE season_id = $1`, seasonID); err != nil {
+		return err
+	}
+
+	for teamID, rating := range result.Ratings {
+		_, err := tx.Exec(`
+			INSERT INTO team_ratings (season_id, team_id, rating, preseason_rating, games_played)
+			VALUES ($1, $2, $3, $4, $5)
+		`, seasonID, teamID, rating, result.PreseasonRatings[teamID], result.GamesPlayed[teamID])
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, game := range result.Games {
+		_, err := tx.Exec(`
+			INSERT INTO game_win_probabilities (game_id, season_id, home_rating, away_rating, home_win_probability)
+			VALUES ($1, $2, $3, $4, $5)
+		`, game.GameID, seasonID, game.HomeRating, game.AwayRating, game.HomeWinProbability)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,347 @@
+// Pure Go rendering of a scenario's playoff picture as a shareable image
+
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+	"sync"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+
+const (
+	CardWidth  = 1600
+	CardHeight = 900
+
+	headerHeight     = 110
+	seedColumnWidth  = 340
+	cardPadding      = 24
+	championHeight   = 150
+	logoSize         = 40
+	matchupRowHeight = 30
+)
+
+var (
+	backgroundColor = color.RGBA{0x11, 0x18, 0x27, 0xff}
+	panelColor      = color.RGBA{0x1f, 0x29, 0x37, 0xff}
+	textColor       = color.RGBA{0xf9, 0xfa, 0xfb, 0xff}
+	mutedTextColor  = color.RGBA{0x9c, 0xa3, 0xaf, 0xff}
+	loserTextColor  = color.RGBA{0x6b, 0x72, 0x80, 0xff}
+)
+
+type CardTeam struct {
+	ID             int
+	Abbreviation   string
+	City           string
+	Name           string
+	PrimaryColor   string
+	SecondaryColor string
+	Record         string
+	Logo           image.Image
+}
+
+type CardSeed struct {
+	Seed int
+	Team CardTeam
+}
+
+type CardConference struct {
+	Name  string
+	Seeds []CardSeed
+}
+
+// Single playoff game or series; Winner is nil until picked
+type CardMatchup struct {
+	HigherSeed int
+	LowerSeed  int
+	HigherTeam CardTeam
+	LowerTeam  CardTeam
+	WinnerID   *int
+}
+
+type CardRound struct {
+	Name     string
+	Matchups []CardMatchup
+}
+
+type Card struct {
+	Title       string
+	Subtitle    string
+	Conferences [2]CardConference
+	Rounds      []CardRound
+	Champion    *CardTeam
+}
+
+type fontSet struct {
+	title    font.Face
+	subtitle font.Face
+	large    font.Face
+	medium   font.Face
+	small    font.Face
+}
+
+var (
+	fonts     *fontSet
+	fontsErr  error
+	fontsOnce sync.Once
+)
+
+// Parses embedded Go fonts once
+func loadFonts() (*fontSet, error) {
+	fontsOnce.Do(func() {
+		bold, err := opentype.Parse(gobold.TTF)
+		if err != nil {
+			fontsErr = err
+			return
+		}
+		regular, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			fontsErr = err
+			return
+		}
+
+		newFace := func(f *opentype.Font, size float64) font.Face {
+			if err != nil {
+				return nil
+			}
+			var face font.Face
+			face, err = opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+			return face
+		}
+
+		set := &fontSet{
+			title:    newFace(bold, 44),
+			subtitle: newFace(regular, 22),
+			large:    newFace(bold, 30),
+			medium:   newFace(bold, 20),
+			small:    newFace(regular, 16),
+		}
+		if err != nil {
+			fontsErr = err
+			return
+		}
+		fonts = set
+	})
+	return fonts, fontsErr
+}
+
+// Draws the card: seeds on both sides, bracket rounds in the middle, champion at the bottom
+func RenderCard(card Card) (*image.RGBA, error) {
+	faces, err := loadFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, CardWidth, CardHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{backgroundColor}, image.Point{}, draw.Src)
+
+	// Header
+	drawText(img, faces.title, card.Title, cardPadding, 60, textColor)
+	drawText(img, faces.subtitle, card.Subtitle, cardPadding, 94, mutedTextColor)
+
+	// Seed columns
+	top := headerHeight + cardPadding/2
+	bottom := CardHeight - cardPadding
+	drawSeedColumn(img, faces, card.Conferences[0], image.Rect(cardPadding, top, cardPadding+seedColumnWidth, bottom))
+	drawSeedColumn(img, faces, card.Conferences[1], image.Rect(CardWidth-cardPadding-seedColumnWidth, top, CardWidth-cardPadding, bottom))
+
+	// Bracket and champion in the middle
+	middle := image.Rect(cardPadding*2+seedColumnWidth, top, CardWidth-cardPadding*2-seedColumnWidth, bottom)
+	bracketArea := image.Rect(middle.Min.X, middle.Min.Y, middle.Max.X, middle.Max.Y-championHeight-cardPadding/2)
+	drawBracket(img, faces, card.Rounds, bracketArea)
+	drawChampion(img, faces, card.Champion, image.Rect(middle.Min.X, middle.Max.Y-championHeight, middle.Max.X, middle.Max.Y))
+
+	return img, nil
+}
+
+func drawSeedColumn(img *image.RGBA, faces *fontSet, conference CardConference, area image.Rectangle) {
+	fillRect(img, area, panelColor)
+	drawText(img, faces.medium, conference.Name, area.Min.X+16, area.Min.Y+32, textColor)
+
+	if len(conference.Seeds) == 0 {
+		drawText(img, faces.small, "No standings yet", area.Min.X+16, area.Min.Y+64, mutedTextColor)
+		return
+	}
+
+	listTop := area.Min.Y + 48
+	rowHeight := (area.Max.Y - listTop - 8) / len(conference.Seeds)
+	if rowHeight > 76 {
+		rowHeight = 76
+	}
+
+	for i, seed := range conference.Seeds {
+		row := image.Rect(area.Min.X+8, listTop+i*rowHeight, area.Max.X-8, listTop+(i+1)*rowHeight-6)
+		primary := parseHexColor(seed.Team.PrimaryColor, panelColor)
+		secondary := parseHexColor(seed.Team.SecondaryColor, textColor)
+
+		// Team color band with a secondary color accent on the left edge
+		fillRect(img, row, primary)
+		fillRect(img, image.Rect(row.Min.X, row.Min.Y, row.Min.X+6, row.Max.Y), secondary)
+
+		centerY := (row.Min.Y + row.Max.Y) / 2
+		onPrimary := contrastingText(primary)
+		drawText(img, faces.medium, strconv.Itoa(seed.Seed), row.Min.X+16, centerY+7, onPrimary)
+		drawLogo(img, faces, seed.Team, image.Pt(row.Min.X+48, centerY-logoSize/2))
+		drawText(img, faces.medium, seed.Team.Abbreviation, row.Min.X+48+logoSize+12, centerY+7, onPrimary)
+		if seed.Team.Record != "" {
+			drawTextRight(img, faces.small, seed.Team.Record, row.Max.X-12, centerY+6, onPrimary)
+		}
+	}
+}
+
+func drawBracket(img *image.RGBA, faces *fontSet, rounds []CardRound, area image.Rectangle) {
+	if len(rounds) == 0 {
+		fillRect(img, area, panelColor)
+		drawTextCentered(img, faces.medium, "Playoff bracket not started", (area.Min.X+area.Max.X)/2, (area.Min.Y+area.Max.Y)/2, mutedTextColor)
+		return
+	}
+
+	columnGap := 8
+	columnWidth := (area.Dx() - columnGap*(len(rounds)-1)) / len(rounds)
+	for i, round := range rounds {
+		column := image.Rect(area.Min.X+i*(columnWidth+columnGap), area.Min.Y, area.Min.X+i*(columnWidth+columnGap)+columnWidth, area.Max.Y)
+		fillRect(img, column, panelColor)
+		drawTextCentered(img, faces.small, round.Name, (column.Min.X+column.Max.X)/2, column.Min.Y+22, mutedTextColor)
+
+		if len(round.Matchups) == 0 {
+			continue
+		}
+
+		// Spread matchups evenly down the column
+		listTop := column.Min.Y + 34
+		slotHeight := (column.Max.Y - listTop) / len(round.Matchups)
+		for j, matchup := range round.Matchups {
+			boxTop := listTop + j*slotHeight + (slotHeight-matchupRowHeight*2)/2
+			box := image.Rect(column.Min.X+6, boxTop, column.Max.X-6, boxTop+matchupRowHeight*2)
+			drawMatchupRow(img, faces, matchup.HigherSeed, matchup.HigherTeam, matchup.WinnerID, image.Rect(box.Min.X, box.Min.Y, box.Max.X, box.Min.Y+matchupRowHeight-1))
+			drawMatchupRow(img, faces, matchup.LowerSeed, matchup.LowerTeam, matchup.WinnerID, image.Rect(box.Min.X, box.Min.Y+matchupRowHeight, box.Max.X, box.Max.Y-1))
+		}
+	}
+}
+
+// Winners are drawn in team colors, losers are dimmed
+func drawMatchupRow(img *image.RGBA, faces *fontSet, seed int, team CardTeam, winnerID *int, row image.Rectangle) {
+	isWinner := winnerID != nil && *winnerID == team.ID
+	isLoser := winnerID != nil && *winnerID != team.ID
+
+	background := backgroundColor
+	foreground := textColor
+	if isWinner {
+		background = parseHexColor(team.PrimaryColor, panelColor)
+		foreground = contrastingText(background)
+	} else if isLoser {
+		foreground = loserTextColor
+	}
+
+	fillRect(img, row, background)
+	fillRect(img, image.Rect(row.Min.X, row.Min.Y, row.Min.X+4, row.Max.Y), parseHexColor(team.SecondaryColor, mutedTextColor))
+
+	label := team.Abbreviation
+	if seed > 0 {
+		label = fmt.Sprintf("%d  %s", seed, team.Abbreviation)
+	}
+	drawText(img, faces.small, label, row.Min.X+12, (row.Min.Y+row.Max.Y)/2+6, foreground)
+}
+
+func drawChampion(img *image.RGBA, faces *fontSet, champion *CardTeam, area image.Rectangle) {
+	centerX := (area.Min.X + area.Max.X) / 2
+	if champion == nil {
+		fillRect(img, area, panelColor)
+		drawTextCentered(img, faces.small, "CHAMPION", centerX, area.Min.Y+30, mutedTextColor)
+		drawTextCentered(img, faces.large, "TBD", centerX, area.Min.Y+90, mutedTextColor)
+		return
+	}
+
+	primary := parseHexColor(champion.PrimaryColor, panelColor)
+	secondary := parseHexColor(champion.SecondaryColor, textColor)
+	fillRect(img, area, primary)
+	fillRect(img, image.Rect(area.Min.X, area.Max.Y-10, area.Max.X, area.Max.Y), secondary)
+
+	onPrimary := contrastingText(primary)
+	drawTextCentered(img, faces.small, "CHAMPION", centerX, area.Min.Y+30, onPrimary)
+
+	// Large logo beside the team name
+	name := strings.TrimSpace(champion.City + " " + champion.Name)
+	nameWidth := font.MeasureString(faces.large, name).Ceil()
+	logoExtent := 80
+	startX := centerX - (nameWidth+logoExtent+16)/2
+	drawScaledLogo(img, faces, *champion, image.Rect(startX, area.Min.Y+44, startX+logoExtent, area.Min.Y+44+logoExtent))
+	drawText(img, faces.large, name, startX+logoExtent+16, area.Min.Y+96, onPrimary)
+}
+
+func drawLogo(img *image.RGBA, faces *fontSet, team CardTeam, at image.Point) {
+	drawScaledLogo(img, faces, team, image.Rect(at.X, at.Y, at.X+logoSize, at.Y+logoSize))
+}
+
+// Draws a team logo scaled into the rectangle, or a secondary color badge when there is no logo
+func drawScaledLogo(img *image.RGBA, faces *fontSet, team CardTeam, rect image.Rectangle) {
+	if team.Logo != nil {
+		xdraw.CatmullRom.Scale(img, rect, team.Logo, team.Logo.Bounds(), xdraw.Over, nil)
+		return
+	}
+
+	badge := parseHexColor(team.SecondaryColor, mutedTextColor)
+	fillRect(img, rect, badge)
+	initial := team.Abbreviation
+	if len(initial) > 1 {
+		initial = initial[:1]
+	}
+	drawTextCentered(img, faces.small, initial, (rect.Min.X+rect.Max.X)/2, (rect.Min.Y+rect.Max.Y)/2+6, contrastingText(badge))
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.Color) {
+	draw.Draw(img, rect, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+func drawText(img *image.RGBA, face font.Face, text string, x int, y int, c color.Color) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{c},
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+func drawTextCentered(img *image.RGBA, face font.Face, text string, centerX int, y int, c color.Color) {
+	width := font.MeasureString(face, text).Ceil()
+	drawText(img, face, text, centerX-width/2, y, c)
+}
+
+func drawTextRight(img *image.RGBA, face font.Face, text string, rightX int, y int, c color.Color) {
+	width := font.MeasureString(face, text).Ceil()
+	drawText(img, face, text, rightX-width, y, c)
+}
+
+// Parses a hex color like "a40227" or "#a40227", returning the fallback if it is invalid
+func parseHexColor(hex string, fallback color.RGBA) color.RGBA {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(hex) != 6 {
+		return fallback
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return fallback
+	}
+	return color.RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 0xff}
+}
+
+// Picks black or white text depending on background luminance
+func contrastingText(background color.RGBA) color.RGBA {
+	luminance := 0.299*float64(background.R) + 0.587*float64(background.G) + 0.114*float64(background.B)
+	if luminance > 160 {
+		return color.RGBA{0x11, 0x18, 0x27, 0xff}
+	}
+	return textColor
+}
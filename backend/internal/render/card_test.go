@@ -0,0 +1,60 @@
+package render
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	fallback := color.RGBA{1, 2, 3, 0xff}
+	tests := []struct {
+		name     string
+		hex      string
+		expected color.RGBA
+	}{
+		{"Without hash", "a40227", color.RGBA{0xa4, 0x02, 0x27, 0xff}},
+		{"With hash", "#FACE07", color.RGBA{0xfa, 0xce, 0x07, 0xff}},
+		{"Empty", "", fallback},
+		{"Invalid", "zzzzzz", fallback},
+		{"Short form", "fff", fallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := parseHexColor(tt.hex, fallback); result != tt.expected {
+				t.Errorf("parseHexColor(%q) = %v; want %v", tt.hex, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderCard(t *testing.T) {
+	chiefs := CardTeam{ID: 1, Abbreviation: "KC", City: "Kansas City", Name: "Chiefs", PrimaryColor: "e31837", SecondaryColor: "ffb612", Record: "15-2"}
+	bills := CardTeam{ID: 2, Abbreviation: "BUF", City: "Buffalo", Name: "Bills", PrimaryColor: "00338d", SecondaryColor: "c60c30", Record: "13-4"}
+
+	card := Card{
+		Title:    "My Scenario",
+		Subtitle: "NFL 2025-26 Playoff Picture",
+		Conferences: [2]CardConference{
+			{Name: "AFC", Seeds: []CardSeed{{Seed: 1, Team: chiefs}, {Seed: 2, Team: bills}}},
+			{Name: "NFC"},
+		},
+		Rounds: []CardRound{
+			{Name: "Conference", Matchups: []CardMatchup{{HigherSeed: 1, LowerSeed: 2, HigherTeam: chiefs, LowerTeam: bills, WinnerID: &chiefs.ID}}},
+		},
+		Champion: &chiefs,
+	}
+
+	img, err := RenderCard(card)
+	if err != nil {
+		t.Fatalf("RenderCard returned error: %v", err)
+	}
+	if img.Bounds().Dx() != CardWidth || img.Bounds().Dy() != CardHeight {
+		t.Errorf("Card size = %v; want %dx%d", img.Bounds().Size(), CardWidth, CardHeight)
+	}
+
+	// Champion panel is filled with the champion's primary color
+	if got := img.RGBAAt(CardWidth/2, CardHeight-cardPadding-20); got != parseHexColor(chiefs.PrimaryColor, color.RGBA{}) {
+		t.Errorf("Champion panel color = %v; want %v", got, parseHexColor(chiefs.PrimaryColor, color.RGBA{}))
+	}
+}
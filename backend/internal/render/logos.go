@@ -0,0 +1,161 @@
+// Downloads team logos once and caches them on local disk
+
+package render
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+
+const (
+	// Maximum logo download size
+	maxLogoBytes = 2 * 1024 * 1024
+
+	// Logos are kept in memory at this size, large enough for the champion panel
+	cachedLogoSize = 160
+)
+
+type LogoCache struct {
+	dir    string
+	client *http.Client
+	mu     sync.Mutex
+	images map[string]image.Image
+}
+
+// Creates a logo cache in LOGO_CACHE_DIR, or a gamescript-logos folder in the system temp directory
+func NewLogoCache() *LogoCache {
+	dir := os.Getenv("LOGO_CACHE_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "gamescript-logos")
+	}
+
+	return &LogoCache{
+		dir:    dir,
+		client: &http.Client{Timeout: 5 * time.Second},
+		images: make(map[string]image.Image),
+	}
+}
+
+// Returns the logo at a URL from memory, then disk, downloading it on first use; nil if it can't be loaded
+func (cache *LogoCache) Get(url string) image.Image {
+	if url == "" {
+		return nil
+	}
+
+	cache.mu.Lock()
+	img, exists := cache.images[url]
+	cache.mu.Unlock()
+	if exists {
+		return img
+	}
+
+	// Load without holding the lock so logos can be fetched concurrently; failures are remembered as nil
+	img, err := cache.load(url)
+	if err != nil {
+		img = nil
+	}
+
+	cache.mu.Lock()
+	cache.images[url] = img
+	cache.mu.Unlock()
+
+	return img
+}
+
+// Loads logos for many URLs concurrently
+func (cache *LogoCache) GetAll(urls []string) map[string]image.Image {
+	logos := make(map[string]image.Image, len(urls))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, url := range urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			img := cache.Get(url)
+			mu.Lock()
+			logos[url] = img
+			mu.Unlock()
+		}(url)
+	}
+	wg.Wait()
+
+	return logos
+}
+
+func (cache *LogoCache) load(url string) (image.Image, error) {
+	sum := sha1.Sum([]byte(url))
+	path := filepath.Join(cache.dir, hex.EncodeToString(sum[:])+filepath.Ext(url))
+
+	if img, err := decodeImageFile(path); err == nil {
+		return img, nil
+	}
+
+	resp, err := cache.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("logo request returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLogoBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(cache.dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, err
+	}
+
+	return decodeImageFile(path)
+}
+
+// Decodes an image file and shrinks it to the cached logo size
+func decodeImageFile(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() <= cachedLogoSize && bounds.Dy() <= cachedLogoSize {
+		return img, nil
+	}
+
+	// Keep aspect ratio within a square
+	width, height := cachedLogoSize, cachedLogoSize
+	if bounds.Dx() > bounds.Dy() {
+		height = bounds.Dy() * cachedLogoSize / bounds.Dx()
+	} else if bounds.Dy() > bounds.Dx() {
+		width = bounds.Dx() * cachedLogoSize / bounds.Dy()
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, xdraw.Src, nil)
+	return thumbnail, nil
+}
@@ -3,11 +3,10 @@
 package scheduler
 
 import (
-	"fmt"
 	"log"
 	"time"
 
-	"gamescript/internal/models"
+	"gamescript/internal/importer"
 	"gamescript/internal/services/espn"
 )
 
@@ -64,11 +63,7 @@ func (s *Scheduler) updateNFLSchedule() {
 	client := espn.NewClient()
 	
 	// Determine NFL season year
-	now := time.Now()
-	seasonYear := now.Year()
-	if now.Month() <= 2 {
-		seasonYear = now.Year() - 1
-	}
+	seasonYear := importer.CurrentSeasonYear(importer.SportNFL, time.Now())
 
 	// Fetch entire NFL season
 	games, err := client.FetchEntireNFLSeason(seasonYear)
@@ -79,80 +74,32 @@ func (s *Scheduler) updateNFLSchedule() {
 
 	// Update games in database
 	updated := 0
+	unchanged := 0
 	errors := 0
+	seasonIDs := make(map[int]bool)
+	changedSeasonIDs := make(map[int]bool)
 	for _, game := range games {
-		if err := s.updateNFLGame(game); err != nil {
+		changed, err := importer.UpsertGame(s.db.Conn, game.SeasonID, game)
+		if err != nil {
 			log.Printf("Error updating NFL game %s: %v", game.ESPNID, err)
 			errors++
 			continue
 		}
+		seasonIDs[game.SeasonID] = true
+		if !changed {
+			unchanged++
+			continue
+		}
+		changedSeasonIDs[game.SeasonID] = true
 		updated++
 	}
 
 	duration := time.Since(startTime)
-	log.Printf("NFL schedule update completed in %v: %d games updated, %d errors", duration, updated, errors)
-}
+	log.Printf("NFL schedule update completed in %v: %d games updated, %d unchanged, %d errors", duration, updated, unchanged, errors)
 
-func (s *Scheduler) updateNFLGame(game models.Game) error {
-    stmt := `
-        INSERT INTO games (
-            season_id, espn_id, home_team_id, away_team_id, start_time,
-            day_of_week, week, location, primetime, network,
-            home_score, away_score, status
-        ) VALUES (
-         	$1, $2,
-            (SELECT id FROM teams WHERE season_id = $1 AND espn_id = $3),
-            (SELECT id FROM teams WHERE season_id = $1 AND espn_id = $4),
-            $5, $6, $7, $8, $9, $10, $11, $12, $13
-        )
-        ON CONFLICT (season_id, espn_id) DO UPDATE SET
-            start_time = EXCLUDED.start_time,
-            day_of_week = EXCLUDED.day_of_week,
-            week = EXCLUDED.week,
-            location = EXCLUDED.location,
-            primetime = EXCLUDED.primetime,
-            network = EXCLUDED.network,
-            home_score = EXCLUDED.home_score,
-            away_score = EXCLUDED.away_score,
-            status = EXCLUDED.status
-    `
-
-    // Update scores only if game is final
-    var homeScore, awayScore *int
-    if game.Status != nil && *game.Status == "final" {
-        homeScore = game.HomeScore
-        awayScore = game.AwayScore
-    }
-
-    result, err := s.db.Conn.Exec(
-        stmt,
-        game.SeasonID,
-        game.ESPNID,
-        *game.HomeTeamESPNID,
-        *game.AwayTeamESPNID,
-        game.StartTime,
-        game.DayOfWeek,
-        game.Week,
-        game.Location,
-        game.Primetime,
-        game.Network,
-        homeScore,   // Will be NULL for upcoming games
-        awayScore,   // Will be NULL for upcoming games
-        game.Status,
-    )
-    if err != nil {
-        return fmt.Errorf("database error: %w", err)
-    }
-
-    rowsAffected, err := result.RowsAffected()
-    if err != nil {
-        return fmt.Errorf("error checking rows affected: %w", err)
-    }
-    if rowsAffected == 0 {
-        return fmt.Errorf("no game found with espn_id %s", game.ESPNID)
-    }
-
-    return nil
+	s.invalidateStandings(changedSeasonIDs)
+	s.refreshRatings(seasonIDs)
+	s.gradeLeagues(changedSeasonIDs)
 }
 
 // Public method for manual triggering
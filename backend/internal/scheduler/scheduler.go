@@ -6,6 +6,9 @@ import (
 	"log"
 
 	"gamescript/internal/database"
+	"gamescript/internal/leagues"
+	"gamescript/internal/ratings"
+	"gamescript/internal/standings"
 )
 
 
@@ -37,4 +40,36 @@ func (s *Scheduler) Start() {
 func (s *Scheduler) Stop() {
 	log.Println("Stopping all schedulers...")
 	close(s.quit)
-}
\ No newline at end of file
+}
+
+// Drops cached standings for each season whose games changed in a schedule update
+func (s *Scheduler) invalidateStandings(seasonIDs map[int]bool) {
+	for seasonID := range seasonIDs {
+		standings.InvalidateSeason(seasonID)
+	}
+}
+
+// Recalculates team ratings for each season touched by a schedule update
+func (s *Scheduler) refreshRatings(seasonIDs map[int]bool) {
+	for seasonID := range seasonIDs {
+		if err := ratings.RefreshSeason(s.db, seasonID); err != nil {
+			log.Printf("Error refreshing ratings for season %d: %v", seasonID, err)
+			continue
+		}
+		log.Printf("Ratings refreshed for season %d", seasonID)
+	}
+}
+
+// Grades pick'em league entries for each season whose games changed in a schedule update
+func (s *Scheduler) gradeLeagues(seasonIDs map[int]bool) {
+	for seasonID := range seasonIDs {
+		graded, err := leagues.GradeSeason(s.db, seasonID)
+		if err != nil {
+			log.Printf("Error grading leagues for season %d: %v", seasonID, err)
+			continue
+		}
+		if graded > 0 {
+			log.Printf("League entries graded for season %d: %d entries", seasonID, graded)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Monte Carlo season simulation of undecided games using team rating win probabilities
+
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"gamescript/internal/database"
+	"gamescript/internal/standings"
+)
+
+
+// Last seed that makes the playoffs outright and, for the NBA, the last seed in the play-in tournament
+const (
+	nflPlayoffSeeds = 7
+	nbaPlayoffSeeds = 6
+	nbaPlayInSeeds  = 10
+)
+
+// Undecided game and the home team's chance of winning it
+type Game struct {
+	GameID             int
+	HomeTeamID         int
+	AwayTeamID         int
+	Week               int
+	HomeWinProbability float64
+}
+
+// How often a team reached each outcome across every simulated season
+type TeamOdds struct {
+	TeamID        int
+	TeamAbbr      string
+	TeamCity      string
+	TeamName      string
+	Conference    string
+	Division      string
+	AverageWins   float64
+	Playoffs      float64 // Share of seasons with a playoff seed (NFL 1-7, NBA 1-6)
+	PlayIn        float64 // Share of seasons with an NBA play-in seed (7-10)
+	DivisionTitle float64
+	TopSeed       float64
+}
+
+// Loads every game in a season with its win probability, defaulting to a coin flip when ratings haven't been calculated
+func LoadGames(db *database.DB, seasonID int) ([]Game, error) {
+	rows, err := db.Query(`
+		SELECT game.id, game.home_team_id, game.away_team_id, COALESCE(game.week, 0),
+			COALESCE(probability.home_win_probability, 0.5)
+		FROM games game
+		LEFT JOIN game_win_probabilities probability ON probability.game_id = game.id
+		WHERE game.season_id = $1
+		ORDER BY game.start_time
+	`, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting games: %w", err)
+	}
+	defer rows.Close()
+
+	var games []Game
+	for rows.Next() {
+		var game Game
+		if err := rows.Scan(&game.GameID, &game.HomeTeamID, &game.AwayTeamID, &game.Week, &game.HomeWinProbability); err != nil {
+			return nil, fmt.Errorf("error scanning game: %w", err)
+		}
+		games = append(games, game)
+	}
+	return games, rows.Err()
+}
+
+// Filters games down to those without a result
+func Remaining(games []Game, decidedGameIDs map[int]bool) []Game {
+	var remaining []Game
+	for _, game := range games {
+		if !decidedGameIDs[game.GameID] {
+			remaining = append(remaining, game)
+		}
+	}
+	return remaining
+}
+
+// Simulates the rest of an NFL season the given number of times on top of its decided results
+func SimulateNFL(teams []standings.NFLTeamRecord, results []standings.NFLGameResult, remaining []Game, iterations int, rng *rand.Rand) []TeamOdds {
+	odds := make(map[int]*TeamOdds)
+	for _, team := range teams {
+		odds[team.TeamID] = &TeamOdds{
+			TeamID:     team.TeamID,
+			TeamAbbr:   team.TeamAbbr,
+			TeamCity:   team.TeamCity,
+			TeamName:   team.TeamName,
+			Conference: team.Conference,
+			Division:   team.Division,
+		}
+	}
+
+	games := make([]standings.NFLGameResult, len(results), len(results)+len(remaining))
+	copy(games, results)
+	for i := 0; i < iterations; i++ {
+		games = games[:len(results)]
+		for _, game := range remaining {
+			homeScore, awayScore := simulateScore(game, rng)
+			games = append(games, standings.NFLGameResult{
+				GameID:     game.GameID,
+				HomeTeamID: game.HomeTeamID,
+				AwayTeamID: game.AwayTeamID,
+				HomeScore:  homeScore,
+				AwayScore:  awayScore,
+				Week:       game.Week,
+			})
+		}
+
+		season := standings.BuildNFLStandings(teams, games)
+		for _, conference := range []standings.NFLConferenceStandings{season.AFC, season.NFC} {
+			for _, division := range conference.Divisions {
+				for _, team := range division {
+					odds[team.TeamID].AverageWins += float64(team.Wins)
+				}
+			}
+			for _, seed := range conference.PlayoffSeeds {
+				tallySeed(odds[seed.Team.TeamID], seed.Seed, seed.IsDivisionWinner, nflPlayoffSeeds, nflPlayoffSeeds)
+			}
+		}
+	}
+
+	return finishOdds(odds, iterations)
+}
+
+// Simulates the rest of an NBA season the given number of times on top of its decided results
+func SimulateNBA(teams []standings.NBATeamRecord, results []standings.NBAGameResult, remaining []Game, iterations int, rng *rand.Rand) []TeamOdds {
+	odds := make(map[int]*TeamOdds)
+	for _, team := range teams {
+		odds[team.TeamID] = &TeamOdds{
+			TeamID:     team.TeamID,
+			TeamAbbr:   team.TeamAbbr,
+			TeamCity:   team.TeamCity,
+			TeamName:   team.TeamName,
+			Conference: team.Conference,
+			Division:   team.Division,
+		}
+	}
+
+	games := make([]standings.NBAGameResult, len(results), len(results)+len(remaining))
+	copy(games, results)
+	for i := 0; i < iterations; i++ {
+		games = games[:len(results)]
+		for _, game := range remaining {
+			homeScore, awayScore := simulateScore(game, rng)
+			games = append(games, standings.NBAGameResult{
+				GameID:     game.GameID,
+				HomeTeamID: game.HomeTeamID,
+				AwayTeamID: game.AwayTeamID,
+				HomeScore:  homeScore,
+				AwayScore:  awayScore,
+				Week:       game.Week,
+			})
+		}
+
+		season := standings.BuildNBAStandings(teams, games)
+		for _, conference := range []standings.NBAConferenceStandings{season.Eastern, season.Western} {
+			for _, division := range conference.Divisions {
+				for _, team := range division {
+					odds[team.TeamID].AverageWins += float64(team.Wins)
+				}
+			}
+			for _, seed := range conference.PlayoffSeeds {
+				tallySeed(odds[seed.Team.TeamID], seed.Seed, seed.IsDivisionWinner, nbaPlayoffSeeds, nbaPlayInSeeds)
+			}
+		}
+	}
+
+	return finishOdds(odds, iterations)
+}
+
+// Picks a winner by the home team's win probability, scored 1-0 like a pick without predicted scores
+func simulateScore(game Game, rng *rand.Rand) (int, int) {
+	if rng.Float64() < game.HomeWinProbability {
+		return 1, 0
+	}
+	return 0, 1
+}
+
+func tallySeed(odds *TeamOdds, seed int, isDivisionWinner bool, playoffSeeds int, playInSeeds int) {
+	if odds == nil {
+		return
+	}
+
+	if seed <= playoffSeeds {
+		odds.Playoffs++
+	} else if seed <= playInSeeds {
+		odds.PlayIn++
+	}
+	if isDivisionWinner {
+		odds.DivisionTitle++
+	}
+	if seed == 1 {
+		odds.TopSeed++
+	}
+}
+
+// Turns tallies into averages and shares, ordered by conference and then by playoff odds
+func finishOdds(odds map[int]*TeamOdds, iterations int) []TeamOdds {
+	result := make([]TeamOdds, 0, len(odds))
+	for _, teamOdds := range odds {
+		if iterations > 0 {
+			count := float64(iterations)
+			teamOdds.AverageWins /= count
+			teamOdds.Playoffs /= count
+			teamOdds.PlayIn /= count
+			teamOdds.DivisionTitle /= count
+			teamOdds.TopSeed /= count
+		}
+		result = append(result, *teamOdds)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Conference != result[j].Conference {
+			return result[i].Conference < result[j].Conference
+		}
+		if result[i].Playoffs != result[j].Playoffs {
+			return result[i].Playoffs > result[j].Playoffs
+		}
+		if result[i].AverageWins != result[j].AverageWins {
+			return result[i].AverageWins > result[j].AverageWins
+		}
+		return result[i].TeamID < result[j].TeamID
+	})
+
+	return result
+}
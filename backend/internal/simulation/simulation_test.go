@@ -0,0 +1,100 @@
+package simulation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gamescript/internal/standings"
+)
+
+func sumOdds(odds []TeamOdds, field func(TeamOdds) float64) float64 {
+	total := 0.0
+	for _, teamOdds := range odds {
+		total += field(teamOdds)
+	}
+	return total
+}
+
+func TestSimulateNFLTotals(t *testing.T) {
+	teams, games, err := standings.GenerateNFLSeason(standings.PatternRandom, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// First half of the season decided, the rest left to coin flips
+	decided := games[:len(games)/2]
+	var remaining []Game
+	for _, game := range games[len(games)/2:] {
+		remaining = append(remaining, Game{GameID: game.GameID, HomeTeamID: game.HomeTeamID, AwayTeamID: game.AwayTeamID, Week: game.Week, HomeWinProbability: 0.5})
+	}
+
+	odds := SimulateNFL(teams, decided, remaining, 20, rand.New(rand.NewSource(1)))
+
+	// Every simulated season hands out the same number of seeds, titles, and wins
+	tests := []struct {
+		name     string
+		field    func(TeamOdds) float64
+		expected float64
+	}{
+		{"Playoffs", func(o TeamOdds) float64 { return o.Playoffs }, 14},
+		{"PlayIn", func(o TeamOdds) float64 { return o.PlayIn }, 0},
+		{"DivisionTitle", func(o TeamOdds) float64 { return o.DivisionTitle }, 8},
+		{"TopSeed", func(o TeamOdds) float64 { return o.TopSeed }, 2},
+	}
+	for _, tt := range tests {
+		if total := sumOdds(odds, tt.field); math.Abs(total-tt.expected) > 1e-9 {
+			t.Errorf("total %s = %f; want %f", tt.name, total, tt.expected)
+		}
+	}
+
+	// Coin flips can't produce ties, so wins only fall short of games by the decided ties
+	decidedTies := 0
+	for _, game := range decided {
+		if game.HomeScore == game.AwayScore {
+			decidedTies++
+		}
+	}
+	if wins := sumOdds(odds, func(o TeamOdds) float64 { return o.AverageWins }); math.Abs(wins-float64(len(games)-decidedTies)) > 1e-9 {
+		t.Errorf("total average wins = %f; want %d", wins, len(games)-decidedTies)
+	}
+}
+
+func TestSimulateNBACertainOutcomes(t *testing.T) {
+	teams, games, err := standings.GenerateNBASeason(standings.PatternRandom, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Home teams win every remaining game, so every simulated season ends the same way
+	var remaining []Game
+	for _, game := range games {
+		remaining = append(remaining, Game{GameID: game.GameID, HomeTeamID: game.HomeTeamID, AwayTeamID: game.AwayTeamID, Week: game.Week, HomeWinProbability: 1})
+	}
+
+	odds := SimulateNBA(teams, nil, remaining, 5, rand.New(rand.NewSource(1)))
+
+	for _, teamOdds := range odds {
+		for _, share := range []float64{teamOdds.Playoffs, teamOdds.PlayIn, teamOdds.DivisionTitle, teamOdds.TopSeed} {
+			if share != 0 && share != 1 {
+				t.Errorf("team %d has a share of %f with certain outcomes; want 0 or 1", teamOdds.TeamID, share)
+			}
+		}
+	}
+	if total := sumOdds(odds, func(o TeamOdds) float64 { return o.Playoffs }); total != 12 {
+		t.Errorf("total Playoffs = %f; want 12", total)
+	}
+	if total := sumOdds(odds, func(o TeamOdds) float64 { return o.PlayIn }); total != 8 {
+		t.Errorf("total PlayIn = %f; want 8", total)
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	games := []Game{{GameID: 1}, {GameID: 2}, {GameID: 3}}
+
+	remaining := Remaining(games, map[int]bool{2: true})
+
+	if len(remaining) != 2 || remaining[0].GameID != 1 || remaining[1].GameID != 3 {
+		t.Errorf("Remaining() = %v; want games 1 and 3", remaining)
+	}
+}
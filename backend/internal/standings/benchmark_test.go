@@ -0,0 +1,112 @@
+package standings
+
+import (
+	"testing"
+)
+
+func BenchmarkBuildNFLStandings(b *testing.B) {
+	for _, pattern := range SyntheticPatterns() {
+		teams, games, err := GenerateNFLSeason(pattern, 1)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(pattern, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				BuildNFLStandings(teams, games)
+			}
+		})
+	}
+}
+
+func BenchmarkBuildNBAStandings(b *testing.B) {
+	for _, pattern := range SyntheticPatterns() {
+		teams, games, err := GenerateNBASeason(pattern, 1)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(pattern, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				BuildNBAStandings(teams, games)
+			}
+		})
+	}
+}
+
+func BenchmarkBuildNFLGameIndex(b *testing.B) {
+	_, games, err := GenerateNFLSeason(PatternRandom, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		newNFLGameIndex(games)
+	}
+}
+
+// Records and index for a synthetic NFL season, plus its largest group of conference teams with the same record
+func largestNFLConferenceTie(b *testing.B, pattern string) ([]NFLTeamRecord, *gameIndex, []NFLTeamRecord) {
+	teams, games, err := GenerateNFLSeason(pattern, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	records := calculateNFLTeamRecords(teams, games)
+	index := newNFLGameIndex(games)
+	calculateNFLStrengthMetrics(records, index)
+
+	var largest []NFLTeamRecord
+	for _, conference := range []string{"AFC", "NFC"} {
+		groups := make(map[float64][]NFLTeamRecord)
+		for _, team := range filterByNFLConference(records, conference) {
+			groups[team.WinPct] = append(groups[team.WinPct], team)
+		}
+		for _, group := range groups {
+			if len(group) > len(largest) {
+				largest = group
+			}
+		}
+	}
+
+	return records, index, largest
+}
+
+// Every home team winning leaves half of each conference tied at 8-9
+func BenchmarkResolveNFLMultiTeamConferenceTie(b *testing.B) {
+	_, index, tied := largestNFLConferenceTie(b, PatternHomeWins)
+	b.Logf("%d-way tie", len(tied))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resolveNFLMultiTeamConferenceTie(append([]NFLTeamRecord(nil), tied...), index)
+	}
+}
+
+func BenchmarkCompareNFLCommonGames(b *testing.B) {
+	_, index, tied := largestNFLConferenceTie(b, PatternHomeWins)
+	pair := tied[:2]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		compareNFLCommonGames(pair, index, 4)
+	}
+}
+
+// Every game tied leaves all 32 teams tied for the draft
+func BenchmarkResolveNFLMultiTeamDraftTie(b *testing.B) {
+	records, index, _ := largestNFLConferenceTie(b, PatternAllTies)
+	afc := calculateNFLConferenceStandings(filterByNFLConference(records, "AFC"), index)
+	nfc := calculateNFLConferenceStandings(filterByNFLConference(records, "NFC"), index)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resolveNFLMultiTeamDraftTie(append([]NFLTeamRecord(nil), records...), index, afc, nfc)
+	}
+}
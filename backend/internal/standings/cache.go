@@ -0,0 +1,167 @@
+// In-memory cache of full-season standings per scenario
+
+package standings
+
+import (
+	"sync"
+	"time"
+)
+
+
+// Upper bound on how long cached standings are trusted, so writes made outside this process
+// (import scripts, other server instances) still show up eventually
+const cacheTTL = 10 * time.Minute
+
+// Limit on cached scenarios before the oldest entries are evicted
+const cacheMaxEntries = 2000
+
+// Versions of the inputs a cached result was calculated from
+type cacheVersion struct {
+	scenario uint64
+	season   uint64
+}
+
+type cacheEntry struct {
+	seasonID   int
+	version    cacheVersion
+	calculated time.Time
+	standings  interface{}
+}
+
+// Standings cache keyed by scenario. Every scenario and season has a version counter: pick writes bump
+// the scenario's and game writes bump the season's, and an entry is only served while both still match.
+type Cache struct {
+	mu              sync.Mutex
+	enabled         bool
+	scenarioVersion map[int]uint64
+	seasonVersion   map[int]uint64
+	entries         map[int]cacheEntry
+	hits            uint64
+	misses          uint64
+}
+
+// Hit and miss counts since the cache was created or last reset
+type CacheStats struct {
+	Entries int
+	Hits    uint64
+	Misses  uint64
+}
+
+var standingsCache = NewCache()
+
+func NewCache() *Cache {
+	return &Cache{
+		enabled:         true,
+		scenarioVersion: make(map[int]uint64),
+		seasonVersion:   make(map[int]uint64),
+		entries:         make(map[int]cacheEntry),
+	}
+}
+
+// Marks a scenario's standings stale after its picks or settings change
+func InvalidateScenario(scenarioID int) {
+	standingsCache.invalidateScenario(scenarioID)
+}
+
+// Marks every scenario's standings in a season stale after its games change
+func InvalidateSeason(seasonID int) {
+	standingsCache.invalidateSeason(seasonID)
+}
+
+// Turns the standings cache on or off, clearing it either way
+func SetCacheEnabled(enabled bool) {
+	standingsCache.setEnabled(enabled)
+}
+
+func GetCacheStats() CacheStats {
+	return standingsCache.stats()
+}
+
+// Returns cached standings for a scenario if they are still current. The returned version must be
+// passed to store when the standings are calculated after a miss.
+func (cache *Cache) lookup(scenarioID int, seasonID int, now time.Time) (interface{}, cacheVersion, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	version := cacheVersion{scenario: cache.scenarioVersion[scenarioID], season: cache.seasonVersion[seasonID]}
+	if !cache.enabled {
+		return nil, version, false
+	}
+
+	entry, exists := cache.entries[scenarioID]
+	if exists && entry.seasonID == seasonID && entry.version == version && now.Sub(entry.calculated) < cacheTTL {
+		cache.hits++
+		return entry.standings, version, true
+	}
+
+	cache.misses++
+	return nil, version, false
+}
+
+// Stores calculated standings unless a write bumped a version while they were being calculated
+func (cache *Cache) store(scenarioID int, seasonID int, version cacheVersion, now time.Time, standings interface{}) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if !cache.enabled {
+		return
+	}
+	if cache.scenarioVersion[scenarioID] != version.scenario || cache.seasonVersion[seasonID] != version.season {
+		return
+	}
+
+	if _, exists := cache.entries[scenarioID]; !exists && len(cache.entries) >= cacheMaxEntries {
+		cache.evictOldest()
+	}
+	cache.entries[scenarioID] = cacheEntry{seasonID: seasonID, version: version, calculated: now, standings: standings}
+}
+
+func (cache *Cache) invalidateScenario(scenarioID int) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.scenarioVersion[scenarioID]++
+	delete(cache.entries, scenarioID)
+}
+
+func (cache *Cache) invalidateSeason(seasonID int) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.seasonVersion[seasonID]++
+	for scenarioID, entry := range cache.entries {
+		if entry.seasonID == seasonID {
+			delete(cache.entries, scenarioID)
+		}
+	}
+}
+
+func (cache *Cache) setEnabled(enabled bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.enabled = enabled
+	cache.entries = make(map[int]cacheEntry)
+	cache.hits = 0
+	cache.misses = 0
+}
+
+func (cache *Cache) stats() CacheStats {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return CacheStats{Entries: len(cache.entries), Hits: cache.hits, Misses: cache.misses}
+}
+
+// Removes the least recently calculated entry. Callers must hold the lock.
+func (cache *Cache) evictOldest() {
+	oldestID := 0
+	var oldest time.Time
+	for scenarioID, entry := range cache.entries {
+		if oldest.IsZero() || entry.calculated.Before(oldest) {
+			oldestID = scenarioID
+			oldest = entry.calculated
+		}
+	}
+	delete(cache.entries, oldestID)
+}
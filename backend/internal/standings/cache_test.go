@@ -0,0 +1,76 @@
+package standings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheLookup(t *testing.T) {
+	now := time.Date(2025, 11, 2, 12, 0, 0, 0, time.UTC)
+	calculated := &NFLStandings{}
+
+	tests := []struct {
+		name     string
+		change   func(cache *Cache)
+		at       time.Time
+		expected bool
+	}{
+		{"Unchanged", func(cache *Cache) {}, now, true},
+		{"Pick changed", func(cache *Cache) { cache.invalidateScenario(1) }, now, false},
+		{"Other scenario's pick changed", func(cache *Cache) { cache.invalidateScenario(2) }, now, true},
+		{"Games changed", func(cache *Cache) { cache.invalidateSeason(10) }, now, false},
+		{"Other season's games changed", func(cache *Cache) { cache.invalidateSeason(11) }, now, true},
+		{"Expired", func(cache *Cache) {}, now.Add(cacheTTL), false},
+		{"Disabled", func(cache *Cache) { cache.setEnabled(false) }, now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewCache()
+			_, version, _ := cache.lookup(1, 10, now)
+			cache.store(1, 10, version, now, calculated)
+
+			tt.change(cache)
+
+			_, _, ok := cache.lookup(1, 10, tt.at)
+			if ok != tt.expected {
+				t.Errorf("lookup after %s = %v; want %v", tt.name, ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCacheStoreAfterConcurrentWrite(t *testing.T) {
+	now := time.Date(2025, 11, 2, 12, 0, 0, 0, time.UTC)
+	cache := NewCache()
+
+	// A pick lands while standings are being calculated from the old picks
+	_, version, _ := cache.lookup(1, 10, now)
+	cache.invalidateScenario(1)
+	cache.store(1, 10, version, now, &NFLStandings{})
+
+	if _, _, ok := cache.lookup(1, 10, now); ok {
+		t.Errorf("lookup returned standings calculated before a pick changed")
+	}
+}
+
+func TestCacheEvictsOldest(t *testing.T) {
+	now := time.Date(2025, 11, 2, 12, 0, 0, 0, time.UTC)
+	cache := NewCache()
+
+	for scenarioID := 1; scenarioID <= cacheMaxEntries+1; scenarioID++ {
+		at := now.Add(time.Duration(scenarioID) * time.Millisecond)
+		_, version, _ := cache.lookup(scenarioID, 10, at)
+		cache.store(scenarioID, 10, version, at, &NFLStandings{})
+	}
+
+	if entries := cache.stats().Entries; entries != cacheMaxEntries {
+		t.Errorf("cache holds %d entries; want %d", entries, cacheMaxEntries)
+	}
+	if _, _, ok := cache.lookup(1, 10, now); ok {
+		t.Errorf("oldest entry was not evicted")
+	}
+	if _, _, ok := cache.lookup(cacheMaxEntries+1, 10, now); !ok {
+		t.Errorf("newest entry was evicted")
+	}
+}
@@ -0,0 +1,33 @@
+// Partial-season checkpoints for standings
+
+package standings
+
+import (
+	"time"
+)
+
+
+// Limits standings to the games played by a point in the season. The zero value counts every game.
+type Cutoff struct {
+	Week   *int       // Only games in this week or earlier
+	Before *time.Time // Only games starting before this time
+}
+
+// Start of the day after a calendar date, in UTC like game start times, so a cutoff before it
+// covers every game on that date
+func EndOfDate(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+}
+
+func (cutoff Cutoff) IsSet() bool {
+	return cutoff.Week != nil || cutoff.Before != nil
+}
+
+// Formats the start time bound as a timestamp parameter, or nil when there isn't one
+func (cutoff Cutoff) formatBefore() *string {
+	if cutoff.Before == nil {
+		return nil
+	}
+	formatted := cutoff.Before.UTC().Format("2006-01-02 15:04:05.999999")
+	return &formatted
+}
@@ -0,0 +1,56 @@
+package standings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEndOfDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		date     time.Time
+		expected time.Time
+	}{
+		{"Midnight", time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)},
+		{"Late evening", time.Date(2025, 11, 2, 23, 30, 0, 0, time.UTC), time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)},
+		{"End of year", time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EndOfDate(tt.date)
+			if !result.Equal(tt.expected) {
+				t.Errorf("EndOfDate(%v) = %v; want %v", tt.date, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGamesThroughWeek(t *testing.T) {
+	games := []NFLGameResult{
+		{HomeTeamID: 1, AwayTeamID: 2, Week: 1},
+		{HomeTeamID: 3, AwayTeamID: 4, Week: 2},
+		{HomeTeamID: 1, AwayTeamID: 3, Week: 3},
+	}
+	weekOf := func(game NFLGameResult) int { return game.Week }
+
+	tests := []struct {
+		name     string
+		week     int
+		expected int
+	}{
+		{"Before the season", 0, 0},
+		{"First week", 1, 1},
+		{"Middle week", 2, 2},
+		{"Past the last week", 18, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := gamesThroughWeek(games, tt.week, weekOf)
+			if len(result) != tt.expected {
+				t.Errorf("gamesThroughWeek(games, %d) returned %d games; want %d", tt.week, len(result), tt.expected)
+			}
+		})
+	}
+}
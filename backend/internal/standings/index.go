@@ -0,0 +1,155 @@
+// Head-to-head and common-opponent tables built once per standings computation
+
+package standings
+
+
+// A team's record against one opponent
+type matchupRecord struct {
+	wins   int
+	losses int
+	ties   int
+}
+
+func (record matchupRecord) games() int {
+	return record.wins + record.losses + record.ties
+}
+
+func (record *matchupRecord) add(other matchupRecord) {
+	record.wins += other.wins
+	record.losses += other.losses
+	record.ties += other.ties
+}
+
+// Every team's record against every opponent, so tiebreakers look results up instead of rescanning
+// the season's games for each comparison
+type gameIndex struct {
+	positions map[int]int       // Team ID to its row and column in records
+	teamIDs   []int             // Team ID at each position
+	records   [][]matchupRecord // records[team][opponent] is the team's record against the opponent
+	trace     *tiebreakerTrace  // Collects tiebreaker decisions when set, nil otherwise
+}
+
+func newGameIndex() *gameIndex {
+	return &gameIndex{positions: make(map[int]int)}
+}
+
+func newNFLGameIndex(games []NFLGameResult) *gameIndex {
+	index := newGameIndex()
+	for _, game := range games {
+		index.addGame(game.HomeTeamID, game.AwayTeamID, game.HomeScore, game.AwayScore, true)
+	}
+	return index
+}
+
+// NBA games can't end tied, so a game without a higher home score counts as an away win, like team records do
+func newNBAGameIndex(games []NBAGameResult) *gameIndex {
+	index := newGameIndex()
+	for _, game := range games {
+		index.addGame(game.HomeTeamID, game.AwayTeamID, game.HomeScore, game.AwayScore, false)
+	}
+	return index
+}
+
+func (index *gameIndex) addGame(homeTeamID int, awayTeamID int, homeScore int, awayScore int, allowTies bool) {
+	home := index.position(homeTeamID)
+	away := index.position(awayTeamID)
+
+	switch {
+	case homeScore > awayScore:
+		index.records[home][away].wins++
+		index.records[away][home].losses++
+	case homeScore < awayScore || !allowTies:
+		index.records[away][home].wins++
+		index.records[home][away].losses++
+	default:
+		index.records[home][away].ties++
+		index.records[away][home].ties++
+	}
+}
+
+// Gets a team's position, growing the tables when it hasn't been seen yet
+func (index *gameIndex) position(teamID int) int {
+	if position, exists := index.positions[teamID]; exists {
+		return position
+	}
+
+	position := len(index.teamIDs)
+	index.positions[teamID] = position
+	index.teamIDs = append(index.teamIDs, teamID)
+	for i := range index.records {
+		index.records[i] = append(index.records[i], matchupRecord{})
+	}
+	index.records = append(index.records, make([]matchupRecord, len(index.teamIDs)))
+
+	return position
+}
+
+// Team's record against one opponent
+func (index *gameIndex) headToHead(teamID int, opponentID int) matchupRecord {
+	team, teamExists := index.positions[teamID]
+	opponent, opponentExists := index.positions[opponentID]
+	if !teamExists || !opponentExists {
+		return matchupRecord{}
+	}
+	return index.records[team][opponent]
+}
+
+// Team's combined record in games against the other given teams
+func (index *gameIndex) recordAmong(teamID int, teamIDs map[int]bool) matchupRecord {
+	var record matchupRecord
+	for opponentID := range teamIDs {
+		if opponentID != teamID {
+			record.add(index.headToHead(teamID, opponentID))
+		}
+	}
+	return record
+}
+
+// Whether a team beat every other given team at least once
+func (index *gameIndex) hasBeatenAll(teamID int, opponentIDs []int) bool {
+	for _, opponentID := range opponentIDs {
+		if opponentID != teamID && index.headToHead(teamID, opponentID).wins == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Calls visit with each opponent a team has played and its record against them
+func (index *gameIndex) eachOpponent(teamID int, visit func(opponentID int, record matchupRecord)) {
+	team, exists := index.positions[teamID]
+	if !exists {
+		return
+	}
+	for opponent, record := range index.records[team] {
+		if record.games() > 0 {
+			visit(index.teamIDs[opponent], record)
+		}
+	}
+}
+
+// Team's combined record against a set of opponents
+func (index *gameIndex) recordAgainst(teamID int, opponentIDs []int) matchupRecord {
+	var record matchupRecord
+	for _, opponentID := range opponentIDs {
+		record.add(index.headToHead(teamID, opponentID))
+	}
+	return record
+}
+
+// Opponents both teams have played
+func (index *gameIndex) commonOpponents(teamA int, teamB int) []int {
+	a, aExists := index.positions[teamA]
+	b, bExists := index.positions[teamB]
+	if !aExists || !bExists {
+		return nil
+	}
+
+	var common []int
+	for opponent := range index.teamIDs {
+		if index.records[a][opponent].games() > 0 && index.records[b][opponent].games() > 0 {
+			common = append(common, index.teamIDs[opponent])
+		}
+	}
+	return common
+}
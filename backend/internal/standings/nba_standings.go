@@ -5,6 +5,7 @@ package standings
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"gamescript/internal/database"
 )
@@ -45,6 +46,7 @@ type NBAStandings struct {
 	Eastern NBAConferenceStandings
 	Western NBAConferenceStandings
 	DraftOrder []NBADraftPick
+	Counterfactuals []CounterfactualResult
 }
 
 type NBAConferenceStandings struct {
@@ -73,32 +75,98 @@ type NBAGameResult struct {
 	HasRealScores bool
 }
 
+// Calculates full-season standings for a scenario, served from the standings cache while its picks and
+// the season's games are unchanged. The result is shared between callers and must not be modified.
 func CalculateNBAStandings(db *database.DB, scenarioID int, seasonID int) (*NBAStandings, error) {
+	cached, version, ok := standingsCache.lookup(scenarioID, seasonID, time.Now())
+	if ok {
+		if calculated, isSport := cached.(*NBAStandings); isSport {
+			return calculated, nil
+		}
+	}
+
+	calculated, err := calculateNBAStandings(db, scenarioID, seasonID, Cutoff{})
+	if err != nil {
+		return nil, err
+	}
+	standingsCache.store(scenarioID, seasonID, version, time.Now(), calculated)
+	return calculated, nil
+}
+
+// Calculates standings counting only the games within a cutoff. Without one, this is the same as the cached
+// full-season standings.
+func CalculateNBAStandingsAsOf(db *database.DB, scenarioID int, seasonID int, cutoff Cutoff) (*NBAStandings, error) {
+	if !cutoff.IsSet() {
+		return CalculateNBAStandings(db, scenarioID, seasonID)
+	}
+	return calculateNBAStandings(db, scenarioID, seasonID, cutoff)
+}
+
+func calculateNBAStandings(db *database.DB, scenarioID int, seasonID int, cutoff Cutoff) (*NBAStandings, error) {
+	teams, games, counterfactuals, err := loadNBAResults(db, scenarioID, seasonID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	calculated := BuildNBAStandings(teams, games)
+	calculated.Counterfactuals = counterfactuals
+	return calculated, nil
+}
+
+// Loads a season's teams and the game results a scenario's picks produce, for building standings outside the
+// database, such as when profiling
+func LoadNBAResults(db *database.DB, scenarioID int, seasonID int) ([]NBATeamRecord, []NBAGameResult, error) {
+	teams, games, _, err := loadNBAResults(db, scenarioID, seasonID, Cutoff{})
+	return teams, games, err
+}
+
+func loadNBAResults(db *database.DB, scenarioID int, seasonID int, cutoff Cutoff) ([]NBATeamRecord, []NBAGameResult, []CounterfactualResult, error) {
 	// Get all teams for the season
 	teams, err := getNBATeams(db, seasonID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting teams: %w", err)
+		return nil, nil, nil, fmt.Errorf("error getting teams: %w", err)
 	}
 
 	// Get all game results for the scenario
-	games, err := getNBAGameResults(db, scenarioID, seasonID)
+	games, counterfactuals, err := getNBAGameResults(db, scenarioID, seasonID, cutoff)
 	if err != nil {
-		return nil, fmt.Errorf("error getting game results: %w", err)
+		return nil, nil, nil, fmt.Errorf("error getting game results: %w", err)
 	}
 
+	return teams, games, counterfactuals, nil
+}
+
+// Calculates records, seeds, and draft order from a set of game results. The teams are copied, so the same
+// teams can be reused across calls.
+func BuildNBAStandings(teams []NBATeamRecord, games []NBAGameResult) *NBAStandings {
+	return buildNBAStandings(teams, games, nil)
+}
+
+// Builds standings like BuildNBAStandings, along with the tiebreaker step behind every tie it broke
+func TraceNBAStandings(teams []NBATeamRecord, games []NBAGameResult) (*NBAStandings, []TiebreakerDecision) {
+	trace := &tiebreakerTrace{}
+	season := buildNBAStandings(teams, games, trace)
+	return season, trace.sorted()
+}
+
+func buildNBAStandings(teams []NBATeamRecord, games []NBAGameResult, trace *tiebreakerTrace) *NBAStandings {
 	// Calculate team records
-	records := calculateNBATeamRecords(teams, games)
+	records := calculateNBATeamRecords(append([]NBATeamRecord(nil), teams...), games)
+
+	// Index head-to-head results once for every tiebreaker
+	index := newNBAGameIndex(games)
+	index.trace = trace
 
 	// Calculate strength metrics
-	calculateNBAStrengthMetrics(records, games)
+	calculateNBAStrengthMetrics(records, index)
 
 	// Separate by conference
 	easternTeams := filterByNBAConference(records, "Eastern")
 	westernTeams := filterByNBAConference(records, "Western")
 
 	// Calculate standings for each conference
-	easternStandings := calculateNBAConferenceStandings(easternTeams, games)
-	westernStandings := calculateNBAConferenceStandings(westernTeams, games)
+	easternStandings := calculateNBAConferenceStandings(easternTeams, index)
+	westernStandings := calculateNBAConferenceStandings(westernTeams, index)
 
 	// Calculate draft order
 	draftOrder := calculateNBADraftOrder(records, easternStandings, westernStandings)
@@ -107,7 +175,7 @@ func CalculateNBAStandings(db *database.DB, scenarioID int, seasonID int) (*NBAS
 		Eastern: easternStandings,
 		Western: westernStandings,
 		DraftOrder: draftOrder,
-	}, nil
+	}
 }
 
 func getNBATeams(db *database.DB, seasonID int) ([]NBATeamRecord, error) {
@@ -148,7 +216,8 @@ func getNBATeams(db *database.DB, seasonID int) ([]NBATeamRecord, error) {
 	return teams, nil
 }
 
-func getNBAGameResults(db *database.DB, scenarioID int, seasonID int) ([]NBAGameResult, error) {
+// Gets the result of every decided game in a scenario, along with the real results its override picks replaced
+func getNBAGameResults(db *database.DB, scenarioID int, seasonID int, cutoff Cutoff) ([]NBAGameResult, []CounterfactualResult, error) {
 	query := `
 		SELECT
 			game.id, game.home_team_id, game.away_team_id, game.week,
@@ -157,25 +226,32 @@ func getNBAGameResults(db *database.DB, scenarioID int, seasonID int) ([]NBAGame
 			game.status,
 			pick.picked_team_id,
 			pick.predicted_home_score,
-			pick.predicted_away_score
+			pick.predicted_away_score,
+			COALESCE(pick.is_override, false),
+			COALESCE((SELECT result_mode FROM scenarios WHERE id = $1), 'alternate_history')
 		FROM games game
 		LEFT JOIN picks pick ON game.id = pick.game_id AND pick.scenario_id = $1
 		WHERE game.season_id = $2
+			AND ($3::int IS NULL OR game.week <= $3)
+			AND ($4::timestamp IS NULL OR game.start_time < $4::timestamp)
 		ORDER BY game.week, game.start_time
 	`
 
-	rows, err := db.Query(query, scenarioID, seasonID)
+	rows, err := db.Query(query, scenarioID, seasonID, cutoff.Week, cutoff.formatBefore())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var games []NBAGameResult
+	var counterfactuals []CounterfactualResult
 	for rows.Next() {
 		var game NBAGameResult
 		var actualHomeScore, actualAwayScore *int
 		var status string
 		var pickedTeamID, predictedHomeScore, predictedAwayScore *int
+		var isOverride bool
+		var resultMode string
 
 		err := rows.Scan(
 			&game.GameID,
@@ -188,13 +264,17 @@ func getNBAGameResults(db *database.DB, scenarioID int, seasonID int) ([]NBAGame
 			&pickedTeamID,
 			&predictedHomeScore,
 			&predictedAwayScore,
+			&isOverride,
+			&resultMode,
 		)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		// Priority 1: User has made a pick
-		if pickedTeamID != nil {
+		isFinal := status == "final" && actualHomeScore != nil && actualAwayScore != nil
+
+		// Priority 1: User has made a pick that decides the game
+		if pickedTeamID != nil && PickDecidesGame(isFinal, isOverride, resultMode) {
 			// If user provided predicted scores, use those
 			if predictedHomeScore != nil && predictedAwayScore != nil {
 				game.HomeScore = *predictedHomeScore
@@ -214,12 +294,25 @@ func getNBAGameResults(db *database.DB, scenarioID int, seasonID int) ([]NBAGame
 					continue
 				}
 			}
+			if isFinal {
+				counterfactuals = append(counterfactuals, CounterfactualResult{
+					GameID:             game.GameID,
+					Week:               game.Week,
+					HomeTeamID:         game.HomeTeamID,
+					AwayTeamID:         game.AwayTeamID,
+					ActualHomeScore:    *actualHomeScore,
+					ActualAwayScore:    *actualAwayScore,
+					PickedTeamID:       *pickedTeamID,
+					PredictedHomeScore: predictedHomeScore,
+					PredictedAwayScore: predictedAwayScore,
+				})
+			}
 			games = append(games, game)
 			continue
 		}
 
-		// Priority 2: No pick, but game is final
-		if status == "final" && actualHomeScore != nil && actualAwayScore != nil {
+		// Priority 2: No deciding pick, but game is final
+		if isFinal {
 			game.HomeScore = *actualHomeScore
 			game.AwayScore = *actualAwayScore
 			game.HasRealScores = true
@@ -231,7 +324,7 @@ func getNBAGameResults(db *database.DB, scenarioID int, seasonID int) ([]NBAGame
 		// (don't add to games slice)
 	}
 
-	return games, nil
+	return games, counterfactuals, nil
 }
 
 func calculateNBATeamRecords(teams []NBATeamRecord, games []NBAGameResult) []NBATeamRecord {
@@ -307,7 +400,7 @@ func calculateNBATeamRecords(teams []NBATeamRecord, games []NBAGameResult) []NBA
 	return teams
 }
 
-func calculateNBAStrengthMetrics(teams []NBATeamRecord, games []NBAGameResult) {
+func calculateNBAStrengthMetrics(teams []NBATeamRecord, index *gameIndex) {
 	// Initialize team map for easy lookup
 	teamMap := make(map[int]*NBATeamRecord)
 	for i := range teams {
@@ -323,37 +416,24 @@ func calculateNBAStrengthMetrics(teams []NBATeamRecord, games []NBAGameResult) {
 		var defeatedOpponentWins, defeatedOpponentLosses int
 		var defeatedCount int
 
-		for _, game := range games {
-			var opponentID int
-			var teamWon bool
-
-			if game.HomeTeamID == team.TeamID {
-				opponentID = game.AwayTeamID
-				teamWon = game.HomeScore > game.AwayScore
-			} else if game.AwayTeamID == team.TeamID {
-				opponentID = game.HomeTeamID
-				teamWon = game.AwayScore > game.HomeScore
-			} else {
-				continue
-			}
-
+		// Opponents count once per game played against them
+		index.eachOpponent(team.TeamID, func(opponentID int, record matchupRecord) {
 			opponent := teamMap[opponentID]
 			if opponent == nil {
-				continue
+				return
 			}
 
 			// Strength of schedule: all opponents
-			opponentTotalWins += opponent.Wins
-			opponentTotalLosses += opponent.Losses
-			opponentCount++
+			games := record.games()
+			opponentTotalWins += games * opponent.Wins
+			opponentTotalLosses += games * opponent.Losses
+			opponentCount += games
 
 			// Strength of victory: only defeated opponents
-			if teamWon {
-				defeatedOpponentWins += opponent.Wins
-				defeatedOpponentLosses += opponent.Losses
-				defeatedCount++
-			}
-		}
+			defeatedOpponentWins += record.wins * opponent.Wins
+			defeatedOpponentLosses += record.wins * opponent.Losses
+			defeatedCount += record.wins
+		})
 
 		// Calculate averages
 		if opponentCount > 0 {
@@ -376,7 +456,7 @@ func filterByNBAConference(teams []NBATeamRecord, conference string) []NBATeamRe
 	return filtered
 }
 
-func calculateNBAConferenceStandings(teams []NBATeamRecord, games []NBAGameResult) NBAConferenceStandings {
+func calculateNBAConferenceStandings(teams []NBATeamRecord, index *gameIndex) NBAConferenceStandings {
 	// Group teams by division
 	divisions := make(map[string][]NBATeamRecord)
 	for _, team := range teams {
@@ -391,7 +471,8 @@ func calculateNBAConferenceStandings(teams []NBATeamRecord, games []NBAGameResul
 	divisionWinners := make(map[string]NBATeamRecord)
 	for divName, divTeams := range divisions {
 		// Sort division teams with tiebreakers
-		sortedDiv := applyNBADivisionTiebreakers(divTeams, games)
+		index.traceContext(divName)
+		sortedDiv := applyNBADivisionTiebreakers(divTeams, index)
 		divisionWinners[divName] = sortedDiv[0]
 
 		// Calculate division games back
@@ -419,7 +500,8 @@ func calculateNBAConferenceStandings(teams []NBATeamRecord, games []NBAGameResul
 	}
 
 	// Apply conference-wide tiebreakers to rank all teams (seeds 1-15)
-	rankedTeams := applyNBAConferenceTiebreakers(teams, games, divisionWinners)
+	index.traceContext(teams[0].Conference + " seeds")
+	rankedTeams := applyNBAConferenceTiebreakers(teams, index, divisionWinners)
 
 	// Calculate conference games back
 	conferenceLeader := rankedTeams[0]
@@ -465,7 +547,7 @@ func calculateNBAConferenceStandings(teams []NBATeamRecord, games []NBAGameResul
 	}
 }
 
-func applyNBADivisionTiebreakers(teams []NBATeamRecord, games []NBAGameResult) []NBATeamRecord {
+func applyNBADivisionTiebreakers(teams []NBATeamRecord, index *gameIndex) []NBATeamRecord {
 	if len(teams) <= 1 {
 		return teams
 	}
@@ -482,9 +564,9 @@ func applyNBADivisionTiebreakers(teams []NBATeamRecord, games []NBAGameResult) [
 		if len(group) == 1 {
 			result = append(result, group[0])
 		} else if len(group) == 2 {
-			result = append(result, resolveNBATwoTeamTie(group, games, nil, true)...)
+			result = append(result, resolveNBATwoTeamTie(group, index, nil, true)...)
 		} else {
-			result = append(result, resolveNBAMultiTeamTie(group, games, nil, true)...)
+			result = append(result, resolveNBAMultiTeamTie(group, index, nil, true)...)
 		}
 	}
 
@@ -496,7 +578,7 @@ func applyNBADivisionTiebreakers(teams []NBATeamRecord, games []NBAGameResult) [
 	return result
 }
 
-func applyNBAConferenceTiebreakers(teams []NBATeamRecord, games []NBAGameResult, divisionWinners map[string]NBATeamRecord) []NBATeamRecord {
+func applyNBAConferenceTiebreakers(teams []NBATeamRecord, index *gameIndex, divisionWinners map[string]NBATeamRecord) []NBATeamRecord {
 	if len(teams) <= 1 {
 		return teams
 	}
@@ -541,25 +623,25 @@ func applyNBAConferenceTiebreakers(teams []NBATeamRecord, games []NBAGameResult,
         if len(group) == 1 {
             result = append(result, group[0])
         } else if len(group) == 2 {
-            result = append(result, resolveNBATwoTeamTie(group, games, divisionWinners, false)...)
+            result = append(result, resolveNBATwoTeamTie(group, index, divisionWinners, false)...)
         } else {
-            result = append(result, resolveNBAMultiTeamTie(group, games, divisionWinners, false)...)
+            result = append(result, resolveNBAMultiTeamTie(group, index, divisionWinners, false)...)
         }
     }
 
 	return result
 }
 
-func resolveNBATwoTeamTie(teams []NBATeamRecord, games []NBAGameResult, divisionWinners map[string]NBATeamRecord, inDivision bool) []NBATeamRecord {
+func resolveNBATwoTeamTie(teams []NBATeamRecord, index *gameIndex, divisionWinners map[string]NBATeamRecord, inDivision bool) []NBATeamRecord {
 	a, b := teams[0], teams[1]
 
 	// Step 1: Head-to-head
-	h2h := compareNBAHeadToHead([]NBATeamRecord{a, b}, games)
+	h2h := compareNBAHeadToHead([]NBATeamRecord{a, b}, index)
 	if len(h2h) == 1 {
 		if h2h[0].TeamID == a.TeamID {
-			return []NBATeamRecord{a, b}
+			return index.orderNBA("Head-to-head", a, b)
 		}
-		return []NBATeamRecord{b, a}
+		return index.orderNBA("Head-to-head", b, a)
 	}
 
 	// Step 2: Division winner (if not already determining division winner)
@@ -576,9 +658,9 @@ func resolveNBATwoTeamTie(teams []NBATeamRecord, games []NBAGameResult, division
 		}
 		if aIsDivWinner != bIsDivWinner {
 			if aIsDivWinner {
-				return []NBATeamRecord{a, b}
+				return index.orderNBA("Division winner", a, b)
 			}
-			return []NBATeamRecord{b, a}
+			return index.orderNBA("Division winner", b, a)
 		}
 	}
 
@@ -588,9 +670,9 @@ func resolveNBATwoTeamTie(teams []NBATeamRecord, games []NBAGameResult, division
 		bDivPct := calculateNBAWinPct(b.DivisionWins, b.DivisionLosses)
 		if aDivPct != bDivPct {
 			if aDivPct > bDivPct {
-				return []NBATeamRecord{a, b}
+				return index.orderNBA("Division win percentage", a, b)
 			}
-			return []NBATeamRecord{b, a}
+			return index.orderNBA("Division win percentage", b, a)
 		}
 	}
 
@@ -599,9 +681,9 @@ func resolveNBATwoTeamTie(teams []NBATeamRecord, games []NBAGameResult, division
 	bConfPct := calculateNBAWinPct(b.ConferenceWins, b.ConferenceLosses)
 	if aConfPct != bConfPct {
 		if aConfPct > bConfPct {
-			return []NBATeamRecord{a, b}
+			return index.orderNBA("Conference win percentage", a, b)
 		}
-		return []NBATeamRecord{b, a}
+		return index.orderNBA("Conference win percentage", b, a)
 	}
 
 	// Step 5: Point differential
@@ -609,24 +691,24 @@ func resolveNBATwoTeamTie(teams []NBATeamRecord, games []NBAGameResult, division
 	bPointDiff := b.PointsFor - b.PointsAgainst
 	if aPointDiff != bPointDiff {
 		if aPointDiff > bPointDiff {
-			return []NBATeamRecord{a, b}
+			return index.orderNBA("Point differential", a, b)
 		}
-		return []NBATeamRecord{b, a}
+		return index.orderNBA("Point differential", b, a)
 	}
 
 	// Random drawing - use TeamID for consistency
 	if a.TeamID < b.TeamID {
-		return []NBATeamRecord{a, b}
+		return index.orderNBA("Random drawing", a, b)
 	}
-	return []NBATeamRecord{b, a}
+	return index.orderNBA("Random drawing", b, a)
 }
 
-func resolveNBAMultiTeamTie(teams []NBATeamRecord, games []NBAGameResult, divisionWinners map[string]NBATeamRecord, inDivision bool) []NBATeamRecord {
+func resolveNBAMultiTeamTie(teams []NBATeamRecord, index *gameIndex, divisionWinners map[string]NBATeamRecord, inDivision bool) []NBATeamRecord {
 	if len(teams) <= 1 {
 		return teams
 	}
 	if len(teams) == 2 {
-		return resolveNBATwoTeamTie(teams, games, divisionWinners, inDivision)
+		return resolveNBATwoTeamTie(teams, index, divisionWinners, inDivision)
 	}
 
 	// Step 1: Division winner (if not already determining division winner)
@@ -655,13 +737,13 @@ func resolveNBAMultiTeamTie(teams []NBATeamRecord, games []NBAGameResult, divisi
 			if len(divWinners) == 1 {
 				result = append(result, divWinners[0])
 			} else {
-				result = append(result, resolveNBAMultiTeamTie(divWinners, games, divisionWinners, inDivision)...)
+				result = append(result, resolveNBAMultiTeamTie(divWinners, index, divisionWinners, inDivision)...)
 			}
 
 			if len(nonWinners) == 1 {
 				result = append(result, nonWinners[0])
 			} else {
-				result = append(result, resolveNBAMultiTeamTie(nonWinners, games, divisionWinners, inDivision)...)
+				result = append(result, resolveNBAMultiTeamTie(nonWinners, index, divisionWinners, inDivision)...)
 			}
 
 			return result
@@ -669,12 +751,13 @@ func resolveNBAMultiTeamTie(teams []NBATeamRecord, games []NBAGameResult, divisi
 	}
 
 	// Step 2: Head-to-head win percentage
-	h2hWinner := findNBAHeadToHeadWinner(teams, games)
+	h2hWinner := findNBAHeadToHeadWinner(teams, index)
 	if h2hWinner != nil {
+		index.recordNBA("Head-to-head", teams, *h2hWinner)
 		remaining := removeNBATeam(teams, h2hWinner.TeamID)
 		result := []NBATeamRecord{*h2hWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNBAMultiTeamTie(remaining, games, divisionWinners, inDivision)...)
+			result = append(result, resolveNBAMultiTeamTie(remaining, index, divisionWinners, inDivision)...)
 		}
 		return result
 	}
@@ -691,10 +774,11 @@ func resolveNBAMultiTeamTie(teams []NBATeamRecord, games []NBAGameResult, divisi
 	if allSameDivision {
 		divWinner := findBestNBADivisionRecord(teams)
 		if divWinner != nil {
+			index.recordNBA("Division win percentage", teams, *divWinner)
 			remaining := removeNBATeam(teams, divWinner.TeamID)
 			result := []NBATeamRecord{*divWinner}
 			if len(remaining) > 0 {
-				result = append(result, resolveNBAMultiTeamTie(remaining, games, divisionWinners, inDivision)...)
+				result = append(result, resolveNBAMultiTeamTie(remaining, index, divisionWinners, inDivision)...)
 			}
 			return result
 		}
@@ -703,10 +787,11 @@ func resolveNBAMultiTeamTie(teams []NBATeamRecord, games []NBAGameResult, divisi
 	// Step 4: Conference win percentage
 	confWinner := findBestNBAConferenceRecord(teams)
 	if confWinner != nil {
+		index.recordNBA("Conference win percentage", teams, *confWinner)
 		remaining := removeNBATeam(teams, confWinner.TeamID)
 		result := []NBATeamRecord{*confWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNBAMultiTeamTie(remaining, games, divisionWinners, inDivision)...)
+			result = append(result, resolveNBAMultiTeamTie(remaining, index, divisionWinners, inDivision)...)
 		}
 		return result
 	}
@@ -714,10 +799,11 @@ func resolveNBAMultiTeamTie(teams []NBATeamRecord, games []NBAGameResult, divisi
 	// Step 5: Point differential
 	pointDiffWinner := findBestNBAPointDifferential(teams)
 	if pointDiffWinner != nil {
+		index.recordNBA("Point differential", teams, *pointDiffWinner)
 		remaining := removeNBATeam(teams, pointDiffWinner.TeamID)
 		result := []NBATeamRecord{*pointDiffWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNBAMultiTeamTie(remaining, games, divisionWinners, inDivision)...)
+			result = append(result, resolveNBAMultiTeamTie(remaining, index, divisionWinners, inDivision)...)
 		}
 		return result
 	}
@@ -726,85 +812,44 @@ func resolveNBAMultiTeamTie(teams []NBATeamRecord, games []NBAGameResult, divisi
 	sort.Slice(teams, func(i, j int) bool {
 		return teams[i].TeamID < teams[j].TeamID
 	})
+	index.recordNBA("Random drawing", teams, teams[0])
 
 	return teams
 }
 
-func compareNBAHeadToHead(teams []NBATeamRecord, games []NBAGameResult) []NBATeamRecord {
+func compareNBAHeadToHead(teams []NBATeamRecord, index *gameIndex) []NBATeamRecord {
 	if len(teams) != 2 {
 		return teams
 	}
 
 	teamA, teamB := teams[0], teams[1]
-	aWins, bWins := 0, 0
-
-	for _, game := range games {
-		isMatchup := (game.HomeTeamID == teamA.TeamID && game.AwayTeamID == teamB.TeamID) || (game.HomeTeamID == teamB.TeamID && game.AwayTeamID == teamA.TeamID)
-
-		if !isMatchup {
-			continue
-		}
-
-		var winner int
-		if game.HomeScore > game.AwayScore {
-			winner = game.HomeTeamID
-		} else {
-			winner = game.AwayTeamID
-		}
 
-		if winner == teamA.TeamID {
-			aWins++
-		} else if winner == teamB.TeamID {
-			bWins++
-		}
-	}
-
-	if aWins > bWins {
+	record := index.headToHead(teamA.TeamID, teamB.TeamID)
+	if record.wins > record.losses {
 		return []NBATeamRecord{teamA}
-	} else if bWins > aWins {
+	} else if record.losses > record.wins {
 		return []NBATeamRecord{teamB}
 	}
 
 	return teams
 }
 
-func findNBAHeadToHeadWinner(teams []NBATeamRecord, games []NBAGameResult) *NBATeamRecord {
-	type h2hRecord struct {
-		teamID  int
-		wins    int
-		losses  int
-	}
-
-	records := make(map[int]*h2hRecord)
+func findNBAHeadToHeadWinner(teams []NBATeamRecord, index *gameIndex) *NBATeamRecord {
 	teamIDs := make(map[int]bool)
 	for _, team := range teams {
-		records[team.TeamID] = &h2hRecord{teamID: team.TeamID}
 		teamIDs[team.TeamID] = true
 	}
 
-	for _, game := range games {
-		if !teamIDs[game.HomeTeamID] || !teamIDs[game.AwayTeamID] {
-			continue
-		}
-
-		if game.HomeScore > game.AwayScore {
-			records[game.HomeTeamID].wins++
-			records[game.AwayTeamID].losses++
-		} else {
-			records[game.AwayTeamID].wins++
-			records[game.HomeTeamID].losses++
-		}
-	}
-
 	var bestPct float64 = -1.0
 	var bestTeamID int
 	var tie bool
 
-	for _, rec := range records {
-		pct := calculateNBAWinPct(rec.wins, rec.losses)
+	for _, team := range teams {
+		record := index.recordAmong(team.TeamID, teamIDs)
+		pct := calculateNBAWinPct(record.wins, record.losses)
 		if pct > bestPct {
 			bestPct = pct
-			bestTeamID = rec.teamID
+			bestTeamID = team.TeamID
 			tie = false
 		} else if pct == bestPct {
 			tie = true
@@ -5,6 +5,7 @@ package standings
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"gamescript/internal/database"
 )
@@ -45,9 +46,10 @@ type NFLTeamRecord struct {
 }
 
 type NFLStandings struct {
-	AFC        NFLConferenceStandings
-	NFC        NFLConferenceStandings
-	DraftOrder []NFLDraftPick
+	AFC             NFLConferenceStandings
+	NFC             NFLConferenceStandings
+	DraftOrder      []NFLDraftPick
+	Counterfactuals []CounterfactualResult
 }
 
 type NFLConferenceStandings struct {
@@ -75,41 +77,107 @@ type NFLGameResult struct {
 	Week       int
 }
 
+// Calculates full-season standings for a scenario, served from the standings cache while its picks and
+// the season's games are unchanged. The result is shared between callers and must not be modified.
 func CalculateNFLStandings(db *database.DB, scenarioID int, seasonID int) (*NFLStandings, error) {
+	cached, version, ok := standingsCache.lookup(scenarioID, seasonID, time.Now())
+	if ok {
+		if calculated, isSport := cached.(*NFLStandings); isSport {
+			return calculated, nil
+		}
+	}
+
+	calculated, err := calculateNFLStandings(db, scenarioID, seasonID, Cutoff{})
+	if err != nil {
+		return nil, err
+	}
+	standingsCache.store(scenarioID, seasonID, version, time.Now(), calculated)
+	return calculated, nil
+}
+
+// Calculates standings counting only the games within a cutoff. Without one, this is the same as the cached
+// full-season standings.
+func CalculateNFLStandingsAsOf(db *database.DB, scenarioID int, seasonID int, cutoff Cutoff) (*NFLStandings, error) {
+	if !cutoff.IsSet() {
+		return CalculateNFLStandings(db, scenarioID, seasonID)
+	}
+	return calculateNFLStandings(db, scenarioID, seasonID, cutoff)
+}
+
+func calculateNFLStandings(db *database.DB, scenarioID int, seasonID int, cutoff Cutoff) (*NFLStandings, error) {
+	teams, games, counterfactuals, err := loadNFLResults(db, scenarioID, seasonID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	calculated := BuildNFLStandings(teams, games)
+	calculated.Counterfactuals = counterfactuals
+	return calculated, nil
+}
+
+// Loads a season's teams and the game results a scenario's picks produce, for building standings outside the
+// database, such as when profiling
+func LoadNFLResults(db *database.DB, scenarioID int, seasonID int) ([]NFLTeamRecord, []NFLGameResult, error) {
+	teams, games, _, err := loadNFLResults(db, scenarioID, seasonID, Cutoff{})
+	return teams, games, err
+}
+
+func loadNFLResults(db *database.DB, scenarioID int, seasonID int, cutoff Cutoff) ([]NFLTeamRecord, []NFLGameResult, []CounterfactualResult, error) {
 	// Get all teams for the season
 	teams, err := getNFLTeams(db, seasonID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting teams: %w", err)
+		return nil, nil, nil, fmt.Errorf("error getting teams: %w", err)
 	}
 
 	// Get all game results for the scenario
-	games, err := getNFLGameResults(db, scenarioID, seasonID)
+	games, counterfactuals, err := getNFLGameResults(db, scenarioID, seasonID, cutoff)
 	if err != nil {
-		return nil, fmt.Errorf("error getting game results: %w", err)
+		return nil, nil, nil, fmt.Errorf("error getting game results: %w", err)
 	}
 
+	return teams, games, counterfactuals, nil
+}
+
+// Calculates records, seeds, and draft order from a set of game results. The teams are copied, so the same
+// teams can be reused across calls.
+func BuildNFLStandings(teams []NFLTeamRecord, games []NFLGameResult) *NFLStandings {
+	return buildNFLStandings(teams, games, nil)
+}
+
+// Builds standings like BuildNFLStandings, along with the tiebreaker step behind every tie it broke
+func TraceNFLStandings(teams []NFLTeamRecord, games []NFLGameResult) (*NFLStandings, []TiebreakerDecision) {
+	trace := &tiebreakerTrace{}
+	season := buildNFLStandings(teams, games, trace)
+	return season, trace.sorted()
+}
+
+func buildNFLStandings(teams []NFLTeamRecord, games []NFLGameResult, trace *tiebreakerTrace) *NFLStandings {
 	// Calculate team records
-	records := calculateNFLTeamRecords(teams, games)
+	records := calculateNFLTeamRecords(append([]NFLTeamRecord(nil), teams...), games)
+
+	// Index head-to-head results once for every tiebreaker
+	index := newNFLGameIndex(games)
+	index.trace = trace
 
 	// Calculate strength metrics
-	calculateNFLStrengthMetrics(records, games)
+	calculateNFLStrengthMetrics(records, index)
 
 	// Separate by conference
 	afcTeams := filterByNFLConference(records, "AFC")
 	nfcTeams := filterByNFLConference(records, "NFC")
 
 	// Calculate playoff seeds for each conference
-	afcStandings := calculateNFLConferenceStandings(afcTeams, games)
-	nfcStandings := calculateNFLConferenceStandings(nfcTeams, games)
+	afcStandings := calculateNFLConferenceStandings(afcTeams, index)
+	nfcStandings := calculateNFLConferenceStandings(nfcTeams, index)
 
 	// Calculate draft order
-	draftOrder := calculateNFLDraftOrder(records, afcStandings, nfcStandings)
+	draftOrder := calculateNFLDraftOrder(records, afcStandings, nfcStandings, trace)
 
 	return &NFLStandings{
 		AFC:        afcStandings,
 		NFC:        nfcStandings,
 		DraftOrder: draftOrder,
-	}, nil
+	}
 }
 
 func getNFLTeams(db *database.DB, seasonID int) ([]NFLTeamRecord, error) {
@@ -150,7 +218,8 @@ func getNFLTeams(db *database.DB, seasonID int) ([]NFLTeamRecord, error) {
 	return teams, nil
 }
 
-func getNFLGameResults(db *database.DB, scenarioID int, seasonID int) ([]NFLGameResult, error) {
+// Gets the result of every decided game in a scenario, along with the real results its override picks replaced
+func getNFLGameResults(db *database.DB, scenarioID int, seasonID int, cutoff Cutoff) ([]NFLGameResult, []CounterfactualResult, error) {
 	query := `
         SELECT
             game.id, game.home_team_id, game.away_team_id, game.week,
@@ -159,25 +228,32 @@ func getNFLGameResults(db *database.DB, scenarioID int, seasonID int) ([]NFLGame
             game.status,
             pick.picked_team_id,
             pick.predicted_home_score,
-            pick.predicted_away_score
+            pick.predicted_away_score,
+            COALESCE(pick.is_override, false),
+            COALESCE((SELECT result_mode FROM scenarios WHERE id = $1), 'alternate_history')
         FROM games game
         LEFT JOIN picks pick ON game.id = pick.game_id AND pick.scenario_id = $1
         WHERE game.season_id = $2
+            AND ($3::int IS NULL OR game.week <= $3)
+            AND ($4::timestamp IS NULL OR game.start_time < $4::timestamp)
         ORDER BY game.week, game.start_time
     `
 
-	rows, err := db.Query(query, scenarioID, seasonID)
+	rows, err := db.Query(query, scenarioID, seasonID, cutoff.Week, cutoff.formatBefore())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var games []NFLGameResult
+	var counterfactuals []CounterfactualResult
 	for rows.Next() {
 		var game NFLGameResult
 		var actualHomeScore, actualAwayScore *int
 		var status string
 		var pickedTeamID, predictedHomeScore, predictedAwayScore *int
+		var isOverride bool
+		var resultMode string
 
 		err := rows.Scan(
 			&game.GameID,
@@ -190,13 +266,17 @@ func getNFLGameResults(db *database.DB, scenarioID int, seasonID int) ([]NFLGame
 			&pickedTeamID,
 			&predictedHomeScore,
 			&predictedAwayScore,
+			&isOverride,
+			&resultMode,
 		)
 		if err != nil {
 			continue
 		}
 
-		// Priority 1: User has made a pick
-		if pickedTeamID != nil {
+		isFinal := status == "final" && actualHomeScore != nil && actualAwayScore != nil
+
+		// Priority 1: User has made a pick that decides the game
+		if pickedTeamID != nil && PickDecidesGame(isFinal, isOverride, resultMode) {
 			// If user provided predicted scores, use those
 			if predictedHomeScore != nil && predictedAwayScore != nil {
 				game.HomeScore = *predictedHomeScore
@@ -218,12 +298,25 @@ func getNFLGameResults(db *database.DB, scenarioID int, seasonID int) ([]NFLGame
 					continue
 				}
 			}
+			if isFinal {
+				counterfactuals = append(counterfactuals, CounterfactualResult{
+					GameID:             game.GameID,
+					Week:               game.Week,
+					HomeTeamID:         game.HomeTeamID,
+					AwayTeamID:         game.AwayTeamID,
+					ActualHomeScore:    *actualHomeScore,
+					ActualAwayScore:    *actualAwayScore,
+					PickedTeamID:       *pickedTeamID,
+					PredictedHomeScore: predictedHomeScore,
+					PredictedAwayScore: predictedAwayScore,
+				})
+			}
 			games = append(games, game)
 			continue
 		}
 
-		// Priority 2: No pick, but game is final
-		if status == "final" && actualHomeScore != nil && actualAwayScore != nil {
+		// Priority 2: No deciding pick, but game is final
+		if isFinal {
 			game.HomeScore = *actualHomeScore
 			game.AwayScore = *actualAwayScore
 			games = append(games, game)
@@ -234,7 +327,7 @@ func getNFLGameResults(db *database.DB, scenarioID int, seasonID int) ([]NFLGame
 		// (don't add to games slice)
 	}
 
-	return games, nil
+	return games, counterfactuals, nil
 }
 
 func calculateNFLTeamRecords(teams []NFLTeamRecord, games []NFLGameResult) []NFLTeamRecord {
@@ -322,7 +415,7 @@ func calculateNFLTeamRecords(teams []NFLTeamRecord, games []NFLGameResult) []NFL
 	return teams
 }
 
-func calculateNFLStrengthMetrics(teams []NFLTeamRecord, games []NFLGameResult) {
+func calculateNFLStrengthMetrics(teams []NFLTeamRecord, index *gameIndex) {
 	// Initialize team map for easy lookup
 	teamMap := make(map[int]*NFLTeamRecord)
 	for i := range teams {
@@ -338,39 +431,26 @@ func calculateNFLStrengthMetrics(teams []NFLTeamRecord, games []NFLGameResult) {
 		var defeatedOpponentWins, defeatedOpponentLosses, defeatedOpponentTies int
 		var defeatedCount int
 
-		for _, game := range games {
-			var opponentID int
-			var teamWon bool
-
-			if game.HomeTeamID == team.TeamID {
-				opponentID = game.AwayTeamID
-				teamWon = game.HomeScore > game.AwayScore
-			} else if game.AwayTeamID == team.TeamID {
-				opponentID = game.HomeTeamID
-				teamWon = game.AwayScore > game.HomeScore
-			} else {
-				continue
-			}
-
+		// Opponents count once per game played against them
+		index.eachOpponent(team.TeamID, func(opponentID int, record matchupRecord) {
 			opponent := teamMap[opponentID]
 			if opponent == nil {
-				continue
+				return
 			}
 
 			// Strength of schedule: all opponents
-			opponentTotalWins += opponent.Wins
-			opponentTotalLosses += opponent.Losses
-			opponentTotalTies += opponent.Ties
-			opponentCount++
+			games := record.games()
+			opponentTotalWins += games * opponent.Wins
+			opponentTotalLosses += games * opponent.Losses
+			opponentTotalTies += games * opponent.Ties
+			opponentCount += games
 
 			// Strength of victory: only defeated opponents
-			if teamWon {
-				defeatedOpponentWins += opponent.Wins
-				defeatedOpponentLosses += opponent.Losses
-				defeatedOpponentTies += opponent.Ties
-				defeatedCount++
-			}
-		}
+			defeatedOpponentWins += record.wins * opponent.Wins
+			defeatedOpponentLosses += record.wins * opponent.Losses
+			defeatedOpponentTies += record.wins * opponent.Ties
+			defeatedCount += record.wins
+		})
 
 		// Calculate averages
 		if opponentCount > 0 {
@@ -392,7 +472,7 @@ func filterByNFLConference(teams []NFLTeamRecord, conference string) []NFLTeamRe
 	return filtered
 }
 
-func calculateNFLConferenceStandings(teams []NFLTeamRecord, games []NFLGameResult) NFLConferenceStandings {
+func calculateNFLConferenceStandings(teams []NFLTeamRecord, index *gameIndex) NFLConferenceStandings {
 	// Group teams by division
 	divisions := make(map[string][]NFLTeamRecord)
 	for _, team := range teams {
@@ -404,7 +484,8 @@ func calculateNFLConferenceStandings(teams []NFLTeamRecord, games []NFLGameResul
 	nonWinners := []NFLTeamRecord{}
 	for divName, divTeams := range divisions {
 		// Sort division teams with tiebreakers
-		sortedDiv := applyNFLDivisionTiebreakers(divTeams, games)
+		index.traceContext(divName)
+		sortedDiv := applyNFLDivisionTiebreakers(divTeams, index)
 
 		// Calculate division games back
 		divLeader := sortedDiv[0]
@@ -423,10 +504,12 @@ func calculateNFLConferenceStandings(teams []NFLTeamRecord, games []NFLGameResul
 	}
 
 	// Rank division winners (seeds 1-4)
-	divisionWinners = applyNFLConferenceTiebreakers(divisionWinners, games, true)
+	index.traceContext(divisionWinners[0].Conference + " division winners")
+	divisionWinners = applyNFLConferenceTiebreakers(divisionWinners, index, true)
 
 	// Rank non-division winners (seeds 5-16)
-	nonWinners = applyNFLConferenceTiebreakers(nonWinners, games, false)
+	index.traceContext(divisionWinners[0].Conference + " wild cards")
+	nonWinners = applyNFLConferenceTiebreakers(nonWinners, index, false)
 
 	// Create playoff seeds
 	playoffSeeds := []NFLPlayoffSeed{}
@@ -457,7 +540,7 @@ func calculateNFLConferenceStandings(teams []NFLTeamRecord, games []NFLGameResul
 	}
 }
 
-func applyNFLDivisionTiebreakers(teams []NFLTeamRecord, games []NFLGameResult) []NFLTeamRecord {
+func applyNFLDivisionTiebreakers(teams []NFLTeamRecord, index *gameIndex) []NFLTeamRecord {
 	if len(teams) <= 1 {
 		return teams
 	}
@@ -474,9 +557,9 @@ func applyNFLDivisionTiebreakers(teams []NFLTeamRecord, games []NFLGameResult) [
 		if len(group) == 1 {
 			result = append(result, group[0])
 		} else if len(group) == 2 {
-			result = append(result, resolveNFLTwoTeamDivisionTie(group, games)...)
+			result = append(result, resolveNFLTwoTeamDivisionTie(group, index)...)
 		} else {
-			result = append(result, resolveNFLMultiTeamDivisionTie(group, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(group, index)...)
 		}
 	}
 
@@ -488,16 +571,16 @@ func applyNFLDivisionTiebreakers(teams []NFLTeamRecord, games []NFLGameResult) [
 	return result
 }
 
-func resolveNFLTwoTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult) []NFLTeamRecord {
+func resolveNFLTwoTeamDivisionTie(teams []NFLTeamRecord, index *gameIndex) []NFLTeamRecord {
 	a, b := teams[0], teams[1]
 
 	// Step 1: Head-to-head
-	h2h := compareNFLHeadToHead(teams, games)
+	h2h := compareNFLHeadToHead(teams, index)
 	if len(h2h) == 1 {
 		if h2h[0].TeamID == a.TeamID {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Head-to-head", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Head-to-head", b, a)
 	}
 
 	// Step 2: Division win percentage
@@ -505,18 +588,18 @@ func resolveNFLTwoTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult)
 	bDivPct := calculateNFLWinPct(b.DivisionWins, b.DivisionLosses, b.DivisionTies)
 	if aDivPct != bDivPct {
 		if aDivPct > bDivPct {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Division win percentage", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Division win percentage", b, a)
 	}
 
 	// Step 3: Common games
-	commonResults := compareNFLCommonGames(teams, games, 0)
+	commonResults := compareNFLCommonGames(teams, index, 0)
 	if len(commonResults) == 1 {
 		if commonResults[0].TeamID == a.TeamID {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Common games", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Common games", b, a)
 	}
 
 	// Step 4: Conference win percentage
@@ -524,25 +607,25 @@ func resolveNFLTwoTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult)
 	bConfPct := calculateNFLWinPct(b.ConferenceWins, b.ConferenceLosses, b.ConferenceTies)
 	if aConfPct != bConfPct {
 		if aConfPct > bConfPct {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Conference win percentage", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Conference win percentage", b, a)
 	}
 
 	// Step 5: Strength of victory
 	if a.StrengthOfVictory != b.StrengthOfVictory {
 		if a.StrengthOfVictory > b.StrengthOfVictory {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Strength of victory", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Strength of victory", b, a)
 	}
 
 	// Step 6: Strength of schedule
 	if a.StrengthOfSchedule != b.StrengthOfSchedule {
 		if a.StrengthOfSchedule > b.StrengthOfSchedule {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Strength of schedule", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Strength of schedule", b, a)
 	}
 
 	// Step 7: Point differential
@@ -550,42 +633,43 @@ func resolveNFLTwoTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult)
 	bDiff := b.PointsFor - b.PointsAgainst
 	if aDiff != bDiff {
 		if aDiff > bDiff {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Point differential", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Point differential", b, a)
 	}
 
 	// Step 8: Points scored
 	if a.PointsFor != b.PointsFor {
 		if a.PointsFor > b.PointsFor {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Points scored", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Points scored", b, a)
 	}
 
 	// Step 9: Points allowed (fewer is better)
 	if a.PointsAgainst != b.PointsAgainst {
 		if a.PointsAgainst < b.PointsAgainst {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Points allowed", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Points allowed", b, a)
 	}
 
 	// Random drawing - use TeamID for consistency
 	if a.TeamID < b.TeamID {
-		return []NFLTeamRecord{a, b}
+		return index.orderNFL("Random drawing", a, b)
 	}
-	return []NFLTeamRecord{b, a}
+	return index.orderNFL("Random drawing", b, a)
 }
 
-func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult) []NFLTeamRecord {
+func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, index *gameIndex) []NFLTeamRecord {
 	// Step 1: Head-to-head (best win pct in games among tied teams)
-	h2hWinner := findNFLHeadToHeadWinner(teams, games)
+	h2hWinner := findNFLHeadToHeadWinner(teams, index)
 	if h2hWinner != nil {
+		index.recordNFL("Head-to-head", teams, *h2hWinner)
 		remaining := removeNFLTeam(teams, h2hWinner.TeamID)
 		result := []NFLTeamRecord{*h2hWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, index)...)
 		}
 		return result
 	}
@@ -593,21 +677,23 @@ func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult
 	// Step 2: Division win percentage
 	divWinner := findBestNFLDivisionRecord(teams)
 	if divWinner != nil {
+		index.recordNFL("Division win percentage", teams, *divWinner)
 		remaining := removeNFLTeam(teams, divWinner.TeamID)
 		result := []NFLTeamRecord{*divWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, index)...)
 		}
 		return result
 	}
 
 	// Step 3: Common games
-	commonWinner := findBestNFLCommonGamesRecord(teams, games, 0)
+	commonWinner := findBestNFLCommonGamesRecord(teams, index, 0)
 	if commonWinner != nil {
+		index.recordNFL("Common games", teams, *commonWinner)
 		remaining := removeNFLTeam(teams, commonWinner.TeamID)
 		result := []NFLTeamRecord{*commonWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, index)...)
 		}
 		return result
 	}
@@ -615,10 +701,11 @@ func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult
 	// Step 4: Conference win percentage
 	confWinner := findBestNFLConferenceRecord(teams)
 	if confWinner != nil {
+		index.recordNFL("Conference win percentage", teams, *confWinner)
 		remaining := removeNFLTeam(teams, confWinner.TeamID)
 		result := []NFLTeamRecord{*confWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, index)...)
 		}
 		return result
 	}
@@ -626,10 +713,11 @@ func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult
 	// Step 5: Strength of victory
 	sovWinner := findBestNFLStrengthOfVictory(teams)
 	if sovWinner != nil {
+		index.recordNFL("Strength of victory", teams, *sovWinner)
 		remaining := removeNFLTeam(teams, sovWinner.TeamID)
 		result := []NFLTeamRecord{*sovWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, index)...)
 		}
 		return result
 	}
@@ -637,10 +725,11 @@ func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult
 	// Step 6: Strength of schedule
 	sosWinner := findBestNFLStrengthOfSchedule(teams)
 	if sosWinner != nil {
+		index.recordNFL("Strength of schedule", teams, *sosWinner)
 		remaining := removeNFLTeam(teams, sosWinner.TeamID)
 		result := []NFLTeamRecord{*sosWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, index)...)
 		}
 		return result
 	}
@@ -648,10 +737,11 @@ func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult
 	// Step 7: Point differential
 	pdWinner := findBestNFLPointDifferential(teams)
 	if pdWinner != nil {
+		index.recordNFL("Point differential", teams, *pdWinner)
 		remaining := removeNFLTeam(teams, pdWinner.TeamID)
 		result := []NFLTeamRecord{*pdWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, index)...)
 		}
 		return result
 	}
@@ -659,10 +749,11 @@ func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult
 	// Step 8: Points scored
 	psWinner := findBestNFLPointsScored(teams)
 	if psWinner != nil {
+		index.recordNFL("Points scored", teams, *psWinner)
 		remaining := removeNFLTeam(teams, psWinner.TeamID)
 		result := []NFLTeamRecord{*psWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, index)...)
 		}
 		return result
 	}
@@ -670,10 +761,11 @@ func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult
 	// Step 9: Points allowed (fewer is better)
 	paWinner := findBestNFLPointsAllowed(teams)
 	if paWinner != nil {
+		index.recordNFL("Points allowed", teams, *paWinner)
 		remaining := removeNFLTeam(teams, paWinner.TeamID)
 		result := []NFLTeamRecord{*paWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamDivisionTie(remaining, index)...)
 		}
 		return result
 	}
@@ -682,10 +774,11 @@ func resolveNFLMultiTeamDivisionTie(teams []NFLTeamRecord, games []NFLGameResult
 	sort.Slice(teams, func(i, j int) bool {
 		return teams[i].TeamID < teams[j].TeamID
 	})
+	index.recordNFL("Random drawing", teams, teams[0])
 	return teams
 }
 
-func applyNFLConferenceTiebreakers(teams []NFLTeamRecord, games []NFLGameResult, areDivisionWinners bool) []NFLTeamRecord {
+func applyNFLConferenceTiebreakers(teams []NFLTeamRecord, index *gameIndex, areDivisionWinners bool) []NFLTeamRecord {
 	if len(teams) <= 1 {
 		return teams
 	}
@@ -702,10 +795,10 @@ func applyNFLConferenceTiebreakers(teams []NFLTeamRecord, games []NFLGameResult,
 		if len(group) == 1 {
 			result = append(result, group[0])
 		} else if len(group) == 2 {
-			resolved := resolveNFLTwoTeamConferenceTie(group, games)
+			resolved := resolveNFLTwoTeamConferenceTie(group, index)
 			result = append(result, resolved...)
 		} else {
-			resolved := resolveNFLMultiTeamConferenceTie(group, games)
+			resolved := resolveNFLMultiTeamConferenceTie(group, index)
 			result = append(result, resolved...)
 		}
 	}
@@ -717,21 +810,21 @@ func applyNFLConferenceTiebreakers(teams []NFLTeamRecord, games []NFLGameResult,
 	return result
 }
 
-func resolveNFLTwoTeamConferenceTie(teams []NFLTeamRecord, games []NFLGameResult) []NFLTeamRecord {
+func resolveNFLTwoTeamConferenceTie(teams []NFLTeamRecord, index *gameIndex) []NFLTeamRecord {
 	a, b := teams[0], teams[1]
 
 	// Step 1: Division winner if from same division
 	if a.Division == b.Division {
-		return resolveNFLTwoTeamDivisionTie(teams, games)
+		return resolveNFLTwoTeamDivisionTie(teams, index)
 	}
 
 	// Step 2: Head-to-head
-	h2h := compareNFLHeadToHead(teams, games)
+	h2h := compareNFLHeadToHead(teams, index)
 	if len(h2h) == 1 {
 		if h2h[0].TeamID == a.TeamID {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Head-to-head", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Head-to-head", b, a)
 	}
 
 	// Step 3: Conference win percentage
@@ -739,34 +832,34 @@ func resolveNFLTwoTeamConferenceTie(teams []NFLTeamRecord, games []NFLGameResult
 	bConfPct := calculateNFLWinPct(b.ConferenceWins, b.ConferenceLosses, b.ConferenceTies)
 	if aConfPct != bConfPct {
 		if aConfPct > bConfPct {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Conference win percentage", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Conference win percentage", b, a)
 	}
 
 	// Step 4: Common games (minimum of 4)
-	commonResults := compareNFLCommonGames(teams, games, 4)
+	commonResults := compareNFLCommonGames(teams, index, 4)
 	if len(commonResults) == 1 {
 		if commonResults[0].TeamID == a.TeamID {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Common games", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Common games", b, a)
 	}
 
 	// Step 5: Strength of victory
 	if a.StrengthOfVictory != b.StrengthOfVictory {
 		if a.StrengthOfVictory > b.StrengthOfVictory {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Strength of victory", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Strength of victory", b, a)
 	}
 
 	// Step 6: Strength of schedule
 	if a.StrengthOfSchedule != b.StrengthOfSchedule {
 		if a.StrengthOfSchedule > b.StrengthOfSchedule {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Strength of schedule", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Strength of schedule", b, a)
 	}
 
 	// Step 7: Point differential
@@ -774,40 +867,40 @@ func resolveNFLTwoTeamConferenceTie(teams []NFLTeamRecord, games []NFLGameResult
 	bDiff := b.PointsFor - b.PointsAgainst
 	if aDiff != bDiff {
 		if aDiff > bDiff {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Point differential", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Point differential", b, a)
 	}
 
 	// Step 8: Points scored
 	if a.PointsFor != b.PointsFor {
 		if a.PointsFor > b.PointsFor {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Points scored", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Points scored", b, a)
 	}
 
 	// Step 9: Points allowed (fewer is better)
 	if a.PointsAgainst != b.PointsAgainst {
 		if a.PointsAgainst < b.PointsAgainst {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Points allowed", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Points allowed", b, a)
 	}
 
 	// Random drawing - use TeamID for consistency
 	if a.TeamID < b.TeamID {
-		return []NFLTeamRecord{a, b}
+		return index.orderNFL("Random drawing", a, b)
 	}
-	return []NFLTeamRecord{b, a}
+	return index.orderNFL("Random drawing", b, a)
 }
 
-func resolveNFLMultiTeamConferenceTie(teams []NFLTeamRecord, games []NFLGameResult) []NFLTeamRecord {
+func resolveNFLMultiTeamConferenceTie(teams []NFLTeamRecord, index *gameIndex) []NFLTeamRecord {
 	if len(teams) == 1 {
 		return teams
 	}
 	if len(teams) == 2 {
-		return resolveNFLTwoTeamConferenceTie(teams, games)
+		return resolveNFLTwoTeamConferenceTie(teams, index)
 	}
 
 	// Step 1: Division winner if all from same division
@@ -820,7 +913,7 @@ func resolveNFLMultiTeamConferenceTie(teams []NFLTeamRecord, games []NFLGameResu
 		}
 	}
 	if allSameDivision {
-		return resolveNFLMultiTeamDivisionTie(teams, games)
+		return resolveNFLMultiTeamDivisionTie(teams, index)
 	}
 
 	// Step 2: Apply division tiebreaker to get best from each division
@@ -835,7 +928,7 @@ func resolveNFLMultiTeamConferenceTie(teams []NFLTeamRecord, games []NFLGameResu
 		if len(divTeams) == 1 {
 			filtered = append(filtered, divTeams[0])
 		} else {
-			sorted := applyNFLDivisionTiebreakers(divTeams, games)
+			sorted := applyNFLDivisionTiebreakers(divTeams, index)
 			filtered = append(filtered, sorted[0])
 		}
 	}
@@ -845,18 +938,19 @@ func resolveNFLMultiTeamConferenceTie(teams []NFLTeamRecord, games []NFLGameResu
 		remaining := removeNFLTeam(teams, winner.TeamID)
 		result := []NFLTeamRecord{winner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 		}
 		return result
 	}
 
 	// Step 3: Head-to-head sweep
-	sweepWinner := checkNFLHeadToHeadSweep(filtered, games)
+	sweepWinner := checkNFLHeadToHeadSweep(filtered, index)
 	if sweepWinner != nil {
+		index.recordNFL("Head-to-head sweep", teams, *sweepWinner)
 		remaining := removeNFLTeam(teams, sweepWinner.TeamID)
 		result := []NFLTeamRecord{*sweepWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 		}
 		return result
 	}
@@ -864,71 +958,78 @@ func resolveNFLMultiTeamConferenceTie(teams []NFLTeamRecord, games []NFLGameResu
 	// Step 4: Conference win percentage
 	confWinner := findBestNFLConferenceRecord(filtered)
 	if confWinner != nil {
+		index.recordNFL("Conference win percentage", teams, *confWinner)
 		remaining := removeNFLTeam(teams, confWinner.TeamID)
 		result := []NFLTeamRecord{*confWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 		}
 		return result
 	}
 
 	// Step 5: Common games (minimum of 4)
-	commonWinner := findBestNFLCommonGamesRecord(filtered, games, 4)
+	commonWinner := findBestNFLCommonGamesRecord(filtered, index, 4)
 	if commonWinner != nil {
+		index.recordNFL("Common games", teams, *commonWinner)
 		remaining := removeNFLTeam(teams, commonWinner.TeamID)
 		result := []NFLTeamRecord{*commonWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 		}
 		return result
 	}
 
 	// Step 6: Strength of victory
 	if sovWinner := findBestNFLStrengthOfVictory(filtered); sovWinner != nil {
+		index.recordNFL("Strength of victory", teams, *sovWinner)
 		remaining := removeNFLTeam(teams, sovWinner.TeamID)
 		result := []NFLTeamRecord{*sovWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 		}
 		return result
 	}
 
 	// Step 7: Strength of schedule
 	if sosWinner := findBestNFLStrengthOfSchedule(filtered); sosWinner != nil {
+		index.recordNFL("Strength of schedule", teams, *sosWinner)
 		remaining := removeNFLTeam(teams, sosWinner.TeamID)
 		result := []NFLTeamRecord{*sosWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 		}
 		return result
 	}
 
 	// Step 8: Point differential
 	if pdWinner := findBestNFLPointDifferential(filtered); pdWinner != nil {
+		index.recordNFL("Point differential", teams, *pdWinner)
 		remaining := removeNFLTeam(teams, pdWinner.TeamID)
 		result := []NFLTeamRecord{*pdWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 		}
 		return result
 	}
 
 	// Step 9: Points scored
 	if psWinner := findBestNFLPointsScored(filtered); psWinner != nil {
+		index.recordNFL("Points scored", teams, *psWinner)
 		remaining := removeNFLTeam(teams, psWinner.TeamID)
 		result := []NFLTeamRecord{*psWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 		}
 		return result
 	}
 
 	// Step 10: Points allowed (fewer is better)
 	if paWinner := findBestNFLPointsAllowed(filtered); paWinner != nil {
+		index.recordNFL("Points allowed", teams, *paWinner)
 		remaining := removeNFLTeam(teams, paWinner.TeamID)
 		result := []NFLTeamRecord{*paWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+			result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 		}
 		return result
 	}
@@ -939,55 +1040,31 @@ func resolveNFLMultiTeamConferenceTie(teams []NFLTeamRecord, games []NFLGameResu
 	})
 
 	winner := filtered[0]
+	index.recordNFL("Random drawing", teams, winner)
 	remaining := removeNFLTeam(teams, winner.TeamID)
 	result := []NFLTeamRecord{winner}
 	if len(remaining) > 0 {
-		result = append(result, resolveNFLMultiTeamConferenceTie(remaining, games)...)
+		result = append(result, resolveNFLMultiTeamConferenceTie(remaining, index)...)
 	}
 	return result
 }
 
-func findNFLHeadToHeadWinner(teams []NFLTeamRecord, games []NFLGameResult) *NFLTeamRecord {
-	type h2hRecord struct {
-		teamID int
-		wins   int
-		losses int
-		ties   int
-	}
-
-	records := make(map[int]*h2hRecord)
+func findNFLHeadToHeadWinner(teams []NFLTeamRecord, index *gameIndex) *NFLTeamRecord {
 	teamIDs := make(map[int]bool)
 	for _, team := range teams {
-		records[team.TeamID] = &h2hRecord{teamID: team.TeamID}
 		teamIDs[team.TeamID] = true
 	}
 
-	for _, game := range games {
-		if !teamIDs[game.HomeTeamID] || !teamIDs[game.AwayTeamID] {
-			continue
-		}
-
-		if game.HomeScore > game.AwayScore {
-			records[game.HomeTeamID].wins++
-			records[game.AwayTeamID].losses++
-		} else if game.AwayScore > game.HomeScore {
-			records[game.AwayTeamID].wins++
-			records[game.HomeTeamID].losses++
-		} else {
-			records[game.HomeTeamID].ties++
-			records[game.AwayTeamID].ties++
-		}
-	}
-
 	var bestPct float64 = -2.0
 	var bestTeamID int
 	var tie bool
 
-	for _, rec := range records {
-		pct := calculateNFLWinPct(rec.wins, rec.losses, rec.ties)
+	for _, team := range teams {
+		record := index.recordAmong(team.TeamID, teamIDs)
+		pct := calculateNFLWinPct(record.wins, record.losses, record.ties)
 		if pct > bestPct {
 			bestPct = pct
-			bestTeamID = rec.teamID
+			bestTeamID = team.TeamID
 			tie = false
 		} else if pct == bestPct {
 			tie = true
@@ -1050,26 +1127,20 @@ func findBestNFLConferenceRecord(teams []NFLTeamRecord) *NFLTeamRecord {
 	return bestTeam
 }
 
-func findBestNFLCommonGamesRecord(teams []NFLTeamRecord, games []NFLGameResult, minGames int) *NFLTeamRecord {
+func findBestNFLCommonGamesRecord(teams []NFLTeamRecord, index *gameIndex, minGames int) *NFLTeamRecord {
 	teamIDs := make(map[int]bool)
 	for _, team := range teams {
 		teamIDs[team.TeamID] = true
 	}
 
-	// Find common opponents
+	// Find common opponents, counting each game played against an opponent outside the tie
 	opponentCounts := make(map[int]int)
 	for _, team := range teams {
-		for _, game := range games {
-			var oppID int
-			if game.HomeTeamID == team.TeamID && !teamIDs[game.AwayTeamID] {
-				oppID = game.AwayTeamID
-			} else if game.AwayTeamID == team.TeamID && !teamIDs[game.HomeTeamID] {
-				oppID = game.HomeTeamID
-			} else {
-				continue
+		index.eachOpponent(team.TeamID, func(opponentID int, record matchupRecord) {
+			if !teamIDs[opponentID] {
+				opponentCounts[opponentID] += record.games()
 			}
-			opponentCounts[oppID]++
-		}
+		})
 	}
 
 	var commonOpponents []int
@@ -1083,72 +1154,16 @@ func findBestNFLCommonGamesRecord(teams []NFLTeamRecord, games []NFLGameResult,
 		return nil
 	}
 
-	type record struct {
-		teamID int
-		wins   int
-		losses int
-		ties   int
-	}
-
-	records := make(map[int]*record)
-	for _, team := range teams {
-		records[team.TeamID] = &record{teamID: team.TeamID}
-	}
-
-	for _, game := range games {
-		var teamID, oppID int
-		var isCommon bool
-
-		if teamIDs[game.HomeTeamID] {
-			teamID = game.HomeTeamID
-			oppID = game.AwayTeamID
-		} else if teamIDs[game.AwayTeamID] {
-			teamID = game.AwayTeamID
-			oppID = game.HomeTeamID
-		} else {
-			continue
-		}
-
-		for _, commonOpp := range commonOpponents {
-			if oppID == commonOpp {
-				isCommon = true
-				break
-			}
-		}
-
-		if !isCommon {
-			continue
-		}
-
-		rec := records[teamID]
-		if game.HomeTeamID == teamID {
-			if game.HomeScore > game.AwayScore {
-				rec.wins++
-			} else if game.AwayScore > game.HomeScore {
-				rec.losses++
-			} else {
-				rec.ties++
-			}
-		} else {
-			if game.AwayScore > game.HomeScore {
-				rec.wins++
-			} else if game.HomeScore > game.AwayScore {
-				rec.losses++
-			} else {
-				rec.ties++
-			}
-		}
-	}
-
 	var bestPct float64 = -2.0
 	var bestTeamID int
 	var tie bool
 
-	for _, rec := range records {
-		pct := calculateNFLWinPct(rec.wins, rec.losses, rec.ties)
+	for _, team := range teams {
+		record := index.recordAgainst(team.TeamID, commonOpponents)
+		pct := calculateNFLWinPct(record.wins, record.losses, record.ties)
 		if pct > bestPct {
 			bestPct = pct
-			bestTeamID = rec.teamID
+			bestTeamID = team.TeamID
 			tie = false
 		} else if pct == bestPct {
 			tie = true
@@ -1273,9 +1288,9 @@ func findBestNFLPointsAllowed(teams []NFLTeamRecord) *NFLTeamRecord {
 	return bestTeam
 }
 
-func resolveNFLMultiTeamTie(teams []NFLTeamRecord, games []NFLGameResult, twoTeamCompare func(NFLTeamRecord, NFLTeamRecord) int) []NFLTeamRecord {
+func resolveNFLMultiTeamTie(teams []NFLTeamRecord, index *gameIndex, twoTeamCompare func(NFLTeamRecord, NFLTeamRecord) int) []NFLTeamRecord {
 	// Try head-to-head sweep first
-	sweepWinner := checkNFLHeadToHeadSweep(teams, games)
+	sweepWinner := checkNFLHeadToHeadSweep(teams, index)
 	if sweepWinner != nil {
 		remaining := []NFLTeamRecord{}
 		for _, team := range teams {
@@ -1286,7 +1301,7 @@ func resolveNFLMultiTeamTie(teams []NFLTeamRecord, games []NFLGameResult, twoTea
 
 		result := []NFLTeamRecord{*sweepWinner}
 		if len(remaining) > 0 {
-			result = append(result, resolveNFLMultiTeamTie(remaining, games, twoTeamCompare)...)
+			result = append(result, resolveNFLMultiTeamTie(remaining, index, twoTeamCompare)...)
 		}
 		return result
 	}
@@ -1302,89 +1317,32 @@ func resolveNFLMultiTeamTie(teams []NFLTeamRecord, games []NFLGameResult, twoTea
 	return sorted
 }
 
-func compareNFLHeadToHead(teams []NFLTeamRecord, games []NFLGameResult) []NFLTeamRecord {
+func compareNFLHeadToHead(teams []NFLTeamRecord, index *gameIndex) []NFLTeamRecord {
 	if len(teams) != 2 {
 		return teams
 	}
 
 	teamA, teamB := teams[0], teams[1]
-	aWins, bWins := 0, 0
-
-	// Find games between these two teams
-	for _, game := range games {
-		isMatchup := (game.HomeTeamID == teamA.TeamID && game.AwayTeamID == teamB.TeamID) || (game.HomeTeamID == teamB.TeamID && game.AwayTeamID == teamA.TeamID)
-
-		if !isMatchup {
-			continue
-		}
 
-		// Determine winner
-		var winner int
-		if game.HomeScore > game.AwayScore {
-			winner = game.HomeTeamID
-		} else if game.AwayScore > game.HomeScore {
-			winner = game.AwayTeamID
-		} else {
-			continue
-		}
-
-		// Track wins
-		if winner == teamA.TeamID {
-			aWins++
-		} else if winner == teamB.TeamID {
-			bWins++
-		}
-	}
-
-	// Return winner or 0 if tied/no games
-	if aWins > bWins {
+	// Tied games don't count toward either team
+	record := index.headToHead(teamA.TeamID, teamB.TeamID)
+	if record.wins > record.losses {
 		return []NFLTeamRecord{teamA}
-	} else if bWins > aWins {
+	} else if record.losses > record.wins {
 		return []NFLTeamRecord{teamB}
 	}
 
 	return teams
 }
 
-func checkNFLHeadToHeadSweep(teams []NFLTeamRecord, games []NFLGameResult) *NFLTeamRecord {
-	for _, candidate := range teams {
-		hasDefeatedAll := true
-
-		for _, opponent := range teams {
-			if candidate.TeamID == opponent.TeamID {
-				continue
-			}
-
-			defeatedOpponent := false
-			for _, game := range games {
-				isMatchup := (game.HomeTeamID == candidate.TeamID && game.AwayTeamID == opponent.TeamID) || (game.HomeTeamID == opponent.TeamID && game.AwayTeamID == candidate.TeamID)
-
-				if !isMatchup {
-					continue
-				}
-
-				var winner int
-				if game.HomeScore > game.AwayScore {
-					winner = game.HomeTeamID
-				} else if game.AwayScore > game.HomeScore {
-					winner = game.AwayTeamID
-				} else {
-					continue
-				}
-
-				if winner == candidate.TeamID {
-					defeatedOpponent = true
-					break
-				}
-			}
-
-			if !defeatedOpponent {
-				hasDefeatedAll = false
-				break
-			}
-		}
+func checkNFLHeadToHeadSweep(teams []NFLTeamRecord, index *gameIndex) *NFLTeamRecord {
+	teamIDs := make([]int, len(teams))
+	for i, team := range teams {
+		teamIDs[i] = team.TeamID
+	}
 
-		if hasDefeatedAll {
+	for _, candidate := range teams {
+		if index.hasBeatenAll(candidate.TeamID, teamIDs) {
 			return &candidate
 		}
 	}
@@ -1392,7 +1350,7 @@ func checkNFLHeadToHeadSweep(teams []NFLTeamRecord, games []NFLGameResult) *NFLT
 	return nil
 }
 
-func compareNFLCommonGames(teams []NFLTeamRecord, games []NFLGameResult, minCommonGames int) []NFLTeamRecord {
+func compareNFLCommonGames(teams []NFLTeamRecord, index *gameIndex, minCommonGames int) []NFLTeamRecord {
 	if len(teams) != 2 {
 		return teams
 	}
@@ -1400,29 +1358,7 @@ func compareNFLCommonGames(teams []NFLTeamRecord, games []NFLGameResult, minComm
 	teamA, teamB := teams[0], teams[1]
 
 	// Find common opponents (teams both have played)
-	aOpponents := make(map[int]bool)
-	bOpponents := make(map[int]bool)
-
-	for _, game := range games {
-		if game.HomeTeamID == teamA.TeamID {
-			aOpponents[game.AwayTeamID] = true
-		} else if game.AwayTeamID == teamA.TeamID {
-			aOpponents[game.HomeTeamID] = true
-		}
-
-		if game.HomeTeamID == teamB.TeamID {
-			bOpponents[game.AwayTeamID] = true
-		} else if game.AwayTeamID == teamB.TeamID {
-			bOpponents[game.HomeTeamID] = true
-		}
-	}
-
-	commonOpponents := []int{}
-	for oppID := range aOpponents {
-		if bOpponents[oppID] {
-			commonOpponents = append(commonOpponents, oppID)
-		}
-	}
+	commonOpponents := index.commonOpponents(teamA.TeamID, teamB.TeamID)
 
 	// Check minimum common games requirement
 	if len(commonOpponents) < minCommonGames {
@@ -1430,58 +1366,11 @@ func compareNFLCommonGames(teams []NFLTeamRecord, games []NFLGameResult, minComm
 	}
 
 	// Calculate records against common opponents
-	aWins, aLosses, aTies := 0, 0, 0
-	bWins, bLosses, bTies := 0, 0, 0
-
-	for _, game := range games {
-		// isCommon := false
-		for _, oppID := range commonOpponents {
-			if (game.HomeTeamID == teamA.TeamID && game.AwayTeamID == oppID) || (game.AwayTeamID == teamA.TeamID && game.HomeTeamID == oppID) {
-				// isCommon = true
-
-				if game.HomeScore > game.AwayScore {
-					if game.HomeTeamID == teamA.TeamID {
-						aWins++
-					} else {
-						aLosses++
-					}
-				} else if game.AwayScore > game.HomeScore {
-					if game.AwayTeamID == teamA.TeamID {
-						aWins++
-					} else {
-						aLosses++
-					}
-				} else {
-					aTies++
-				}
-				break
-			}
-
-			if (game.HomeTeamID == teamB.TeamID && game.AwayTeamID == oppID) || (game.AwayTeamID == teamB.TeamID && game.HomeTeamID == oppID) {
-				// isCommon = true
-
-				if game.HomeScore > game.AwayScore {
-					if game.HomeTeamID == teamB.TeamID {
-						bWins++
-					} else {
-						bLosses++
-					}
-				} else if game.AwayScore > game.HomeScore {
-					if game.AwayTeamID == teamB.TeamID {
-						bWins++
-					} else {
-						bLosses++
-					}
-				} else {
-					bTies++
-				}
-				break
-			}
-		}
-	}
+	aRecord := index.recordAgainst(teamA.TeamID, commonOpponents)
+	bRecord := index.recordAgainst(teamB.TeamID, commonOpponents)
 
-	aPct := calculateNFLWinPct(aWins, aLosses, aTies)
-	bPct := calculateNFLWinPct(bWins, bLosses, bTies)
+	aPct := calculateNFLWinPct(aRecord.wins, aRecord.losses, aRecord.ties)
+	bPct := calculateNFLWinPct(bRecord.wins, bRecord.losses, bRecord.ties)
 
 	if aPct > bPct {
 		return []NFLTeamRecord{teamA}
@@ -1502,7 +1391,7 @@ func removeNFLTeam(teams []NFLTeamRecord, teamID int) []NFLTeamRecord {
 	return result
 }
 
-func calculateNFLDraftOrder(allTeams []NFLTeamRecord, afc NFLConferenceStandings, nfc NFLConferenceStandings) []NFLDraftPick {
+func calculateNFLDraftOrder(allTeams []NFLTeamRecord, afc NFLConferenceStandings, nfc NFLConferenceStandings, trace *tiebreakerTrace) []NFLDraftPick {
 	// Get playoff teams (first 7 from each conference)
 	playoffTeamIDs := make(map[int]bool)
 	for _, seed := range afc.PlayoffSeeds {
@@ -1524,16 +1413,19 @@ func calculateNFLDraftOrder(allTeams []NFLTeamRecord, afc NFLConferenceStandings
 		}
 	}
 
-	// Sort non-playoff teams by record (worst to best)
-	sortedNonPlayoff := applyNFLDraftOrderTiebreakers(nonPlayoffTeams, []NFLGameResult{}, afc, nfc)
+	// Order non-playoff teams worst to best. Draft tiebreakers run without head-to-head results,
+	// so they get an empty index that only shares the trace.
+	draftIndex := newGameIndex()
+	draftIndex.trace = trace
+	draftIndex.traceContext("Draft order")
+	sortedNonPlayoff := applyNFLDraftOrderTiebreakers(nonPlayoffTeams, draftIndex, afc, nfc)
 
 	// Build draft order
 	draftOrder := []NFLDraftPick{}
 	pickNum := 1
 
 	// Picks 1-18: Non-playoff teams
-	for i := len(sortedNonPlayoff) - 1; i >= 0; i-- {
-		team := sortedNonPlayoff[i]
+	for _, team := range sortedNonPlayoff {
 		draftOrder = append(draftOrder, NFLDraftPick{
 			Pick: pickNum,
 			Team: team,
@@ -1574,49 +1466,51 @@ func calculateNFLDraftOrder(allTeams []NFLTeamRecord, afc NFLConferenceStandings
 	return draftOrder
 }
 
-func applyNFLDraftOrderTiebreakers(teams []NFLTeamRecord, games []NFLGameResult, afc NFLConferenceStandings, nfc NFLConferenceStandings) []NFLTeamRecord {
+// Orders teams worst to best for the draft. Tied groups keep their resolver order, which puts the team
+// that picks earlier first.
+func applyNFLDraftOrderTiebreakers(teams []NFLTeamRecord, index *gameIndex, afc NFLConferenceStandings, nfc NFLConferenceStandings) []NFLTeamRecord {
 	if len(teams) <= 1 {
 		return teams
 	}
 
 	// Group teams by win percentage
 	pctGroups := make(map[float64][]NFLTeamRecord)
+	groupOrder := []float64{}
 	for _, team := range teams {
+		if _, exists := pctGroups[team.WinPct]; !exists {
+			groupOrder = append(groupOrder, team.WinPct)
+		}
 		pctGroups[team.WinPct] = append(pctGroups[team.WinPct], team)
 	}
 
+	// Worst win percentage first, keeping each group's tiebreaker order intact
+	sort.Float64s(groupOrder)
 	var result []NFLTeamRecord
-
-	// Sort each group
-	for _, group := range pctGroups {
+	for _, pct := range groupOrder {
+		group := pctGroups[pct]
 		if len(group) == 1 {
 			result = append(result, group[0])
 		} else if len(group) == 2 {
-			sorted := resolveNFLTwoTeamDraftTie(group, games, afc, nfc)
+			sorted := resolveNFLTwoTeamDraftTie(group, index, afc, nfc)
 			result = append(result, sorted...)
 		} else {
-			sorted := resolveNFLMultiTeamDraftTie(group, games, afc, nfc)
+			sorted := resolveNFLMultiTeamDraftTie(group, index, afc, nfc)
 			result = append(result, sorted...)
 		}
 	}
 
-	// Sort groups by win percentage
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].WinPct > result[j].WinPct
-	})
-
 	return result
 }
 
-func resolveNFLTwoTeamDraftTie(teams []NFLTeamRecord, games []NFLGameResult, afc NFLConferenceStandings, nfc NFLConferenceStandings) []NFLTeamRecord {
+func resolveNFLTwoTeamDraftTie(teams []NFLTeamRecord, index *gameIndex, afc NFLConferenceStandings, nfc NFLConferenceStandings) []NFLTeamRecord {
 	a, b := teams[0], teams[1]
 
 	// Step 1: Strength of schedule (worse gets earlier pick)
 	if a.StrengthOfSchedule != b.StrengthOfSchedule {
 		if a.StrengthOfSchedule < b.StrengthOfSchedule {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Strength of schedule", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Strength of schedule", b, a)
 	}
 
 	// Step 2: Division rank if same division (lower rank = earlier pick)
@@ -1644,9 +1538,9 @@ func resolveNFLTwoTeamDraftTie(teams []NFLTeamRecord, games []NFLGameResult, afc
 
 		if aRank != bRank && aRank != -1 && bRank != -1 {
 			if aRank > bRank { // Higher rank number = worse record = earlier pick
-				return []NFLTeamRecord{a, b}
+				return index.orderNFL("Division rank", a, b)
 			}
-			return []NFLTeamRecord{b, a}
+			return index.orderNFL("Division rank", b, a)
 		}
 	}
 
@@ -1671,38 +1565,38 @@ func resolveNFLTwoTeamDraftTie(teams []NFLTeamRecord, games []NFLGameResult, afc
 
 		if aRank != bRank && aRank != -1 && bRank != -1 {
 			if aRank > bRank {
-				return []NFLTeamRecord{a, b}
+				return index.orderNFL("Conference rank", a, b)
 			}
-			return []NFLTeamRecord{b, a}
+			return index.orderNFL("Conference rank", b, a)
 		}
 	}
 
 	// Inter-conference tiebreakers (reversed for draft order)
 
 	// Step 1: Head-to-head (if applicable) (loser gets earlier pick)
-	h2hResult := compareNFLHeadToHead(teams, games)
+	h2hResult := compareNFLHeadToHead(teams, index)
 	if len(h2hResult) == 1 {
 		if h2hResult[0].TeamID == a.TeamID {
-			return []NFLTeamRecord{b, a}
+			return index.orderNFL("Head-to-head", b, a)
 		}
-		return []NFLTeamRecord{a, b}
+		return index.orderNFL("Head-to-head", a, b)
 	}
 
 	// Step 2: Worst win percentage in common games (minimum of 4)
-	commonResults := compareNFLCommonGames(teams, games, 4)
+	commonResults := compareNFLCommonGames(teams, index, 4)
 	if len(commonResults) == 1 {
 		if commonResults[0].TeamID == a.TeamID {
-			return []NFLTeamRecord{b, a}
+			return index.orderNFL("Common games", b, a)
 		}
-		return []NFLTeamRecord{a, b}
+		return index.orderNFL("Common games", a, b)
 	}
 
 	// Step 3: Strength of victory (lower gets earlier pick)
 	if a.StrengthOfVictory != b.StrengthOfVictory {
 		if a.StrengthOfVictory < b.StrengthOfVictory {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Strength of victory", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Strength of victory", b, a)
 	}
 
 	// Step 4: Worst point differential
@@ -1710,24 +1604,24 @@ func resolveNFLTwoTeamDraftTie(teams []NFLTeamRecord, games []NFLGameResult, afc
 	bDiff := b.PointsFor - b.PointsAgainst
 	if aDiff != bDiff {
 		if aDiff < bDiff {
-			return []NFLTeamRecord{a, b}
+			return index.orderNFL("Point differential", a, b)
 		}
-		return []NFLTeamRecord{b, a}
+		return index.orderNFL("Point differential", b, a)
 	}
 
 	// Random drawing - use TeamID for consistency
 	if a.TeamID < b.TeamID {
-		return []NFLTeamRecord{a, b}
+		return index.orderNFL("Random drawing", a, b)
 	}
-	return []NFLTeamRecord{b, a}
+	return index.orderNFL("Random drawing", b, a)
 }
 
-func resolveNFLMultiTeamDraftTie(teams []NFLTeamRecord, games []NFLGameResult, afc NFLConferenceStandings, nfc NFLConferenceStandings) []NFLTeamRecord {
+func resolveNFLMultiTeamDraftTie(teams []NFLTeamRecord, index *gameIndex, afc NFLConferenceStandings, nfc NFLConferenceStandings) []NFLTeamRecord {
 	if len(teams) == 1 {
 		return teams
 	}
 	if len(teams) == 2 {
-		return resolveNFLTwoTeamDraftTie(teams, games, afc, nfc)
+		return resolveNFLTwoTeamDraftTie(teams, index, afc, nfc)
 	}
 
 	// Check if all same division
@@ -1765,6 +1659,7 @@ func resolveNFLMultiTeamDraftTie(teams []NFLTeamRecord, games []NFLGameResult, a
 		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
 			result[i], result[j] = result[j], result[i]
 		}
+		index.recordNFL("Division rank", teams, result[0])
 
 		return result
 	}
@@ -1801,6 +1696,7 @@ func resolveNFLMultiTeamDraftTie(teams []NFLTeamRecord, games []NFLGameResult, a
 			}
 			return iSeed > jSeed // Higher seed number = worse record = earlier pick
 		})
+		index.recordNFL("Conference rank", teams, teams[0])
 		return teams
 	}
 
@@ -1818,14 +1714,15 @@ func resolveNFLMultiTeamDraftTie(teams []NFLTeamRecord, games []NFLGameResult, a
 		if len(divTeams) == 1 {
 			divRepresentatives = append(divRepresentatives, divTeams[0])
 		} else {
-			sorted := applyNFLDivisionTiebreakers(divTeams, games)
+			sorted := applyNFLDivisionTiebreakers(divTeams, index)
 			divRepresentatives = append(divRepresentatives, sorted[len(sorted)-1])
 		}
 	}
 
 	// If only 2 teams remain, use two-team inter-conference tiebreakers
 	if len(divRepresentatives) == 2 {
-		return resolveNFLTwoTeamDraftTie(divRepresentatives, games, afc, nfc)
+		earliest := resolveNFLTwoTeamDraftTie(divRepresentatives, index, afc, nfc)[0]
+		return placeNFLDraftTieTeam(teams, earliest, index, afc, nfc)
 	}
 
 	// Group remaining teams by conference
@@ -1872,17 +1769,33 @@ func resolveNFLMultiTeamDraftTie(teams []NFLTeamRecord, games []NFLGameResult, a
 	}
 
 	// Now should have max 2 inter-conference teams
-	if len(finalRepresentatives) <= 2 {
-		return resolveNFLTwoTeamDraftTie(finalRepresentatives, games, afc, nfc)
+	if len(finalRepresentatives) == 1 {
+		return placeNFLDraftTieTeam(teams, finalRepresentatives[0], index, afc, nfc)
+	}
+	if len(finalRepresentatives) == 2 {
+		earliest := resolveNFLTwoTeamDraftTie(finalRepresentatives, index, afc, nfc)[0]
+		return placeNFLDraftTieTeam(teams, earliest, index, afc, nfc)
 	}
 
 	// Fallback: sort by strength of schedule
 	sort.Slice(teams, func(i, j int) bool {
 		return teams[i].StrengthOfSchedule < teams[j].StrengthOfSchedule
 	})
+	index.recordNFL("Strength of schedule", teams, teams[0])
 	return teams
 }
 
+// Places the team that won a round of a multi-team draft tie, then breaks the tie again among the rest.
+// Representatives only settle one team per round, so every other tied team has to go through another round.
+func placeNFLDraftTieTeam(teams []NFLTeamRecord, placed NFLTeamRecord, index *gameIndex, afc NFLConferenceStandings, nfc NFLConferenceStandings) []NFLTeamRecord {
+	result := []NFLTeamRecord{placed}
+	remaining := removeNFLTeam(teams, placed.TeamID)
+	if len(remaining) > 0 {
+		result = append(result, resolveNFLMultiTeamDraftTie(remaining, index, afc, nfc)...)
+	}
+	return result
+}
+
 func calculateNFLWinPct(wins, losses, ties int) float64 {
 	total := wins + losses + ties
 	if total == 0 {
@@ -1,6 +1,9 @@
 package standings
 
 import (
+	"encoding/json"
+	"os"
+	"reflect"
 	"testing"
 )
 
@@ -121,7 +124,7 @@ func TestGetHeadToHeadWinner(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			teams := []NFLTeamRecord{teamA, teamB}
-			result := compareNFLHeadToHead(teams, tt.games)
+			result := compareNFLHeadToHead(teams, newNFLGameIndex(tt.games))
 			if !equalTeamRecordSlices(result, tt.expected) {
 				t.Errorf("compareHeadToHead() = %v; want %v", result, tt.expected)
 			}
@@ -204,7 +207,7 @@ func TestApplyTiebreakers(t *testing.T) {
 		{TeamID: 3, Wins: 12, Losses: 5, WinPct: 0.706},
 	}
 
-	sorted := applyNFLDivisionTiebreakers(teams, []NFLGameResult{})
+	sorted := applyNFLDivisionTiebreakers(teams, newNFLGameIndex(nil))
 
 	if sorted[0].TeamID != 3 || sorted[1].TeamID != 2 || sorted[2].TeamID != 1 {
 		t.Errorf("Teams not sorted correctly by win percentage")
@@ -218,10 +221,89 @@ func TestApplyTiebreakersWithPointDifferential(t *testing.T) {
 		{TeamID: 2, Wins: 10, Losses: 7, WinPct: 0.588, PointsFor: 380, PointsAgainst: 340},
 	}
 
-	sorted := applyNFLDivisionTiebreakers(teams, []NFLGameResult{})
+	sorted := applyNFLDivisionTiebreakers(teams, newNFLGameIndex(nil))
 
 	// Team 2 should rank higher (better point differential: +40 vs +30)
 	if sorted[0].TeamID != 2 {
 		t.Errorf("Expected Team 2 to rank first due to point differential")
 	}
 }
+
+func TestNFLDraftOrderIncludesEveryTeam(t *testing.T) {
+	for _, pattern := range SyntheticPatterns() {
+		t.Run(pattern, func(t *testing.T) {
+			teams, games, err := GenerateNFLSeason(pattern, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			draftOrder := BuildNFLStandings(teams, games).DraftOrder
+
+			picked := make(map[int]bool)
+			for _, pick := range draftOrder {
+				picked[pick.Team.TeamID] = true
+			}
+			if len(draftOrder) != len(teams) || len(picked) != len(teams) {
+				t.Errorf("draft order has %d picks for %d distinct teams; want %d of each", len(draftOrder), len(picked), len(teams))
+			}
+		})
+	}
+}
+
+func TestNFLDraftOrderBreaksTiesByStrengthOfSchedule(t *testing.T) {
+	teams := []NFLTeamRecord{
+		{TeamID: 1, Conference: "AFC", Division: "AFC East", WinPct: 0.294, StrengthOfSchedule: 0.5346},
+		{TeamID: 2, Conference: "NFC", Division: "NFC West", WinPct: 0.412},
+		{TeamID: 3, Conference: "NFC", Division: "NFC North", WinPct: 0.294, StrengthOfSchedule: 0.5294},
+	}
+
+	draftOrder := calculateNFLDraftOrder(teams, NFLConferenceStandings{}, NFLConferenceStandings{}, nil)
+
+	// Tied on record, the easier schedule picks first
+	expected := []int{3, 1, 2}
+	for i, teamID := range expected {
+		if draftOrder[i].Team.TeamID != teamID || draftOrder[i].Pick != i+1 {
+			t.Errorf("pick %d = team %d; want team %d", i+1, draftOrder[i].Team.TeamID, teamID)
+		}
+	}
+}
+
+// Seeds from the engine before it was rebuilt on pre-indexed tables, for the synthetic seasons in the file.
+// Regenerate only when a tiebreaker change is meant to move seeds.
+func TestNFLSeedsMatchPreIndexEngine(t *testing.T) {
+	data, err := os.ReadFile("testdata/nfl_seeds_pre_index.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seasons []struct {
+		Pattern string `json:"pattern"`
+		Seed    int64  `json:"seed"`
+		AFC     []int  `json:"afc"`
+		NFC     []int  `json:"nfc"`
+	}
+	if err := json.Unmarshal(data, &seasons); err != nil {
+		t.Fatal(err)
+	}
+
+	seedIDs := func(conference NFLConferenceStandings) []int {
+		var ids []int
+		for _, seed := range conference.PlayoffSeeds {
+			ids = append(ids, seed.Team.TeamID)
+		}
+		return ids
+	}
+
+	for _, season := range seasons {
+		teams, games, err := GenerateNFLSeason(season.Pattern, season.Seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		calculated := BuildNFLStandings(teams, games)
+		if afc := seedIDs(calculated.AFC); !reflect.DeepEqual(afc, season.AFC) {
+			t.Errorf("%s seed %d: AFC seeds = %v; want %v", season.Pattern, season.Seed, afc, season.AFC)
+		}
+		if nfc := seedIDs(calculated.NFC); !reflect.DeepEqual(nfc, season.NFC) {
+			t.Errorf("%s seed %d: NFC seeds = %v; want %v", season.Pattern, season.Seed, nfc, season.NFC)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Resolving scenario picks against real game results
+
+package standings
+
+
+// Scenario result modes
+const (
+	ResultModeAlternateHistory = "alternate_history" // Override picks replace the real results of final games
+	ResultModeHonorReal        = "honor_real"        // Real results of final games always stand
+)
+
+// Final game whose real result was replaced by a scenario's override pick
+type CounterfactualResult struct {
+	GameID             int
+	Week               int
+	HomeTeamID         int
+	AwayTeamID         int
+	ActualHomeScore    int
+	ActualAwayScore    int
+	PickedTeamID       int
+	PredictedHomeScore *int
+	PredictedAwayScore *int
+}
+
+func IsValidResultMode(mode string) bool {
+	return mode == ResultModeAlternateHistory || mode == ResultModeHonorReal
+}
+
+// Whether a scenario's pick decides a game instead of its real result. Picks decide every game that
+// isn't final yet; once it is, only override picks do, and never when the scenario honors real results.
+func PickDecidesGame(isFinal bool, isOverride bool, resultMode string) bool {
+	if !isFinal {
+		return true
+	}
+	return isOverride && resultMode != ResultModeHonorReal
+}
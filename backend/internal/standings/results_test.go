@@ -0,0 +1,30 @@
+package standings
+
+import (
+	"testing"
+)
+
+func TestPickDecidesGame(t *testing.T) {
+	tests := []struct {
+		name       string
+		isFinal    bool
+		isOverride bool
+		resultMode string
+		expected   bool
+	}{
+		{"Game not final", false, false, ResultModeHonorReal, true},
+		{"Prediction on final game", true, false, ResultModeAlternateHistory, false},
+		{"Override in alternate history", true, true, ResultModeAlternateHistory, true},
+		{"Override when honoring real results", true, true, ResultModeHonorReal, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PickDecidesGame(tt.isFinal, tt.isOverride, tt.resultMode)
+			if result != tt.expected {
+				t.Errorf("PickDecidesGame(%v, %v, %q) = %v; want %v",
+					tt.isFinal, tt.isOverride, tt.resultMode, result, tt.expected)
+			}
+		})
+	}
+}
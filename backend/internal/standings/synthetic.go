@@ -0,0 +1,232 @@
+// Synthetic seasons for benchmarking and profiling the standings engine
+
+package standings
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+
+// Synthetic season outcome patterns
+const (
+	PatternRandom   = "random"    // Random scores, the typical case
+	PatternHomeWins = "home_wins" // Every home team wins 24-17, piling half of each conference up at 8-9
+	PatternAllTies  = "all_ties"  // Every NFL game ends 20-20 and every NBA season series is split, leaving whole divisions tied
+)
+
+var syntheticPatterns = []string{PatternRandom, PatternHomeWins, PatternAllTies}
+
+func SyntheticPatterns() []string {
+	return syntheticPatterns
+}
+
+// Generates a 32-team NFL season with a 17-game schedule built like the real rotation: division home and
+// away series, a full intra-conference and inter-conference division pairing, and three same-place games
+func GenerateNFLSeason(pattern string, seed int64) ([]NFLTeamRecord, []NFLGameResult, error) {
+	if !isSyntheticPattern(pattern) {
+		return nil, nil, fmt.Errorf("unknown pattern %q", pattern)
+	}
+
+	conferences := []string{"AFC", "NFC"}
+	regions := []string{"East", "North", "South", "West"}
+
+	// teamIDs[conference][division][place]
+	var teams []NFLTeamRecord
+	teamIDs := make([][][]int, len(conferences))
+	for c, conference := range conferences {
+		teamIDs[c] = make([][]int, len(regions))
+		for d, region := range regions {
+			for place := 0; place < 4; place++ {
+				id := len(teams) + 1
+				teamIDs[c][d] = append(teamIDs[c][d], id)
+				teams = append(teams, NFLTeamRecord{
+					TeamID:     id,
+					TeamCity:   fmt.Sprintf("%s %s", conference, region),
+					TeamName:   fmt.Sprintf("Team %d", id),
+					TeamAbbr:   fmt.Sprintf("T%02d", id),
+					Conference: conference,
+					Division:   fmt.Sprintf("%s %s", conference, region),
+				})
+			}
+		}
+	}
+
+	type matchup struct{ home, away int }
+	var matchups []matchup
+	// Home team when the two places' sum is even, so each side of a four-game pairing hosts twice
+	addPairing := func(a []int, b []int) {
+		for p, teamA := range a {
+			for q, teamB := range b {
+				if (p+q)%2 == 0 {
+					matchups = append(matchups, matchup{teamA, teamB})
+				} else {
+					matchups = append(matchups, matchup{teamB, teamA})
+				}
+			}
+		}
+	}
+
+	for c := range conferences {
+		for d := range regions {
+			division := teamIDs[c][d]
+			for i := range division {
+				for j := i + 1; j < len(division); j++ {
+					matchups = append(matchups, matchup{division[i], division[j]}, matchup{division[j], division[i]})
+				}
+			}
+		}
+
+		// Divisions 0-1 and 2-3 play each other, then same-place games against the other pair
+		addPairing(teamIDs[c][0], teamIDs[c][1])
+		addPairing(teamIDs[c][2], teamIDs[c][3])
+		for place := 0; place < 4; place++ {
+			matchups = append(matchups,
+				matchup{teamIDs[c][0][place], teamIDs[c][2][place]},
+				matchup{teamIDs[c][3][place], teamIDs[c][0][place]},
+				matchup{teamIDs[c][2][place], teamIDs[c][1][place]},
+				matchup{teamIDs[c][1][place], teamIDs[c][3][place]},
+			)
+		}
+	}
+	for d := range regions {
+		addPairing(teamIDs[0][d], teamIDs[1][d])
+
+		// Seventeenth game against the same place in the next division of the other conference
+		for place := 0; place < 4; place++ {
+			afcTeam, nfcTeam := teamIDs[0][d][place], teamIDs[1][(d+1)%len(regions)][place]
+			if d%2 == 0 {
+				matchups = append(matchups, matchup{afcTeam, nfcTeam})
+			} else {
+				matchups = append(matchups, matchup{nfcTeam, afcTeam})
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(matchups), func(i, j int) { matchups[i], matchups[j] = matchups[j], matchups[i] })
+
+	games := make([]NFLGameResult, 0, len(matchups))
+	for i, m := range matchups {
+		homeScore, awayScore := syntheticScores(pattern, rng, 45)
+		games = append(games, NFLGameResult{
+			GameID:     i + 1,
+			HomeTeamID: m.home,
+			AwayTeamID: m.away,
+			HomeScore:  homeScore,
+			AwayScore:  awayScore,
+			Week:       i*18/len(matchups) + 1,
+		})
+	}
+
+	return teams, games, nil
+}
+
+// Generates a 30-team NBA season where division rivals meet four times, conference rivals three times, and
+// teams from the other conference twice
+func GenerateNBASeason(pattern string, seed int64) ([]NBATeamRecord, []NBAGameResult, error) {
+	if !isSyntheticPattern(pattern) {
+		return nil, nil, fmt.Errorf("unknown pattern %q", pattern)
+	}
+
+	conferences := map[string][]string{
+		"Eastern": {"Atlantic", "Central", "Southeast"},
+		"Western": {"Northwest", "Pacific", "Southwest"},
+	}
+
+	var teams []NBATeamRecord
+	for _, conference := range []string{"Eastern", "Western"} {
+		for _, division := range conferences[conference] {
+			for place := 0; place < 5; place++ {
+				id := len(teams) + 1
+				teams = append(teams, NBATeamRecord{
+					TeamID:     id,
+					TeamCity:   division,
+					TeamName:   fmt.Sprintf("Team %d", id),
+					TeamAbbr:   fmt.Sprintf("T%02d", id),
+					Conference: conference,
+					Division:   division,
+				})
+			}
+		}
+	}
+
+	type matchup struct {
+		home, away int
+		splitWinner int // Winner when season series are split
+	}
+	var matchups []matchup
+	for i := range teams {
+		for j := i + 1; j < len(teams); j++ {
+			a, b := teams[i], teams[j]
+			meetings := 2
+			if a.Division == b.Division {
+				meetings = 4
+			} else if a.Conference == b.Conference {
+				meetings = 3
+			}
+
+			// Alternate hosts, starting with whichever team the pair's parity picks
+			for meeting := 0; meeting < meetings; meeting++ {
+				splitWinner := a.TeamID
+				if meeting%2 == 1 {
+					splitWinner = b.TeamID
+				}
+				if (i+j+meeting)%2 == 0 {
+					matchups = append(matchups, matchup{a.TeamID, b.TeamID, splitWinner})
+				} else {
+					matchups = append(matchups, matchup{b.TeamID, a.TeamID, splitWinner})
+				}
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(matchups), func(i, j int) { matchups[i], matchups[j] = matchups[j], matchups[i] })
+
+	games := make([]NBAGameResult, 0, len(matchups))
+	for i, m := range matchups {
+		// NBA games can't end tied, so close ones go to the home team
+		homeScore, awayScore := syntheticScores(pattern, rng, 60)
+		if pattern == PatternAllTies {
+			homeScore, awayScore = 24, 17
+			if m.splitWinner != m.home {
+				homeScore, awayScore = awayScore, homeScore
+			}
+		} else if homeScore == awayScore {
+			homeScore++
+		}
+
+		games = append(games, NBAGameResult{
+			GameID:        i + 1,
+			HomeTeamID:    m.home,
+			AwayTeamID:    m.away,
+			HomeScore:     homeScore + 80,
+			AwayScore:     awayScore + 80,
+			Week:          i*25/len(matchups) + 1,
+			HasRealScores: true,
+		})
+	}
+
+	return teams, games, nil
+}
+
+func isSyntheticPattern(pattern string) bool {
+	for _, known := range syntheticPatterns {
+		if pattern == known {
+			return true
+		}
+	}
+	return false
+}
+
+func syntheticScores(pattern string, rng *rand.Rand, maxScore int) (int, int) {
+	switch pattern {
+	case PatternHomeWins:
+		return 24, 17
+	case PatternAllTies:
+		return 20, 20
+	default:
+		return rng.Intn(maxScore + 1), rng.Intn(maxScore + 1)
+	}
+}
@@ -0,0 +1,51 @@
+package standings
+
+import (
+	"testing"
+)
+
+func TestGenerateNFLSeason(t *testing.T) {
+	teams, games, err := GenerateNFLSeason(PatternRandom, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gamesPlayed := make(map[int]int)
+	for _, game := range games {
+		gamesPlayed[game.HomeTeamID]++
+		gamesPlayed[game.AwayTeamID]++
+	}
+	for _, team := range teams {
+		if gamesPlayed[team.TeamID] != 17 {
+			t.Errorf("team %d plays %d games; want 17", team.TeamID, gamesPlayed[team.TeamID])
+		}
+	}
+}
+
+func TestGenerateNBASeason(t *testing.T) {
+	teams, games, err := GenerateNBASeason(PatternRandom, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gamesPlayed := make(map[int]int)
+	for _, game := range games {
+		if game.HomeScore == game.AwayScore {
+			t.Errorf("game %d ends tied at %d", game.GameID, game.HomeScore)
+		}
+		gamesPlayed[game.HomeTeamID]++
+		gamesPlayed[game.AwayTeamID]++
+	}
+	for _, team := range teams {
+		// 4 games against 4 division rivals, 3 against 10 conference rivals, 2 against 15 other teams
+		if gamesPlayed[team.TeamID] != 76 {
+			t.Errorf("team %d plays %d games; want 76", team.TeamID, gamesPlayed[team.TeamID])
+		}
+	}
+}
+
+func TestGenerateUnknownPattern(t *testing.T) {
+	if _, _, err := GenerateNFLSeason("blowouts", 1); err == nil {
+		t.Errorf("GenerateNFLSeason(\"blowouts\", 1) returned no error")
+	}
+}
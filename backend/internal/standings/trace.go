@@ -0,0 +1,84 @@
+// Optional record of which tiebreaker step decided each tie, for inspecting standings
+
+package standings
+
+import (
+	"sort"
+)
+
+
+// One tiebreaker step that separated tied teams
+type TiebreakerDecision struct {
+	Context string   `json:"context"` // Where the tie was broken, like "AFC East", "NFC wild cards", or "Draft order"
+	Teams   []string `json:"teams"`   // Abbreviations of the teams still tied, sorted
+	Step    string   `json:"step"`    // The tiebreaker that separated them
+	Winner  string   `json:"winner"`  // The team placed ahead, which for the draft is the team picking earlier
+}
+
+type tiebreakerTrace struct {
+	context   string
+	decisions []TiebreakerDecision
+}
+
+// Orders decisions by context so output doesn't depend on map iteration, keeping each context's
+// decisions in the order they were made
+func (trace *tiebreakerTrace) sorted() []TiebreakerDecision {
+	decisions := append([]TiebreakerDecision{}, trace.decisions...)
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return decisions[i].Context < decisions[j].Context
+	})
+	return decisions
+}
+
+// Sets where the following decisions are being made. Does nothing unless tracing.
+func (index *gameIndex) traceContext(context string) {
+	if index.trace != nil {
+		index.trace.context = context
+	}
+}
+
+func (index *gameIndex) record(step string, teams []string, winner string) {
+	sort.Strings(teams)
+	index.trace.decisions = append(index.trace.decisions, TiebreakerDecision{
+		Context: index.trace.context,
+		Teams:   teams,
+		Step:    step,
+		Winner:  winner,
+	})
+}
+
+// Records that a step put the winner ahead of the rest of the tied teams. Does nothing unless tracing.
+func (index *gameIndex) recordNFL(step string, teams []NFLTeamRecord, winner NFLTeamRecord) {
+	if index.trace == nil {
+		return
+	}
+	abbrs := make([]string, len(teams))
+	for i, team := range teams {
+		abbrs[i] = team.TeamAbbr
+	}
+	index.record(step, abbrs, winner.TeamAbbr)
+}
+
+func (index *gameIndex) recordNBA(step string, teams []NBATeamRecord, winner NBATeamRecord) {
+	if index.trace == nil {
+		return
+	}
+	abbrs := make([]string, len(teams))
+	for i, team := range teams {
+		abbrs[i] = team.TeamAbbr
+	}
+	index.record(step, abbrs, winner.TeamAbbr)
+}
+
+// Orders a two-team tie, recording the step that decided it
+func (index *gameIndex) orderNFL(step string, first NFLTeamRecord, second NFLTeamRecord) []NFLTeamRecord {
+	ordered := []NFLTeamRecord{first, second}
+	index.recordNFL(step, ordered, first)
+	return ordered
+}
+
+func (index *gameIndex) orderNBA(step string, first NBATeamRecord, second NBATeamRecord) []NBATeamRecord {
+	ordered := []NBATeamRecord{first, second}
+	index.recordNBA(step, ordered, first)
+	return ordered
+}
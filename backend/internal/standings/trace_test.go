@@ -0,0 +1,79 @@
+package standings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTraceNFLStandings(t *testing.T) {
+	for _, pattern := range SyntheticPatterns() {
+		t.Run(pattern, func(t *testing.T) {
+			teams, games, err := GenerateNFLSeason(pattern, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			traced, decisions := TraceNFLStandings(teams, games)
+			if !reflect.DeepEqual(traced, BuildNFLStandings(teams, games)) {
+				t.Errorf("traced standings differ from untraced standings")
+			}
+			checkTiebreakerDecisions(t, decisions)
+		})
+	}
+}
+
+func TestTraceNBAStandings(t *testing.T) {
+	for _, pattern := range SyntheticPatterns() {
+		t.Run(pattern, func(t *testing.T) {
+			teams, games, err := GenerateNBASeason(pattern, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			traced, decisions := TraceNBAStandings(teams, games)
+			if !reflect.DeepEqual(traced, BuildNBAStandings(teams, games)) {
+				t.Errorf("traced standings differ from untraced standings")
+			}
+			checkTiebreakerDecisions(t, decisions)
+		})
+	}
+}
+
+// Every team finishes tied, so every division, seeding, and draft tie has to be broken
+func TestTraceNFLStandingsAllTies(t *testing.T) {
+	teams, games, err := GenerateNFLSeason(PatternAllTies, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, decisions := TraceNFLStandings(teams, games)
+	contexts := make(map[string]bool)
+	for _, decision := range decisions {
+		contexts[decision.Context] = true
+	}
+	for _, context := range []string{"AFC East", "NFC West", "AFC division winners", "NFC wild cards", "Draft order"} {
+		if !contexts[context] {
+			t.Errorf("no tiebreaker decisions recorded for %s", context)
+		}
+	}
+}
+
+func checkTiebreakerDecisions(t *testing.T, decisions []TiebreakerDecision) {
+	t.Helper()
+	for _, decision := range decisions {
+		if decision.Context == "" || decision.Step == "" {
+			t.Errorf("decision %+v is missing its context or step", decision)
+		}
+		if len(decision.Teams) < 2 {
+			t.Errorf("decision %+v breaks a tie between fewer than 2 teams", decision)
+		}
+
+		found := false
+		for _, team := range decision.Teams {
+			found = found || team == decision.Winner
+		}
+		if !found {
+			t.Errorf("decision %+v has a winner that wasn't tied", decision)
+		}
+	}
+}
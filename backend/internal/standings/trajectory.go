@@ -0,0 +1,177 @@
+// Week-by-week seed trajectories for charting how standings evolve
+
+package standings
+
+import (
+	"fmt"
+	"sort"
+
+	"gamescript/internal/database"
+)
+
+
+// Team's conference seed and record after one week
+type WeeklySeed struct {
+	Week   int
+	Seed   int
+	Wins   int
+	Losses int
+	Ties   int
+}
+
+// Team's seed after every week of the season
+type SeedTrajectory struct {
+	TeamID     int
+	TeamAbbr   string
+	TeamCity   string
+	TeamName   string
+	Conference string
+	Seeds      []WeeklySeed
+}
+
+// Seed trajectories for every team, ordered by conference and then final seed
+type SeedTrajectories struct {
+	Weeks []int
+	Teams []SeedTrajectory
+}
+
+// Calculates each NFL team's seed after every week, counting the scenario's picks
+func CalculateNFLSeedTrajectories(db *database.DB, scenarioID int, seasonID int) (*SeedTrajectories, error) {
+	teams, err := getNFLTeams(db, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting teams: %w", err)
+	}
+	games, _, err := getNFLGameResults(db, scenarioID, seasonID, Cutoff{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting game results: %w", err)
+	}
+	weeks, err := getSeasonWeeks(db, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting weeks: %w", err)
+	}
+
+	trajectories := newTrajectoryBuilder(weeks)
+	for _, week := range weeks {
+		weekStandings := BuildNFLStandings(teams, gamesThroughWeek(games, week, func(game NFLGameResult) int { return game.Week }))
+		for _, conference := range []NFLConferenceStandings{weekStandings.AFC, weekStandings.NFC} {
+			for _, seed := range conference.PlayoffSeeds {
+				team := seed.Team
+				trajectories.add(team.TeamID, team.TeamAbbr, team.TeamCity, team.TeamName, team.Conference,
+					WeeklySeed{Week: week, Seed: seed.Seed, Wins: team.Wins, Losses: team.Losses, Ties: team.Ties})
+			}
+		}
+	}
+
+	return trajectories.build(), nil
+}
+
+// Calculates each NBA team's seed after every week, counting the scenario's picks
+func CalculateNBASeedTrajectories(db *database.DB, scenarioID int, seasonID int) (*SeedTrajectories, error) {
+	teams, err := getNBATeams(db, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting teams: %w", err)
+	}
+	games, _, err := getNBAGameResults(db, scenarioID, seasonID, Cutoff{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting game results: %w", err)
+	}
+	weeks, err := getSeasonWeeks(db, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting weeks: %w", err)
+	}
+
+	trajectories := newTrajectoryBuilder(weeks)
+	for _, week := range weeks {
+		weekStandings := BuildNBAStandings(teams, gamesThroughWeek(games, week, func(game NBAGameResult) int { return game.Week }))
+		for _, conference := range []NBAConferenceStandings{weekStandings.Eastern, weekStandings.Western} {
+			for _, seed := range conference.PlayoffSeeds {
+				team := seed.Team
+				trajectories.add(team.TeamID, team.TeamAbbr, team.TeamCity, team.TeamName, team.Conference,
+					WeeklySeed{Week: week, Seed: seed.Seed, Wins: team.Wins, Losses: team.Losses})
+			}
+		}
+	}
+
+	return trajectories.build(), nil
+}
+
+// Gets every week with games in a season, in order
+func getSeasonWeeks(db *database.DB, seasonID int) ([]int, error) {
+	rows, err := db.Query(`
+		SELECT DISTINCT week
+		FROM games
+		WHERE season_id = $1 AND week IS NOT NULL
+		ORDER BY week
+	`, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var weeks []int
+	for rows.Next() {
+		var week int
+		if err := rows.Scan(&week); err != nil {
+			return nil, err
+		}
+		weeks = append(weeks, week)
+	}
+
+	return weeks, rows.Err()
+}
+
+// Filters game results to those played in a week or earlier
+func gamesThroughWeek[T any](games []T, week int, weekOf func(T) int) []T {
+	var filtered []T
+	for _, game := range games {
+		if weekOf(game) <= week {
+			filtered = append(filtered, game)
+		}
+	}
+	return filtered
+}
+
+type trajectoryBuilder struct {
+	weeks  []int
+	byTeam map[int]*SeedTrajectory
+}
+
+func newTrajectoryBuilder(weeks []int) *trajectoryBuilder {
+	return &trajectoryBuilder{weeks: weeks, byTeam: make(map[int]*SeedTrajectory)}
+}
+
+func (builder *trajectoryBuilder) add(teamID int, abbr string, city string, name string, conference string, seed WeeklySeed) {
+	trajectory, exists := builder.byTeam[teamID]
+	if !exists {
+		trajectory = &SeedTrajectory{TeamID: teamID, TeamAbbr: abbr, TeamCity: city, TeamName: name, Conference: conference}
+		builder.byTeam[teamID] = trajectory
+	}
+	trajectory.Seeds = append(trajectory.Seeds, seed)
+}
+
+func (builder *trajectoryBuilder) build() *SeedTrajectories {
+	teams := make([]SeedTrajectory, 0, len(builder.byTeam))
+	for _, trajectory := range builder.byTeam {
+		teams = append(teams, *trajectory)
+	}
+
+	sort.Slice(teams, func(i, j int) bool {
+		if teams[i].Conference != teams[j].Conference {
+			return teams[i].Conference < teams[j].Conference
+		}
+		return finalSeed(teams[i]) < finalSeed(teams[j])
+	})
+
+	weeks := builder.weeks
+	if weeks == nil {
+		weeks = []int{}
+	}
+	return &SeedTrajectories{Weeks: weeks, Teams: teams}
+}
+
+func finalSeed(trajectory SeedTrajectory) int {
+	if len(trajectory.Seeds) == 0 {
+		return 0
+	}
+	return trajectory.Seeds[len(trajectory.Seeds)-1].Seed
+}
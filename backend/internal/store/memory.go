@@ -0,0 +1,238 @@
+// In-memory store for testing handlers without a database
+
+package store
+
+import (
+	"sort"
+	"sync"
+
+	"gamescript/internal/models"
+)
+
+
+type Memory struct {
+	mu        sync.RWMutex
+	sports    []models.Sport
+	seasons   []models.Season
+	teams     map[int]models.Team
+	games     []models.Game
+	picks     []models.Pick
+	scenarios map[int]models.Scenario
+	members   []memoryMember
+}
+
+var _ Store = (*Memory)(nil)
+
+type memoryMember struct {
+	scenarioID   int
+	userID       int
+	sessionToken string
+	role         string
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		teams:     make(map[int]models.Team),
+		scenarios: make(map[int]models.Scenario),
+	}
+}
+
+func (store *Memory) AddSport(sport models.Sport) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.sports = append(store.sports, sport)
+}
+
+func (store *Memory) AddSeason(season models.Season) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.seasons = append(store.seasons, season)
+}
+
+func (store *Memory) AddTeam(team models.Team) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.teams[team.ID] = team
+}
+
+// Adds a game. Its teams have to be added for it to be returned, like the join in Postgres.
+func (store *Memory) AddGame(game models.Game) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.games = append(store.games, game)
+}
+
+func (store *Memory) AddPick(pick models.Pick) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.picks = append(store.picks, pick)
+}
+
+func (store *Memory) AddScenario(scenario models.Scenario) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.scenarios[scenario.ID] = scenario
+}
+
+// Adds a member by user ID, or by guest session token when userID is 0
+func (store *Memory) AddScenarioMember(scenarioID int, userID int, sessionToken string, role string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.members = append(store.members, memoryMember{scenarioID, userID, sessionToken, role})
+}
+
+func (store *Memory) Sports() ([]models.Sport, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	sports := append([]models.Sport{}, store.sports...)
+	sort.SliceStable(sports, func(i, j int) bool { return sports[i].ID < sports[j].ID })
+	return sports, nil
+}
+
+func (store *Memory) SeasonsBySport(sportID int) ([]models.Season, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	seasons := []models.Season{}
+	for _, season := range store.seasons {
+		if season.SportID == sportID {
+			seasons = append(seasons, season)
+		}
+	}
+	sort.SliceStable(seasons, func(i, j int) bool { return seasons[i].StartYear > seasons[j].StartYear })
+	return seasons, nil
+}
+
+func (store *Memory) Season(seasonID int) (*models.Season, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	for _, season := range store.seasons {
+		if season.ID == seasonID {
+			return &season, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (store *Memory) TeamsBySeason(seasonID int) ([]models.Team, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	teams := []models.Team{}
+	for _, team := range store.teams {
+		if team.SeasonID == seasonID {
+			teams = append(teams, team)
+		}
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		if teams[i].Name != teams[j].Name {
+			return teams[i].Name < teams[j].Name
+		}
+		return teams[i].ID < teams[j].ID
+	})
+	return teams, nil
+}
+
+func (store *Memory) Team(teamID int) (*models.Team, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	team, exists := store.teams[teamID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return &team, nil
+}
+
+// Joins a game to its teams, reporting false when either is missing
+func (store *Memory) withTeams(game models.Game) (models.GameWithTeams, bool) {
+	homeTeam, homeOK := store.teams[game.HomeTeamID]
+	awayTeam, awayOK := store.teams[game.AwayTeamID]
+	return models.GameWithTeams{Game: game, HomeTeam: homeTeam, AwayTeam: awayTeam}, homeOK && awayOK
+}
+
+func (store *Memory) gamesWhere(matches func(models.Game) bool) []models.GameWithTeams {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	games := []models.GameWithTeams{}
+	for _, game := range store.games {
+		if joined, ok := store.withTeams(game); ok && matches(game) {
+			games = append(games, joined)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool { return gameBefore(games[i].Game, games[j].Game) })
+	return games
+}
+
+// Orders games by start time, then ID, like the Postgres queries
+func gameBefore(a models.Game, b models.Game) bool {
+	if !a.StartTime.Equal(b.StartTime) {
+		return a.StartTime.Before(b.StartTime)
+	}
+	return a.ID < b.ID
+}
+
+func (store *Memory) GamesBySeason(seasonID int) ([]models.GameWithTeams, error) {
+	return store.gamesWhere(func(game models.Game) bool {
+		return game.SeasonID == seasonID
+	}), nil
+}
+
+func (store *Memory) GamesByWeek(seasonID int, week int) ([]models.GameWithTeams, error) {
+	return store.gamesWhere(func(game models.Game) bool {
+		return game.SeasonID == seasonID && game.Week != nil && *game.Week == week
+	}), nil
+}
+
+func (store *Memory) GamesByTeam(teamID int) ([]models.GameWithTeams, error) {
+	return store.gamesWhere(func(game models.Game) bool {
+		return game.HomeTeamID == teamID || game.AwayTeamID == teamID
+	}), nil
+}
+
+func (store *Memory) Game(gameID int) (*models.GameWithTeams, error) {
+	games := store.gamesWhere(func(game models.Game) bool {
+		return game.ID == gameID
+	})
+	if len(games) == 0 {
+		return nil, ErrNotFound
+	}
+	return &games[0], nil
+}
+
+func (store *Memory) PicksForScenario(scenarioID int) ([]models.PickWithGame, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	picks := []models.PickWithGame{}
+	for _, pick := range store.picks {
+		if pick.ScenarioID != scenarioID {
+			continue
+		}
+		for _, game := range store.games {
+			if joined, ok := store.withTeams(game); ok && game.ID == pick.GameID {
+				picks = append(picks, models.PickWithGame{Pick: pick, Game: joined})
+			}
+		}
+	}
+	sort.Slice(picks, func(i, j int) bool { return gameBefore(picks[i].Game.Game, picks[j].Game.Game) })
+	return picks, nil
+}
+
+func (store *Memory) ScenarioOwner(scenarioID int, userID int, sessionToken string) (*models.ScenarioOwner, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	scenario, exists := store.scenarios[scenarioID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	owner := &models.ScenarioOwner{Scenario: scenario}
+	for _, member := range store.members {
+		if member.scenarioID != scenarioID {
+			continue
+		}
+		if (userID > 0 && member.userID == userID) || (sessionToken != "" && member.sessionToken == sessionToken) {
+			role := member.role
+			owner.MemberRole = &role
+			break
+		}
+	}
+	return owner, nil
+}
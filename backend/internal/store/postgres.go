@@ -0,0 +1,279 @@
+// Store backed by the application database
+
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"gamescript/internal/database"
+	"gamescript/internal/models"
+)
+
+
+type Postgres struct {
+	db *database.DB
+}
+
+var _ Store = (*Postgres)(nil)
+
+func NewPostgres(db *database.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+// Anything rows.Scan or row.Scan can read from
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+const teamColumns = `
+	%[1]s.id, %[1]s.sport_id, %[1]s.season_id, COALESCE(%[1]s.espn_id, ''),
+	%[1]s.abbreviation, %[1]s.city, %[1]s.name, %[1]s.conference, %[1]s.division,
+	COALESCE(%[1]s.primary_color, ''), COALESCE(%[1]s.secondary_color, ''), %[1]s.logo_url, %[1]s.alternate_logo_url,
+	%[1]s.created_at`
+
+// Columns for a game joined to its teams as home_team and away_team
+var gameColumns = `
+	game.id, game.season_id, COALESCE(game.espn_id, ''), game.home_team_id, game.away_team_id,
+	game.start_time, game.day_of_week, game.week, game.location, game.primetime, game.network,
+	game.home_score, game.away_score, game.status, game.created_at,` +
+	fmt.Sprintf(teamColumns, "home_team") + `,` +
+	fmt.Sprintf(teamColumns, "away_team")
+
+const gameJoins = `
+	FROM games game
+	JOIN teams home_team ON game.home_team_id = home_team.id
+	JOIN teams away_team ON game.away_team_id = away_team.id`
+
+func scanTeam(row scanner) (models.Team, error) {
+	var team models.Team
+	err := row.Scan(teamFields(&team)...)
+	return team, err
+}
+
+func teamFields(team *models.Team) []interface{} {
+	return []interface{}{
+		&team.ID, &team.SportID, &team.SeasonID, &team.ESPNID,
+		&team.Abbreviation, &team.City, &team.Name, &team.Conference, &team.Division,
+		&team.PrimaryColor, &team.SecondaryColor, &team.LogoURL, &team.AlternateLogoURL,
+		&team.CreatedAt,
+	}
+}
+
+func gameFields(game *models.GameWithTeams) []interface{} {
+	fields := []interface{}{
+		&game.ID, &game.SeasonID, &game.ESPNID, &game.HomeTeamID, &game.AwayTeamID,
+		&game.StartTime, &game.DayOfWeek, &game.Week, &game.Location, &game.Primetime, &game.Network,
+		&game.HomeScore, &game.AwayScore, &game.Status, &game.CreatedAt,
+	}
+	fields = append(fields, teamFields(&game.HomeTeam)...)
+	return append(fields, teamFields(&game.AwayTeam)...)
+}
+
+func scanGame(row scanner) (models.GameWithTeams, error) {
+	var game models.GameWithTeams
+	err := row.Scan(gameFields(&game)...)
+	return game, err
+}
+
+func (store *Postgres) Sports() ([]models.Sport, error) {
+	rows, err := store.db.Query("SELECT id, name, short_name, created_at FROM sports ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("error getting sports: %w", err)
+	}
+	defer rows.Close()
+
+	sports := []models.Sport{}
+	for rows.Next() {
+		var sport models.Sport
+		if err := rows.Scan(&sport.ID, &sport.Name, &sport.ShortName, &sport.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning sport: %w", err)
+		}
+		sports = append(sports, sport)
+	}
+	return sports, rows.Err()
+}
+
+const seasonColumns = "id, sport_id, start_year, end_year, COALESCE(is_active, FALSE), created_at"
+
+func scanSeason(row scanner) (models.Season, error) {
+	var season models.Season
+	err := row.Scan(&season.ID, &season.SportID, &season.StartYear, &season.EndYear, &season.IsActive, &season.CreatedAt)
+	return season, err
+}
+
+func (store *Postgres) SeasonsBySport(sportID int) ([]models.Season, error) {
+	rows, err := store.db.Query(`
+		SELECT `+seasonColumns+`
+		FROM seasons
+		WHERE sport_id = $1
+		ORDER BY start_year DESC
+	`, sportID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting seasons: %w", err)
+	}
+	defer rows.Close()
+
+	seasons := []models.Season{}
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning season: %w", err)
+		}
+		seasons = append(seasons, season)
+	}
+	return seasons, rows.Err()
+}
+
+func (store *Postgres) Season(seasonID int) (*models.Season, error) {
+	season, err := scanSeason(store.db.Conn.QueryRow("SELECT "+seasonColumns+" FROM seasons WHERE id = $1", seasonID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting season: %w", err)
+	}
+	return &season, nil
+}
+
+func (store *Postgres) TeamsBySeason(seasonID int) ([]models.Team, error) {
+	rows, err := store.db.Query(`
+		SELECT `+fmt.Sprintf(teamColumns, "team")+`
+		FROM teams team
+		WHERE team.season_id = $1
+		ORDER BY team.name
+	`, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting teams: %w", err)
+	}
+	defer rows.Close()
+
+	teams := []models.Team{}
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+	return teams, rows.Err()
+}
+
+func (store *Postgres) Team(teamID int) (*models.Team, error) {
+	team, err := scanTeam(store.db.Conn.QueryRow(`
+		SELECT `+fmt.Sprintf(teamColumns, "team")+`
+		FROM teams team
+		WHERE team.id = $1
+	`, teamID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting team: %w", err)
+	}
+	return &team, nil
+}
+
+func (store *Postgres) games(condition string, args ...interface{}) ([]models.GameWithTeams, error) {
+	rows, err := store.db.Query(`
+		SELECT `+gameColumns+gameJoins+`
+		WHERE `+condition+`
+		ORDER BY game.start_time, game.id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting games: %w", err)
+	}
+	defer rows.Close()
+
+	games := []models.GameWithTeams{}
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning game: %w", err)
+		}
+		games = append(games, game)
+	}
+	return games, rows.Err()
+}
+
+func (store *Postgres) GamesBySeason(seasonID int) ([]models.GameWithTeams, error) {
+	return store.games("game.season_id = $1", seasonID)
+}
+
+func (store *Postgres) GamesByWeek(seasonID int, week int) ([]models.GameWithTeams, error) {
+	return store.games("game.season_id = $1 AND game.week = $2", seasonID, week)
+}
+
+func (store *Postgres) GamesByTeam(teamID int) ([]models.GameWithTeams, error) {
+	return store.games("game.home_team_id = $1 OR game.away_team_id = $1", teamID)
+}
+
+func (store *Postgres) Game(gameID int) (*models.GameWithTeams, error) {
+	game, err := scanGame(store.db.Conn.QueryRow("SELECT "+gameColumns+gameJoins+" WHERE game.id = $1", gameID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting game: %w", err)
+	}
+	return &game, nil
+}
+
+func (store *Postgres) PicksForScenario(scenarioID int) ([]models.PickWithGame, error) {
+	rows, err := store.db.Query(`
+		SELECT
+			pick.id, pick.scenario_id, pick.game_id, pick.picked_team_id,
+			pick.predicted_home_score, pick.predicted_away_score,
+			pick.status, pick.is_override, pick.created_at, pick.updated_at,`+gameColumns+`
+		FROM picks pick
+		JOIN games game ON pick.game_id = game.id
+		JOIN teams home_team ON game.home_team_id = home_team.id
+		JOIN teams away_team ON game.away_team_id = away_team.id
+		WHERE pick.scenario_id = $1
+		ORDER BY game.start_time, game.id
+	`, scenarioID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting picks: %w", err)
+	}
+	defer rows.Close()
+
+	picks := []models.PickWithGame{}
+	for rows.Next() {
+		var pick models.PickWithGame
+		fields := []interface{}{
+			&pick.ID, &pick.ScenarioID, &pick.GameID, &pick.PickedTeamID,
+			&pick.PredictedHomeScore, &pick.PredictedAwayScore,
+			&pick.Status, &pick.IsOverride, &pick.CreatedAt, &pick.UpdatedAt,
+		}
+		if err := rows.Scan(append(fields, gameFields(&pick.Game)...)...); err != nil {
+			return nil, fmt.Errorf("error scanning pick: %w", err)
+		}
+		picks = append(picks, pick)
+	}
+	return picks, rows.Err()
+}
+
+func (store *Postgres) ScenarioOwner(scenarioID int, userID int, sessionToken string) (*models.ScenarioOwner, error) {
+	var scenario models.ScenarioOwner
+	err := store.db.Conn.QueryRow(`
+		SELECT
+			scenario.id, scenario.user_id, scenario.session_token, scenario.name, scenario.sport_id, scenario.season_id,
+			COALESCE(scenario.is_public, FALSE), scenario.mode, scenario.result_mode, scenario.created_at, scenario.updated_at,
+			member.role
+		FROM scenarios scenario
+		LEFT JOIN scenario_members member ON member.scenario_id = scenario.id
+			AND ((member.user_id = $2 AND $2 > 0) OR (member.session_token = $3 AND $3 <> ''))
+		WHERE scenario.id = $1
+	`, scenarioID, userID, sessionToken).Scan(
+		&scenario.ID, &scenario.UserID, &scenario.SessionToken, &scenario.Name, &scenario.SportID, &scenario.SeasonID,
+		&scenario.IsPublic, &scenario.Mode, &scenario.ResultMode, &scenario.CreatedAt, &scenario.UpdatedAt,
+		&scenario.MemberRole,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting scenario: %w", err)
+	}
+	return &scenario, nil
+}
@@ -0,0 +1,38 @@
+// Typed data access for handlers, backed by Postgres or by an in-memory fake in tests
+
+package store
+
+import (
+	"errors"
+
+	"gamescript/internal/models"
+)
+
+
+// Returned when a lookup by ID doesn't match a row
+var ErrNotFound = errors.New("not found")
+
+// Reads handlers need, returning models. List methods return an empty result rather than ErrNotFound.
+type Store interface {
+	Sports() ([]models.Sport, error)
+	// Newest season first
+	SeasonsBySport(sportID int) ([]models.Season, error)
+	Season(seasonID int) (*models.Season, error)
+
+	// Ordered by team name
+	TeamsBySeason(seasonID int) ([]models.Team, error)
+	Team(teamID int) (*models.Team, error)
+
+	// Game lists are ordered by start time
+	GamesBySeason(seasonID int) ([]models.GameWithTeams, error)
+	GamesByWeek(seasonID int, week int) ([]models.GameWithTeams, error)
+	GamesByTeam(teamID int) ([]models.GameWithTeams, error)
+	Game(gameID int) (*models.GameWithTeams, error)
+
+	// Ordered by game start time
+	PicksForScenario(scenarioID int) ([]models.PickWithGame, error)
+
+	// Gets a scenario with its owner columns and the member role of the given user, or of the given guest
+	// session when userID is 0
+	ScenarioOwner(scenarioID int, userID int, sessionToken string) (*models.ScenarioOwner, error)
+}
@@ -0,0 +1,113 @@
+// Flat tables written as CSV, TSV, or XLSX for spreadsheet exports
+
+package tabular
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatTSV  = "tsv"
+	FormatXLSX = "xlsx"
+)
+
+// Named table with a header row; cells are strings, ints, float64s, bools, or nil
+type Table struct {
+	Name   string
+	Header []string
+	Rows   [][]interface{}
+}
+
+// Returns the MIME type for a format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatTSV:
+		return "text/tab-separated-values; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return ""
+}
+
+// Writes tables in the given format
+func Write(w io.Writer, format string, tables []Table) error {
+	switch format {
+	case FormatCSV:
+		return WriteDelimited(w, ',', tables)
+	case FormatTSV:
+		return WriteDelimited(w, '\t', tables)
+	case FormatXLSX:
+		return WriteXLSX(w, tables)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+// Writes tables as delimited text, with the table name above each table when there is more than one
+func WriteDelimited(w io.Writer, delimiter rune, tables []Table) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+
+	for i, table := range tables {
+		if len(tables) > 1 {
+			if i > 0 {
+				if err := writer.Write([]string{}); err != nil {
+					return err
+				}
+			}
+			if err := writer.Write([]string{table.Name}); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Write(table.Header); err != nil {
+			return err
+		}
+		for _, row := range table.Rows {
+			record := make([]string, len(row))
+			for j, value := range row {
+				record[j] = FormatValue(value)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Formats a cell value as text, dereferencing pointers and leaving nil values empty
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case int:
+		return strconv.Itoa(v)
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
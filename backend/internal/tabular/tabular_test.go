@@ -0,0 +1,112 @@
+package tabular
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func sampleTables() []Table {
+	seven := 7
+	return []Table{
+		{
+			Name:   "Playoff Seeds",
+			Header: []string{"seed", "team_abbr", "win_pct"},
+			Rows: [][]interface{}{
+				{1, "KC", 0.824},
+				{&seven, "MIA, FL", nil},
+			},
+		},
+		{
+			Name:   "Draft Order",
+			Header: []string{"pick", "team_abbr"},
+			Rows:   [][]interface{}{{1, "TEN"}},
+		},
+	}
+}
+
+func TestWriteDelimited(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteDelimited(&buffer, ',', sampleTables()); err != nil {
+		t.Fatalf("WriteDelimited returned error: %v", err)
+	}
+
+	expected := "Playoff Seeds\nseed,team_abbr,win_pct\n1,KC,0.824\n7,\"MIA, FL\",\n\nDraft Order\npick,team_abbr\n1,TEN\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteDelimited output = %q; want %q", buffer.String(), expected)
+	}
+}
+
+func TestWriteDelimitedSingleTable(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteDelimited(&buffer, '\t', sampleTables()[1:]); err != nil {
+		t.Fatalf("WriteDelimited returned error: %v", err)
+	}
+
+	// Single table has no name row
+	expected := "pick\tteam_abbr\n1\tTEN\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteDelimited output = %q; want %q", buffer.String(), expected)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteXLSX(&buffer, sampleTables()); err != nil {
+		t.Fatalf("WriteXLSX returned error: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("WriteXLSX output is not a zip archive: %v", err)
+	}
+
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", file.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[file.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, exists := files[name]; !exists {
+			t.Errorf("Workbook is missing %s", name)
+		}
+	}
+
+	if !strings.Contains(files["xl/workbook.xml"], `name="Playoff Seeds"`) || !strings.Contains(files["xl/workbook.xml"], `name="Draft Order"`) {
+		t.Errorf("Workbook sheets not named after tables: %s", files["xl/workbook.xml"])
+	}
+	if !strings.Contains(files["xl/worksheets/sheet1.xml"], `<c r="C2"><v>0.824</v></c>`) {
+		t.Errorf("Numeric cell not written as a number: %s", files["xl/worksheets/sheet1.xml"])
+	}
+	if !strings.Contains(files["xl/worksheets/sheet1.xml"], `<t>MIA, FL</t>`) {
+		t.Errorf("String cell not written inline: %s", files["xl/worksheets/sheet1.xml"])
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		index    int
+		expected string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if result := columnName(tt.index); result != tt.expected {
+			t.Errorf("columnName(%d) = %s; want %s", tt.index, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+// Throwaway Postgres databases for tests, migrated and loaded with the teams and games in database/
+
+package testdb
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+
+	"gamescript/internal/database"
+	"gamescript/internal/importer"
+	"gamescript/internal/migrations"
+	"gamescript/internal/standings"
+)
+
+
+// Season loaded from the data files in database/
+const FixtureYear = 2025
+
+// Points tests at an existing server instead of starting an embedded one. The user needs permission to
+// create databases, and every database made for a test is dropped afterwards.
+const serverURLVariable = "TEST_DATABASE_URL"
+
+var (
+	setupOnce   sync.Once
+	setupErr    error
+	embedded    *embeddedpostgres.EmbeddedPostgres
+	runtimeDir  string
+	serverURL   *url.URL
+	template    string
+	mu          sync.Mutex
+	databaseSeq int
+)
+
+// Runs a package's tests and shuts down the server they used. Call it from TestMain.
+func Run(m *testing.M) int {
+	code := m.Run()
+	if err := teardown(); err != nil {
+		fmt.Fprintln(os.Stderr, "testdb:", err)
+	}
+	return code
+}
+
+// Creates a database for a test from a template with every migration applied and the fixture NFL and NBA
+// seasons loaded. It's dropped when the test ends. Without TEST_DATABASE_URL, an embedded server is started
+// on first use, and tests are skipped if it can't be.
+func New(t testing.TB) *database.DB {
+	t.Helper()
+	setupOnce.Do(func() {
+		setupErr = setup()
+	})
+	if setupErr != nil {
+		if os.Getenv(serverURLVariable) != "" {
+			t.Fatal("testdb:", setupErr)
+		}
+		t.Skip("testdb: no Postgres available:", setupErr)
+	}
+
+	mu.Lock()
+	databaseSeq++
+	name := fmt.Sprintf("gamescript_test_%d_%d", os.Getpid(), databaseSeq)
+	mu.Unlock()
+
+	if err := adminExec(fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", name, template)); err != nil {
+		t.Fatal("testdb:", err)
+	}
+	db, err := open(name)
+	if err != nil {
+		t.Fatal("testdb:", err)
+	}
+
+	// Cached standings are keyed by scenario ID, which every new database starts over
+	standings.SetCacheEnabled(true)
+
+	t.Cleanup(func() {
+		db.Close()
+		if err := adminExec(fmt.Sprintf("DROP DATABASE IF EXISTS %s WITH (FORCE)", name)); err != nil {
+			t.Error("testdb:", err)
+		}
+	})
+	return db
+}
+
+// Gets the sport and season IDs of a fixture season, by sport short name like "NFL"
+func Season(t testing.TB, db *database.DB, sport string) (int, int) {
+	t.Helper()
+	var sportID, seasonID int
+	err := db.Conn.QueryRow(`
+		SELECT sport.id, season.id
+		FROM sports sport
+		JOIN seasons season ON season.sport_id = sport.id
+		WHERE sport.short_name = $1 AND season.start_year = $2
+	`, sport, FixtureYear).Scan(&sportID, &seasonID)
+	if err != nil {
+		t.Fatalf("testdb: no %s %d season: %v", sport, FixtureYear, err)
+	}
+	return sportID, seasonID
+}
+
+func setup() error {
+	if rawURL := os.Getenv(serverURLVariable); rawURL != "" {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", serverURLVariable, err)
+		}
+		serverURL = parsed
+	} else if err := startEmbedded(); err != nil {
+		return err
+	}
+
+	template = fmt.Sprintf("gamescript_template_%d", os.Getpid())
+	if err := adminExec("DROP DATABASE IF EXISTS " + template); err != nil {
+		return err
+	}
+	if err := adminExec("CREATE DATABASE " + template); err != nil {
+		return err
+	}
+
+	db, err := open(template)
+	if err != nil {
+		return err
+	}
+	// A template can't be copied while anything is connected to it
+	defer db.Close()
+
+	if _, err := migrations.Up(db); err != nil {
+		return fmt.Errorf("failed to migrate template: %w", err)
+	}
+	return loadFixtures(db)
+}
+
+func startEmbedded() error {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return err
+	}
+	port := uint32(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
+
+	runtimeDir, err = os.MkdirTemp("", "gamescript-testdb-")
+	if err != nil {
+		return err
+	}
+
+	config := embeddedpostgres.DefaultConfig().
+		Version(embeddedpostgres.V16).
+		Port(port).
+		RuntimePath(runtimeDir).
+		Logger(io.Discard)
+	embedded = embeddedpostgres.NewDatabase(config)
+	if err := embedded.Start(); err != nil {
+		embedded = nil
+		os.RemoveAll(runtimeDir)
+		return err
+	}
+
+	serverURL, err = url.Parse(config.GetConnectionURL())
+	if err != nil {
+		return err
+	}
+	query := serverURL.Query()
+	query.Set("sslmode", "disable")
+	serverURL.RawQuery = query.Encode()
+	return nil
+}
+
+func teardown() error {
+	if serverURL != nil && template != "" {
+		if err := adminExec("DROP DATABASE IF EXISTS " + template); err != nil {
+			return err
+		}
+	}
+	if embedded != nil {
+		defer os.RemoveAll(runtimeDir)
+		return embedded.Stop()
+	}
+	return nil
+}
+
+func open(name string) (*database.DB, error) {
+	databaseURL := *serverURL
+	databaseURL.Path = "/" + name
+	conn, err := sql.Open("postgres", databaseURL.String())
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to %s: %w", name, err)
+	}
+	return &database.DB{Conn: conn}, nil
+}
+
+// Runs a statement against the server's postgres database, for creating and dropping test databases
+func adminExec(statement string) error {
+	db, err := open("postgres")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if _, err := db.Conn.Exec(statement); err != nil {
+		return fmt.Errorf("%s: %w", statement, err)
+	}
+	return nil
+}
+
+// Imports the fixture season's teams and schedule for each sport with data files
+func loadFixtures(db *database.DB) error {
+	dataDir, err := findDataDir()
+	if err != nil {
+		return err
+	}
+
+	for _, sport := range []string{importer.SportNFL, importer.SportNBA} {
+		var sportID, seasonID int
+		err := db.Conn.QueryRow(`
+			SELECT sport.id, season.id
+			FROM sports sport
+			JOIN seasons season ON season.sport_id = sport.id
+			WHERE LOWER(sport.short_name) = $1 AND season.start_year = $2
+		`, sport, FixtureYear).Scan(&sportID, &seasonID)
+		if err != nil {
+			return fmt.Errorf("no %s %d season to load fixtures into: %w", sport, FixtureYear, err)
+		}
+
+		teams, err := importer.ReadTeams(importer.TeamsFile(dataDir, sport, FixtureYear))
+		if err != nil {
+			return err
+		}
+		games, err := importer.ReadGames(importer.ScheduleFile(dataDir, sport, FixtureYear, 0))
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.Conn.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := importer.UpsertTeams(tx, sportID, seasonID, teams); err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, game := range games {
+			if _, err := importer.UpsertGame(tx, seasonID, game); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to load %s game %s: %w", sport, game.ESPNID, err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Finds the database/ data directory next to go.mod, from whichever package the tests run in
+func findDataDir() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return filepath.Join(dir, "database"), nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.mod above the working directory")
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,141 @@
+// Typed path and query parameters
+
+package validation
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+
+// Largest ID a parameter can hold; IDs are Postgres serials
+const maxID = 1<<31 - 1
+
+// Reads path and query parameters into typed values, recording an error for each invalid one.
+// Invalid parameters read as zero values, so check Err before using them.
+type Params struct {
+	Errors
+	c *fiber.Ctx
+}
+
+func NewParams(c *fiber.Ctx) *Params {
+	return &Params{c: c}
+}
+
+// Parses a single ID path parameter
+func PathID(c *fiber.Ctx, name string) (int, error) {
+	params := NewParams(c)
+	id := params.ID(name)
+	return id, params.Err()
+}
+
+// Positive integer ID path parameter
+func (p *Params) ID(name string) int {
+	id, ok := parseInt(p.c.Params(name), 1, maxID)
+	if !ok {
+		p.Add(InPath, name, "Invalid "+label(name))
+	}
+	return id
+}
+
+// Integer path parameter within [min, max]
+func (p *Params) Int(name string, min, max int) int {
+	value, ok := parseInt(p.c.Params(name), min, max)
+	if !ok {
+		p.Addf(InPath, name, "Invalid %s, must be between %d and %d", label(name), min, max)
+	}
+	return value
+}
+
+// Opaque path parameter such as an invite token: letters, digits, '-' and '_', up to 128 characters
+func (p *Params) Token(name string) string {
+	value := p.c.Params(name)
+	if value == "" || len(value) > 128 || strings.IndexFunc(value, notTokenRune) >= 0 {
+		p.Add(InPath, name, "Invalid "+label(name))
+		return ""
+	}
+	return value
+}
+
+// Required positive integer ID query parameter
+func (p *Params) QueryID(name string) int {
+	id, ok := parseInt(p.c.Query(name), 1, maxID)
+	if !ok {
+		p.Add(InQuery, name, "Invalid "+label(name))
+	}
+	return id
+}
+
+// Optional positive integer ID query parameter; nil when absent
+func (p *Params) OptionalQueryID(name string) *int {
+	if p.c.Query(name) == "" {
+		return nil
+	}
+	id := p.QueryID(name)
+	return &id
+}
+
+// Optional integer query parameter within [min, max]; nil when absent
+func (p *Params) QueryInt(name string, min, max int) *int {
+	raw := p.c.Query(name)
+	if raw == "" {
+		return nil
+	}
+	value, ok := parseInt(raw, min, max)
+	if !ok {
+		p.Addf(InQuery, name, "Invalid %s, must be between %d and %d", name, min, max)
+		return nil
+	}
+	return &value
+}
+
+// Optional YYYY-MM-DD query parameter; nil when absent
+func (p *Params) QueryDate(name string) *time.Time {
+	raw := p.c.Query(name)
+	if raw == "" {
+		return nil
+	}
+	date, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		p.Addf(InQuery, name, "Invalid %s, expected YYYY-MM-DD", name)
+		return nil
+	}
+	return &date
+}
+
+// Optional RFC 3339 timestamp query parameter; nil when absent
+func (p *Params) QueryTime(name string) *time.Time {
+	raw := p.c.Query(name)
+	if raw == "" {
+		return nil
+	}
+	value, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		p.Addf(InQuery, name, "Invalid %s, expected an RFC 3339 timestamp", name)
+		return nil
+	}
+	return &value
+}
+
+func parseInt(raw string, min, max int) (int, bool) {
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || value > max {
+		return 0, false
+	}
+	return value, true
+}
+
+func notTokenRune(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+}
+
+// Parameter name as it reads in a message: scenario_id becomes "scenario ID", others stay as sent
+func label(name string) string {
+	if base, ok := strings.CutSuffix(name, "_id"); ok {
+		return strings.ReplaceAll(base, "_", " ") + " ID"
+	}
+	return name
+}
@@ -0,0 +1,99 @@
+// Per-sport predicted score ranges and pick consistency checks
+
+package validation
+
+
+// Inclusive range a predicted score must fall in
+type ScoreRange struct {
+	Min int
+	Max int
+}
+
+// Keyed by sport ID. The upper bounds sit well above any real score, so only typos and junk are rejected.
+var scoreRanges = map[int]ScoreRange{
+	1: {Min: 0, Max: 99},  // NFL
+	2: {Min: 0, Max: 250}, // NBA
+}
+
+// Used for sports without their own range
+var defaultScoreRange = ScoreRange{Min: 0, Max: 999}
+
+func ScoreRangeFor(sportID int) ScoreRange {
+	if scoreRange, ok := scoreRanges[sportID]; ok {
+		return scoreRange
+	}
+	return defaultScoreRange
+}
+
+// Whether a sport's games can end in a tie; only NFL games can
+func AllowsTies(sportID int) bool {
+	return sportID == 1
+}
+
+// Records an error when a predicted score is outside the sport's range; nil scores are skipped
+func (e *Errors) Score(field string, sportID int, score *int) {
+	if score == nil {
+		return
+	}
+	scoreRange := ScoreRangeFor(sportID)
+	if *score < scoreRange.Min || *score > scoreRange.Max {
+		e.Addf(InBody, field, "Predicted scores must be between %d and %d", scoreRange.Min, scoreRange.Max)
+	}
+}
+
+// Checks a predicted pick against its game and returns the picked team. When both scores are given they decide
+// the winner, otherwise the picked team must be playing in the game, with 0 picking a tie. prefix is prepended
+// to field names for array items, e.g. "upserts[2].".
+func (e *Errors) Pick(prefix string, sportID int, homeTeamID int, awayTeamID int, pickedTeamID *int, homeScore *int, awayScore *int) *int {
+	fields := pickFields{team: prefix + "picked_team_id", first: prefix + "predicted_home_score", second: prefix + "predicted_away_score"}
+	return e.checkPick(fields, sportID, AllowsTies(sportID), homeTeamID, awayTeamID, pickedTeamID, homeScore, awayScore)
+}
+
+// Checks a pick on a single-game playoff matchup, which can't end in a tie, and returns the picked team
+func (e *Errors) PlayoffPick(sportID int, higherSeedTeamID int, lowerSeedTeamID int, pickedTeamID *int, higherSeedScore *int, lowerSeedScore *int) *int {
+	fields := pickFields{team: "picked_team_id", first: "predicted_higher_seed_score", second: "predicted_lower_seed_score"}
+	return e.checkPick(fields, sportID, false, higherSeedTeamID, lowerSeedTeamID, pickedTeamID, higherSeedScore, lowerSeedScore)
+}
+
+type pickFields struct {
+	team   string
+	first  string
+	second string
+}
+
+func (e *Errors) checkPick(fields pickFields, sportID int, allowTies bool, firstTeamID int, secondTeamID int, pickedTeamID *int, firstScore *int, secondScore *int) *int {
+	e.Score(fields.first, sportID, firstScore)
+	e.Score(fields.second, sportID, secondScore)
+
+	if firstScore != nil && secondScore != nil {
+		if e.Has(fields.first) || e.Has(fields.second) {
+			return pickedTeamID
+		}
+		winner := 0
+		if *firstScore > *secondScore {
+			winner = firstTeamID
+		} else if *secondScore > *firstScore {
+			winner = secondTeamID
+		}
+		if winner == 0 && !allowTies {
+			e.Add(InBody, fields.second, tieMessage(sportID))
+		}
+		return &winner
+	}
+
+	if pickedTeamID != nil {
+		if *pickedTeamID != firstTeamID && *pickedTeamID != secondTeamID && *pickedTeamID != 0 {
+			e.Add(InBody, fields.team, "Picked team is not playing in this game")
+		} else if *pickedTeamID == 0 && !allowTies {
+			e.Add(InBody, fields.team, tieMessage(sportID))
+		}
+	}
+	return pickedTeamID
+}
+
+func tieMessage(sportID int) string {
+	if AllowsTies(sportID) {
+		return "Playoff games can't end in a tie"
+	}
+	return "Ties are only allowed for NFL games"
+}
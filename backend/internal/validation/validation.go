@@ -0,0 +1,93 @@
+// Request validation: field-level error lists and the problems they're sent as
+
+package validation
+
+import (
+	"fmt"
+
+	"gamescript/internal/apperror"
+)
+
+
+// Where an invalid field was sent
+const (
+	InPath  = "path"
+	InQuery = "query"
+	InBody  = "body"
+)
+
+// One invalid field. Field is the parameter or JSON name, with an index for array items, e.g. upserts[2].home_score.
+type FieldError struct {
+	Field   string `json:"field"`
+	In      string `json:"in"`
+	Message string `json:"message"`
+}
+
+// Problem body for INVALID_PARAMETER and VALIDATION_FAILED errors, listing every invalid field
+type Response struct {
+	apperror.Problem
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// Collects field errors so one response reports every invalid field instead of only the first
+type Errors struct {
+	fields []FieldError
+}
+
+func (e *Errors) Add(in, field, message string) {
+	e.fields = append(e.fields, FieldError{Field: field, In: in, Message: message})
+}
+
+func (e *Errors) Addf(in, field, format string, args ...interface{}) {
+	e.Add(in, field, fmt.Sprintf(format, args...))
+}
+
+// Whether a field already has an error, so checks that depend on it can be skipped
+func (e *Errors) Has(field string) bool {
+	for _, fieldErr := range e.fields {
+		if fieldErr.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Errors) Empty() bool {
+	return len(e.fields) == 0
+}
+
+func (e *Errors) List() []FieldError {
+	return e.fields
+}
+
+// Nil when every field was valid. Errors only in the path or query are INVALID_PARAMETER, any in the
+// body make it VALIDATION_FAILED; the detail is the first message and the full list goes in errors.
+func (e *Errors) Err() error {
+	if e.Empty() {
+		return nil
+	}
+	return e.ErrWithDetail(e.fields[0].Message)
+}
+
+// Same as Err, with a summary detail instead of the first message
+func (e *Errors) ErrWithDetail(detail string) error {
+	if e.Empty() {
+		return nil
+	}
+
+	code := apperror.InvalidParameter
+	for _, fieldErr := range e.fields {
+		if fieldErr.In == InBody {
+			code = apperror.ValidationFailed
+			break
+		}
+	}
+	return apperror.New(code, detail).With(Response{Errors: e.fields})
+}
+
+// Error for a single invalid body field
+func Field(field, message string) error {
+	var errs Errors
+	errs.Add(InBody, field, message)
+	return errs.Err()
+}
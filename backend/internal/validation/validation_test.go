@@ -0,0 +1,259 @@
+package validation
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gamescript/internal/apperror"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func messages(errs *Errors) map[string]string {
+	byField := map[string]string{}
+	for _, fieldErr := range errs.List() {
+		byField[fieldErr.Field] = fieldErr.Message
+	}
+	return byField
+}
+
+func TestErrorsCode(t *testing.T) {
+	var none Errors
+	if err := none.Err(); err != nil {
+		t.Errorf("Err() with no errors = %v; want nil", err)
+	}
+
+	var params Errors
+	params.Add(InPath, "game_id", "Invalid game ID")
+	params.Add(InQuery, "week", "Invalid week")
+	appErr, ok := apperror.As(params.Err())
+	if !ok || appErr.Code != apperror.InvalidParameter || appErr.Detail != "Invalid game ID" {
+		t.Errorf("Path and query errors = %v; want INVALID_PARAMETER with the first message", params.Err())
+	}
+
+	params.Add(InBody, "predicted_home_score", "Predicted scores must be between 0 and 99")
+	appErr, _ = apperror.As(params.ErrWithDetail("Invalid pick"))
+	if appErr.Code != apperror.ValidationFailed || appErr.Detail != "Invalid pick" {
+		t.Errorf("Body errors = %s %q; want VALIDATION_FAILED \"Invalid pick\"", appErr.Code, appErr.Detail)
+	}
+	response, ok := appErr.Extensions.(Response)
+	if !ok || len(response.Errors) != 3 {
+		t.Errorf("Extensions = %#v; want all 3 field errors", appErr.Extensions)
+	}
+}
+
+func TestParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		read     func(p *Params)
+		expected map[string]string
+	}{
+		{"valid", "/scenarios/4/weeks/9?season_id=2&as_of=2024-11-03", func(p *Params) {
+			p.ID("scenario_id")
+			p.Int("week", 1, 53)
+			p.QueryID("season_id")
+			p.QueryDate("as_of")
+		}, map[string]string{}},
+		{"bad IDs", "/scenarios/-1/weeks/x", func(p *Params) {
+			p.ID("scenario_id")
+			p.Int("week", 1, 53)
+		}, map[string]string{"scenario_id": "Invalid scenario ID", "week": "Invalid week, must be between 1 and 53"}},
+		{"ID overflow", "/scenarios/99999999999/weeks/1", func(p *Params) {
+			p.ID("scenario_id")
+		}, map[string]string{"scenario_id": "Invalid scenario ID"}},
+		{"optional query absent", "/scenarios/1/weeks/1", func(p *Params) {
+			if p.OptionalQueryID("season_id") != nil || p.QueryInt("limit", 1, 10) != nil || p.QueryTime("since") != nil {
+				t.Error("Absent query parameters should read as nil")
+			}
+		}, map[string]string{}},
+		{"bad query", "/scenarios/1/weeks/1?limit=0&as_of=11/03/2024&since=yesterday&season_id=abc", func(p *Params) {
+			p.QueryInt("limit", 1, 10)
+			p.QueryDate("as_of")
+			p.QueryTime("since")
+			p.OptionalQueryID("season_id")
+		}, map[string]string{
+			"limit":     "Invalid limit, must be between 1 and 10",
+			"as_of":     "Invalid as_of, expected YYYY-MM-DD",
+			"since":     "Invalid since, expected an RFC 3339 timestamp",
+			"season_id": "Invalid season ID",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got map[string]string
+			app := fiber.New()
+			app.Get("/scenarios/:scenario_id/weeks/:week", func(c *fiber.Ctx) error {
+				params := NewParams(c)
+				test.read(params)
+				got = messages(&params.Errors)
+				return nil
+			})
+			if _, err := app.Test(httptest.NewRequest("GET", test.url, nil)); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != len(test.expected) {
+				t.Errorf("errors = %v; want %v", got, test.expected)
+			}
+			for field, message := range test.expected {
+				if got[field] != message {
+					t.Errorf("%s = %q; want %q", field, got[field], message)
+				}
+			}
+		})
+	}
+}
+
+func TestToken(t *testing.T) {
+	tests := []struct {
+		token string
+		valid bool
+	}{
+		{"a1B2-c3_d4", true},
+		{"abc%27%3B", false},
+		{"has.dot", false},
+	}
+
+	for _, test := range tests {
+		var errs Errors
+		app := fiber.New()
+		app.Get("/invites/:token", func(c *fiber.Ctx) error {
+			params := NewParams(c)
+			params.Token("token")
+			errs = params.Errors
+			return nil
+		})
+		if _, err := app.Test(httptest.NewRequest("GET", "/invites/"+test.token, nil)); err != nil {
+			t.Fatal(err)
+		}
+		if errs.Empty() != test.valid {
+			t.Errorf("Token(%q) valid = %v; want %v", test.token, errs.Empty(), test.valid)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		sportID int
+		score   *int
+		valid   bool
+	}{
+		{"Missing score", 1, nil, true},
+		{"NFL shutout", 1, intPtr(0), true},
+		{"Negative", 1, intPtr(-3), false},
+		{"NFL too high", 1, intPtr(140), false},
+		{"NBA", 2, intPtr(140), true},
+		{"Unknown sport", 9, intPtr(500), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var errs Errors
+			errs.Score("predicted_home_score", test.sportID, test.score)
+			if errs.Empty() != test.valid {
+				t.Errorf("valid = %v; want %v (%v)", errs.Empty(), test.valid, errs.List())
+			}
+		})
+	}
+}
+
+func TestPick(t *testing.T) {
+	const nfl, nba = 1, 2
+	const home, away = 10, 20
+
+	tests := []struct {
+		name           string
+		sportID        int
+		pickedTeamID   *int
+		homeScore      *int
+		awayScore      *int
+		expectedPicked *int
+		expected       map[string]string
+	}{
+		{
+			name:           "Scores decide the winner",
+			sportID:        nfl,
+			pickedTeamID:   intPtr(home),
+			homeScore:      intPtr(17),
+			awayScore:      intPtr(24),
+			expectedPicked: intPtr(away),
+			expected:       map[string]string{},
+		},
+		{
+			name:           "NFL tie",
+			sportID:        nfl,
+			homeScore:      intPtr(20),
+			awayScore:      intPtr(20),
+			expectedPicked: intPtr(0),
+			expected:       map[string]string{},
+		},
+		{
+			name:      "NBA tie",
+			sportID:   nba,
+			homeScore: intPtr(101),
+			awayScore: intPtr(101),
+			expected:  map[string]string{"upserts[0].predicted_away_score": "Ties are only allowed for NFL games"},
+		},
+		{
+			name:      "Negative score",
+			sportID:   nfl,
+			homeScore: intPtr(-7),
+			awayScore: intPtr(3),
+			expected:  map[string]string{"upserts[0].predicted_home_score": "Predicted scores must be between 0 and 99"},
+		},
+		{
+			name:         "Team not in game",
+			sportID:      nfl,
+			pickedTeamID: intPtr(30),
+			expected:     map[string]string{"upserts[0].picked_team_id": "Picked team is not playing in this game"},
+		},
+		{
+			name:           "Team only",
+			sportID:        nba,
+			pickedTeamID:   intPtr(home),
+			expectedPicked: intPtr(home),
+			expected:       map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var errs Errors
+			picked := errs.Pick("upserts[0].", test.sportID, home, away, test.pickedTeamID, test.homeScore, test.awayScore)
+
+			got := messages(&errs)
+			if len(got) != len(test.expected) {
+				t.Errorf("errors = %v; want %v", got, test.expected)
+			}
+			for field, message := range test.expected {
+				if got[field] != message {
+					t.Errorf("%s = %q; want %q", field, got[field], message)
+				}
+			}
+			if test.expectedPicked != nil && (picked == nil || *picked != *test.expectedPicked) {
+				t.Errorf("picked = %v; want %d", picked, *test.expectedPicked)
+			}
+		})
+	}
+}
+
+func TestPlayoffPickRejectsTies(t *testing.T) {
+	var errs Errors
+	errs.PlayoffPick(1, 10, 20, nil, intPtr(24), intPtr(24))
+	if got := messages(&errs)["predicted_lower_seed_score"]; got != "Playoff games can't end in a tie" {
+		t.Errorf("Tied NFL playoff scores = %q; want the playoff tie error", got)
+	}
+
+	errs = Errors{}
+	errs.PlayoffPick(1, 10, 20, intPtr(0), nil, nil)
+	if !errs.Has("picked_team_id") {
+		t.Error("Picking a tie in a playoff game should be rejected")
+	}
+}